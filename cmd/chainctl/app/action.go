@@ -16,9 +16,12 @@ import (
 
 	"github.com/dobrovols/chainctl/cmd/chainctl/declarative"
 	"github.com/dobrovols/chainctl/internal/config"
+	"github.com/dobrovols/chainctl/internal/phases"
 	internalstate "github.com/dobrovols/chainctl/internal/state"
 	"github.com/dobrovols/chainctl/pkg/bundle"
+	"github.com/dobrovols/chainctl/pkg/chartcache"
 	"github.com/dobrovols/chainctl/pkg/helm"
+	"github.com/dobrovols/chainctl/pkg/readiness"
 	pkgstate "github.com/dobrovols/chainctl/pkg/state"
 	"github.com/dobrovols/chainctl/pkg/telemetry"
 )
@@ -33,15 +36,28 @@ const (
 type sharedOptions struct {
 	ClusterEndpoint  string
 	ValuesFile       string
+	ValuesFiles      []string
+	SetValues        []string
+	SetStringValues  []string
 	ValuesPassphrase string
 	BundlePath       string
 	ChartReference   string
+	ExpectDigest     string
+	Offline          bool
+	VerifyKeyring    string
+	VerifyKeys       []string
 	ReleaseName      string
 	AppVersion       string
 	Namespace        string
 	StateFileName    string
 	StateFilePath    string
+	StateBackend     string
+	HistoryLimit     int
 	Output           string
+	WaitTimeout      time.Duration
+	LockTimeout      time.Duration
+	Atomic           bool
+	Force            bool
 }
 
 type ChartResolver interface {
@@ -53,6 +69,11 @@ type StateManager interface {
 	Write(pkgstate.Record, pkgstate.Overrides) (string, error)
 }
 
+// StateReader loads the previously persisted application state.
+type StateReader interface {
+	Read(pkgstate.Overrides) (pkgstate.Record, error)
+}
+
 type resolutionResult struct {
 	Outcome helm.ResolveResult
 	Bundle  *bundle.Bundle
@@ -60,6 +81,7 @@ type resolutionResult struct {
 
 var (
 	errResolverPullerMissing = errors.New("oci puller not configured")
+	errLockTimeout           = errors.New("--lock-timeout must not be negative")
 	titleCaser               = cases.Title(language.English)
 )
 
@@ -67,19 +89,37 @@ func (o UpgradeOptions) shared() sharedOptions {
 	return sharedOptions{
 		ClusterEndpoint:  o.ClusterEndpoint,
 		ValuesFile:       o.ValuesFile,
+		ValuesFiles:      o.ValuesFiles,
+		SetValues:        o.SetValues,
+		SetStringValues:  o.SetStringValues,
 		ValuesPassphrase: o.ValuesPassphrase,
 		BundlePath:       o.BundlePath,
 		ChartReference:   o.ChartReference,
+		ExpectDigest:     o.ExpectDigest,
+		Offline:          o.Offline,
+		VerifyKeyring:    o.VerifyKeyring,
+		VerifyKeys:       o.VerifyKeys,
 		ReleaseName:      o.ReleaseName,
 		AppVersion:       o.AppVersion,
 		Namespace:        o.Namespace,
 		StateFileName:    o.StateFileName,
 		StateFilePath:    o.StateFilePath,
+		StateBackend:     o.StateBackend,
+		HistoryLimit:     o.HistoryLimit,
 		Output:           o.Output,
+		WaitTimeout:      o.WaitTimeout,
+		LockTimeout:      o.LockTimeout,
+		Atomic:           o.Atomic,
+		Force:            o.Force,
 	}
 }
 
 func runAppAction(cmd *cobra.Command, options sharedOptions, deps UpgradeDeps, action appAction) (err error) {
+	if deps.StateManager == nil {
+		if deps.StateManager, err = internalstate.NewStore(options.StateBackend, internalstate.WithHistoryLimit(options.HistoryLimit)); err != nil {
+			return err
+		}
+	}
 	ensureDeps(&deps)
 
 	if err := validateAppActionInputs(options, action); err != nil {
@@ -95,8 +135,9 @@ func runAppAction(cmd *cobra.Command, options sharedOptions, deps UpgradeDeps, a
 	if err != nil {
 		return err
 	}
+	stateOverrides.Release, stateOverrides.Namespace, stateOverrides.ClusterEndpoint = profile.HelmRelease, profile.HelmNamespace, profile.ClusterEndpoint
 
-	tel, logger, err := initAppTelemetry(cmd, deps)
+	tel, logger, err := initAppTelemetry(cmd, deps.TelemetryEmitter)
 	if err != nil {
 		return err
 	}
@@ -110,43 +151,176 @@ func runAppAction(cmd *cobra.Command, options sharedOptions, deps UpgradeDeps, a
 		}
 	}()
 
+	lock, err := acquireWorkflowLock(deps.Locker, profile, tel.WorkflowID(), options.LockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	if err := guardUnresolvedFailure(deps.StateManager, stateOverrides, profile, options.Force, logger, workflowStep, workflowMetadata); err != nil {
+		return err
+	}
+
 	resolved, err := resolveChartWithLogging(cmd.Context(), options, deps, logger, workflowMetadata)
 	if err != nil {
 		return err
 	}
 
 	bundleInstance := resolved.Bundle
+	profile.ChartPath = resolved.Outcome.ChartPath
+	profile.ChartDigest = resolved.Outcome.Source.Digest
 	installer, helmHasLogging := prepareAppInstaller(deps.Installer, logger)
 
+	validator, _ := installer.(helm.ValuesValidator)
+	validateMetadata := map[string]string{"release": profile.HelmRelease, "namespace": profile.HelmNamespace}
+	if err = phases.ExecuteValidate(tel, validator, profile, bundleInstance, validateMetadata, logger); err != nil {
+		return err
+	}
+
 	helmMetadata := buildHelmInstallMetadata(profile, resolved.Outcome)
 	helmArgs := buildHelmInstallArgs(profile, options)
 
-	if err = executeHelmPhase(tel, installer, profile, bundleInstance, helmMetadata, helmArgs, logger, helmHasLogging); err != nil {
-		return err
+	failedPhase := telemetry.PhaseHelm
+	release, err := executeHelmPhase(tel, installer, profile, bundleInstance, helmMetadata, helmArgs, logger, helmHasLogging)
+	if err == nil {
+		failedPhase = telemetry.PhaseVerify
+		err = phases.ExecuteVerify(cmd.Context(), tel, phases.VerifyOptions{
+			Manifest:  release.Manifest,
+			Namespace: profile.HelmNamespace,
+			Timeout:   options.WaitTimeout,
+			Metadata:  map[string]string{"release": profile.HelmRelease, "namespace": profile.HelmNamespace},
+			Prober: func() (readiness.Prober, error) {
+				if deps.Readiness != nil {
+					return deps.Readiness, nil
+				}
+				return defaultReadinessChecker()
+			},
+			Logger: logger,
+		})
+	}
+	if err != nil {
+		failure := failureRecorder{
+			tel:       tel,
+			manager:   deps.StateManager,
+			profile:   profile,
+			outcome:   resolved.Outcome,
+			action:    action,
+			options:   options,
+			overrides: stateOverrides,
+			stateHint: statePathHint,
+		}
+		if !options.Atomic {
+			return failure.record(release, failedPhase, err)
+		}
+		workflowMetadata["atomic"] = "true"
+		err = atomicRecovery{
+			tel:       tel,
+			logger:    logger,
+			deps:      deps,
+			profile:   profile,
+			outcome:   resolved.Outcome,
+			action:    action,
+			options:   options,
+			overrides: stateOverrides,
+			stateHint: statePathHint,
+		}.recover(release, failedPhase, err)
+		if errors.Is(err, errAtomicRolledBack) {
+			return err
+		}
+		// The release was left in its failed state, so the failure stays unresolved.
+		return failure.record(release, failedPhase, err)
 	}
 
-	record := buildStateRecord(profile, resolved.Outcome, action, options)
+	record := stampAudit(buildStateRecord(profile, resolved.Outcome, release, action, options), tel.WorkflowID())
 	statePath, err := persistState(deps.StateManager, record, stateOverrides, statePathHint)
 	if err != nil {
 		return err
 	}
 
 	logWorkflowSuccess(logger, workflowStep, workflowMetadata)
-	return emitOutput(cmd, profile, resolved.Outcome, statePath, options.Output, action, options)
+	return emitOutput(cmd, profile, resolved.Outcome, release, statePath, options.Output, action, options)
+}
+
+// ErrLockTimeout exposes the invalid --lock-timeout sentinel.
+func ErrLockTimeout() error { return errLockTimeout }
+
+// acquireWorkflowLock serialises mutating workflows that target the same release on the same
+// cluster. The lock is released by the caller once state has been written.
+func acquireWorkflowLock(locker WorkflowLocker, profile *config.Profile, workflowID string, timeout time.Duration) (*pkgstate.FileLock, error) {
+	if timeout < 0 {
+		return nil, errLockTimeout
+	}
+	if locker == nil {
+		return nil, nil
+	}
+	key := pkgstate.WorkflowLockKey(profile.ClusterEndpoint, profile.HelmNamespace, profile.HelmRelease)
+	lock, err := locker.Acquire(key, pkgstate.LockOptions{
+		Operator:   pkgstate.OperatorName(),
+		WorkflowID: workflowID,
+		Timeout:    timeout,
+	})
+	if err != nil {
+		if errors.Is(err, pkgstate.ErrLocked()) {
+			return nil, fmt.Errorf("release %s/%s: %w; retry once it finishes or raise --lock-timeout", profile.HelmNamespace, profile.HelmRelease, err)
+		}
+		return nil, err
+	}
+	return lock, nil
+}
+
+// lockIdentity returns the release, namespace, and cluster endpoint that key the workflow lock,
+// filling the ones the options leave unset from recorded state. Nothing else read here is acted
+// on: callers read the record again once the lock is held and compare it with lockedRelease.
+func lockIdentity(reader StateReader, overrides pkgstate.Overrides, release, namespace, endpoint string) (*config.Profile, error) {
+	profile := &config.Profile{Mode: config.ModeReuse, ClusterEndpoint: endpoint, HelmRelease: release, HelmNamespace: namespace}
+	if release != "" && namespace != "" && endpoint != "" {
+		return profile, nil
+	}
+	recorded, err := reader.Read(overrides)
+	switch {
+	case errors.Is(err, pkgstate.ErrNotFound()):
+		return profile, nil
+	case err != nil:
+		return nil, fmt.Errorf("state file could not be read: %w", err)
+	}
+	profile.ClusterEndpoint = firstNonEmpty(endpoint, recorded.ClusterEndpoint)
+	profile.HelmRelease = firstNonEmpty(release, recorded.Release)
+	profile.HelmNamespace = firstNonEmpty(namespace, recorded.Namespace)
+	return profile, nil
+}
+
+// lockedRelease fails when the state read under the workflow lock describes another release than
+// the one locked, which happens when a workflow replaced the record while this one waited.
+func lockedRelease(locked, current *config.Profile) error {
+	if pkgstate.WorkflowLockKey(locked.ClusterEndpoint, locked.HelmNamespace, locked.HelmRelease) ==
+		pkgstate.WorkflowLockKey(current.ClusterEndpoint, current.HelmNamespace, current.HelmRelease) {
+		return nil
+	}
+	return fmt.Errorf("%w: locked %s/%s but state now records %s/%s", pkgstate.ErrConflict(), locked.HelmNamespace, locked.HelmRelease, current.HelmNamespace, current.HelmRelease)
 }
 
 func validateAppActionInputs(options sharedOptions, action appAction) error {
-	if strings.TrimSpace(options.ValuesFile) == "" {
+	if len(options.valuesFiles()) == 0 {
 		return errValuesFile
 	}
-	if action == actionUpgrade && strings.TrimSpace(options.ClusterEndpoint) == "" {
+	if action != actionInstall && strings.TrimSpace(options.ClusterEndpoint) == "" {
 		return errClusterEndpoint
 	}
 	return nil
 }
 
-func initAppTelemetry(cmd *cobra.Command, deps UpgradeDeps) (*telemetry.Emitter, telemetry.StructuredLogger, error) {
-	emitter := deps.TelemetryEmitter
+// valuesFiles lists the non-blank values layers in merge order.
+func (o sharedOptions) valuesFiles() []string {
+	var files []string
+	for _, path := range append([]string{o.ValuesFile}, o.ValuesFiles...) {
+		if strings.TrimSpace(path) != "" {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+func initAppTelemetry(cmd *cobra.Command, emitter func(io.Writer) (*telemetry.Emitter, error)) (*telemetry.Emitter, telemetry.StructuredLogger, error) {
 	if emitter == nil {
 		emitter = telemetryEmitterDefault
 	}
@@ -230,9 +404,12 @@ func executeHelmPhase(
 	helmArgs []string,
 	logger telemetry.StructuredLogger,
 	helmHasLogging bool,
-) error {
+) (helm.ReleaseInfo, error) {
+	var release helm.ReleaseInfo
 	execute := func() error {
-		installErr := installer.Install(profile, bundleInstance)
+		var installErr error
+		release, installErr = applyRelease(installer, profile, bundleInstance)
+		helm.AddReleaseMetadata(helmMetadata, release)
 		if !helmHasLogging {
 			stderr := ""
 			severity := telemetry.SeverityInfo
@@ -245,20 +422,41 @@ func executeHelmPhase(
 		return installErr
 	}
 
-	return tel.EmitPhase(telemetry.PhaseHelm, helmMetadata, execute)
+	err := tel.EmitPhase(telemetry.PhaseHelm, helmMetadata, execute)
+	return release, err
+}
+
+func applyRelease(installer HelmInstaller, profile *config.Profile, bundleInstance *bundle.Bundle) (helm.ReleaseInfo, error) {
+	if applier, ok := installer.(helm.ReleaseApplier); ok {
+		return applier.Apply(profile, bundleInstance)
+	}
+	return helm.ReleaseInfo{}, installer.Install(profile, bundleInstance)
 }
 
-func buildStateRecord(profile *config.Profile, outcome helm.ResolveResult, action appAction, opts sharedOptions) pkgstate.Record {
+func buildStateRecord(profile *config.Profile, outcome helm.ResolveResult, release helm.ReleaseInfo, action appAction, opts sharedOptions) pkgstate.Record {
 	return pkgstate.Record{
 		Release:         profile.HelmRelease,
 		Namespace:       profile.HelmNamespace,
 		Chart:           outcome.Source,
+		Verification:    outcome.Verification,
 		Version:         deriveVersion(opts, outcome),
 		LastAction:      string(action),
 		ClusterEndpoint: profile.ClusterEndpoint,
+		Revision:        release.Revision,
+		Status:          release.Status,
+		Notes:           release.Notes,
+		ValuesHash:      release.ValuesHash,
 	}
 }
 
+// stampAudit records the operator and the CLI workflow that wrote the record, so history entries
+// can be traced back to the run's telemetry.
+func stampAudit(record pkgstate.Record, workflowID string) pkgstate.Record {
+	record.Operator = pkgstate.OperatorName()
+	record.WorkflowID = workflowID
+	return record
+}
+
 func persistState(manager StateManager, record pkgstate.Record, overrides pkgstate.Overrides, hint string) (string, error) {
 	if manager == nil {
 		return "", fmt.Errorf("state manager unavailable")
@@ -279,6 +477,9 @@ func buildProfileForAction(opts sharedOptions, action appAction) (*config.Profil
 		Mode:                config.ModeReuse,
 		ClusterEndpoint:     opts.ClusterEndpoint,
 		EncryptedValuesPath: opts.ValuesFile,
+		ValuesFiles:         opts.ValuesFiles,
+		SetValues:           opts.SetValues,
+		SetStringValues:     opts.SetStringValues,
 		ValuesPassphrase:    opts.ValuesPassphrase,
 		AirgappedBundlePath: opts.BundlePath,
 		Offline:             strings.TrimSpace(opts.BundlePath) != "",
@@ -301,6 +502,12 @@ func resolveChartSource(ctx context.Context, opts sharedOptions, deps UpgradeDep
 	if err := validateResolveSource(hasChart, hasBundle); err != nil {
 		return resolutionResult{}, err
 	}
+	if !hasChart && strings.TrimSpace(opts.ExpectDigest) != "" {
+		return resolutionResult{}, errDigestNeedsChart
+	}
+	if !hasChart && opts.Offline {
+		return resolutionResult{}, errOfflineNeedsChart
+	}
 	if hasChart {
 		return resolveFromChart(ctx, opts, deps)
 	}
@@ -323,7 +530,22 @@ func resolveFromChart(ctx context.Context, opts sharedOptions, deps UpgradeDeps)
 		return resolutionResult{}, errResolverPullerMissing
 	}
 
-	res, err := deps.Resolver.Resolve(ctx, helm.ResolveOptions{OCIReference: opts.ChartReference})
+	resolveOpts := helm.ResolveOptions{
+		ExpectedDigest: opts.ExpectDigest,
+		ReleaseName:    opts.ReleaseName,
+		Verify:         opts.verifyOptions(),
+		Offline:        opts.Offline,
+	}
+	switch helm.ClassifyChartReference(opts.ChartReference) {
+	case pkgstate.SourceTypeRepo:
+		resolveOpts.RepoReference = opts.ChartReference
+	case pkgstate.SourceTypeDir:
+		resolveOpts.ChartDirectory = opts.ChartReference
+	default:
+		resolveOpts.OCIReference = opts.ChartReference
+	}
+
+	res, err := deps.Resolver.Resolve(ctx, resolveOpts)
 	if err != nil {
 		return resolutionResult{}, err
 	}
@@ -332,14 +554,20 @@ func resolveFromChart(ctx context.Context, opts sharedOptions, deps UpgradeDeps)
 }
 
 func resolveFromBundle(ctx context.Context, opts sharedOptions, deps UpgradeDeps) (resolutionResult, error) {
+	verify := opts.verifyOptions()
 	if deps.Resolver != nil {
 		res, err := deps.Resolver.Resolve(ctx, helm.ResolveOptions{
 			BundlePath:     opts.BundlePath,
 			BundleCacheDir: filepath.Dir(opts.BundlePath),
+			ReleaseName:    opts.ReleaseName,
+			Verify:         verify,
 		})
 		if err == nil && res.Bundle != nil {
 			return resolutionResult{Outcome: res, Bundle: res.Bundle}, nil
 		}
+		if err != nil && verify.Enabled() {
+			return resolutionResult{}, err
+		}
 	}
 
 	loader := deps.BundleLoader
@@ -351,19 +579,53 @@ func resolveFromBundle(ctx context.Context, opts sharedOptions, deps UpgradeDeps
 	if err != nil {
 		return resolutionResult{}, err
 	}
+	verification, err := verifyBundleChart(bundleInst, opts.ReleaseName, verify)
+	if err != nil {
+		return resolutionResult{}, err
+	}
 
 	return resolutionResult{
 		Outcome: helm.ResolveResult{
 			Source: pkgstate.ChartSource{
-				Type:      "bundle",
+				Type:      pkgstate.SourceTypeBundle,
 				Reference: opts.BundlePath,
 			},
+			Verification: verification,
 		},
 		Bundle: bundleInst,
 	}, nil
 }
 
+func verifyBundleChart(bundleInst *bundle.Bundle, release string, verify helm.VerifyOptions) (*pkgstate.ChartVerification, error) {
+	if !verify.Enabled() {
+		return nil, nil
+	}
+	chartPath, err := helm.LocateChartPath(&config.Profile{HelmRelease: release}, bundleInst)
+	if err != nil {
+		return nil, err
+	}
+	verification, err := helm.VerifyChart(chartPath, verify)
+	if err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+func (o sharedOptions) verifyOptions() helm.VerifyOptions {
+	return helm.VerifyOptions{Keyring: o.VerifyKeyring, PublicKeys: o.VerifyKeys}
+}
+
 func resolveStateOverrides(opts sharedOptions) (pkgstate.Overrides, string, error) {
+	if err := pkgstate.ValidateBackend(opts.StateBackend); err != nil {
+		return pkgstate.Overrides{}, "", err
+	}
+	if opts.StateBackend == pkgstate.BackendSecret {
+		if opts.StateFileName != "" || opts.StateFilePath != "" {
+			return pkgstate.Overrides{}, "", errStateFileWithSecret
+		}
+		return pkgstate.Overrides{Release: opts.ReleaseName, Namespace: opts.Namespace, ClusterEndpoint: opts.ClusterEndpoint}, "", nil
+	}
+
 	resolver := internalstate.NewResolver()
 	overrides := pkgstate.Overrides{
 		StateFileName: opts.StateFileName,
@@ -375,14 +637,22 @@ func resolveStateOverrides(opts sharedOptions) (pkgstate.Overrides, string, erro
 		return pkgstate.Overrides{}, "", err
 	}
 
-	return pkgstate.Overrides{StateFilePath: path}, path, nil
+	return pkgstate.Overrides{
+		StateFilePath:   path,
+		Release:         opts.ReleaseName,
+		Namespace:       opts.Namespace,
+		ClusterEndpoint: opts.ClusterEndpoint,
+	}, path, nil
 }
 
 func deriveVersion(opts sharedOptions, res helm.ResolveResult) string {
 	if opts.AppVersion != "" {
 		return opts.AppVersion
 	}
-	if res.Source.Type == "oci" {
+	if res.Source.Version != "" {
+		return res.Source.Version
+	}
+	if res.Source.Type == pkgstate.SourceTypeOCI {
 		if i := strings.LastIndex(res.Source.Reference, ":"); i != -1 && i+1 < len(res.Source.Reference) {
 			return res.Source.Reference[i+1:]
 		}
@@ -396,12 +666,24 @@ func workflowStepName(action appAction) string {
 		return stepAppInstall
 	case actionUpgrade:
 		return stepAppUpgrade
+	case actionRollback:
+		return stepAppRollback
+	case actionDiff:
+		return stepAppDiff
+	case actionUninstall:
+		return stepAppUninstall
+	case actionStatus:
+		return stepAppStatus
+	case actionHistory:
+		return stepAppHistory
+	case actionVerify:
+		return stepAppVerify
 	default:
 		return fmt.Sprintf("app-%s", action)
 	}
 }
 
-func emitOutput(cmd *cobra.Command, profile *config.Profile, result helm.ResolveResult, statePath string, format string, action appAction, opts sharedOptions) error {
+func emitOutput(cmd *cobra.Command, profile *config.Profile, result helm.ResolveResult, release helm.ReleaseInfo, statePath string, format string, action appAction, opts sharedOptions) error {
 	title := ""
 	switch action {
 	case actionInstall:
@@ -416,6 +698,15 @@ func emitOutput(cmd *cobra.Command, profile *config.Profile, result helm.Resolve
 	case "text":
 		fmt.Fprintf(cmd.OutOrStdout(), "%s completed successfully for release %s in namespace %s\n", title, profile.HelmRelease, profile.HelmNamespace)
 		fmt.Fprintf(cmd.OutOrStdout(), "State written to %s\n", statePath)
+		if release.Revision > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "Release revision %d (%s)\n", release.Revision, release.Status)
+		}
+		if v := result.Verification; v != nil {
+			fmt.Fprintf(cmd.OutOrStdout(), "Chart verified by %s (signer %s, key %s)\n", v.Method, v.Signer, v.KeyID)
+		}
+		if notes := strings.TrimSpace(release.Notes); notes != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "\nNOTES:\n%s\n", notes)
+		}
 		return nil
 	case "json":
 		payload := map[string]any{
@@ -434,6 +725,19 @@ func emitOutput(cmd *cobra.Command, profile *config.Profile, result helm.Resolve
 		if opts.AppVersion != "" {
 			payload["version"] = opts.AppVersion
 		}
+		if result.Source.Version != "" {
+			payload["chartVersion"] = result.Source.Version
+		}
+		if release.Revision > 0 {
+			payload["revision"] = release.Revision
+			payload["releaseStatus"] = release.Status
+		}
+		if release.Notes != "" {
+			payload["notes"] = release.Notes
+		}
+		if result.Verification != nil {
+			payload["verification"] = result.Verification
+		}
 		enc := json.NewEncoder(cmd.OutOrStdout())
 		enc.SetEscapeHTML(false)
 		return enc.Encode(payload)
@@ -444,6 +748,9 @@ func emitOutput(cmd *cobra.Command, profile *config.Profile, result helm.Resolve
 
 func buildResolveArgs(opts sharedOptions) []string {
 	if strings.TrimSpace(opts.ChartReference) != "" {
+		if helm.ClassifyChartReference(opts.ChartReference) == pkgstate.SourceTypeDir {
+			return []string{"chart", "load", opts.ChartReference}
+		}
 		return []string{"helm", "pull", opts.ChartReference}
 	}
 	if strings.TrimSpace(opts.BundlePath) != "" {
@@ -454,6 +761,12 @@ func buildResolveArgs(opts sharedOptions) []string {
 
 func buildResolveMetadata(res *helm.ResolveResult, opts sharedOptions) map[string]string {
 	meta := map[string]string{}
+	if expected := strings.TrimSpace(opts.ExpectDigest); expected != "" {
+		meta["expectedDigest"] = chartcache.NormalizeDigest(expected)
+	}
+	if opts.Offline {
+		meta["offline"] = "true"
+	}
 	if res != nil {
 		if res.Source.Type != "" {
 			meta["source"] = res.Source.Type
@@ -461,9 +774,20 @@ func buildResolveMetadata(res *helm.ResolveResult, opts sharedOptions) map[strin
 		if res.Source.Reference != "" {
 			meta["reference"] = res.Source.Reference
 		}
+		if res.Source.Version != "" {
+			meta["version"] = res.Source.Version
+		}
 		if res.Source.Digest != "" {
 			meta["digest"] = res.Source.Digest
 		}
+		if res.Source.Type == pkgstate.SourceTypeOCI {
+			meta["cache"] = cacheOutcome(res.Cached)
+		}
+		if v := res.Verification; v != nil {
+			meta["verification"] = v.Method
+			meta["signer"] = v.Signer
+			meta["keyId"] = v.KeyID
+		}
 	} else {
 		if strings.TrimSpace(opts.ChartReference) != "" {
 			meta["reference"] = opts.ChartReference
@@ -475,6 +799,13 @@ func buildResolveMetadata(res *helm.ResolveResult, opts sharedOptions) map[strin
 	return meta
 }
 
+func cacheOutcome(cached bool) string {
+	if cached {
+		return "hit"
+	}
+	return "miss"
+}
+
 func buildHelmInstallMetadata(profile *config.Profile, res helm.ResolveResult) map[string]string {
 	meta := map[string]string{
 		"namespace": profile.HelmNamespace,
@@ -497,12 +828,18 @@ func buildHelmInstallArgs(profile *config.Profile, opts sharedOptions) []string
 	if ns := strings.TrimSpace(profile.HelmNamespace); ns != "" {
 		args = append(args, "--namespace", ns)
 	}
-	if profile.EncryptedFile != "" {
-		args = append(args, "--values", profile.EncryptedFile)
+	for _, path := range profile.ValuesLayers() {
+		args = append(args, "--values", path)
 	}
 	if profile.Passphrase != "" {
 		args = append(args, "--values-passphrase", profile.Passphrase)
 	}
+	for _, expr := range profile.SetValues {
+		args = append(args, "--set", expr)
+	}
+	for _, expr := range profile.SetStringValues {
+		args = append(args, "--set-string", expr)
+	}
 	if strings.TrimSpace(opts.ChartReference) != "" {
 		args = append(args, opts.ChartReference)
 	}
@@ -94,6 +94,26 @@ func TestResolveChartSourceUsesResolverForOCI(t *testing.T) {
 	}
 }
 
+func TestResolveChartSourceRoutesRepoAndDirectoryCharts(t *testing.T) {
+	cases := map[string]func(helm.ResolveOptions) string{
+		"https://charts.example.com/stable/app:1.4.0": func(o helm.ResolveOptions) string { return o.RepoReference },
+		"./charts/app": func(o helm.ResolveOptions) string { return o.ChartDirectory },
+	}
+	for ref, field := range cases {
+		res := &stubResolver{result: helm.ResolveResult{Source: pkgstate.ChartSource{Type: helm.ClassifyChartReference(ref), Reference: ref, Version: "1.4.0"}}}
+		result, err := resolveChartSource(context.Background(), sharedOptions{ChartReference: ref}, UpgradeDeps{Resolver: res})
+		if err != nil {
+			t.Fatalf("%s: resolve chart source: %v", ref, err)
+		}
+		if got := field(res.called[0]); got != ref || res.called[0].OCIReference != "" {
+			t.Fatalf("%s: unexpected resolve options %+v", ref, res.called[0])
+		}
+		if version := deriveVersion(sharedOptions{}, result.Outcome); version != "1.4.0" {
+			t.Fatalf("%s: expected resolved chart version, got %q", ref, version)
+		}
+	}
+}
+
 func TestResolveChartSourceRequiresResolverForOCI(t *testing.T) {
 	ctx := context.Background()
 	options := sharedOptions{ChartReference: appTestOCIChartRef}
@@ -201,24 +221,6 @@ func TestEnsureDepsSetsDefaults(t *testing.T) {
 	}
 }
 
-func TestSplitOCIReferenceParsesVersion(t *testing.T) {
-	chart, version := splitOCIReference("oci://registry.local/apps/app:1.2.3")
-	if version != "1.2.3" {
-		t.Fatalf("expected version 1.2.3, got %s", version)
-	}
-	if chart != "oci://registry.local/apps/app" {
-		t.Fatalf("unexpected chart reference %s", chart)
-	}
-
-	chart, version = splitOCIReference("oci://registry.local:5000/apps/app")
-	if version != "" {
-		t.Fatalf("expected empty version when colon belongs to host, got %s", version)
-	}
-	if chart != "oci://registry.local:5000/apps/app" {
-		t.Fatalf("unexpected chart reference %s", chart)
-	}
-}
-
 func TestBuildProfileForActionInstall(t *testing.T) {
 	opts := sharedOptions{
 		ValuesFile:       appTestValuesFile,
@@ -258,22 +260,12 @@ func TestBuildProfileForActionUpgradeRequiresEndpoint(t *testing.T) {
 
 func TestEmitOutputUnsupportedFormat(t *testing.T) {
 	cmd := &cobra.Command{}
-	err := emitOutput(cmd, &config.Profile{}, helm.ResolveResult{}, "", "yaml", actionInstall, sharedOptions{})
+	err := emitOutput(cmd, &config.Profile{}, helm.ResolveResult{}, helm.ReleaseInfo{}, "", "yaml", actionInstall, sharedOptions{})
 	if !errors.Is(err, errUnsupportedOutput) {
 		t.Fatalf("expected errUnsupportedOutput, got %v", err)
 	}
 }
 
-func TestNewOCIPullerConstructsInstance(t *testing.T) {
-	puller, err := newOCIPuller()
-	if err != nil {
-		t.Fatalf("expected puller without error, got %v", err)
-	}
-	if puller == nil {
-		t.Fatal("expected non-nil puller")
-	}
-}
-
 func TestRunAppActionRequiresValuesFile(t *testing.T) {
 	cmd := &cobra.Command{}
 	cmd.SetContext(context.Background())
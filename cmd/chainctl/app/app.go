@@ -11,6 +11,12 @@ func NewAppCommand() *cobra.Command {
 
 	cmd.AddCommand(NewInstallCommand())
 	cmd.AddCommand(NewUpgradeCommand())
+	cmd.AddCommand(NewRollbackCommand())
+	cmd.AddCommand(NewDiffCommand())
+	cmd.AddCommand(NewUninstallCommand())
+	cmd.AddCommand(NewStatusCommand())
+	cmd.AddCommand(NewHistoryCommand())
+	cmd.AddCommand(NewVerifyCommand())
 
 	return cmd
 }
@@ -0,0 +1,199 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/dobrovols/chainctl/internal/config"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+var (
+	errAtomicRolledBack = errors.New("release rolled back")
+	errAtomicNoTarget   = errors.New("atomic rollback skipped: the failed run created no release revision")
+)
+
+// ErrAtomicRolledBack exposes the sentinel returned after --atomic restored the previous release.
+func ErrAtomicRolledBack() error { return errAtomicRolledBack }
+
+// ErrAtomicNoTarget exposes the sentinel returned when --atomic had nothing to revert.
+func ErrAtomicNoTarget() error { return errAtomicNoTarget }
+
+// atomicRecovery reverts a release whose helm or verify phase failed under --atomic.
+type atomicRecovery struct {
+	tel       *telemetry.Emitter
+	logger    telemetry.StructuredLogger
+	deps      UpgradeDeps
+	profile   *config.Profile
+	outcome   helm.ResolveResult
+	action    appAction
+	options   sharedOptions
+	overrides pkgstate.Overrides
+	stateHint string
+}
+
+// atomicPlan describes how the failed release is reverted: a rollback to Revision (zero lets Helm
+// pick the preceding revision) or, for a failed first install, an uninstall.
+type atomicPlan struct {
+	Revision  int
+	Uninstall bool
+	Restored  *pkgstate.HistoryEntry
+}
+
+// recover rolls back to the last good revision inside a rollback telemetry phase, records the
+// failed attempt and the restored revision in state, and returns an error wrapping the cause.
+func (a atomicRecovery) recover(failed helm.ReleaseInfo, failedPhase telemetry.Phase, cause error) error {
+	previous, err := a.previousRecord()
+	if err != nil {
+		return errors.Join(cause, err)
+	}
+	plan, ok := planAtomicRollback(previous, failed)
+	if !ok {
+		return fmt.Errorf("%w: %w", errAtomicNoTarget, cause)
+	}
+
+	restored, err := a.executeRollbackPhase(plan, failed, failedPhase)
+	if err != nil {
+		return errors.Join(cause, fmt.Errorf("atomic rollback: %w", err))
+	}
+
+	if _, err := persistState(a.deps.StateManager, a.revertedRecord(previous, plan, failed, restored), a.overrides, a.stateHint); err != nil {
+		return errors.Join(cause, err)
+	}
+
+	if plan.Uninstall {
+		return fmt.Errorf("%w: uninstalled failed first install after %s phase failure: %w", errAtomicRolledBack, failedPhase, cause)
+	}
+	return fmt.Errorf("%w to revision %d after %s phase failure: %w", errAtomicRolledBack, restored.Revision, failedPhase, cause)
+}
+
+// previousRecord returns the last good state recorded for this release, if any.
+func (a atomicRecovery) previousRecord() (*pkgstate.Record, error) {
+	return readPreviousRecord(a.deps.StateManager, a.overrides, a.profile)
+}
+
+// planAtomicRollback returns no plan when the failed run created no release revision, since the
+// deployed release is then untouched. Otherwise it prefers the revision recorded in state; without
+// one, a failed first revision is uninstalled and later revisions are rolled back to the revision
+// Helm deployed before them.
+func planAtomicRollback(previous *pkgstate.Record, failed helm.ReleaseInfo) (atomicPlan, bool) {
+	if failed.Revision == 0 {
+		return atomicPlan{}, false
+	}
+	if previous != nil && previous.Revision > 0 && previous.Revision < failed.Revision {
+		entry, found := previous.FindRevision(previous.Revision)
+		if !found {
+			entry = previous.Entry()
+		}
+		return atomicPlan{Revision: previous.Revision, Restored: &entry}, true
+	}
+	if failed.Revision == 1 {
+		return atomicPlan{Uninstall: true}, true
+	}
+	return atomicPlan{}, true
+}
+
+func (a atomicRecovery) executeRollbackPhase(plan atomicPlan, failed helm.ReleaseInfo, failedPhase telemetry.Phase) (helm.ReleaseInfo, error) {
+	profile := a.profile
+	metadata := map[string]string{
+		"release":     profile.HelmRelease,
+		"namespace":   profile.HelmNamespace,
+		"failedPhase": string(failedPhase),
+	}
+	if failed.Revision > 0 {
+		metadata["failedRevision"] = strconv.Itoa(failed.Revision)
+	}
+	args := []string{"helm", "rollback", profile.HelmRelease}
+	if plan.Uninstall {
+		metadata["strategy"] = "uninstall"
+		args = []string{"helm", "uninstall", profile.HelmRelease}
+	} else {
+		metadata["strategy"] = "rollback"
+		if plan.Revision > 0 {
+			metadata["targetRevision"] = strconv.Itoa(plan.Revision)
+			args = append(args, strconv.Itoa(plan.Revision))
+		}
+	}
+	args = append(args, "--namespace", profile.HelmNamespace)
+
+	var restored helm.ReleaseInfo
+	err := a.tel.EmitPhase(telemetry.PhaseRollback, metadata, func() error {
+		var revertErr error
+		if plan.Uninstall {
+			_, revertErr = a.deps.Uninstaller.Uninstall(helm.UninstallOptions{
+				Release:   profile.HelmRelease,
+				Namespace: profile.HelmNamespace,
+				Wait:      true,
+			})
+		} else {
+			restored, revertErr = a.deps.Rollbacker.Rollback(helm.RollbackOptions{
+				Release:   profile.HelmRelease,
+				Namespace: profile.HelmNamespace,
+				Revision:  plan.Revision,
+			})
+			helm.AddReleaseMetadata(metadata, restored)
+		}
+		stderr := ""
+		severity := telemetry.SeverityInfo
+		if revertErr != nil {
+			severity = telemetry.SeverityError
+			stderr = revertErr.Error()
+		}
+		logCommandEntry(a.logger, stepHelmCommand, args, stderr, severity, metadata, revertErr)
+		return revertErr
+	})
+	return restored, err
+}
+
+// revertedRecord keeps the failed attempt in history and records how the release was reverted:
+// the restored revision as the current release, or a tombstone when a failed first install was
+// uninstalled.
+func (a atomicRecovery) revertedRecord(previous *pkgstate.Record, plan atomicPlan, failed helm.ReleaseInfo, restored helm.ReleaseInfo) pkgstate.Record {
+	attempt := pkgstate.HistoryEntry{
+		Revision:   failed.Revision,
+		Action:     string(a.action),
+		Chart:      a.outcome.Source,
+		Version:    deriveVersion(a.options, a.outcome),
+		Operator:   pkgstate.OperatorName(),
+		WorkflowID: a.tel.WorkflowID(),
+	}
+	base := pkgstate.Record{
+		Release:         a.profile.HelmRelease,
+		Namespace:       a.profile.HelmNamespace,
+		ClusterEndpoint: a.profile.ClusterEndpoint,
+	}
+	if previous != nil {
+		base = *previous
+	}
+	base = base.WithFailedAttempt(attempt)
+
+	record := pkgstate.Record{
+		Release:         base.Release,
+		Namespace:       base.Namespace,
+		LastAction:      string(actionRollback),
+		ClusterEndpoint: a.profile.ClusterEndpoint,
+		Revision:        restored.Revision,
+		Status:          restored.Status,
+		Notes:           restored.Notes,
+		ValuesHash:      restored.ValuesHash,
+	}
+	switch {
+	case plan.Uninstall:
+		record.Chart, record.Version = attempt.Chart, attempt.Version
+		record.LastAction = string(actionUninstall)
+		record.Status = statusUninstalled
+	case plan.Restored != nil:
+		record.Chart, record.Version, record.Verification = plan.Restored.Chart, plan.Restored.Version, base.Verification
+	case previous != nil:
+		record.Chart, record.Version, record.Verification = previous.Chart, previous.Version, previous.Verification
+	default:
+		// Helm restored a revision that predates the recorded state; only the chart digest and
+		// version it reports are known, so the attempted source keeps the reference.
+		record.Chart, record.Version = attempt.Chart, restored.ChartVersion
+		record.Chart.Digest = restored.ChartDigest
+	}
+	return stampAudit(record, a.tel.WorkflowID()).WithHistory(&base)
+}
@@ -0,0 +1,230 @@
+package app_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	appcmd "github.com/dobrovols/chainctl/cmd/chainctl/app"
+	"github.com/dobrovols/chainctl/internal/config"
+	"github.com/dobrovols/chainctl/pkg/bundle"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	"github.com/dobrovols/chainctl/pkg/readiness"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+)
+
+type revisionInstaller struct {
+	fakeHelmInstaller
+	release helm.ReleaseInfo
+}
+
+func (r *revisionInstaller) Apply(p *config.Profile, _ *bundle.Bundle) (helm.ReleaseInfo, error) {
+	r.called = true
+	return r.release, r.err
+}
+
+func atomicOptions() appcmd.UpgradeOptions {
+	return appcmd.UpgradeOptions{
+		ClusterEndpoint:  "https://cluster.local",
+		ValuesFile:       "/tmp/values.enc",
+		ValuesPassphrase: "secret",
+		ChartReference:   "oci://registry.example.com/apps/myapp:1.4.0",
+		ReleaseName:      "myapp-demo",
+		Namespace:        "demo",
+		Output:           "text",
+		Atomic:           true,
+	}
+}
+
+func atomicResolver() *resolvingStub {
+	return &resolvingStub{result: helm.ResolveResult{Source: pkgstate.ChartSource{Type: "oci", Reference: "oci://registry.example.com/apps/myapp:1.4.0", Digest: "sha256:three"}}}
+}
+
+func TestAppUpgradeCommand_AtomicRollsBackFailedHelmApply(t *testing.T) {
+	stateMgr := &readableStateStub{current: recordedState()}
+	rollbacker := &fakeRollbacker{result: helm.ReleaseInfo{Revision: 4, Status: "deployed"}}
+	applyErr := errors.New("timed out waiting for the condition")
+	deps := appcmd.UpgradeDeps{
+		Installer:        &revisionInstaller{fakeHelmInstaller: fakeHelmInstaller{err: applyErr}, release: helm.ReleaseInfo{Revision: 3, Status: "failed"}},
+		TelemetryEmitter: telemetryNoop,
+		Resolver:         atomicResolver(),
+		StateManager:     stateMgr,
+		Rollbacker:       rollbacker,
+		Uninstaller:      &fakeUninstaller{},
+	}
+
+	cmd := &cobra.Command{}
+	var logs bytes.Buffer
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(&logs)
+	err := appcmd.RunUpgradeForTest(cmd, atomicOptions(), deps)
+	if !errors.Is(err, appcmd.ErrAtomicRolledBack()) || !errors.Is(err, applyErr) {
+		t.Fatalf("expected rollback error wrapping the helm failure, got %v", err)
+	}
+	if rollbacker.opts.Revision != 2 || rollbacker.opts.Release != "myapp-demo" {
+		t.Fatalf("expected rollback to recorded revision 2, got %+v", rollbacker.opts)
+	}
+	if !strings.Contains(logs.String(), `"phase":"rollback","outcome":"success"`) {
+		t.Fatalf("expected rollback phase telemetry, got %s", logs.String())
+	}
+
+	record := stateMgr.record
+	if record.Revision != 4 || record.LastAction != "rollback" || record.Version != "1.3.0" || record.Chart.Digest != "sha256:two" {
+		t.Fatalf("expected restored revision in state, got %+v", record)
+	}
+	if len(record.History) != 4 {
+		t.Fatalf("expected failed attempt and restore in history, got %+v", record.History)
+	}
+	failed, restored := record.History[2], record.History[3]
+	if !failed.Failed() || failed.Revision != 3 || failed.Chart.Digest != "sha256:three" || failed.Action != "upgrade" {
+		t.Fatalf("unexpected failed entry %+v", failed)
+	}
+	if restored.Failed() || restored.Revision != 4 || restored.Action != "rollback" {
+		t.Fatalf("unexpected restored entry %+v", restored)
+	}
+}
+
+func TestAppUpgradeCommand_AtomicRollsBackFailedVerification(t *testing.T) {
+	stateMgr := &readableStateStub{current: recordedState()}
+	rollbacker := &fakeRollbacker{result: helm.ReleaseInfo{Revision: 4, Status: "deployed"}}
+	opts := atomicOptions()
+	opts.WaitTimeout = 20 * time.Millisecond
+	deps := appcmd.UpgradeDeps{
+		Installer:        &revisionInstaller{release: helm.ReleaseInfo{Revision: 3, Status: "deployed", Manifest: statusManifest}},
+		TelemetryEmitter: telemetryNoop,
+		Resolver:         atomicResolver(),
+		StateManager:     stateMgr,
+		Readiness:        &fakeReadiness{ready: false},
+		Rollbacker:       rollbacker,
+		Uninstaller:      &fakeUninstaller{},
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	err := appcmd.RunUpgradeForTest(cmd, opts, deps)
+	if !errors.Is(err, appcmd.ErrAtomicRolledBack()) || !errors.Is(err, readiness.ErrNotReady()) {
+		t.Fatalf("expected rollback error wrapping readiness failure, got %v", err)
+	}
+	if !rollbacker.called || stateMgr.record.Revision != 4 {
+		t.Fatalf("expected rollback and restored state, got %+v", stateMgr.record)
+	}
+}
+
+func TestAppInstallCommand_AtomicUninstallsFailedFirstInstall(t *testing.T) {
+	stateMgr := &readableStateStub{readErr: pkgstate.ErrNotFound()}
+	uninstaller := &fakeUninstaller{}
+	rollbacker := &fakeRollbacker{}
+	deps := appcmd.UpgradeDeps{
+		Installer:        &revisionInstaller{fakeHelmInstaller: fakeHelmInstaller{err: errors.New("install failed")}, release: helm.ReleaseInfo{Revision: 1, Status: "failed"}},
+		TelemetryEmitter: telemetryNoop,
+		Resolver:         atomicResolver(),
+		StateManager:     stateMgr,
+		Rollbacker:       rollbacker,
+		Uninstaller:      uninstaller,
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	err := appcmd.RunInstallForTest(cmd, atomicOptions(), deps)
+	if !errors.Is(err, appcmd.ErrAtomicRolledBack()) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+	if uninstaller.opts.Release != "myapp-demo" || rollbacker.called {
+		t.Fatalf("expected failed first install to be uninstalled, got %+v", uninstaller.opts)
+	}
+	record := stateMgr.record
+	if record.LastAction != "uninstall" || record.Status != "uninstalled" || record.Revision != 0 || record.Chart.Digest != "sha256:three" || record.Failure != nil {
+		t.Fatalf("expected a tombstone for the uninstalled first install, got %+v", record)
+	}
+	if len(record.History) != 2 {
+		t.Fatalf("expected failed attempt and uninstall in history, got %+v", record.History)
+	}
+	if failed, removed := record.History[0], record.History[1]; !failed.Failed() || failed.Revision != 1 || failed.Action != "install" || removed.Failed() || removed.Action != "uninstall" {
+		t.Fatalf("unexpected history %+v", record.History)
+	}
+}
+
+func TestAppUpgradeCommand_AtomicRecordsRollbackWithoutRecordedState(t *testing.T) {
+	stateMgr := &readableStateStub{readErr: pkgstate.ErrNotFound()}
+	rollbacker := &fakeRollbacker{result: helm.ReleaseInfo{Revision: 4, Status: "deployed", ChartVersion: "1.3.0", ChartDigest: "sha256:two"}}
+	deps := appcmd.UpgradeDeps{
+		Installer:        &revisionInstaller{fakeHelmInstaller: fakeHelmInstaller{err: errors.New("upgrade failed")}, release: helm.ReleaseInfo{Revision: 3, Status: "failed"}},
+		TelemetryEmitter: telemetryNoop,
+		Resolver:         atomicResolver(),
+		StateManager:     stateMgr,
+		Rollbacker:       rollbacker,
+		Uninstaller:      &fakeUninstaller{},
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	if err := appcmd.RunUpgradeForTest(cmd, atomicOptions(), deps); !errors.Is(err, appcmd.ErrAtomicRolledBack()) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+	if rollbacker.opts.Revision != 0 {
+		t.Fatalf("expected Helm to pick the preceding revision, got %d", rollbacker.opts.Revision)
+	}
+	record := stateMgr.record
+	if record.LastAction != "rollback" || record.Revision != 4 || record.Version != "1.3.0" || record.Chart.Digest != "sha256:two" {
+		t.Fatalf("expected restored revision in state, got %+v", record)
+	}
+	if len(record.History) != 2 || !record.History[0].Failed() || record.History[0].Revision != 3 || record.History[1].Action != "rollback" {
+		t.Fatalf("expected failed attempt and restore in history, got %+v", record.History)
+	}
+}
+
+func TestAppUpgradeCommand_WithoutAtomicLeavesFailedRelease(t *testing.T) {
+	rollbacker := &fakeRollbacker{}
+	opts := atomicOptions()
+	opts.Atomic = false
+	deps := appcmd.UpgradeDeps{
+		Installer:        &revisionInstaller{fakeHelmInstaller: fakeHelmInstaller{err: errors.New("upgrade failed")}, release: helm.ReleaseInfo{Revision: 3}},
+		TelemetryEmitter: telemetryNoop,
+		Resolver:         atomicResolver(),
+		StateManager:     &readableStateStub{current: recordedState()},
+		Rollbacker:       rollbacker,
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	if err := appcmd.RunUpgradeForTest(cmd, opts, deps); err == nil || errors.Is(err, appcmd.ErrAtomicRolledBack()) {
+		t.Fatalf("expected plain failure without --atomic, got %v", err)
+	}
+	if rollbacker.called {
+		t.Fatal("did not expect a rollback without --atomic")
+	}
+}
+
+func TestAppUpgradeCommand_AtomicSkipsRollbackWithoutNewRevision(t *testing.T) {
+	rollbacker := &fakeRollbacker{}
+	uninstaller := &fakeUninstaller{}
+	applyErr := errors.New("render templates: parse error")
+	deps := appcmd.UpgradeDeps{
+		Installer:        &revisionInstaller{fakeHelmInstaller: fakeHelmInstaller{err: applyErr}},
+		TelemetryEmitter: telemetryNoop,
+		Resolver:         atomicResolver(),
+		StateManager:     &readableStateStub{current: recordedState()},
+		Rollbacker:       rollbacker,
+		Uninstaller:      uninstaller,
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	err := appcmd.RunUpgradeForTest(cmd, atomicOptions(), deps)
+	if !errors.Is(err, appcmd.ErrAtomicNoTarget()) || !errors.Is(err, applyErr) {
+		t.Fatalf("expected skipped rollback wrapping the helm failure, got %v", err)
+	}
+	if rollbacker.called || uninstaller.opts.Release != "" {
+		t.Fatal("did not expect the deployed release to be reverted when no revision was created")
+	}
+}
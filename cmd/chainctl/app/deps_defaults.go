@@ -1,78 +1,168 @@
 package app
 
 import (
-	"context"
-	"strings"
+	"fmt"
 
+	internalhelm "github.com/dobrovols/chainctl/internal/helm"
+	"github.com/dobrovols/chainctl/internal/kubeclient"
 	"github.com/dobrovols/chainctl/internal/state"
 	"github.com/dobrovols/chainctl/pkg/bundle"
 	"github.com/dobrovols/chainctl/pkg/helm"
+	"github.com/dobrovols/chainctl/pkg/readiness"
 	pkgstate "github.com/dobrovols/chainctl/pkg/state"
-	"helm.sh/helm/v3/pkg/action"
-	"helm.sh/helm/v3/pkg/cli"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
 )
 
-var defaultUpgradeDeps UpgradeDeps
+var defaultUpgradeDeps = UpgradeDeps{
+	Installer: helm.NewInstaller(helm.NewSDKExecutor()),
+}
+
+var defaultRollbackDeps = RollbackDeps{
+	Rollbacker: helm.NewSDKExecutor(),
+}
+
+var defaultDiffDeps = DiffDeps{
+	Renderer: helm.NewSDKExecutor(),
+}
 
-type ociPuller struct {
-	settings *cli.EnvSettings
+var defaultUninstallDeps = UninstallDeps{
+	Uninstaller: helm.NewSDKExecutor(),
 }
 
-func newOCIPuller() (helm.OCIPuller, error) {
-	return &ociPuller{settings: cli.New()}, nil
+var defaultStatusDeps = StatusDeps{
+	Releases: helm.NewSDKExecutor(),
 }
 
-func (p *ociPuller) Pull(ctx context.Context, ref string) (helm.PullResult, error) {
-	chartRef, version := splitOCIReference(ref)
-	opts := action.ChartPathOptions{PassCredentialsAll: true}
-	if version != "" {
-		opts.Version = version
+var defaultVerifyDeps = VerifyDeps{
+	Releases: helm.NewSDKExecutor(),
+}
+
+func ensureDeps(deps *UpgradeDeps) {
+	if deps.Locker == nil {
+		deps.Locker = defaultWorkflowLocker()
 	}
-	path, err := opts.LocateChart(chartRef, p.settings)
-	if err != nil {
-		return helm.PullResult{}, err
+	if deps.BundleLoader == nil {
+		deps.BundleLoader = bundle.Load
+	}
+	if deps.TelemetryEmitter == nil {
+		deps.TelemetryEmitter = telemetryEmitterDefault
+	}
+	if deps.StateManager == nil {
+		deps.StateManager = state.NewFileManager()
+	}
+	if deps.Rollbacker == nil || deps.Uninstaller == nil {
+		executor := helm.NewSDKExecutor()
+		if deps.Rollbacker == nil {
+			deps.Rollbacker = executor
+		}
+		if deps.Uninstaller == nil {
+			deps.Uninstaller = executor
+		}
 	}
+	if deps.Resolver == nil {
+		deps.Resolver = internalhelm.NewChartResolver(deps.BundleLoader)
+	}
+}
 
-	// Attempt to extract digest from the ref (e.g., ...@sha256:...)
-	var digest string
-	if atIdx := strings.LastIndex(ref, "@sha256:"); atIdx != -1 {
-		digest = ref[atIdx+1:]
+func ensureRollbackDeps(deps *RollbackDeps) {
+	if deps.Locker == nil {
+		deps.Locker = defaultWorkflowLocker()
 	}
+	if deps.TelemetryEmitter == nil {
+		deps.TelemetryEmitter = telemetryEmitterDefault
+	}
+	if deps.StateManager == nil {
+		deps.StateManager = state.NewFileManager()
+	}
+	if deps.Rollbacker == nil {
+		deps.Rollbacker = helm.NewSDKExecutor()
+	}
+}
 
-	return helm.PullResult{
-		ChartPath: path,
-		Digest:    digest,
-	}, nil
+func ensureUninstallDeps(deps *UninstallDeps) {
+	if deps.Locker == nil {
+		deps.Locker = defaultWorkflowLocker()
+	}
+	if deps.TelemetryEmitter == nil {
+		deps.TelemetryEmitter = telemetryEmitterDefault
+	}
+	if deps.StateManager == nil {
+		deps.StateManager = state.NewFileManager()
+	}
+	if deps.Uninstaller == nil {
+		deps.Uninstaller = helm.NewSDKExecutor()
+	}
 }
 
-func splitOCIReference(ref string) (string, string) {
-	idx := strings.LastIndex(ref, ":")
-	if idx == -1 {
-		return ref, ""
+func ensureStatusDeps(deps *StatusDeps) {
+	if deps.TelemetryEmitter == nil {
+		deps.TelemetryEmitter = telemetryEmitterDefault
 	}
-	lastSlash := strings.LastIndex(ref, "/")
-	if idx > lastSlash {
-		return ref[:idx], ref[idx+1:]
+	if deps.StateManager == nil {
+		deps.StateManager = state.NewFileManager()
+	}
+	if deps.Releases == nil {
+		deps.Releases = helm.NewSDKExecutor()
 	}
-	return ref, ""
 }
 
-func ensureDeps(deps *UpgradeDeps) {
-	if deps.BundleLoader == nil {
-		deps.BundleLoader = bundle.Load
+func ensureVerifyDeps(deps *VerifyDeps) {
+	if deps.TelemetryEmitter == nil {
+		deps.TelemetryEmitter = telemetryEmitterDefault
+	}
+	if deps.StateManager == nil {
+		deps.StateManager = state.NewFileManager()
 	}
+	if deps.Releases == nil {
+		deps.Releases = helm.NewSDKExecutor()
+	}
+}
+
+func ensureHistoryDeps(deps *HistoryDeps) {
 	if deps.TelemetryEmitter == nil {
 		deps.TelemetryEmitter = telemetryEmitterDefault
 	}
 	if deps.StateManager == nil {
-		deps.StateManager = pkgstate.NewManager(state.NewResolver())
+		deps.StateManager = state.NewFileManager()
 	}
-	if deps.Resolver == nil {
-		puller, err := newOCIPuller()
-		if err != nil {
-			deps.Resolver = helm.NewResolver(nil, deps.BundleLoader)
-		} else {
-			deps.Resolver = helm.NewResolver(puller, deps.BundleLoader)
-		}
+}
+
+// defaultWorkflowLocker keeps workflow locks in the managed config directory. Without a home
+// directory no locker is configured and workflows run unlocked.
+func defaultWorkflowLocker() WorkflowLocker {
+	dir, err := state.LockDirectory()
+	if err != nil {
+		return nil
+	}
+	return pkgstate.NewLocker(dir)
+}
+
+// defaultReadinessChecker builds a checker against the active kubeconfig on first use.
+func defaultReadinessChecker() (ReadinessChecker, error) {
+	client, err := kubeclient.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes client: %w", err)
+	}
+	return readiness.NewChecker(client), nil
+}
+
+// defaultObjectReader builds a live object reader against the active kubeconfig on first use.
+func defaultObjectReader() (helm.ObjectReader, error) {
+	cfg, err := kubeclient.RESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes client: %w", err)
+	}
+	client, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes client: %w", err)
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes client: %w", err)
 	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+	return helm.NewClusterObjectReader(client, mapper), nil
 }
@@ -0,0 +1,264 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dobrovols/chainctl/internal/cli/exitcode"
+	"github.com/dobrovols/chainctl/internal/config"
+	"github.com/dobrovols/chainctl/pkg/bundle"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+const actionDiff appAction = "diff"
+
+// DiffOptions holds CLI flags for app diff.
+type DiffOptions struct {
+	ClusterEndpoint  string
+	ValuesFile       string
+	ValuesFiles      []string
+	SetValues        []string
+	SetStringValues  []string
+	ValuesPassphrase string
+	BundlePath       string
+	ChartReference   string
+	ExpectDigest     string
+	Offline          bool
+	VerifyKeyring    string
+	VerifyKeys       []string
+	ReleaseName      string
+	Namespace        string
+	Output           string
+}
+
+// DiffDeps defines dependencies required by the diff command.
+type DiffDeps struct {
+	Renderer         helm.ManifestRenderer
+	Resolver         ChartResolver
+	BundleLoader     func(string, string) (*bundle.Bundle, error)
+	TelemetryEmitter func(io.Writer) (*telemetry.Emitter, error)
+}
+
+var errChangesDetected = errors.New("release has pending changes")
+
+// ErrChangesDetected exposes the pending changes sentinel returned with exit code 2.
+func ErrChangesDetected() error { return errChangesDetected }
+
+// NewDiffCommand constructs the `chainctl app diff` command.
+func NewDiffCommand() *cobra.Command {
+	opts := DiffOptions{}
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Preview manifest changes an upgrade would apply to the release",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return runDiff(cmd, opts, defaultDiffDeps)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ClusterEndpoint, "cluster-endpoint", "", "Kubernetes API endpoint of the target cluster")
+	bindValuesFlags(cmd, &opts.ValuesFiles, &opts.SetValues, &opts.SetStringValues)
+	cmd.Flags().StringVar(&opts.ValuesPassphrase, "values-passphrase", "", "Passphrase for encrypted values")
+	cmd.Flags().StringVar(&opts.BundlePath, "bundle-path", "", "Path to local Helm bundle when operating offline")
+	cmd.Flags().StringVar(&opts.ChartReference, "chart", "", chartFlagUsage)
+	cmd.Flags().StringVar(&opts.ExpectDigest, "expect-digest", "", "Fail unless the pulled chart manifest has this digest (sha256:...)")
+	cmd.Flags().BoolVar(&opts.Offline, "offline", false, offlineFlagUsage)
+	bindVerifyFlags(cmd, &opts.VerifyKeyring, &opts.VerifyKeys)
+	cmd.Flags().StringVar(&opts.ReleaseName, "release-name", "", "Helm release name override")
+	cmd.Flags().StringVar(&opts.Namespace, "namespace", "", "Kubernetes namespace for the Helm release")
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+	markDeclarative(cmd)
+
+	return cmd
+}
+
+// RunDiffForTest executes the diff flow with injected dependencies.
+func RunDiffForTest(cmd *cobra.Command, opts DiffOptions, deps DiffDeps) error {
+	cmd.SilenceUsage = true
+	return runDiff(cmd, opts, deps)
+}
+
+func (o DiffOptions) shared() sharedOptions {
+	return sharedOptions{
+		ClusterEndpoint:  o.ClusterEndpoint,
+		ValuesFile:       o.ValuesFile,
+		ValuesFiles:      o.ValuesFiles,
+		SetValues:        o.SetValues,
+		SetStringValues:  o.SetStringValues,
+		ValuesPassphrase: o.ValuesPassphrase,
+		BundlePath:       o.BundlePath,
+		ChartReference:   o.ChartReference,
+		ExpectDigest:     o.ExpectDigest,
+		Offline:          o.Offline,
+		VerifyKeyring:    o.VerifyKeyring,
+		VerifyKeys:       o.VerifyKeys,
+		ReleaseName:      o.ReleaseName,
+		Namespace:        o.Namespace,
+		Output:           o.Output,
+	}
+}
+
+func runDiff(cmd *cobra.Command, opts DiffOptions, deps DiffDeps) error {
+	diffs, err := computeDiff(cmd, opts, deps)
+	if err != nil {
+		return err
+	}
+	if len(diffs) > 0 {
+		cmd.SilenceErrors = true
+		return exitcode.New(exitcode.ChangesDetected, errChangesDetected)
+	}
+	return nil
+}
+
+func computeDiff(cmd *cobra.Command, opts DiffOptions, deps DiffDeps) (diffs []helm.ResourceDiff, err error) {
+	resolverDeps := UpgradeDeps{
+		Resolver:         deps.Resolver,
+		BundleLoader:     deps.BundleLoader,
+		TelemetryEmitter: deps.TelemetryEmitter,
+	}
+	ensureDeps(&resolverDeps)
+	renderer := deps.Renderer
+	if renderer == nil {
+		renderer = helm.NewSDKExecutor()
+	}
+
+	options := opts.shared()
+	if err := validateAppActionInputs(options, actionDiff); err != nil {
+		return nil, err
+	}
+	if options.Output != "text" && options.Output != "json" {
+		return nil, errUnsupportedOutput
+	}
+
+	profile, err := buildProfileForAction(options, actionDiff)
+	if err != nil {
+		return nil, err
+	}
+
+	tel, logger, err := initAppTelemetry(cmd, resolverDeps.TelemetryEmitter)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowStep := workflowStepName(actionDiff)
+	workflowMetadata := buildWorkflowMetadata(profile)
+	logWorkflowStart(logger, workflowStep, workflowMetadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, workflowStep, workflowMetadata, err)
+		}
+	}()
+
+	resolved, err := resolveChartWithLogging(cmd.Context(), options, resolverDeps, logger, workflowMetadata)
+	if err != nil {
+		return nil, err
+	}
+	profile.ChartPath = resolved.Outcome.ChartPath
+	profile.ChartDigest = resolved.Outcome.Source.Digest
+
+	helmMetadata := buildHelmInstallMetadata(profile, resolved.Outcome)
+	helmMetadata["operation"] = string(actionDiff)
+	helmArgs := append([]string{"helm", "diff"}, buildHelmInstallArgs(profile, options)[1:]...)
+
+	err = tel.EmitPhase(telemetry.PhaseHelm, helmMetadata, func() error {
+		var diffErr error
+		diffs, diffErr = diffRelease(renderer, profile, resolved.Bundle)
+		if diffErr == nil {
+			helmMetadata["changes"] = strconv.Itoa(len(diffs))
+		}
+		stderr := ""
+		severity := telemetry.SeverityInfo
+		if diffErr != nil {
+			severity = telemetry.SeverityError
+			stderr = diffErr.Error()
+		}
+		logCommandEntry(logger, stepHelmCommand, helmArgs, stderr, severity, helmMetadata, diffErr)
+		return diffErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logWorkflowSuccess(logger, workflowStep, workflowMetadata)
+	if err := emitDiffOutput(cmd, profile, resolved.Outcome, diffs, options.Output); err != nil {
+		return nil, err
+	}
+	return diffs, nil
+}
+
+func diffRelease(renderer helm.ManifestRenderer, profile *config.Profile, bundleInstance *bundle.Bundle) ([]helm.ResourceDiff, error) {
+	desired, err := renderer.RenderManifest(profile, bundleInstance)
+	if err != nil {
+		return nil, err
+	}
+	deployed, err := renderer.ReleaseManifest(profile.HelmRelease, profile.HelmNamespace)
+	if err != nil {
+		return nil, err
+	}
+	return helm.DiffManifests(deployed, desired)
+}
+
+func summarizeDiff(diffs []helm.ResourceDiff) map[helm.ResourceChange]int {
+	summary := map[helm.ResourceChange]int{
+		helm.ChangeAdded:    0,
+		helm.ChangeModified: 0,
+		helm.ChangeRemoved:  0,
+	}
+	for _, diff := range diffs {
+		summary[diff.Change]++
+	}
+	return summary
+}
+
+func emitDiffOutput(cmd *cobra.Command, profile *config.Profile, result helm.ResolveResult, diffs []helm.ResourceDiff, format string) error {
+	summary := summarizeDiff(diffs)
+	switch format {
+	case "text":
+		out := cmd.OutOrStdout()
+		if len(diffs) == 0 {
+			fmt.Fprintf(out, "No changes detected for release %s in namespace %s\n", profile.HelmRelease, profile.HelmNamespace)
+			return nil
+		}
+		for _, diff := range diffs {
+			fmt.Fprintf(out, "%s (%s)\n", diff.Key(), diff.Change)
+			fmt.Fprint(out, diff.Diff)
+			if !strings.HasSuffix(diff.Diff, "\n") {
+				fmt.Fprintln(out)
+			}
+		}
+		fmt.Fprintf(out, "%d resource(s) changed for release %s in namespace %s: %d added, %d modified, %d removed\n",
+			len(diffs), profile.HelmRelease, profile.HelmNamespace,
+			summary[helm.ChangeAdded], summary[helm.ChangeModified], summary[helm.ChangeRemoved])
+		return nil
+	case "json":
+		resources := diffs
+		if resources == nil {
+			resources = []helm.ResourceDiff{}
+		}
+		payload := map[string]any{
+			"action":    string(actionDiff),
+			"release":   profile.HelmRelease,
+			"namespace": profile.HelmNamespace,
+			"chart":     result.Source.Reference,
+			"chartType": result.Source.Type,
+			"changed":   len(diffs) > 0,
+			"summary":   summary,
+			"resources": resources,
+		}
+		if result.Source.Digest != "" {
+			payload["digest"] = result.Source.Digest
+		}
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetEscapeHTML(false)
+		return enc.Encode(payload)
+	default:
+		return errUnsupportedOutput
+	}
+}
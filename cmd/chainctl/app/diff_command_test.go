@@ -0,0 +1,140 @@
+package app_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	appcmd "github.com/dobrovols/chainctl/cmd/chainctl/app"
+	"github.com/dobrovols/chainctl/internal/cli/exitcode"
+	"github.com/dobrovols/chainctl/internal/config"
+	"github.com/dobrovols/chainctl/pkg/bundle"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+)
+
+type fakeRenderer struct {
+	desired string
+	live    string
+	profile *config.Profile
+	err     error
+}
+
+func (f *fakeRenderer) RenderManifest(p *config.Profile, _ *bundle.Bundle) (string, error) {
+	f.profile = p
+	return f.desired, f.err
+}
+
+func (f *fakeRenderer) ReleaseManifest(string, string) (string, error) {
+	return f.live, nil
+}
+
+const diffConfigMap = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: demo\ndata:\n  replicas: \"%s\"\n"
+
+func diffOptions(output string) appcmd.DiffOptions {
+	return appcmd.DiffOptions{
+		ClusterEndpoint:  "https://cluster.local",
+		ValuesFile:       "/tmp/values.enc",
+		ValuesPassphrase: "secret",
+		ChartReference:   "oci://registry.example.com/apps/myapp:1.2.3",
+		ReleaseName:      "myapp-demo",
+		Namespace:        "demo",
+		Output:           output,
+	}
+}
+
+func diffResolver() *resolvingStub {
+	return &resolvingStub{result: helm.ResolveResult{
+		Source:    pkgstate.ChartSource{Type: "oci", Reference: "oci://registry.example.com/apps/myapp:1.2.3", Digest: "sha256:abc"},
+		ChartPath: "/tmp/myapp-1.2.3.tgz",
+	}}
+}
+
+func TestAppDiffCommand_ChangesExitWithCodeTwo(t *testing.T) {
+	renderer := &fakeRenderer{
+		live:    fmt.Sprintf(diffConfigMap, "1"),
+		desired: fmt.Sprintf(diffConfigMap, "3"),
+	}
+	deps := appcmd.DiffDeps{Renderer: renderer, Resolver: diffResolver(), TelemetryEmitter: telemetryNoop}
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+
+	err := appcmd.RunDiffForTest(cmd, diffOptions("text"), deps)
+	var exitErr *exitcode.Error
+	if !errors.As(err, &exitErr) || exitErr.Code != exitcode.ChangesDetected {
+		t.Fatalf("expected exit code %d, got %v", exitcode.ChangesDetected, err)
+	}
+	if !errors.Is(err, appcmd.ErrChangesDetected()) {
+		t.Fatalf("expected changes detected sentinel, got %v", err)
+	}
+	if renderer.profile.ChartPath != "/tmp/myapp-1.2.3.tgz" || renderer.profile.ChartDigest != "sha256:abc" {
+		t.Fatalf("expected resolved chart to be rendered, got %+v", renderer.profile)
+	}
+	for _, want := range []string{"ConfigMap//demo (modified)", `+  replicas: "3"`, "1 resource(s) changed"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestAppDiffCommand_NoChangesJSON(t *testing.T) {
+	manifest := fmt.Sprintf(diffConfigMap, "1")
+	deps := appcmd.DiffDeps{Renderer: &fakeRenderer{live: manifest, desired: manifest}, Resolver: diffResolver(), TelemetryEmitter: telemetryNoop}
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+
+	if err := appcmd.RunDiffForTest(cmd, diffOptions("json"), deps); err != nil {
+		t.Fatalf("expected no error without changes, got %v", err)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if payload["changed"] != false || payload["action"] != "diff" {
+		t.Fatalf("unexpected payload %v", payload)
+	}
+	if resources, ok := payload["resources"].([]any); !ok || len(resources) != 0 {
+		t.Fatalf("expected empty resources list, got %v", payload["resources"])
+	}
+}
+
+func TestAppDiffCommand_PropagatesRenderErrors(t *testing.T) {
+	wantErr := errors.New("render failed")
+	deps := appcmd.DiffDeps{Renderer: &fakeRenderer{err: wantErr}, Resolver: diffResolver(), TelemetryEmitter: telemetryNoop}
+
+	err := appcmd.RunDiffForTest(&cobra.Command{}, diffOptions("text"), deps)
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected render error, got %v", err)
+	}
+	var exitErr *exitcode.Error
+	if errors.As(err, &exitErr) {
+		t.Fatalf("expected failures to use the default exit code, got %d", exitErr.Code)
+	}
+}
+
+func TestAppDiffCommand_ValidatesInputs(t *testing.T) {
+	deps := appcmd.DiffDeps{Renderer: &fakeRenderer{}}
+	if err := appcmd.RunDiffForTest(&cobra.Command{}, appcmd.DiffOptions{}, deps); err != appcmd.ErrValuesFileRequired() {
+		t.Fatalf("expected values file error, got %v", err)
+	}
+	opts := diffOptions("text")
+	opts.ClusterEndpoint = ""
+	if err := appcmd.RunDiffForTest(&cobra.Command{}, opts, deps); err != appcmd.ErrClusterEndpointRequired() {
+		t.Fatalf("expected cluster endpoint error, got %v", err)
+	}
+	if err := appcmd.RunDiffForTest(&cobra.Command{}, diffOptions("yaml"), deps); !errors.Is(err, appcmd.ErrUnsupportedOutput()) {
+		t.Fatalf("expected unsupported output error, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	clilogging "github.com/dobrovols/chainctl/internal/cli/logging"
+	"github.com/dobrovols/chainctl/internal/config"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+// maxFailureErrorLength bounds the error text persisted with a failed workflow.
+const maxFailureErrorLength = 1024
+
+var errUnresolvedFailure = errors.New("the last run for this release failed and has not been resolved")
+
+// ErrUnresolvedFailure exposes the sentinel returned when state records an unresolved failure.
+func ErrUnresolvedFailure() error { return errUnresolvedFailure }
+
+// failureRecorder persists an install or upgrade that failed after it started changing the release.
+type failureRecorder struct {
+	tel       *telemetry.Emitter
+	manager   StateManager
+	profile   *config.Profile
+	outcome   helm.ResolveResult
+	action    appAction
+	options   sharedOptions
+	overrides pkgstate.Overrides
+	stateHint string
+}
+
+// record annotates the release's state with the failure and returns cause, joined with any
+// error raised while writing state. A failure that created no release revision, such as a
+// template or connection error before Helm applied anything, is recorded with revision zero.
+func (f failureRecorder) record(release helm.ReleaseInfo, failedPhase telemetry.Phase, cause error) error {
+	failure := pkgstate.Failure{
+		Action:     string(f.action),
+		Phase:      string(failedPhase),
+		Error:      sanitizeFailure(cause),
+		Chart:      f.outcome.Source,
+		Version:    deriveVersion(f.options, f.outcome),
+		Revision:   release.Revision,
+		Operator:   pkgstate.OperatorName(),
+		WorkflowID: f.tel.WorkflowID(),
+	}
+	previous, err := readPreviousRecord(f.manager, f.overrides, f.profile)
+	if err != nil {
+		return errors.Join(cause, err)
+	}
+	base := pkgstate.Record{
+		Release:         f.profile.HelmRelease,
+		Namespace:       f.profile.HelmNamespace,
+		ClusterEndpoint: f.profile.ClusterEndpoint,
+	}
+	if previous != nil {
+		base = *previous
+	}
+	if _, err := persistState(f.manager, base.WithFailure(failure), f.overrides, f.stateHint); err != nil {
+		return errors.Join(cause, err)
+	}
+	return cause
+}
+
+// guardUnresolvedFailure refuses to build on a release whose last install or upgrade failed.
+// With force the run proceeds and the failure is logged as a warning. Failures that left the
+// release untouched only warn.
+func guardUnresolvedFailure(manager StateManager, overrides pkgstate.Overrides, profile *config.Profile, force bool, logger telemetry.StructuredLogger, step string, metadata map[string]string) error {
+	previous, err := readPreviousRecord(manager, overrides, profile)
+	if err != nil || previous == nil || previous.Failure == nil {
+		return err
+	}
+	if !previous.Failure.ReleaseChanged() {
+		logWorkflowEntry(logger, step, fmt.Sprintf("last run failed before changing the release: %s", previous.Failure), telemetry.SeverityWarn, metadata, nil)
+		return nil
+	}
+	if !force {
+		return fmt.Errorf("%w: %s; inspect the release, then roll back or rerun with --force", errUnresolvedFailure, previous.Failure)
+	}
+	metadata["forced"] = "true"
+	logWorkflowEntry(logger, step, fmt.Sprintf("proceeding past unresolved failure: %s", previous.Failure), telemetry.SeverityWarn, metadata, nil)
+	return nil
+}
+
+// readPreviousRecord returns the state recorded for the profile's release, if any. Managers
+// that cannot read state report none.
+func readPreviousRecord(manager StateManager, overrides pkgstate.Overrides, profile *config.Profile) (*pkgstate.Record, error) {
+	reader, ok := manager.(StateReader)
+	if !ok {
+		return nil, nil
+	}
+	previous, err := reader.Read(overrides)
+	switch {
+	case errors.Is(err, pkgstate.ErrNotFound()):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("state file could not be read: %w", err)
+	case previous.Release != profile.HelmRelease || previous.Namespace != profile.HelmNamespace:
+		return nil, nil
+	}
+	return &previous, nil
+}
+
+// sanitizeFailure redacts secrets from the error and bounds its length before it is persisted.
+func sanitizeFailure(err error) string {
+	if err == nil {
+		return ""
+	}
+	text := strings.TrimSpace(clilogging.SanitizeText(err.Error()))
+	if len(text) > maxFailureErrorLength {
+		text = text[:maxFailureErrorLength] + "..."
+	}
+	return text
+}
@@ -0,0 +1,156 @@
+package app_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	appcmd "github.com/dobrovols/chainctl/cmd/chainctl/app"
+	internalstate "github.com/dobrovols/chainctl/internal/state"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+)
+
+func TestAppUpgradeCommand_RecordsFailureAndRequiresForce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	manager := pkgstate.NewManager(internalstate.NewResolver())
+	if _, err := manager.Write(recordedState(), pkgstate.Overrides{StateFilePath: path}); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+	opts := atomicOptions()
+	opts.Atomic = false
+	opts.StateFilePath = path
+
+	applyErr := errors.New("helm upgrade failed: values-passphrase=hunter2 rejected")
+	installer := &revisionInstaller{fakeHelmInstaller: fakeHelmInstaller{err: applyErr}, release: helm.ReleaseInfo{Revision: 3, Status: "failed"}}
+	deps := appcmd.UpgradeDeps{Installer: installer, TelemetryEmitter: telemetryNoop, Resolver: atomicResolver(), StateManager: manager}
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	if err := appcmd.RunUpgradeForTest(cmd, opts, deps); !errors.Is(err, applyErr) {
+		t.Fatalf("expected helm failure, got %v", err)
+	}
+
+	record, err := manager.Read(pkgstate.Overrides{StateFilePath: path})
+	if err != nil {
+		t.Fatalf("read state: %v", err)
+	}
+	failure := record.Failure
+	if failure == nil || failure.Phase != "helm" || failure.Action != "upgrade" || failure.Revision != 3 || failure.Chart.Digest != "sha256:three" || failure.WorkflowID == "" {
+		t.Fatalf("expected failure recorded in state, got %+v", failure)
+	}
+	if strings.Contains(failure.Error, "hunter2") {
+		t.Fatalf("expected sanitized error, got %q", failure.Error)
+	}
+	if record.Revision != 2 || record.Chart.Digest != "sha256:two" {
+		t.Fatalf("expected last good release to be kept, got %+v", record)
+	}
+	if last := record.History[len(record.History)-1]; !last.Failed() || last.Revision != 3 {
+		t.Fatalf("expected failed attempt in history, got %+v", last)
+	}
+
+	installer.called, installer.err = false, nil
+	installer.release = helm.ReleaseInfo{Revision: 4, Status: "deployed"}
+	err = appcmd.RunUpgradeForTest(cmd, opts, deps)
+	if !errors.Is(err, appcmd.ErrUnresolvedFailure()) || installer.called {
+		t.Fatalf("expected unresolved failure to block the upgrade, got %v", err)
+	}
+	if !strings.Contains(err.Error(), failure.WorkflowID) {
+		t.Fatalf("expected error to name the failed workflow, got %v", err)
+	}
+
+	opts.Force = true
+	if err := appcmd.RunUpgradeForTest(cmd, opts, deps); err != nil {
+		t.Fatalf("forced upgrade: %v", err)
+	}
+	record, err = manager.Read(pkgstate.Overrides{StateFilePath: path})
+	if err != nil || record.Failure != nil || record.Revision != 4 {
+		t.Fatalf("expected successful upgrade to clear the failure, got %+v (%v)", record, err)
+	}
+	if len(record.History) != 4 || !record.History[2].Failed() || record.History[3].Revision != 4 {
+		t.Fatalf("expected the state manager to continue the stored history, got %+v", record.History)
+	}
+}
+
+func TestAppInstallCommand_RecordsFailedFirstInstall(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	manager := pkgstate.NewManager(internalstate.NewResolver())
+	opts := atomicOptions()
+	opts.Atomic = false
+	opts.StateFilePath = path
+
+	applyErr := errors.New("install failed")
+	deps := appcmd.UpgradeDeps{
+		Installer:        &revisionInstaller{fakeHelmInstaller: fakeHelmInstaller{err: applyErr}, release: helm.ReleaseInfo{Revision: 1, Status: "failed"}},
+		TelemetryEmitter: telemetryNoop,
+		Resolver:         atomicResolver(),
+		StateManager:     manager,
+	}
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	if err := appcmd.RunInstallForTest(cmd, opts, deps); !errors.Is(err, applyErr) {
+		t.Fatalf("expected install failure, got %v", err)
+	}
+
+	record, err := manager.Read(pkgstate.Overrides{StateFilePath: path})
+	if err != nil {
+		t.Fatalf("read state: %v", err)
+	}
+	if record.Status != pkgstate.StatusFailed || record.Failure == nil || record.Failure.Action != "install" || len(record.History) != 1 || !record.History[0].Failed() {
+		t.Fatalf("expected failed first install in state, got %+v", record)
+	}
+}
+
+func TestAppUpgradeCommand_RecordsFailureWithoutNewRevisionAndWarns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	manager := pkgstate.NewManager(internalstate.NewResolver())
+	if _, err := manager.Write(recordedState(), pkgstate.Overrides{StateFilePath: path}); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+	opts := atomicOptions()
+	opts.Atomic = false
+	opts.StateFilePath = path
+
+	applyErr := errors.New("kubernetes cluster unreachable")
+	installer := &revisionInstaller{fakeHelmInstaller: fakeHelmInstaller{err: applyErr}}
+	deps := appcmd.UpgradeDeps{Installer: installer, TelemetryEmitter: telemetryNoop, Resolver: atomicResolver(), StateManager: manager}
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	if err := appcmd.RunUpgradeForTest(cmd, opts, deps); !errors.Is(err, applyErr) {
+		t.Fatalf("expected helm failure, got %v", err)
+	}
+	record, err := manager.Read(pkgstate.Overrides{StateFilePath: path})
+	if err != nil {
+		t.Fatalf("read state: %v", err)
+	}
+	if record.Failure == nil || record.Failure.Revision != 0 || record.Failure.ReleaseChanged() {
+		t.Fatalf("expected failure recorded without a revision, got %+v", record.Failure)
+	}
+	if record.Revision != 2 || len(record.History) != len(recordedState().History)+1 || !record.History[len(record.History)-1].Failed() {
+		t.Fatalf("expected last good release kept with the failed attempt in history, got %+v", record)
+	}
+
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+	installer.called, installer.err = false, nil
+	installer.release = helm.ReleaseInfo{Revision: 3, Status: "deployed"}
+	if err := appcmd.RunUpgradeForTest(cmd, opts, deps); err != nil || !installer.called {
+		t.Fatalf("expected the next upgrade to run without --force, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), "failed before changing the release") {
+		t.Fatalf("expected a warning about the earlier failure, got %s", stderr.String())
+	}
+	record, err = manager.Read(pkgstate.Overrides{StateFilePath: path})
+	if err != nil || record.Failure != nil || record.Revision != 3 {
+		t.Fatalf("expected successful upgrade to clear the failure, got %+v (%v)", record, err)
+	}
+}
@@ -1,18 +1,61 @@
 package app
 
-import "github.com/spf13/cobra"
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+)
+
+const (
+	chartFlagUsage   = "Helm chart: OCI reference (oci://registry/repo:tag), repository chart (https://repo/chart[:version]), or local chart directory"
+	offlineFlagUsage = "Resolve --chart from the local chart cache only, without contacting the registry"
+)
 
 func bindCommonFlags(cmd *cobra.Command, upgradeOpts *UpgradeOptions) {
 	cmd.Flags().StringVar(&upgradeOpts.ClusterEndpoint, "cluster-endpoint", "", "Kubernetes API endpoint of the target cluster")
 	cmd.Flags().BoolVar(&upgradeOpts.Airgapped, "airgapped", false, "Use offline assets from bundle")
-	cmd.Flags().StringVar(&upgradeOpts.ValuesFile, "values-file", "", "Encrypted Helm values file path")
+	bindValuesFlags(cmd, &upgradeOpts.ValuesFiles, &upgradeOpts.SetValues, &upgradeOpts.SetStringValues)
 	cmd.Flags().StringVar(&upgradeOpts.ValuesPassphrase, "values-passphrase", "", "Passphrase for encrypted values")
 	cmd.Flags().StringVar(&upgradeOpts.BundlePath, "bundle-path", "", "Path to local Helm bundle when operating offline")
-	cmd.Flags().StringVar(&upgradeOpts.ChartReference, "chart", "", "OCI Helm chart reference (oci://registry/repo:tag)")
+	cmd.Flags().StringVar(&upgradeOpts.ChartReference, "chart", "", chartFlagUsage)
+	cmd.Flags().StringVar(&upgradeOpts.ExpectDigest, "expect-digest", "", "Fail unless the pulled chart manifest has this digest (sha256:...)")
+	cmd.Flags().BoolVar(&upgradeOpts.Offline, "offline", false, offlineFlagUsage)
+	bindVerifyFlags(cmd, &upgradeOpts.VerifyKeyring, &upgradeOpts.VerifyKeys)
 	cmd.Flags().StringVar(&upgradeOpts.ReleaseName, "release-name", "", "Helm release name override")
 	cmd.Flags().StringVar(&upgradeOpts.AppVersion, "app-version", "", "Application version recorded in state")
 	cmd.Flags().StringVar(&upgradeOpts.Namespace, "namespace", "", "Kubernetes namespace for the Helm release")
-	cmd.Flags().StringVar(&upgradeOpts.StateFilePath, "state-file", "", "Absolute path for persisted state JSON")
-	cmd.Flags().StringVar(&upgradeOpts.StateFileName, "state-file-name", "", "Custom state file name within the config directory")
+	bindStateFlags(cmd, &upgradeOpts.StateFilePath, &upgradeOpts.StateFileName, &upgradeOpts.StateBackend)
+	bindHistoryLimitFlag(cmd, &upgradeOpts.HistoryLimit)
 	cmd.Flags().StringVar(&upgradeOpts.Output, "output", "text", "Output format: text or json")
+	cmd.Flags().DurationVar(&upgradeOpts.WaitTimeout, "wait-timeout", defaultWaitTimeout, "Maximum time to wait for release workloads to become ready (0 skips the check)")
+	bindLockFlag(cmd, &upgradeOpts.LockTimeout)
+	cmd.Flags().BoolVar(&upgradeOpts.Atomic, "atomic", false, "Roll back to the last good revision when the Helm apply or readiness verification fails")
+	cmd.Flags().BoolVar(&upgradeOpts.Force, "force", false, "Proceed even though state records an unresolved failed install or upgrade of the release")
+}
+
+func bindValuesFlags(cmd *cobra.Command, files, set, setString *[]string) {
+	cmd.Flags().StringArrayVar(files, "values-file", nil, "Helm values file, encrypted or plaintext; repeatable, later files take precedence")
+	cmd.Flags().StringArrayVar(set, "set", nil, "Set a Helm value (key=value); repeatable, applied after values files")
+	cmd.Flags().StringArrayVar(setString, "set-string", nil, "Set a Helm value as a string (key=value); repeatable, applied after --set")
+}
+
+func bindStateFlags(cmd *cobra.Command, path, name, backend *string) {
+	cmd.Flags().StringVar(path, "state-file", "", "Absolute path for persisted state JSON")
+	cmd.Flags().StringVar(name, "state-file-name", "", "Custom state file name within the config directory")
+	cmd.Flags().StringVar(backend, "state-backend", pkgstate.BackendFile, "State backend: file (local JSON) or secret (Secret in the release namespace)")
+}
+
+func bindHistoryLimitFlag(cmd *cobra.Command, limit *int) {
+	cmd.Flags().IntVar(limit, "state-history-limit", pkgstate.DefaultHistoryLimit, "History entries retained per release in state, oldest dropped first (0 keeps the full history)")
+}
+
+func bindLockFlag(cmd *cobra.Command, timeout *time.Duration) {
+	cmd.Flags().DurationVar(timeout, "lock-timeout", 0, "How long to wait for another chainctl run holding the release lock (0 fails immediately)")
+}
+
+func bindVerifyFlags(cmd *cobra.Command, keyring *string, keys *[]string) {
+	cmd.Flags().StringVar(keyring, "verify-keyring", "", "PGP public keyring used to verify chart provenance (.prov) files")
+	cmd.Flags().StringSliceVar(keys, "verify-key", nil, "PEM public key (ed25519 or ECDSA) used to verify detached chart signatures (.sig) of bundle and repository charts; repeatable")
 }
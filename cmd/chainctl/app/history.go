@@ -0,0 +1,203 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dobrovols/chainctl/internal/config"
+	internalstate "github.com/dobrovols/chainctl/internal/state"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+const actionHistory appAction = "history"
+
+// HistoryOptions holds CLI flags for app history.
+type HistoryOptions struct {
+	ReleaseName   string
+	Namespace     string
+	Max           int
+	StateFileName string
+	StateFilePath string
+	StateBackend  string
+	Output        string
+}
+
+// HistoryDeps defines dependencies required by the history command.
+type HistoryDeps struct {
+	StateManager     StateReader
+	TelemetryEmitter func(io.Writer) (*telemetry.Emitter, error)
+}
+
+var errHistoryMax = errors.New("--max must not be negative")
+
+// ErrHistoryMax exposes the invalid --max sentinel.
+func ErrHistoryMax() error { return errHistoryMax }
+
+type historyReport struct {
+	Release   string                  `json:"release"`
+	Namespace string                  `json:"namespace"`
+	StateFile string                  `json:"stateFile"`
+	Entries   []pkgstate.HistoryEntry `json:"entries"`
+}
+
+// NewHistoryCommand constructs the `chainctl app history` command.
+func NewHistoryCommand() *cobra.Command {
+	opts := HistoryOptions{}
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List the deployment history recorded in state for the application",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return runHistory(cmd, opts, HistoryDeps{})
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ReleaseName, "release-name", "", "Helm release name (defaults to the release recorded in state)")
+	cmd.Flags().StringVar(&opts.Namespace, "namespace", "", "Kubernetes namespace of the Helm release (defaults to the recorded namespace)")
+	cmd.Flags().IntVar(&opts.Max, "max", 0, "Show only the most recent entries (0 lists all retained entries)")
+	bindStateFlags(cmd, &opts.StateFilePath, &opts.StateFileName, &opts.StateBackend)
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+	markDeclarative(cmd)
+
+	return cmd
+}
+
+// RunHistoryForTest executes the history flow with injected dependencies.
+func RunHistoryForTest(cmd *cobra.Command, opts HistoryOptions, deps HistoryDeps) error {
+	cmd.SilenceUsage = true
+	return runHistory(cmd, opts, deps)
+}
+
+func runHistory(cmd *cobra.Command, opts HistoryOptions, deps HistoryDeps) (err error) {
+	if deps.StateManager == nil && opts.StateBackend == pkgstate.BackendSecret {
+		if deps.StateManager, err = internalstate.NewStore(opts.StateBackend); err != nil {
+			return err
+		}
+	}
+	ensureHistoryDeps(&deps)
+	if opts.Output != "text" && opts.Output != "json" {
+		return errUnsupportedOutput
+	}
+	if opts.Max < 0 {
+		return errHistoryMax
+	}
+
+	stateOverrides, statePathHint, err := resolveStateOverrides(sharedOptions{
+		ReleaseName:   opts.ReleaseName,
+		Namespace:     opts.Namespace,
+		StateFileName: opts.StateFileName,
+		StateFilePath: opts.StateFilePath,
+		StateBackend:  opts.StateBackend,
+	})
+	if err != nil {
+		return err
+	}
+	record, err := deps.StateManager.Read(stateOverrides)
+	if err != nil {
+		return fmt.Errorf("history requires recorded state: %w", err)
+	}
+
+	profile := &config.Profile{
+		Mode:            config.ModeReuse,
+		ClusterEndpoint: record.ClusterEndpoint,
+		HelmRelease:     firstNonEmpty(opts.ReleaseName, record.Release),
+		HelmNamespace:   firstNonEmpty(opts.Namespace, record.Namespace),
+	}
+	if profile.HelmRelease != record.Release || profile.HelmNamespace != record.Namespace {
+		return fmt.Errorf("%w: %s/%s recorded, %s/%s requested", errStateReleaseMismatch, record.Namespace, record.Release, profile.HelmNamespace, profile.HelmRelease)
+	}
+
+	_, logger, err := initAppTelemetry(cmd, deps.TelemetryEmitter)
+	if err != nil {
+		return err
+	}
+	workflowStep := workflowStepName(actionHistory)
+	workflowMetadata := buildWorkflowMetadata(profile)
+	logWorkflowStart(logger, workflowStep, workflowMetadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, workflowStep, workflowMetadata, err)
+		}
+	}()
+
+	entries := recordedHistory(record).TrimHistory(opts.Max).History
+	workflowMetadata["entries"] = strconv.Itoa(len(entries))
+
+	logWorkflowSuccess(logger, workflowStep, workflowMetadata)
+	return emitHistoryOutput(cmd, historyReport{
+		Release:   record.Release,
+		Namespace: record.Namespace,
+		StateFile: statePathHint,
+		Entries:   entries,
+	}, opts.Output)
+}
+
+// recordedHistory returns the record with its history populated. Records written before history
+// was tracked contribute their own entry.
+func recordedHistory(record pkgstate.Record) pkgstate.Record {
+	if len(record.History) == 0 && record.LastAction != "" {
+		record.History = []pkgstate.HistoryEntry{record.Entry()}
+	}
+	if record.History == nil {
+		record.History = []pkgstate.HistoryEntry{}
+	}
+	return record
+}
+
+func emitHistoryOutput(cmd *cobra.Command, report historyReport, format string) error {
+	switch format {
+	case "text":
+		tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "REVISION\tUPDATED\tACTION\tOUTCOME\tCHART\tVERSION\tDIGEST\tOPERATOR\tWORKFLOW")
+		for _, entry := range report.Entries {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				historyRevision(entry.Revision),
+				historyCell(entry.Timestamp),
+				historyCell(entry.Action),
+				historyOutcome(entry),
+				historyCell(entry.Chart.Reference),
+				historyCell(entry.Version),
+				historyCell(entry.Chart.Digest),
+				historyCell(entry.Operator),
+				historyCell(entry.WorkflowID),
+			)
+		}
+		return tw.Flush()
+	case "json":
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetEscapeHTML(false)
+		return enc.Encode(report)
+	default:
+		return errUnsupportedOutput
+	}
+}
+
+func historyRevision(revision int) string {
+	if revision <= 0 {
+		return "-"
+	}
+	return strconv.Itoa(revision)
+}
+
+// historyOutcome reports entries recorded before outcomes were tracked as succeeded, since only
+// applied revisions were written to history then.
+func historyOutcome(entry pkgstate.HistoryEntry) string {
+	if entry.Outcome == "" {
+		return pkgstate.OutcomeSucceeded
+	}
+	return entry.Outcome
+}
+
+func historyCell(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,94 @@
+package app_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	appcmd "github.com/dobrovols/chainctl/cmd/chainctl/app"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+)
+
+func auditedState() pkgstate.Record {
+	record := recordedState()
+	record.History[1].Outcome = pkgstate.OutcomeSucceeded
+	record.History[1].Operator = "alice"
+	record.History[1].WorkflowID = "wf-2"
+	record.History = append(record.History, pkgstate.HistoryEntry{
+		Revision: 3, Action: "upgrade", Chart: record.Chart, Version: "1.4.0",
+		Timestamp: "2025-10-07T12:00:00Z", Outcome: pkgstate.OutcomeFailed, Operator: "bob", WorkflowID: "wf-3",
+	})
+	return record
+}
+
+func TestAppHistoryCommand_ListsEntriesAsTable(t *testing.T) {
+	deps := appcmd.HistoryDeps{StateManager: &readableStateStub{current: auditedState()}, TelemetryEmitter: telemetryNoop}
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+
+	if err := appcmd.RunHistoryForTest(cmd, appcmd.HistoryOptions{StateFilePath: "/var/lib/chainctl/state.json", Output: "text"}, deps); err != nil {
+		t.Fatalf("history failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "REVISION") {
+		t.Fatalf("expected header and three entries, got %q", out.String())
+	}
+	for _, want := range []string{"sha256:two", "alice", "wf-2", "succeeded"} {
+		if !strings.Contains(lines[2], want) {
+			t.Fatalf("expected %q in %q", want, lines[2])
+		}
+	}
+	if !strings.Contains(lines[3], "failed") || !strings.Contains(lines[3], "bob") {
+		t.Fatalf("expected failed attempt by bob, got %q", lines[3])
+	}
+}
+
+func TestAppHistoryCommand_JSONHonoursMax(t *testing.T) {
+	deps := appcmd.HistoryDeps{StateManager: &readableStateStub{current: auditedState()}, TelemetryEmitter: telemetryNoop}
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+
+	opts := appcmd.HistoryOptions{StateFilePath: "/var/lib/chainctl/state.json", Max: 2, Output: "json"}
+	if err := appcmd.RunHistoryForTest(cmd, opts, deps); err != nil {
+		t.Fatalf("history failed: %v", err)
+	}
+	var payload struct {
+		Release   string                  `json:"release"`
+		StateFile string                  `json:"stateFile"`
+		Entries   []pkgstate.HistoryEntry `json:"entries"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if payload.Release != "myapp-demo" || payload.StateFile != "/var/lib/chainctl/state.json" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+	if len(payload.Entries) != 2 || payload.Entries[0].Revision != 2 || !payload.Entries[1].Failed() {
+		t.Fatalf("expected the two newest entries, got %+v", payload.Entries)
+	}
+}
+
+func TestAppHistoryCommand_RequiresMatchingState(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	missing := appcmd.HistoryDeps{StateManager: &readableStateStub{readErr: pkgstate.ErrNotFound()}, TelemetryEmitter: telemetryNoop}
+	if err := appcmd.RunHistoryForTest(cmd, appcmd.HistoryOptions{Output: "text"}, missing); !errors.Is(err, pkgstate.ErrNotFound()) {
+		t.Fatalf("expected missing state error, got %v", err)
+	}
+
+	deps := appcmd.HistoryDeps{StateManager: &readableStateStub{current: auditedState()}, TelemetryEmitter: telemetryNoop}
+	if err := appcmd.RunHistoryForTest(cmd, appcmd.HistoryOptions{ReleaseName: "other", Output: "text"}, deps); !errors.Is(err, appcmd.ErrStateReleaseMismatch()) {
+		t.Fatalf("expected release mismatch, got %v", err)
+	}
+}
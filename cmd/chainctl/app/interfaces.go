@@ -3,6 +3,7 @@ package app
 import (
 	"github.com/dobrovols/chainctl/internal/config"
 	"github.com/dobrovols/chainctl/pkg/bundle"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
 )
 
 // HelmInstaller orchestrates Helm release operations.
@@ -10,6 +11,11 @@ type HelmInstaller interface {
 	Install(*config.Profile, *bundle.Bundle) error
 }
 
+// WorkflowLocker serialises mutating workflows that target the same release.
+type WorkflowLocker interface {
+	Acquire(key string, opts pkgstate.LockOptions) (*pkgstate.FileLock, error)
+}
+
 type noopInstaller struct{}
 
 func (noopInstaller) Install(*config.Profile, *bundle.Bundle) error { return nil }
@@ -8,10 +8,16 @@ import (
 )
 
 const (
-	stepAppInstall  = "app-install"
-	stepAppUpgrade  = "app-upgrade"
-	stepHelmResolve = "helm-resolve"
-	stepHelmCommand = "helm"
+	stepAppInstall   = "app-install"
+	stepAppUpgrade   = "app-upgrade"
+	stepAppRollback  = "app-rollback"
+	stepAppDiff      = "app-diff"
+	stepAppUninstall = "app-uninstall"
+	stepAppStatus    = "app-status"
+	stepAppHistory   = "app-history"
+	stepAppVerify    = "app-verify"
+	stepHelmResolve  = "helm-resolve"
+	stepHelmCommand  = "helm"
 )
 
 func logWorkflowStart(logger telemetry.StructuredLogger, step string, metadata map[string]string) {
@@ -0,0 +1,275 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dobrovols/chainctl/internal/config"
+	internalstate "github.com/dobrovols/chainctl/internal/state"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+const actionRollback appAction = "rollback"
+
+// RollbackOptions holds CLI flags for app rollback.
+type RollbackOptions struct {
+	ClusterEndpoint string
+	ReleaseName     string
+	Namespace       string
+	ToRevision      int
+	ToPrevious      bool
+	StateFileName   string
+	StateFilePath   string
+	StateBackend    string
+	HistoryLimit    int
+	LockTimeout     time.Duration
+	Output          string
+}
+
+// RollbackStateStore reads the recorded release history and persists the rollback outcome.
+type RollbackStateStore interface {
+	StateManager
+	StateReader
+}
+
+// RollbackDeps defines dependencies required by the rollback command.
+type RollbackDeps struct {
+	Rollbacker       helm.Rollbacker
+	TelemetryEmitter func(io.Writer) (*telemetry.Emitter, error)
+	StateManager     RollbackStateStore
+	Locker           WorkflowLocker
+}
+
+var (
+	errRollbackTarget       = errors.New("exactly one of --to-revision or --to-previous must be provided")
+	errRevisionNotRecorded  = errors.New("revision not recorded in state history")
+	errStateReleaseMismatch = errors.New("state file records a different release")
+)
+
+// ErrRollbackTarget exposes the rollback target selection sentinel.
+func ErrRollbackTarget() error { return errRollbackTarget }
+
+// ErrRevisionNotRecorded exposes the missing history entry sentinel.
+func ErrRevisionNotRecorded() error { return errRevisionNotRecorded }
+
+// ErrStateReleaseMismatch exposes the release mismatch sentinel.
+func ErrStateReleaseMismatch() error { return errStateReleaseMismatch }
+
+// NewRollbackCommand constructs the `chainctl app rollback` command.
+func NewRollbackCommand() *cobra.Command {
+	opts := RollbackOptions{}
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Roll the application Helm release back to a recorded revision",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return runRollback(cmd, opts, defaultRollbackDeps)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ClusterEndpoint, "cluster-endpoint", "", "Kubernetes API endpoint of the target cluster")
+	cmd.Flags().StringVar(&opts.ReleaseName, "release-name", "", "Helm release name (defaults to the release recorded in state)")
+	cmd.Flags().StringVar(&opts.Namespace, "namespace", "", "Kubernetes namespace of the Helm release (defaults to the recorded namespace)")
+	cmd.Flags().IntVar(&opts.ToRevision, "to-revision", 0, "Release revision to restore")
+	cmd.Flags().BoolVar(&opts.ToPrevious, "to-previous", false, "Restore the revision preceding the current one")
+	bindStateFlags(cmd, &opts.StateFilePath, &opts.StateFileName, &opts.StateBackend)
+	bindHistoryLimitFlag(cmd, &opts.HistoryLimit)
+	bindLockFlag(cmd, &opts.LockTimeout)
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+	markDeclarative(cmd)
+
+	return cmd
+}
+
+// RunRollbackForTest executes the rollback flow with injected dependencies.
+func RunRollbackForTest(cmd *cobra.Command, opts RollbackOptions, deps RollbackDeps) error {
+	cmd.SilenceUsage = true
+	return runRollback(cmd, opts, deps)
+}
+
+func runRollback(cmd *cobra.Command, opts RollbackOptions, deps RollbackDeps) (err error) {
+	if deps.StateManager == nil {
+		if deps.StateManager, err = internalstate.NewStore(opts.StateBackend, internalstate.WithHistoryLimit(opts.HistoryLimit)); err != nil {
+			return err
+		}
+	}
+	ensureRollbackDeps(&deps)
+
+	if err := validateRollbackInputs(opts); err != nil {
+		return err
+	}
+
+	stateOverrides, statePathHint, err := resolveStateOverrides(sharedOptions{
+		ClusterEndpoint: opts.ClusterEndpoint,
+		ReleaseName:     opts.ReleaseName,
+		Namespace:       opts.Namespace,
+		StateFileName:   opts.StateFileName,
+		StateFilePath:   opts.StateFilePath,
+		StateBackend:    opts.StateBackend,
+	})
+	if err != nil {
+		return err
+	}
+
+	locked, err := lockIdentity(deps.StateManager, stateOverrides, opts.ReleaseName, opts.Namespace, opts.ClusterEndpoint)
+	if err != nil {
+		return err
+	}
+
+	tel, logger, err := initAppTelemetry(cmd, deps.TelemetryEmitter)
+	if err != nil {
+		return err
+	}
+
+	workflowStep := workflowStepName(actionRollback)
+	workflowMetadata := buildWorkflowMetadata(locked)
+	logWorkflowStart(logger, workflowStep, workflowMetadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, workflowStep, workflowMetadata, err)
+		}
+	}()
+
+	lock, err := acquireWorkflowLock(deps.Locker, locked, tel.WorkflowID(), opts.LockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	// Read state only under the lock so a workflow that finished meanwhile is kept in history.
+	previous, err := deps.StateManager.Read(stateOverrides)
+	if err != nil {
+		return fmt.Errorf("rollback requires recorded state: %w", err)
+	}
+
+	profile, err := buildRollbackProfile(opts, previous)
+	if err != nil {
+		return err
+	}
+	if err := lockedRelease(locked, profile); err != nil {
+		return err
+	}
+
+	target, err := selectRollbackTarget(opts, previous)
+	if err != nil {
+		return err
+	}
+	workflowMetadata["targetRevision"] = strconv.Itoa(target.Revision)
+
+	release, err := executeRollbackPhase(tel, deps.Rollbacker, profile, target, logger)
+	if err != nil {
+		return err
+	}
+
+	record := stampAudit(pkgstate.Record{
+		Release:         profile.HelmRelease,
+		Namespace:       profile.HelmNamespace,
+		Chart:           target.Chart,
+		Version:         target.Version,
+		LastAction:      string(actionRollback),
+		ClusterEndpoint: profile.ClusterEndpoint,
+		Revision:        release.Revision,
+		Status:          release.Status,
+		Notes:           release.Notes,
+		ValuesHash:      release.ValuesHash,
+	}, tel.WorkflowID())
+
+	statePath, err := persistState(deps.StateManager, record, stateOverrides, statePathHint)
+	if err != nil {
+		return err
+	}
+
+	logWorkflowSuccess(logger, workflowStep, workflowMetadata)
+	outcome := helm.ResolveResult{Source: target.Chart}
+	return emitOutput(cmd, profile, outcome, release, statePath, opts.Output, actionRollback, sharedOptions{AppVersion: target.Version})
+}
+
+func validateRollbackInputs(opts RollbackOptions) error {
+	if opts.ToRevision < 0 || (opts.ToRevision > 0) == opts.ToPrevious {
+		return errRollbackTarget
+	}
+	switch opts.Output {
+	case "text", "json":
+		return nil
+	default:
+		return errUnsupportedOutput
+	}
+}
+
+func buildRollbackProfile(opts RollbackOptions, previous pkgstate.Record) (*config.Profile, error) {
+	release := firstNonEmpty(opts.ReleaseName, previous.Release)
+	namespace := firstNonEmpty(opts.Namespace, previous.Namespace)
+	if release != previous.Release || namespace != previous.Namespace {
+		return nil, fmt.Errorf("%w: %s/%s recorded, %s/%s requested", errStateReleaseMismatch, previous.Namespace, previous.Release, namespace, release)
+	}
+	return &config.Profile{
+		Mode:            config.ModeReuse,
+		ClusterEndpoint: firstNonEmpty(opts.ClusterEndpoint, previous.ClusterEndpoint),
+		HelmRelease:     release,
+		HelmNamespace:   namespace,
+	}, nil
+}
+
+func selectRollbackTarget(opts RollbackOptions, previous pkgstate.Record) (pkgstate.HistoryEntry, error) {
+	if opts.ToPrevious {
+		entry, ok := previous.PreviousRevision()
+		if !ok {
+			return pkgstate.HistoryEntry{}, fmt.Errorf("%w: no revision precedes %d", errRevisionNotRecorded, previous.Revision)
+		}
+		return entry, nil
+	}
+	entry, ok := previous.FindRevision(opts.ToRevision)
+	if !ok {
+		return pkgstate.HistoryEntry{}, fmt.Errorf("%w: %d", errRevisionNotRecorded, opts.ToRevision)
+	}
+	return entry, nil
+}
+
+func executeRollbackPhase(
+	tel *telemetry.Emitter,
+	rollbacker helm.Rollbacker,
+	profile *config.Profile,
+	target pkgstate.HistoryEntry,
+	logger telemetry.StructuredLogger,
+) (helm.ReleaseInfo, error) {
+	helmMetadata := buildHelmInstallMetadata(profile, helm.ResolveResult{Source: target.Chart})
+	helmMetadata["targetRevision"] = strconv.Itoa(target.Revision)
+	helmArgs := []string{"helm", "rollback", profile.HelmRelease, strconv.Itoa(target.Revision), "--namespace", profile.HelmNamespace}
+
+	var release helm.ReleaseInfo
+	err := tel.EmitPhase(telemetry.PhaseHelm, helmMetadata, func() error {
+		var rollbackErr error
+		release, rollbackErr = rollbacker.Rollback(helm.RollbackOptions{
+			Release:   profile.HelmRelease,
+			Namespace: profile.HelmNamespace,
+			Revision:  target.Revision,
+		})
+		helm.AddReleaseMetadata(helmMetadata, release)
+		stderr := ""
+		severity := telemetry.SeverityInfo
+		if rollbackErr != nil {
+			severity = telemetry.SeverityError
+			stderr = rollbackErr.Error()
+		}
+		logCommandEntry(logger, stepHelmCommand, helmArgs, stderr, severity, helmMetadata, rollbackErr)
+		return rollbackErr
+	})
+	return release, err
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
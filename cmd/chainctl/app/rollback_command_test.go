@@ -0,0 +1,210 @@
+package app_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	appcmd "github.com/dobrovols/chainctl/cmd/chainctl/app"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+)
+
+type fakeRollbacker struct {
+	opts   helm.RollbackOptions
+	result helm.ReleaseInfo
+	err    error
+	called bool
+}
+
+func (f *fakeRollbacker) Rollback(opts helm.RollbackOptions) (helm.ReleaseInfo, error) {
+	f.called = true
+	f.opts = opts
+	return f.result, f.err
+}
+
+type readableStateStub struct {
+	stateStub
+	current pkgstate.Record
+	readErr error
+}
+
+func (s *readableStateStub) Read(pkgstate.Overrides) (pkgstate.Record, error) {
+	return s.current, s.readErr
+}
+
+// Write continues the stored history the way the state managers do.
+func (s *readableStateStub) Write(rec pkgstate.Record, o pkgstate.Overrides) (string, error) {
+	if len(rec.History) == 0 {
+		rec = rec.WithHistory(&s.current)
+	}
+	return s.stateStub.Write(rec, o)
+}
+
+func recordedState() pkgstate.Record {
+	first := pkgstate.ChartSource{Type: "oci", Reference: "oci://registry.example.com/apps/myapp:1.2.3", Digest: "sha256:one"}
+	second := pkgstate.ChartSource{Type: "oci", Reference: "oci://registry.example.com/apps/myapp:1.3.0", Digest: "sha256:two"}
+	return pkgstate.Record{
+		Release:         "myapp-demo",
+		Namespace:       "demo",
+		Chart:           second,
+		Version:         "1.3.0",
+		LastAction:      "upgrade",
+		ClusterEndpoint: "https://cluster.local",
+		Revision:        2,
+		History: []pkgstate.HistoryEntry{
+			{Revision: 1, Action: "install", Chart: first, Version: "1.2.3", Timestamp: "2025-10-05T12:00:00Z"},
+			{Revision: 2, Action: "upgrade", Chart: second, Version: "1.3.0", Timestamp: "2025-10-06T12:00:00Z"},
+		},
+	}
+}
+
+func TestNewRollbackCommandFlags(t *testing.T) {
+	cmd := appcmd.NewRollbackCommand()
+	for _, name := range []string{"cluster-endpoint", "release-name", "namespace", "to-revision", "to-previous", "state-file", "state-file-name", "output"} {
+		if cmd.Flag(name) == nil {
+			t.Fatalf("expected flag %s to exist", name)
+		}
+	}
+}
+
+func TestAppRollbackCommand_ToPreviousJSON(t *testing.T) {
+	t.Setenv("USER", "release-bot")
+	stateMgr := &readableStateStub{stateStub: stateStub{path: "/var/lib/chainctl/state.json"}, current: recordedState()}
+	rollbacker := &fakeRollbacker{result: helm.ReleaseInfo{Revision: 3, Status: "deployed"}}
+	deps := appcmd.RollbackDeps{Rollbacker: rollbacker, TelemetryEmitter: telemetryNoop, StateManager: stateMgr}
+	opts := appcmd.RollbackOptions{ToPrevious: true, StateFilePath: "/var/lib/chainctl/state.json", Output: "json"}
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+
+	if err := appcmd.RunRollbackForTest(cmd, opts, deps); err != nil {
+		t.Fatalf("rollback failed: %v", err)
+	}
+
+	if rollbacker.opts != (helm.RollbackOptions{Release: "myapp-demo", Namespace: "demo", Revision: 1}) {
+		t.Fatalf("unexpected rollback options %+v", rollbacker.opts)
+	}
+	rec := stateMgr.record
+	if rec.LastAction != "rollback" || rec.Revision != 3 || rec.Chart.Digest != "sha256:one" || rec.Version != "1.2.3" {
+		t.Fatalf("unexpected state record %+v", rec)
+	}
+	if len(rec.History) != 3 || rec.History[2].Action != "rollback" {
+		t.Fatalf("expected rollback appended to history, got %+v", rec.History)
+	}
+	if entry := rec.History[2]; entry.Operator != "release-bot" || entry.WorkflowID == "" || entry.Outcome != pkgstate.OutcomeSucceeded {
+		t.Fatalf("expected audited rollback entry, got %+v", entry)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if payload["action"] != "rollback" || payload["revision"] != float64(3) || payload["chart"] != "oci://registry.example.com/apps/myapp:1.2.3" {
+		t.Fatalf("unexpected json payload %v", payload)
+	}
+}
+
+func TestAppRollbackCommand_ValidatesTarget(t *testing.T) {
+	deps := appcmd.RollbackDeps{Rollbacker: &fakeRollbacker{}, StateManager: &readableStateStub{current: recordedState()}}
+
+	for _, opts := range []appcmd.RollbackOptions{
+		{Output: "text"},
+		{ToRevision: 1, ToPrevious: true, Output: "text"},
+		{ToRevision: -1, Output: "text"},
+	} {
+		if err := appcmd.RunRollbackForTest(&cobra.Command{}, opts, deps); !errors.Is(err, appcmd.ErrRollbackTarget()) {
+			t.Fatalf("expected rollback target error for %+v, got %v", opts, err)
+		}
+	}
+}
+
+func TestAppRollbackCommand_RejectsUnrecordedRevision(t *testing.T) {
+	rollbacker := &fakeRollbacker{}
+	deps := appcmd.RollbackDeps{Rollbacker: rollbacker, TelemetryEmitter: telemetryNoop, StateManager: &readableStateStub{current: recordedState()}}
+
+	err := appcmd.RunRollbackForTest(&cobra.Command{}, appcmd.RollbackOptions{ToRevision: 7, Output: "text"}, deps)
+	if !errors.Is(err, appcmd.ErrRevisionNotRecorded()) {
+		t.Fatalf("expected revision not recorded error, got %v", err)
+	}
+	if rollbacker.called {
+		t.Fatal("expected rollback not to run")
+	}
+}
+
+func TestAppRollbackCommand_RejectsReleaseMismatch(t *testing.T) {
+	deps := appcmd.RollbackDeps{Rollbacker: &fakeRollbacker{}, TelemetryEmitter: telemetryNoop, StateManager: &readableStateStub{current: recordedState()}}
+
+	err := appcmd.RunRollbackForTest(&cobra.Command{}, appcmd.RollbackOptions{ReleaseName: "other", ToPrevious: true, Output: "text"}, deps)
+	if !errors.Is(err, appcmd.ErrStateReleaseMismatch()) {
+		t.Fatalf("expected release mismatch error, got %v", err)
+	}
+}
+
+func TestAppRollbackCommand_PropagatesHelmFailure(t *testing.T) {
+	stateMgr := &readableStateStub{current: recordedState()}
+	wantErr := errors.New("rollback failed")
+	deps := appcmd.RollbackDeps{Rollbacker: &fakeRollbacker{err: wantErr}, TelemetryEmitter: telemetryNoop, StateManager: stateMgr}
+
+	err := appcmd.RunRollbackForTest(&cobra.Command{}, appcmd.RollbackOptions{ToRevision: 1, Output: "text"}, deps)
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected helm error, got %v", err)
+	}
+	if stateMgr.called {
+		t.Fatal("expected state to remain untouched on failure")
+	}
+}
+
+// racingLocker stands in for another workflow that finishes while this one waits for the lock.
+type racingLocker struct {
+	locker   *pkgstate.Locker
+	finished func()
+}
+
+func (l *racingLocker) Acquire(key string, opts pkgstate.LockOptions) (*pkgstate.FileLock, error) {
+	l.finished()
+	return l.locker.Acquire(key, opts)
+}
+
+func TestAppRollbackCommand_ReadsStateUnderWorkflowLock(t *testing.T) {
+	stateMgr := &readableStateStub{current: recordedState()}
+	locker := &racingLocker{locker: pkgstate.NewLocker(t.TempDir()), finished: func() {
+		upgraded := stateMgr.current
+		upgraded.Chart = pkgstate.ChartSource{Type: "oci", Reference: "oci://registry.example.com/apps/myapp:1.4.0", Digest: "sha256:three"}
+		upgraded.Version, upgraded.Revision, upgraded.History = "1.4.0", 3, nil
+		stateMgr.current = upgraded.WithHistory(&stateMgr.current)
+	}}
+	rollbacker := &fakeRollbacker{result: helm.ReleaseInfo{Revision: 4, Status: "deployed"}}
+	deps := appcmd.RollbackDeps{Rollbacker: rollbacker, TelemetryEmitter: telemetryNoop, StateManager: stateMgr, Locker: locker}
+
+	if err := appcmd.RunRollbackForTest(&cobra.Command{}, appcmd.RollbackOptions{ToPrevious: true, Output: "text"}, deps); err != nil {
+		t.Fatalf("rollback failed: %v", err)
+	}
+	if rollbacker.opts.Revision != 2 {
+		t.Fatalf("expected rollback to the revision preceding the concurrent upgrade, got %d", rollbacker.opts.Revision)
+	}
+	history := stateMgr.record.History
+	if len(history) != 4 || history[2].Revision != 3 || history[3].Action != "rollback" {
+		t.Fatalf("expected the concurrent upgrade kept in history, got %+v", history)
+	}
+}
+
+func TestAppRollbackCommand_RejectsStateReplacedWhileLocking(t *testing.T) {
+	stateMgr := &readableStateStub{current: recordedState()}
+	locker := &racingLocker{locker: pkgstate.NewLocker(t.TempDir()), finished: func() {
+		stateMgr.current.Release = "other"
+	}}
+	rollbacker := &fakeRollbacker{}
+	deps := appcmd.RollbackDeps{Rollbacker: rollbacker, TelemetryEmitter: telemetryNoop, StateManager: stateMgr, Locker: locker}
+
+	err := appcmd.RunRollbackForTest(&cobra.Command{}, appcmd.RollbackOptions{ToPrevious: true, Output: "text"}, deps)
+	if !errors.Is(err, pkgstate.ErrConflict()) || rollbacker.called {
+		t.Fatalf("expected conflict without rollback, got %v", err)
+	}
+}
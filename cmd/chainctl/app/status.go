@@ -0,0 +1,289 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dobrovols/chainctl/internal/config"
+	internalstate "github.com/dobrovols/chainctl/internal/state"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	"github.com/dobrovols/chainctl/pkg/readiness"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+const actionStatus appAction = "status"
+
+// StatusOptions holds CLI flags for app status.
+type StatusOptions struct {
+	ClusterEndpoint string
+	ReleaseName     string
+	Namespace       string
+	StateFileName   string
+	StateFilePath   string
+	StateBackend    string
+	Output          string
+}
+
+// ReadinessChecker evaluates the readiness of workloads owned by a release.
+type ReadinessChecker interface {
+	Check(context.Context, []readiness.Resource) ([]readiness.Status, error)
+}
+
+// StatusDeps defines dependencies required by the status command.
+type StatusDeps struct {
+	Releases         helm.StatusReader
+	Readiness        ReadinessChecker
+	StateManager     StateReader
+	TelemetryEmitter func(io.Writer) (*telemetry.Emitter, error)
+}
+
+type helmStatus struct {
+	Revision     int    `json:"revision"`
+	Status       string `json:"status"`
+	Chart        string `json:"chart"`
+	ChartVersion string `json:"chartVersion"`
+	Digest       string `json:"digest,omitempty"`
+}
+
+type driftReport struct {
+	Detected       bool   `json:"detected"`
+	StateDigest    string `json:"stateDigest,omitempty"`
+	DeployedDigest string `json:"deployedDigest,omitempty"`
+	Reason         string `json:"reason"`
+}
+
+type statusReport struct {
+	Release   string             `json:"release"`
+	Namespace string             `json:"namespace"`
+	State     *pkgstate.Record   `json:"state,omitempty"`
+	Helm      *helmStatus        `json:"helm,omitempty"`
+	Workloads []readiness.Status `json:"workloads"`
+	Ready     bool               `json:"ready"`
+	Drift     driftReport        `json:"drift"`
+}
+
+// NewStatusCommand constructs the `chainctl app status` command.
+func NewStatusCommand() *cobra.Command {
+	opts := StatusOptions{}
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Report release, state, and workload health for the application",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return runStatus(cmd, opts, defaultStatusDeps)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ClusterEndpoint, "cluster-endpoint", "", "Kubernetes API endpoint of the target cluster")
+	cmd.Flags().StringVar(&opts.ReleaseName, "release-name", "", "Helm release name (defaults to the release recorded in state)")
+	cmd.Flags().StringVar(&opts.Namespace, "namespace", "", "Kubernetes namespace of the Helm release (defaults to the recorded namespace)")
+	bindStateFlags(cmd, &opts.StateFilePath, &opts.StateFileName, &opts.StateBackend)
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+	markDeclarative(cmd)
+
+	return cmd
+}
+
+// RunStatusForTest executes the status flow with injected dependencies.
+func RunStatusForTest(cmd *cobra.Command, opts StatusOptions, deps StatusDeps) error {
+	cmd.SilenceUsage = true
+	return runStatus(cmd, opts, deps)
+}
+
+func runStatus(cmd *cobra.Command, opts StatusOptions, deps StatusDeps) (err error) {
+	if deps.StateManager == nil && opts.StateBackend == pkgstate.BackendSecret {
+		if deps.StateManager, err = internalstate.NewStore(opts.StateBackend); err != nil {
+			return err
+		}
+	}
+	ensureStatusDeps(&deps)
+	if opts.Output != "text" && opts.Output != "json" {
+		return errUnsupportedOutput
+	}
+
+	stateOverrides, _, err := resolveStateOverrides(sharedOptions{
+		ClusterEndpoint: opts.ClusterEndpoint,
+		ReleaseName:     opts.ReleaseName,
+		Namespace:       opts.Namespace,
+		StateFileName:   opts.StateFileName,
+		StateFilePath:   opts.StateFilePath,
+		StateBackend:    opts.StateBackend,
+	})
+	if err != nil {
+		return err
+	}
+	record, hasState, err := readOptionalState(deps.StateManager, stateOverrides)
+	if err != nil {
+		return err
+	}
+
+	profile := &config.Profile{
+		Mode:            config.ModeReuse,
+		ClusterEndpoint: firstNonEmpty(opts.ClusterEndpoint, record.ClusterEndpoint),
+		HelmRelease:     firstNonEmpty(opts.ReleaseName, record.Release),
+		HelmNamespace:   firstNonEmpty(opts.Namespace, record.Namespace),
+	}
+	if profile.HelmRelease == "" || profile.HelmNamespace == "" {
+		return errReleaseRequired
+	}
+	if hasState && (record.Release != profile.HelmRelease || record.Namespace != profile.HelmNamespace) {
+		hasState = false
+	}
+
+	_, logger, err := initAppTelemetry(cmd, deps.TelemetryEmitter)
+	if err != nil {
+		return err
+	}
+	workflowStep := workflowStepName(actionStatus)
+	workflowMetadata := buildWorkflowMetadata(profile)
+	logWorkflowStart(logger, workflowStep, workflowMetadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, workflowStep, workflowMetadata, err)
+		}
+	}()
+
+	var state *pkgstate.Record
+	if hasState {
+		state = &record
+	}
+	report, err := buildStatusReport(cmd.Context(), deps, profile, state)
+	if err != nil {
+		return err
+	}
+	workflowMetadata["ready"] = strconv.FormatBool(report.Ready)
+	workflowMetadata["drift"] = strconv.FormatBool(report.Drift.Detected)
+
+	logWorkflowSuccess(logger, workflowStep, workflowMetadata)
+	return emitStatusOutput(cmd, report, opts.Output)
+}
+
+func readOptionalState(reader StateReader, overrides pkgstate.Overrides) (pkgstate.Record, bool, error) {
+	record, err := reader.Read(overrides)
+	switch {
+	case err == nil:
+		return record, true, nil
+	case errors.Is(err, pkgstate.ErrNotFound()):
+		return pkgstate.Record{}, false, nil
+	default:
+		return pkgstate.Record{}, false, fmt.Errorf("state file could not be read: %w", err)
+	}
+}
+
+func buildStatusReport(ctx context.Context, deps StatusDeps, profile *config.Profile, state *pkgstate.Record) (statusReport, error) {
+	report := statusReport{
+		Release:   profile.HelmRelease,
+		Namespace: profile.HelmNamespace,
+		State:     state,
+		Workloads: []readiness.Status{},
+	}
+
+	release, err := deps.Releases.ReleaseStatus(profile.HelmRelease, profile.HelmNamespace)
+	switch {
+	case errors.Is(err, helm.ErrReleaseNotFound()):
+		report.Drift = detectDrift(state, nil)
+		return report, nil
+	case err != nil:
+		return statusReport{}, err
+	}
+	report.Helm = &helmStatus{
+		Revision:     release.Revision,
+		Status:       release.Status,
+		Chart:        release.ChartName,
+		ChartVersion: release.ChartVersion,
+		Digest:       release.ChartDigest,
+	}
+	report.Drift = detectDrift(state, &release)
+
+	resources, err := readiness.WorkloadsFromManifest(release.Manifest, profile.HelmNamespace)
+	if err != nil {
+		return statusReport{}, err
+	}
+	if len(resources) > 0 {
+		checker := deps.Readiness
+		if checker == nil {
+			if checker, err = defaultReadinessChecker(); err != nil {
+				return statusReport{}, err
+			}
+		}
+		statuses, err := checker.Check(ctx, resources)
+		if err != nil {
+			return statusReport{}, err
+		}
+		report.Workloads = statuses
+	}
+	report.Ready = release.Status == "deployed" && readiness.AllReady(report.Workloads)
+	return report, nil
+}
+
+// detectDrift compares the chart digest recorded in state with the digest annotated on the deployed chart.
+func detectDrift(state *pkgstate.Record, release *helm.ReleaseInfo) driftReport {
+	switch {
+	case state == nil:
+		return driftReport{Reason: "no state record for release"}
+	case release == nil:
+		return driftReport{StateDigest: state.Chart.Digest, Reason: "release not deployed"}
+	}
+	report := driftReport{StateDigest: state.Chart.Digest, DeployedDigest: release.ChartDigest}
+	switch {
+	case report.StateDigest == "" || report.DeployedDigest == "":
+		report.Reason = "chart digest unavailable"
+	case report.StateDigest != report.DeployedDigest:
+		report.Detected = true
+		report.Reason = "deployed chart digest differs from state"
+	default:
+		report.Reason = "in sync"
+	}
+	return report
+}
+
+func emitStatusOutput(cmd *cobra.Command, report statusReport, format string) error {
+	switch format {
+	case "text":
+		tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "Release:\t%s\n", report.Release)
+		fmt.Fprintf(tw, "Namespace:\t%s\n", report.Namespace)
+		if report.Helm != nil {
+			fmt.Fprintf(tw, "Helm:\t%s (revision %d)\n", report.Helm.Status, report.Helm.Revision)
+			fmt.Fprintf(tw, "Chart:\t%s %s\n", report.Helm.Chart, report.Helm.ChartVersion)
+		} else {
+			fmt.Fprintf(tw, "Helm:\tnot deployed\n")
+		}
+		if report.State != nil {
+			fmt.Fprintf(tw, "State:\t%s at %s (revision %d)\n", report.State.LastAction, report.State.Timestamp, report.State.Revision)
+			if report.State.Failure != nil {
+				fmt.Fprintf(tw, "Failure:\t%s\n", report.State.Failure)
+			}
+		} else {
+			fmt.Fprintf(tw, "State:\tnot recorded\n")
+		}
+		if report.Drift.Detected {
+			fmt.Fprintf(tw, "Drift:\tDETECTED (state %s, deployed %s)\n", report.Drift.StateDigest, report.Drift.DeployedDigest)
+		} else {
+			fmt.Fprintf(tw, "Drift:\tnone (%s)\n", report.Drift.Reason)
+		}
+		fmt.Fprintf(tw, "Ready:\t%t\n", report.Ready)
+		if len(report.Workloads) > 0 {
+			fmt.Fprintln(tw)
+			fmt.Fprintln(tw, "Kind\tNamespace\tName\tReady\tDetails")
+			for _, w := range report.Workloads {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%t\t%s\n", w.Kind, w.Namespace, w.Name, w.Ready, w.Message)
+			}
+		}
+		return tw.Flush()
+	case "json":
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetEscapeHTML(false)
+		return enc.Encode(report)
+	default:
+		return errUnsupportedOutput
+	}
+}
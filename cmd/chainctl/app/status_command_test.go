@@ -0,0 +1,131 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	appcmd "github.com/dobrovols/chainctl/cmd/chainctl/app"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	"github.com/dobrovols/chainctl/pkg/readiness"
+)
+
+type fakeStatusReader struct {
+	info helm.ReleaseInfo
+	err  error
+}
+
+func (f *fakeStatusReader) ReleaseStatus(string, string) (helm.ReleaseInfo, error) {
+	return f.info, f.err
+}
+
+type fakeReadiness struct {
+	checked []readiness.Resource
+	ready   bool
+}
+
+func (f *fakeReadiness) Check(_ context.Context, resources []readiness.Resource) ([]readiness.Status, error) {
+	f.checked = resources
+	statuses := make([]readiness.Status, 0, len(resources))
+	for _, res := range resources {
+		statuses = append(statuses, readiness.Status{Resource: res, Ready: f.ready, Message: "1/1 available"})
+	}
+	return statuses, nil
+}
+
+const statusManifest = "---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n"
+
+func deployedRelease(digest string) helm.ReleaseInfo {
+	return helm.ReleaseInfo{
+		Name:         "myapp-demo",
+		Namespace:    "demo",
+		Revision:     2,
+		Status:       "deployed",
+		ChartName:    "myapp",
+		ChartVersion: "1.3.0",
+		ChartDigest:  digest,
+		Manifest:     statusManifest,
+	}
+}
+
+func runStatusJSON(t *testing.T, deps appcmd.StatusDeps) map[string]any {
+	t.Helper()
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	if err := appcmd.RunStatusForTest(cmd, appcmd.StatusOptions{Output: "json"}, deps); err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	return payload
+}
+
+func TestAppStatusCommand_ReportsHealthyRelease(t *testing.T) {
+	checker := &fakeReadiness{ready: true}
+	deps := appcmd.StatusDeps{
+		Releases:         &fakeStatusReader{info: deployedRelease("sha256:two")},
+		Readiness:        checker,
+		StateManager:     &readableStateStub{current: recordedState()},
+		TelemetryEmitter: telemetryNoop,
+	}
+
+	payload := runStatusJSON(t, deps)
+	if payload["ready"] != true {
+		t.Fatalf("expected ready release, got %v", payload)
+	}
+	drift := payload["drift"].(map[string]any)
+	if drift["detected"] != false || drift["reason"] != "in sync" {
+		t.Fatalf("expected no drift, got %v", drift)
+	}
+	if len(checker.checked) != 1 || checker.checked[0] != (readiness.Resource{Kind: "Deployment", Namespace: "demo", Name: "web"}) {
+		t.Fatalf("expected deployment workload to be checked, got %+v", checker.checked)
+	}
+}
+
+func TestAppStatusCommand_FlagsDigestDrift(t *testing.T) {
+	deps := appcmd.StatusDeps{
+		Releases:         &fakeStatusReader{info: deployedRelease("sha256:other")},
+		Readiness:        &fakeReadiness{ready: true},
+		StateManager:     &readableStateStub{current: recordedState()},
+		TelemetryEmitter: telemetryNoop,
+	}
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	if err := appcmd.RunStatusForTest(cmd, appcmd.StatusOptions{Output: "text"}, deps); err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	for _, want := range []string{"DETECTED (state sha256:two, deployed sha256:other)", "deployed (revision 2)", "Deployment  demo"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestAppStatusCommand_ReleaseNotDeployed(t *testing.T) {
+	deps := appcmd.StatusDeps{
+		Releases:         &fakeStatusReader{err: fmt.Errorf("release status: %w", helm.ErrReleaseNotFound())},
+		StateManager:     &readableStateStub{current: recordedState()},
+		TelemetryEmitter: telemetryNoop,
+	}
+
+	payload := runStatusJSON(t, deps)
+	if payload["ready"] != false || payload["helm"] != nil {
+		t.Fatalf("expected undeployed release, got %v", payload)
+	}
+	if payload["drift"].(map[string]any)["reason"] != "release not deployed" {
+		t.Fatalf("unexpected drift %v", payload["drift"])
+	}
+}
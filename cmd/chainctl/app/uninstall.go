@@ -0,0 +1,328 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dobrovols/chainctl/internal/config"
+	internalstate "github.com/dobrovols/chainctl/internal/state"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+const (
+	actionUninstall appAction = "uninstall"
+
+	statusUninstalled  = "uninstalled"
+	defaultWaitTimeout = 5 * time.Minute
+)
+
+// State outcomes reported after an uninstall.
+const (
+	stateRemoved    = "removed"
+	stateTombstoned = "tombstoned"
+	stateUnchanged  = "unchanged"
+)
+
+// UninstallOptions holds CLI flags for app uninstall.
+type UninstallOptions struct {
+	ClusterEndpoint string
+	ReleaseName     string
+	Namespace       string
+	KeepHistory     bool
+	Wait            bool
+	WaitTimeout     time.Duration
+	DeletePVCs      bool
+	StateFileName   string
+	StateFilePath   string
+	StateBackend    string
+	HistoryLimit    int
+	LockTimeout     time.Duration
+	Output          string
+}
+
+// UninstallStateStore reads, tombstones, and removes the recorded application state.
+type UninstallStateStore interface {
+	StateManager
+	StateReader
+	Remove(pkgstate.Overrides) (string, error)
+}
+
+// UninstallDeps defines dependencies required by the uninstall command.
+type UninstallDeps struct {
+	Uninstaller      helm.Uninstaller
+	TelemetryEmitter func(io.Writer) (*telemetry.Emitter, error)
+	StateManager     UninstallStateStore
+	Locker           WorkflowLocker
+}
+
+var errReleaseRequired = errors.New("release name and namespace must be provided or recorded in state")
+
+// ErrReleaseRequired exposes the missing release sentinel.
+func ErrReleaseRequired() error { return errReleaseRequired }
+
+// NewUninstallCommand constructs the `chainctl app uninstall` command.
+func NewUninstallCommand() *cobra.Command {
+	opts := UninstallOptions{}
+	cmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Uninstall the micro-services application Helm release",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return runUninstall(cmd, opts, defaultUninstallDeps)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ClusterEndpoint, "cluster-endpoint", "", "Kubernetes API endpoint of the target cluster")
+	cmd.Flags().StringVar(&opts.ReleaseName, "release-name", "", "Helm release name (defaults to the release recorded in state)")
+	cmd.Flags().StringVar(&opts.Namespace, "namespace", "", "Kubernetes namespace of the Helm release (defaults to the recorded namespace)")
+	cmd.Flags().BoolVar(&opts.KeepHistory, "keep-history", false, "Keep Helm release history and tombstone the state record instead of removing it")
+	cmd.Flags().BoolVar(&opts.Wait, "wait", true, "Wait until release resources are deleted")
+	cmd.Flags().DurationVar(&opts.WaitTimeout, "wait-timeout", defaultWaitTimeout, "Maximum time to wait for resource deletion")
+	cmd.Flags().BoolVar(&opts.DeletePVCs, "delete-pvcs", false, "Delete PersistentVolumeClaims labelled with the release instance")
+	bindStateFlags(cmd, &opts.StateFilePath, &opts.StateFileName, &opts.StateBackend)
+	bindHistoryLimitFlag(cmd, &opts.HistoryLimit)
+	bindLockFlag(cmd, &opts.LockTimeout)
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+	markDeclarative(cmd)
+
+	return cmd
+}
+
+// RunUninstallForTest executes the uninstall flow with injected dependencies.
+func RunUninstallForTest(cmd *cobra.Command, opts UninstallOptions, deps UninstallDeps) error {
+	cmd.SilenceUsage = true
+	return runUninstall(cmd, opts, deps)
+}
+
+func runUninstall(cmd *cobra.Command, opts UninstallOptions, deps UninstallDeps) (err error) {
+	if deps.StateManager == nil {
+		if deps.StateManager, err = internalstate.NewStore(opts.StateBackend, internalstate.WithHistoryLimit(opts.HistoryLimit)); err != nil {
+			return err
+		}
+	}
+	ensureUninstallDeps(&deps)
+
+	if opts.Output != "text" && opts.Output != "json" {
+		return errUnsupportedOutput
+	}
+
+	stateOverrides, _, err := resolveStateOverrides(sharedOptions{
+		ClusterEndpoint: opts.ClusterEndpoint,
+		ReleaseName:     opts.ReleaseName,
+		Namespace:       opts.Namespace,
+		StateFileName:   opts.StateFileName,
+		StateFilePath:   opts.StateFilePath,
+		StateBackend:    opts.StateBackend,
+	})
+	if err != nil {
+		return err
+	}
+
+	locked, err := lockIdentity(deps.StateManager, stateOverrides, opts.ReleaseName, opts.Namespace, opts.ClusterEndpoint)
+	if err != nil {
+		return err
+	}
+	if locked.HelmRelease == "" || locked.HelmNamespace == "" {
+		return errReleaseRequired
+	}
+
+	tel, logger, err := initAppTelemetry(cmd, deps.TelemetryEmitter)
+	if err != nil {
+		return err
+	}
+
+	workflowStep := workflowStepName(actionUninstall)
+	workflowMetadata := buildWorkflowMetadata(locked)
+	logWorkflowStart(logger, workflowStep, workflowMetadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, workflowStep, workflowMetadata, err)
+		}
+	}()
+
+	lock, err := acquireWorkflowLock(deps.Locker, locked, tel.WorkflowID(), opts.LockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	// Read state only under the lock so the record removed or tombstoned is the latest one.
+	previous, hasState, err := readUninstallState(deps.StateManager, stateOverrides)
+	if err != nil {
+		return err
+	}
+
+	profile := &config.Profile{
+		Mode:            config.ModeReuse,
+		ClusterEndpoint: firstNonEmpty(opts.ClusterEndpoint, previous.ClusterEndpoint),
+		HelmRelease:     firstNonEmpty(opts.ReleaseName, previous.Release),
+		HelmNamespace:   firstNonEmpty(opts.Namespace, previous.Namespace),
+	}
+	if err := lockedRelease(locked, profile); err != nil {
+		return err
+	}
+	ownsState := hasState && previous.Release == profile.HelmRelease && previous.Namespace == profile.HelmNamespace
+
+	result, err := executeUninstallPhase(tel, deps.Uninstaller, profile, opts, logger)
+	if err != nil {
+		return err
+	}
+
+	stateOutcome, statePath, err := cleanupUninstallState(deps.StateManager, previous, ownsState, opts.KeepHistory, stateOverrides, tel.WorkflowID())
+	if err != nil {
+		return err
+	}
+	workflowMetadata["state"] = stateOutcome
+
+	logWorkflowSuccess(logger, workflowStep, workflowMetadata)
+	return emitUninstallOutput(cmd, profile, result, opts, stateOutcome, statePath)
+}
+
+func readUninstallState(store UninstallStateStore, overrides pkgstate.Overrides) (pkgstate.Record, bool, error) {
+	record, err := store.Read(overrides)
+	switch {
+	case err == nil:
+		return record, true, nil
+	case errors.Is(err, pkgstate.ErrNotFound()):
+		return pkgstate.Record{}, false, nil
+	default:
+		return pkgstate.Record{}, false, fmt.Errorf("state file could not be read: %w", err)
+	}
+}
+
+func executeUninstallPhase(
+	tel *telemetry.Emitter,
+	uninstaller helm.Uninstaller,
+	profile *config.Profile,
+	opts UninstallOptions,
+	logger telemetry.StructuredLogger,
+) (helm.UninstallResult, error) {
+	helmMetadata := map[string]string{
+		"namespace":   profile.HelmNamespace,
+		"release":     profile.HelmRelease,
+		"operation":   string(actionUninstall),
+		"keepHistory": strconv.FormatBool(opts.KeepHistory),
+		"deletePVCs":  strconv.FormatBool(opts.DeletePVCs),
+	}
+	helmArgs := buildHelmUninstallArgs(profile, opts)
+
+	var result helm.UninstallResult
+	err := tel.EmitPhase(telemetry.PhaseHelm, helmMetadata, func() error {
+		var uninstallErr error
+		result, uninstallErr = uninstaller.Uninstall(helm.UninstallOptions{
+			Release:     profile.HelmRelease,
+			Namespace:   profile.HelmNamespace,
+			KeepHistory: opts.KeepHistory,
+			Wait:        opts.Wait,
+			Timeout:     opts.WaitTimeout,
+			DeletePVCs:  opts.DeletePVCs,
+		})
+		if len(result.DeletedPVCs) > 0 {
+			helmMetadata["deletedPVCs"] = strings.Join(result.DeletedPVCs, ",")
+		}
+		stderr := ""
+		severity := telemetry.SeverityInfo
+		if uninstallErr != nil {
+			severity = telemetry.SeverityError
+			stderr = uninstallErr.Error()
+		}
+		logCommandEntry(logger, stepHelmCommand, helmArgs, stderr, severity, helmMetadata, uninstallErr)
+		return uninstallErr
+	})
+	return result, err
+}
+
+func buildHelmUninstallArgs(profile *config.Profile, opts UninstallOptions) []string {
+	args := []string{"helm", "uninstall", profile.HelmRelease, "--namespace", profile.HelmNamespace}
+	if opts.KeepHistory {
+		args = append(args, "--keep-history")
+	}
+	if opts.Wait {
+		args = append(args, "--wait", "--timeout", opts.WaitTimeout.String())
+	}
+	return args
+}
+
+// cleanupUninstallState removes the matching state record, or tombstones it when history is kept.
+// Records describing a different release are left untouched.
+func cleanupUninstallState(store UninstallStateStore, previous pkgstate.Record, ownsState, keepHistory bool, overrides pkgstate.Overrides, workflowID string) (string, string, error) {
+	if !ownsState {
+		return stateUnchanged, "", nil
+	}
+	overrides.Release, overrides.Namespace, overrides.ClusterEndpoint = previous.Release, previous.Namespace, previous.ClusterEndpoint
+	if !keepHistory {
+		path, err := store.Remove(overrides)
+		if err != nil && !errors.Is(err, pkgstate.ErrNotFound()) {
+			return "", "", fmt.Errorf("state file could not be removed: %w", err)
+		}
+		return stateRemoved, path, nil
+	}
+
+	tombstone := stampAudit(previous, workflowID)
+	tombstone.LastAction = string(actionUninstall)
+	tombstone.Status = statusUninstalled
+	tombstone.Notes = ""
+	tombstone.ValuesHash = ""
+	tombstone.Timestamp = ""
+	tombstone.History = nil
+	path, err := persistState(store, tombstone, overrides, overrides.StateFilePath)
+	if err != nil {
+		return "", "", err
+	}
+	return stateTombstoned, path, nil
+}
+
+func emitUninstallOutput(cmd *cobra.Command, profile *config.Profile, result helm.UninstallResult, opts UninstallOptions, stateOutcome, statePath string) error {
+	switch opts.Output {
+	case "text":
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Uninstall completed successfully for release %s in namespace %s\n", profile.HelmRelease, profile.HelmNamespace)
+		switch stateOutcome {
+		case stateRemoved:
+			fmt.Fprintf(out, "State record removed from %s\n", statePath)
+		case stateTombstoned:
+			fmt.Fprintf(out, "State record tombstoned at %s\n", statePath)
+		}
+		if len(result.DeletedPVCs) > 0 {
+			fmt.Fprintf(out, "Deleted PersistentVolumeClaims: %s\n", strings.Join(result.DeletedPVCs, ", "))
+		} else if !opts.DeletePVCs {
+			fmt.Fprintln(out, "PersistentVolumeClaims were retained; pass --delete-pvcs to remove them")
+		}
+		return nil
+	case "json":
+		deleted := result.DeletedPVCs
+		if deleted == nil {
+			deleted = []string{}
+		}
+		payload := map[string]any{
+			"status":      "success",
+			"action":      string(actionUninstall),
+			"release":     profile.HelmRelease,
+			"namespace":   profile.HelmNamespace,
+			"keepHistory": opts.KeepHistory,
+			"state":       stateOutcome,
+			"deletedPVCs": deleted,
+			"timestamp":   time.Now().UTC().Format(time.RFC3339),
+		}
+		if statePath != "" {
+			payload["stateFile"] = statePath
+		}
+		if profile.ClusterEndpoint != "" {
+			payload["cluster"] = profile.ClusterEndpoint
+		}
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetEscapeHTML(false)
+		return enc.Encode(payload)
+	default:
+		return errUnsupportedOutput
+	}
+}
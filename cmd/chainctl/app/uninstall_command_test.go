@@ -0,0 +1,159 @@
+package app_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	appcmd "github.com/dobrovols/chainctl/cmd/chainctl/app"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+)
+
+type fakeUninstaller struct {
+	opts   helm.UninstallOptions
+	result helm.UninstallResult
+	err    error
+}
+
+func (f *fakeUninstaller) Uninstall(opts helm.UninstallOptions) (helm.UninstallResult, error) {
+	f.opts = opts
+	return f.result, f.err
+}
+
+type removableStateStub struct {
+	readableStateStub
+	removed bool
+}
+
+func (s *removableStateStub) Remove(o pkgstate.Overrides) (string, error) {
+	s.removed = true
+	return o.StateFilePath, nil
+}
+
+func TestAppUninstallCommand_RemovesState(t *testing.T) {
+	stateMgr := &removableStateStub{readableStateStub: readableStateStub{current: recordedState()}}
+	uninstaller := &fakeUninstaller{}
+	deps := appcmd.UninstallDeps{Uninstaller: uninstaller, TelemetryEmitter: telemetryNoop, StateManager: stateMgr}
+	opts := appcmd.UninstallOptions{Wait: true, StateFilePath: "/var/lib/chainctl/state.json", Output: "text"}
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+
+	if err := appcmd.RunUninstallForTest(cmd, opts, deps); err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+	if uninstaller.opts.Release != "myapp-demo" || uninstaller.opts.Namespace != "demo" || !uninstaller.opts.Wait {
+		t.Fatalf("expected recorded release to be uninstalled with wait, got %+v", uninstaller.opts)
+	}
+	if !stateMgr.removed || stateMgr.called {
+		t.Fatalf("expected state file removal without rewrite")
+	}
+	if !strings.Contains(out.String(), "State record removed from /var/lib/chainctl/state.json") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestAppUninstallCommand_KeepHistoryTombstonesState(t *testing.T) {
+	stateMgr := &removableStateStub{readableStateStub: readableStateStub{stateStub: stateStub{path: "/var/lib/chainctl/state.json"}, current: recordedState()}}
+	uninstaller := &fakeUninstaller{result: helm.UninstallResult{DeletedPVCs: []string{"data-myapp-0"}}}
+	deps := appcmd.UninstallDeps{Uninstaller: uninstaller, TelemetryEmitter: telemetryNoop, StateManager: stateMgr}
+	opts := appcmd.UninstallOptions{KeepHistory: true, DeletePVCs: true, StateFilePath: "/var/lib/chainctl/state.json", Output: "json"}
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+
+	if err := appcmd.RunUninstallForTest(cmd, opts, deps); err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+	if stateMgr.removed {
+		t.Fatal("expected state to be kept when history is kept")
+	}
+	rec := stateMgr.record
+	if rec.LastAction != "uninstall" || rec.Status != "uninstalled" || rec.Chart.Digest != "sha256:two" {
+		t.Fatalf("unexpected tombstone %+v", rec)
+	}
+	if len(rec.History) != 3 || rec.History[2].Action != "uninstall" {
+		t.Fatalf("expected uninstall appended to history, got %+v", rec.History)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if payload["state"] != "tombstoned" || payload["keepHistory"] != true {
+		t.Fatalf("unexpected payload %v", payload)
+	}
+	if pvcs, ok := payload["deletedPVCs"].([]any); !ok || len(pvcs) != 1 {
+		t.Fatalf("expected deleted PVCs in payload, got %v", payload["deletedPVCs"])
+	}
+}
+
+func TestAppUninstallCommand_LeavesForeignStateUntouched(t *testing.T) {
+	stateMgr := &removableStateStub{readableStateStub: readableStateStub{current: recordedState()}}
+	deps := appcmd.UninstallDeps{Uninstaller: &fakeUninstaller{}, TelemetryEmitter: telemetryNoop, StateManager: stateMgr}
+	opts := appcmd.UninstallOptions{ReleaseName: "other", Namespace: "demo", Output: "text"}
+
+	if err := appcmd.RunUninstallForTest(&cobra.Command{}, opts, deps); err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+	if stateMgr.removed || stateMgr.called {
+		t.Fatal("expected state for another release to remain untouched")
+	}
+}
+
+func TestAppUninstallCommand_RequiresRelease(t *testing.T) {
+	stateMgr := &removableStateStub{readableStateStub: readableStateStub{readErr: pkgstate.ErrNotFound()}}
+	deps := appcmd.UninstallDeps{Uninstaller: &fakeUninstaller{}, StateManager: stateMgr}
+
+	err := appcmd.RunUninstallForTest(&cobra.Command{}, appcmd.UninstallOptions{Output: "text"}, deps)
+	if !errors.Is(err, appcmd.ErrReleaseRequired()) {
+		t.Fatalf("expected release required error, got %v", err)
+	}
+}
+
+func TestAppUninstallCommand_PropagatesHelmFailure(t *testing.T) {
+	stateMgr := &removableStateStub{readableStateStub: readableStateStub{current: recordedState()}}
+	wantErr := errors.New("uninstall failed")
+	deps := appcmd.UninstallDeps{Uninstaller: &fakeUninstaller{err: wantErr}, TelemetryEmitter: telemetryNoop, StateManager: stateMgr}
+
+	err := appcmd.RunUninstallForTest(&cobra.Command{}, appcmd.UninstallOptions{Output: "text"}, deps)
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected helm error, got %v", err)
+	}
+	if stateMgr.removed {
+		t.Fatal("expected state to remain when uninstall fails")
+	}
+}
+
+func TestAppUninstallCommand_TombstonesStateReadUnderWorkflowLock(t *testing.T) {
+	stateMgr := &removableStateStub{readableStateStub: readableStateStub{current: recordedState()}}
+	locker := &racingLocker{locker: pkgstate.NewLocker(t.TempDir()), finished: func() {
+		upgraded := stateMgr.current
+		upgraded.Chart = pkgstate.ChartSource{Type: "oci", Reference: "oci://registry.example.com/apps/myapp:1.4.0", Digest: "sha256:three"}
+		upgraded.Version, upgraded.Revision, upgraded.History = "1.4.0", 3, nil
+		stateMgr.current = upgraded.WithHistory(&stateMgr.current)
+	}}
+	deps := appcmd.UninstallDeps{Uninstaller: &fakeUninstaller{}, TelemetryEmitter: telemetryNoop, StateManager: stateMgr, Locker: locker}
+	opts := appcmd.UninstallOptions{KeepHistory: true, StateFilePath: "/var/lib/chainctl/state.json", Output: "text"}
+
+	if err := appcmd.RunUninstallForTest(&cobra.Command{}, opts, deps); err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+	rec := stateMgr.record
+	if rec.Revision != 3 || rec.Chart.Digest != "sha256:three" {
+		t.Fatalf("expected the tombstone to describe the concurrent upgrade, got %+v", rec)
+	}
+	if len(rec.History) != 4 || rec.History[2].Revision != 3 || rec.History[3].Action != "uninstall" {
+		t.Fatalf("expected the concurrent upgrade kept in history, got %+v", rec.History)
+	}
+}
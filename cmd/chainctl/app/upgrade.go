@@ -3,10 +3,12 @@ package app
 import (
 	"errors"
 	"io"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/dobrovols/chainctl/pkg/bundle"
+	"github.com/dobrovols/chainctl/pkg/helm"
 	"github.com/dobrovols/chainctl/pkg/telemetry"
 )
 
@@ -14,16 +16,29 @@ import (
 type UpgradeOptions struct {
 	ClusterEndpoint  string
 	ValuesFile       string
+	ValuesFiles      []string
+	SetValues        []string
+	SetStringValues  []string
 	ValuesPassphrase string
 	BundlePath       string
 	ChartReference   string
+	ExpectDigest     string
+	Offline          bool
+	VerifyKeyring    string
+	VerifyKeys       []string
 	ReleaseName      string
 	AppVersion       string
 	Namespace        string
 	StateFileName    string
 	StateFilePath    string
+	StateBackend     string
+	HistoryLimit     int
 	Output           string
 	Airgapped        bool
+	WaitTimeout      time.Duration
+	LockTimeout      time.Duration
+	Atomic           bool
+	Force            bool
 }
 
 // UpgradeDeps defines dependencies required by the upgrade command.
@@ -33,14 +48,22 @@ type UpgradeDeps struct {
 	TelemetryEmitter func(io.Writer) (*telemetry.Emitter, error)
 	Resolver         ChartResolver
 	StateManager     StateManager
+	Readiness        ReadinessChecker
+	// Rollbacker and Uninstaller revert failed releases under --atomic.
+	Rollbacker  helm.Rollbacker
+	Uninstaller helm.Uninstaller
+	Locker      WorkflowLocker
 }
 
 var (
-	errValuesFile         = errors.New("values file is required")
-	errClusterEndpoint    = errors.New("cluster endpoint must be provided")
-	errUnsupportedOutput  = errors.New("unsupported output format")
-	errConflictingSources = errors.New("exactly one of --chart or --bundle-path must be provided")
-	errMissingSource      = errors.New("a chart reference or bundle path must be provided")
+	errValuesFile          = errors.New("values file is required")
+	errClusterEndpoint     = errors.New("cluster endpoint must be provided")
+	errUnsupportedOutput   = errors.New("unsupported output format")
+	errConflictingSources  = errors.New("exactly one of --chart or --bundle-path must be provided")
+	errMissingSource       = errors.New("a chart reference or bundle path must be provided")
+	errDigestNeedsChart    = errors.New("--expect-digest requires an OCI --chart reference")
+	errOfflineNeedsChart   = errors.New("--offline requires an OCI --chart reference")
+	errStateFileWithSecret = errors.New("--state-file and --state-file-name cannot be used with --state-backend secret")
 )
 
 // ErrValuesFileRequired exposes the sentinel.
@@ -58,6 +81,15 @@ func ErrConflictingSources() error { return errConflictingSources }
 // ErrMissingSource exposes the missing source sentinel.
 func ErrMissingSource() error { return errMissingSource }
 
+// ErrDigestRequiresChart exposes the sentinel returned when a digest pin accompanies a bundle source.
+func ErrDigestRequiresChart() error { return errDigestNeedsChart }
+
+// ErrOfflineRequiresChart exposes the offline source validation sentinel.
+func ErrOfflineRequiresChart() error { return errOfflineNeedsChart }
+
+// ErrStateFileWithSecretBackend exposes the sentinel returned when a state file override accompanies the secret backend.
+func ErrStateFileWithSecretBackend() error { return errStateFileWithSecret }
+
 // NewUpgradeCommand constructs the `chainctl app upgrade` command.
 func NewUpgradeCommand() *cobra.Command {
 	opts := UpgradeOptions{}
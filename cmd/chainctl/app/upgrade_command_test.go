@@ -3,28 +3,34 @@ package app_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes/fake"
 
 	appcmd "github.com/dobrovols/chainctl/cmd/chainctl/app"
 	"github.com/dobrovols/chainctl/internal/config"
 	"github.com/dobrovols/chainctl/pkg/bundle"
 	"github.com/dobrovols/chainctl/pkg/helm"
+	"github.com/dobrovols/chainctl/pkg/readiness"
 	pkgstate "github.com/dobrovols/chainctl/pkg/state"
 	"github.com/dobrovols/chainctl/pkg/telemetry"
 )
 
 type fakeHelmInstaller struct {
-	called bool
-	err    error
+	called  bool
+	err     error
+	profile *config.Profile
 }
 
 func (f *fakeHelmInstaller) Install(p *config.Profile, b *bundle.Bundle) error {
 	f.called = true
+	f.profile = p
 	return f.err
 }
 
@@ -32,10 +38,12 @@ type resolvingStub struct {
 	result helm.ResolveResult
 	err    error
 	called bool
+	opts   helm.ResolveOptions
 }
 
 func (r *resolvingStub) Resolve(ctx context.Context, opts helm.ResolveOptions) (helm.ResolveResult, error) {
 	r.called = true
+	r.opts = opts
 	return r.result, r.err
 }
 
@@ -67,7 +75,7 @@ func TestNewAppCommandRegistersSubcommands(t *testing.T) {
 	if cmd.Use != "app" {
 		t.Fatalf("expected use app, got %s", cmd.Use)
 	}
-	expected := map[string]bool{"upgrade": false, "install": false}
+	expected := map[string]bool{"upgrade": false, "install": false, "rollback": false, "diff": false, "uninstall": false, "status": false, "history": false, "verify": false}
 	for _, sub := range cmd.Commands() {
 		if _, ok := expected[sub.Name()]; ok {
 			expected[sub.Name()] = true
@@ -82,7 +90,7 @@ func TestNewAppCommandRegistersSubcommands(t *testing.T) {
 
 func TestNewUpgradeCommandFlags(t *testing.T) {
 	cmd := appcmd.NewUpgradeCommand()
-	for _, name := range []string{"cluster-endpoint", "values-file", "values-passphrase", "bundle-path", "chart", "release-name", "app-version", "namespace", "state-file", "state-file-name", "airgapped", "output"} {
+	for _, name := range []string{"cluster-endpoint", "values-file", "values-passphrase", "bundle-path", "chart", "release-name", "app-version", "namespace", "state-file", "state-file-name", "airgapped", "output", "expect-digest", "verify-keyring", "verify-key", "wait-timeout", "set", "set-string", "offline", "atomic", "state-backend", "lock-timeout", "force"} {
 		if cmd.Flag(name) == nil {
 			t.Fatalf("expected flag %s to exist", name)
 		}
@@ -197,6 +205,85 @@ func TestAppUpgradeCommand_UnsupportedOutput(t *testing.T) {
 	}
 }
 
+func TestAppUpgradeCommand_SecretStateBackend(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	deps := appcmd.UpgradeDeps{
+		Installer:        &revisionInstaller{release: helm.ReleaseInfo{Revision: 2, Status: "deployed", ValuesHash: "sha256:values"}},
+		TelemetryEmitter: telemetryNoop,
+		Resolver:         atomicResolver(),
+		StateManager:     pkgstate.NewSecretStore(client),
+	}
+	opts := atomicOptions()
+	opts.Atomic = false
+	opts.StateBackend = pkgstate.BackendSecret
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	if err := appcmd.RunUpgradeForTest(cmd, opts, deps); err != nil {
+		t.Fatalf("upgrade failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "State written to secret://demo/chainctl-state-myapp-demo") {
+		t.Fatalf("expected secret location in output, got %q", out.String())
+	}
+	record, err := pkgstate.NewSecretStore(client).Read(pkgstate.Overrides{Namespace: "demo"})
+	if err != nil || record.Revision != 2 || record.Chart.Digest != "sha256:three" || record.ValuesHash != "sha256:values" {
+		t.Fatalf("expected record stored in secret, got %+v (%v)", record, err)
+	}
+
+	opts.StateFilePath = "/var/lib/chainctl/state.json"
+	if err := appcmd.RunUpgradeForTest(cmd, opts, deps); !errors.Is(err, appcmd.ErrStateFileWithSecretBackend()) {
+		t.Fatalf("expected state file conflict with secret backend, got %v", err)
+	}
+	opts.StateFilePath, opts.StateBackend = "", "etcd"
+	if err := appcmd.RunUpgradeForTest(cmd, opts, deps); !errors.Is(err, pkgstate.ErrUnknownBackend()) {
+		t.Fatalf("expected unknown backend error, got %v", err)
+	}
+}
+
+func TestAppUpgradeCommand_RefusesWhileReleaseLocked(t *testing.T) {
+	locker := pkgstate.NewLocker(t.TempDir())
+	opts := atomicOptions()
+	opts.Atomic = false
+	held, err := locker.Acquire(pkgstate.WorkflowLockKey(opts.ClusterEndpoint, opts.Namespace, opts.ReleaseName), pkgstate.LockOptions{Operator: "alice", WorkflowID: "wf-other"})
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	installer := &revisionInstaller{release: helm.ReleaseInfo{Revision: 2, Status: "deployed"}}
+	stateMgr := &stateStub{path: "/var/lib/chainctl/state.json"}
+	deps := appcmd.UpgradeDeps{
+		Installer:        installer,
+		TelemetryEmitter: telemetryNoop,
+		Resolver:         atomicResolver(),
+		StateManager:     stateMgr,
+		Locker:           locker,
+	}
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	err = appcmd.RunUpgradeForTest(cmd, opts, deps)
+	if !errors.Is(err, pkgstate.ErrLocked()) || !strings.Contains(err.Error(), "alice") || !strings.Contains(err.Error(), "wf-other") {
+		t.Fatalf("expected lock error naming the holder, got %v", err)
+	}
+	if installer.called || stateMgr.called {
+		t.Fatalf("expected no helm or state activity while locked")
+	}
+
+	if err := held.Release(); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if err := appcmd.RunUpgradeForTest(cmd, opts, deps); err != nil {
+		t.Fatalf("upgrade after release: %v", err)
+	}
+	opts.LockTimeout = -time.Second
+	if err := appcmd.RunUpgradeForTest(cmd, opts, deps); !errors.Is(err, appcmd.ErrLockTimeout()) {
+		t.Fatalf("expected negative timeout error, got %v", err)
+	}
+}
+
 func TestAppUpgradeCommand_ValidatesInputs(t *testing.T) {
 	deps := appcmd.UpgradeDeps{Installer: &fakeHelmInstaller{}}
 
@@ -282,3 +369,350 @@ func TestAppUpgradeCommand_MissingSource(t *testing.T) {
 		t.Fatalf("expected missing source error, got %v", err)
 	}
 }
+
+type digestPuller struct {
+	digest string
+}
+
+func (p digestPuller) Pull(ctx context.Context, ref string) (helm.PullResult, error) {
+	return helm.PullResult{ChartPath: "/tmp/chart.tgz", Digest: p.digest}, nil
+}
+
+func TestAppUpgradeCommand_PassesExpectedDigest(t *testing.T) {
+	resolver := &resolvingStub{result: helm.ResolveResult{Source: pkgstate.ChartSource{Type: "oci", Reference: "oci://example.com/app:1.0.0", Digest: "sha256:abc"}}}
+	deps := appcmd.UpgradeDeps{
+		Installer:        &fakeHelmInstaller{},
+		TelemetryEmitter: telemetryNoop,
+		Resolver:         resolver,
+		StateManager:     &stateStub{},
+	}
+	opts := appcmd.UpgradeOptions{
+		ClusterEndpoint:  "https://cluster.local",
+		ValuesFile:       "/tmp/values.enc",
+		ValuesPassphrase: "secret",
+		ChartReference:   "oci://example.com/app:1.0.0",
+		ExpectDigest:     "sha256:abc",
+		Output:           "text",
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	if err := appcmd.RunUpgradeForTest(cmd, opts, deps); err != nil {
+		t.Fatalf("upgrade failed: %v", err)
+	}
+	if resolver.opts.ExpectedDigest != "sha256:abc" {
+		t.Fatalf("expected digest pin to reach resolver, got %q", resolver.opts.ExpectedDigest)
+	}
+}
+
+func TestAppUpgradeCommand_LayersValuesAndOverrides(t *testing.T) {
+	installer := &fakeHelmInstaller{}
+	deps := appcmd.UpgradeDeps{
+		Installer:        installer,
+		TelemetryEmitter: telemetryNoop,
+		Resolver:         &resolvingStub{result: helm.ResolveResult{Source: pkgstate.ChartSource{Type: "oci", Reference: "oci://example.com/app:1.0.0"}}},
+		StateManager:     &stateStub{},
+	}
+	opts := appcmd.UpgradeOptions{
+		ClusterEndpoint:  "https://cluster.local",
+		ValuesFiles:      []string{"/etc/chain/base.yaml", "/etc/chain/prod.yaml", "/etc/chain/secrets.enc"},
+		SetValues:        []string{"replicaCount=3"},
+		SetStringValues:  []string{"image.tag=1.10"},
+		ValuesPassphrase: "secret",
+		ChartReference:   "oci://example.com/app:1.0.0",
+		Output:           "text",
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	if err := appcmd.RunUpgradeForTest(cmd, opts, deps); err != nil {
+		t.Fatalf("upgrade failed: %v", err)
+	}
+	if installer.profile == nil {
+		t.Fatalf("expected installer to receive profile")
+	}
+	if got := installer.profile.ValuesLayers(); len(got) != 3 || got[0] != "/etc/chain/base.yaml" || got[2] != "/etc/chain/secrets.enc" {
+		t.Fatalf("expected values layers in flag order, got %v", got)
+	}
+	if len(installer.profile.SetValues) != 1 || len(installer.profile.SetStringValues) != 1 {
+		t.Fatalf("expected overrides on profile, got %v %v", installer.profile.SetValues, installer.profile.SetStringValues)
+	}
+}
+
+func TestAppUpgradeCommand_DigestMismatchStopsBeforeHelm(t *testing.T) {
+	installer := &fakeHelmInstaller{}
+	stateMgr := &stateStub{}
+	deps := appcmd.UpgradeDeps{
+		Installer:        installer,
+		TelemetryEmitter: telemetryNoop,
+		Resolver:         helm.NewResolver(digestPuller{digest: "sha256:def"}, nil),
+		StateManager:     stateMgr,
+	}
+	opts := appcmd.UpgradeOptions{
+		ClusterEndpoint:  "https://cluster.local",
+		ValuesFile:       "/tmp/values.enc",
+		ValuesPassphrase: "secret",
+		ChartReference:   "oci://example.com/app:1.0.0",
+		ExpectDigest:     "sha256:abc",
+		Output:           "text",
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	err := appcmd.RunUpgradeForTest(cmd, opts, deps)
+	if !errors.Is(err, helm.ErrResolverDigestMismatch()) {
+		t.Fatalf("expected digest mismatch error, got %v", err)
+	}
+	if installer.called || stateMgr.called {
+		t.Fatal("expected helm and state to be skipped on digest mismatch")
+	}
+}
+
+func TestAppUpgradeCommand_ExpectDigestRequiresChart(t *testing.T) {
+	deps := appcmd.UpgradeDeps{Installer: &fakeHelmInstaller{}, TelemetryEmitter: telemetryNoop}
+	opts := appcmd.UpgradeOptions{
+		ClusterEndpoint:  "https://cluster.local",
+		ValuesFile:       "/tmp/values.enc",
+		ValuesPassphrase: "secret",
+		BundlePath:       "/tmp/bundle.tar",
+		ExpectDigest:     "sha256:abc",
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetErr(io.Discard)
+	err := appcmd.RunUpgradeForTest(cmd, opts, deps)
+	if !errors.Is(err, appcmd.ErrDigestRequiresChart()) {
+		t.Fatalf("expected digest requires chart error, got %v", err)
+	}
+}
+
+func TestAppUpgradeCommand_OfflineRequiresChart(t *testing.T) {
+	deps := appcmd.UpgradeDeps{Installer: &fakeHelmInstaller{}, TelemetryEmitter: telemetryNoop}
+	opts := appcmd.UpgradeOptions{
+		ClusterEndpoint:  "https://cluster.local",
+		ValuesFile:       "/tmp/values.enc",
+		ValuesPassphrase: "secret",
+		BundlePath:       "/tmp/bundle.tar",
+		Offline:          true,
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetErr(io.Discard)
+	err := appcmd.RunUpgradeForTest(cmd, opts, deps)
+	if !errors.Is(err, appcmd.ErrOfflineRequiresChart()) {
+		t.Fatalf("expected offline requires chart error, got %v", err)
+	}
+}
+
+func TestAppUpgradeCommand_OfflineResolvesFromCache(t *testing.T) {
+	resolver := &resolvingStub{result: helm.ResolveResult{
+		Source: pkgstate.ChartSource{Type: "oci", Reference: "oci://example.com/app:1.0.0", Digest: "sha256:abc"},
+		Cached: true,
+	}}
+	deps := appcmd.UpgradeDeps{
+		Installer:        &fakeHelmInstaller{},
+		TelemetryEmitter: telemetry.NewEmitter,
+		Resolver:         resolver,
+		StateManager:     &stateStub{},
+	}
+	opts := appcmd.UpgradeOptions{
+		ClusterEndpoint:  "https://cluster.local",
+		ValuesFile:       "/tmp/values.enc",
+		ValuesPassphrase: "secret",
+		ChartReference:   "oci://example.com/app:1.0.0",
+		Offline:          true,
+		Output:           "text",
+	}
+
+	cmd := &cobra.Command{}
+	var logs bytes.Buffer
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(&logs)
+	if err := appcmd.RunUpgradeForTest(cmd, opts, deps); err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	if !resolver.opts.Offline {
+		t.Fatal("expected offline mode forwarded to the resolver")
+	}
+	if !strings.Contains(logs.String(), `"cache":"hit"`) {
+		t.Fatalf("expected cache hit recorded in resolve telemetry, got %s", logs.String())
+	}
+}
+
+func TestAppUpgradeCommand_RecordsChartVerification(t *testing.T) {
+	verification := &pkgstate.ChartVerification{Method: helm.VerifyMethodSignature, Signer: "release-team", KeyID: "0f1e2d3c4b5a6978"}
+	resolver := &resolvingStub{result: helm.ResolveResult{
+		Source:       pkgstate.ChartSource{Type: "oci", Reference: "oci://example.com/app:1.0.0", Digest: "sha256:abc"},
+		Verification: verification,
+	}}
+	stateMgr := &stateStub{}
+	deps := appcmd.UpgradeDeps{
+		Installer:        &fakeHelmInstaller{},
+		TelemetryEmitter: telemetryNoop,
+		Resolver:         resolver,
+		StateManager:     stateMgr,
+	}
+	opts := appcmd.UpgradeOptions{
+		ClusterEndpoint:  "https://cluster.local",
+		ValuesFile:       "/tmp/values.enc",
+		ValuesPassphrase: "secret",
+		ChartReference:   "oci://example.com/app:1.0.0",
+		VerifyKeys:       []string{"/etc/chainctl/release-team.pub"},
+		Output:           "json",
+	}
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	if err := appcmd.RunUpgradeForTest(cmd, opts, deps); err != nil {
+		t.Fatalf("upgrade failed: %v", err)
+	}
+
+	if got := resolver.opts.Verify.PublicKeys; len(got) != 1 || got[0] != "/etc/chainctl/release-team.pub" {
+		t.Fatalf("expected verification keys to reach resolver, got %v", got)
+	}
+	if stateMgr.record.Verification == nil || *stateMgr.record.Verification != *verification {
+		t.Fatalf("expected verification persisted in state, got %+v", stateMgr.record.Verification)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if _, ok := payload["verification"]; !ok {
+		t.Fatalf("expected verification in JSON output, got %s", out.String())
+	}
+}
+
+type manifestInstaller struct {
+	fakeHelmInstaller
+	manifest string
+}
+
+func (m *manifestInstaller) Apply(p *config.Profile, b *bundle.Bundle) (helm.ReleaseInfo, error) {
+	m.called = true
+	return helm.ReleaseInfo{Name: p.HelmRelease, Revision: 2, Status: "deployed", Manifest: m.manifest}, nil
+}
+
+func TestAppUpgradeCommand_WaitsForWorkloadReadiness(t *testing.T) {
+	checker := &fakeReadiness{ready: true}
+	stateMgr := &stateStub{}
+	var logs bytes.Buffer
+	deps := appcmd.UpgradeDeps{
+		Installer:        &manifestInstaller{manifest: statusManifest},
+		TelemetryEmitter: telemetryNoop,
+		Resolver:         &resolvingStub{result: helm.ResolveResult{Source: pkgstate.ChartSource{Type: "oci", Reference: "oci://example.com/app:1.0.0"}}},
+		StateManager:     stateMgr,
+		Readiness:        checker,
+	}
+	opts := appcmd.UpgradeOptions{
+		ClusterEndpoint:  "https://cluster.local",
+		ValuesFile:       "/tmp/values.enc",
+		ValuesPassphrase: "secret",
+		ChartReference:   "oci://example.com/app:1.0.0",
+		Namespace:        "demo",
+		Output:           "text",
+		WaitTimeout:      time.Second,
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(&logs)
+	if err := appcmd.RunUpgradeForTest(cmd, opts, deps); err != nil {
+		t.Fatalf("upgrade failed: %v", err)
+	}
+	if len(checker.checked) != 1 || checker.checked[0].Name != "web" || checker.checked[0].Namespace != "demo" {
+		t.Fatalf("expected release workloads to be checked, got %+v", checker.checked)
+	}
+	if !strings.Contains(logs.String(), `"phase":"verify"`) || !strings.Contains(logs.String(), `"step":"verify"`) {
+		t.Fatalf("expected verify phase and progress entries, got %s", logs.String())
+	}
+	if !stateMgr.called {
+		t.Fatal("expected state to be persisted after verification")
+	}
+}
+
+func TestAppUpgradeCommand_FailsWhenWorkloadsNotReady(t *testing.T) {
+	stateMgr := &stateStub{}
+	deps := appcmd.UpgradeDeps{
+		Installer:        &manifestInstaller{manifest: statusManifest},
+		TelemetryEmitter: telemetryNoop,
+		Resolver:         &resolvingStub{result: helm.ResolveResult{Source: pkgstate.ChartSource{Type: "oci", Reference: "oci://example.com/app:1.0.0"}}},
+		StateManager:     stateMgr,
+		Readiness:        &fakeReadiness{ready: false},
+	}
+	opts := appcmd.UpgradeOptions{
+		ClusterEndpoint:  "https://cluster.local",
+		ValuesFile:       "/tmp/values.enc",
+		ValuesPassphrase: "secret",
+		ChartReference:   "oci://example.com/app:1.0.0",
+		Namespace:        "demo",
+		Output:           "text",
+		WaitTimeout:      20 * time.Millisecond,
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	err := appcmd.RunUpgradeForTest(cmd, opts, deps)
+	if !errors.Is(err, readiness.ErrNotReady()) {
+		t.Fatalf("expected not ready error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Deployment demo/web") {
+		t.Fatalf("expected unhealthy workload in error, got %v", err)
+	}
+	failure := stateMgr.record.Failure
+	if !stateMgr.called || failure == nil || failure.Phase != "verify" || failure.Action != "upgrade" || failure.Chart.Reference != "oci://example.com/app:1.0.0" {
+		t.Fatalf("expected failed verification to be recorded in state, got %+v", stateMgr.record)
+	}
+	if strings.Contains(failure.Error, "secret") || failure.WorkflowID == "" {
+		t.Fatalf("expected sanitized error and workflow ID, got %+v", failure)
+	}
+}
+
+type schemaRejectingInstaller struct {
+	fakeHelmInstaller
+}
+
+func (s *schemaRejectingInstaller) ValidateValues(*config.Profile, *bundle.Bundle) error {
+	return &helm.SchemaError{Violations: []helm.SchemaViolation{{Chart: "app", Pointer: "/image/tag", Keyword: "type", Message: "got number, want string"}}}
+}
+
+func TestAppUpgradeCommand_ValidatePhaseStopsBeforeHelm(t *testing.T) {
+	installer := &schemaRejectingInstaller{}
+	stateMgr := &stateStub{}
+	deps := appcmd.UpgradeDeps{
+		Installer:        installer,
+		TelemetryEmitter: telemetryNoop,
+		Resolver:         &resolvingStub{result: helm.ResolveResult{Source: pkgstate.ChartSource{Type: "oci", Reference: "oci://example.com/app:1.0.0"}}},
+		StateManager:     stateMgr,
+	}
+	opts := appcmd.UpgradeOptions{
+		ClusterEndpoint:  "https://cluster.local",
+		ValuesFile:       "/tmp/values.enc",
+		ValuesPassphrase: "secret",
+		ChartReference:   "oci://example.com/app:1.0.0",
+		Output:           "text",
+	}
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	err := appcmd.RunUpgradeForTest(cmd, opts, deps)
+	if !errors.Is(err, helm.ErrValuesSchema()) {
+		t.Fatalf("expected schema error, got %v", err)
+	}
+	if installer.called || stateMgr.called {
+		t.Fatal("expected schema failure to stop before helm and state persistence")
+	}
+	if !strings.Contains(out.String(), `"phase":"validate","outcome":"failure"`) {
+		t.Fatalf("expected failed validate phase event, got %s", out.String())
+	}
+	if !strings.Contains(out.String(), `"pointers":"/image/tag"`) {
+		t.Fatalf("expected violation pointer in phase metadata, got %s", out.String())
+	}
+}
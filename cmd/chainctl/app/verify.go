@@ -0,0 +1,278 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dobrovols/chainctl/internal/cli/exitcode"
+	"github.com/dobrovols/chainctl/internal/config"
+	internalstate "github.com/dobrovols/chainctl/internal/state"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+const actionVerify appAction = "verify"
+
+// Verification check outcomes.
+const (
+	checkMatch       = "match"
+	checkDrift       = "drift"
+	checkUnavailable = "unavailable"
+)
+
+// VerifyOptions holds CLI flags for app verify.
+type VerifyOptions struct {
+	ClusterEndpoint string
+	ReleaseName     string
+	Namespace       string
+	StateFileName   string
+	StateFilePath   string
+	StateBackend    string
+	Output          string
+}
+
+// VerifyDeps defines dependencies required by the verify command.
+type VerifyDeps struct {
+	Releases         helm.StatusReader
+	Objects          helm.ObjectReader
+	StateManager     StateReader
+	TelemetryEmitter func(io.Writer) (*telemetry.Emitter, error)
+}
+
+type verifyCheck struct {
+	Name     string `json:"name"`
+	Recorded string `json:"recorded,omitempty"`
+	Deployed string `json:"deployed,omitempty"`
+	Status   string `json:"status"`
+}
+
+type verifyReport struct {
+	Release         string               `json:"release"`
+	Namespace       string               `json:"namespace"`
+	ClusterEndpoint string               `json:"clusterEndpoint,omitempty"`
+	Deployed        bool                 `json:"deployed"`
+	Drifted         bool                 `json:"drifted"`
+	Checks          []verifyCheck        `json:"checks"`
+	Resources       []helm.ResourceDrift `json:"resources"`
+}
+
+var errDriftDetected = errors.New("release has drifted from recorded state")
+
+// ErrDriftDetected exposes the drift sentinel returned with exit code 3.
+func ErrDriftDetected() error { return errDriftDetected }
+
+// NewVerifyCommand constructs the `chainctl app verify` command.
+func NewVerifyCommand() *cobra.Command {
+	opts := VerifyOptions{}
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Detect drift between recorded state and the live release",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return runVerify(cmd, opts, defaultVerifyDeps)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ClusterEndpoint, "cluster-endpoint", "", "Kubernetes API endpoint of the target cluster")
+	cmd.Flags().StringVar(&opts.ReleaseName, "release-name", "", "Helm release name (defaults to the release recorded in state)")
+	cmd.Flags().StringVar(&opts.Namespace, "namespace", "", "Kubernetes namespace of the Helm release (defaults to the recorded namespace)")
+	bindStateFlags(cmd, &opts.StateFilePath, &opts.StateFileName, &opts.StateBackend)
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+	markDeclarative(cmd)
+
+	return cmd
+}
+
+// RunVerifyForTest executes the verify flow with injected dependencies.
+func RunVerifyForTest(cmd *cobra.Command, opts VerifyOptions, deps VerifyDeps) error {
+	cmd.SilenceUsage = true
+	return runVerify(cmd, opts, deps)
+}
+
+func runVerify(cmd *cobra.Command, opts VerifyOptions, deps VerifyDeps) error {
+	report, err := computeVerify(cmd, opts, deps)
+	if err != nil {
+		return err
+	}
+	if report.Drifted {
+		cmd.SilenceErrors = true
+		return exitcode.New(exitcode.DriftDetected, errDriftDetected)
+	}
+	return nil
+}
+
+func computeVerify(cmd *cobra.Command, opts VerifyOptions, deps VerifyDeps) (report verifyReport, err error) {
+	if deps.StateManager == nil && opts.StateBackend == pkgstate.BackendSecret {
+		if deps.StateManager, err = internalstate.NewStore(opts.StateBackend); err != nil {
+			return verifyReport{}, err
+		}
+	}
+	ensureVerifyDeps(&deps)
+	if opts.Output != "text" && opts.Output != "json" {
+		return verifyReport{}, errUnsupportedOutput
+	}
+
+	stateOverrides, _, err := resolveStateOverrides(sharedOptions{
+		ClusterEndpoint: opts.ClusterEndpoint,
+		ReleaseName:     opts.ReleaseName,
+		Namespace:       opts.Namespace,
+		StateFileName:   opts.StateFileName,
+		StateFilePath:   opts.StateFilePath,
+		StateBackend:    opts.StateBackend,
+	})
+	if err != nil {
+		return verifyReport{}, err
+	}
+	record, err := deps.StateManager.Read(stateOverrides)
+	if err != nil {
+		return verifyReport{}, fmt.Errorf("state file could not be read: %w", err)
+	}
+
+	profile := &config.Profile{
+		Mode:            config.ModeReuse,
+		ClusterEndpoint: firstNonEmpty(opts.ClusterEndpoint, record.ClusterEndpoint),
+		HelmRelease:     record.Release,
+		HelmNamespace:   record.Namespace,
+	}
+
+	_, logger, err := initAppTelemetry(cmd, deps.TelemetryEmitter)
+	if err != nil {
+		return verifyReport{}, err
+	}
+	workflowStep := workflowStepName(actionVerify)
+	workflowMetadata := buildWorkflowMetadata(profile)
+	logWorkflowStart(logger, workflowStep, workflowMetadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, workflowStep, workflowMetadata, err)
+		}
+	}()
+
+	report, err = buildVerifyReport(cmd.Context(), deps, profile, record)
+	if err != nil {
+		return verifyReport{}, err
+	}
+	workflowMetadata["drift"] = strconv.FormatBool(report.Drifted)
+	workflowMetadata["driftedResources"] = strconv.Itoa(len(report.Resources))
+
+	logWorkflowSuccess(logger, workflowStep, workflowMetadata)
+	if err := emitVerifyOutput(cmd, report, opts.Output); err != nil {
+		return verifyReport{}, err
+	}
+	return report, nil
+}
+
+func buildVerifyReport(ctx context.Context, deps VerifyDeps, profile *config.Profile, record pkgstate.Record) (verifyReport, error) {
+	report := verifyReport{
+		Release:         profile.HelmRelease,
+		Namespace:       profile.HelmNamespace,
+		ClusterEndpoint: profile.ClusterEndpoint,
+		Checks:          []verifyCheck{},
+		Resources:       []helm.ResourceDrift{},
+	}
+
+	release, err := deps.Releases.ReleaseStatus(profile.HelmRelease, profile.HelmNamespace)
+	switch {
+	case errors.Is(err, helm.ErrReleaseNotFound()):
+		report.Drifted = true
+		return report, nil
+	case err != nil:
+		return verifyReport{}, err
+	}
+	report.Deployed = true
+
+	recordedRevision := ""
+	if record.Revision > 0 {
+		recordedRevision = strconv.Itoa(record.Revision)
+	}
+	report.Checks = []verifyCheck{
+		compareRecorded("chartDigest", record.Chart.Digest, release.ChartDigest),
+		compareRecorded("chartVersion", firstNonEmpty(record.Chart.Version, record.Version), release.ChartVersion),
+		compareRecorded("valuesHash", record.ValuesHash, release.ValuesHash),
+		compareRecorded("revision", recordedRevision, strconv.Itoa(release.Revision)),
+	}
+
+	objects := deps.Objects
+	if objects == nil {
+		if objects, err = defaultObjectReader(); err != nil {
+			return verifyReport{}, err
+		}
+	}
+	resources, err := helm.DetectResourceDrift(ctx, release.Manifest, profile.HelmNamespace, objects)
+	if err != nil {
+		return verifyReport{}, err
+	}
+	if len(resources) > 0 {
+		report.Resources = resources
+		report.Drifted = true
+	}
+	for _, check := range report.Checks {
+		if check.Status == checkDrift {
+			report.Drifted = true
+		}
+	}
+	return report, nil
+}
+
+// compareRecorded reports drift only when both sides are known; records written before a field
+// was tracked cannot be checked.
+func compareRecorded(name, recorded, deployed string) verifyCheck {
+	check := verifyCheck{Name: name, Recorded: recorded, Deployed: deployed}
+	switch {
+	case recorded == "" || deployed == "":
+		check.Status = checkUnavailable
+	case recorded != deployed:
+		check.Status = checkDrift
+	default:
+		check.Status = checkMatch
+	}
+	return check
+}
+
+func emitVerifyOutput(cmd *cobra.Command, report verifyReport, format string) error {
+	switch format {
+	case "text":
+		tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "Release:\t%s\n", report.Release)
+		fmt.Fprintf(tw, "Namespace:\t%s\n", report.Namespace)
+		if !report.Deployed {
+			fmt.Fprintf(tw, "Helm:\tnot deployed\n")
+			fmt.Fprintf(tw, "Drift:\tDETECTED (release recorded in state is missing)\n")
+			return tw.Flush()
+		}
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "CHECK\tRECORDED\tDEPLOYED\tSTATUS")
+		for _, check := range report.Checks {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", check.Name, historyCell(check.Recorded), historyCell(check.Deployed), check.Status)
+		}
+		if len(report.Resources) > 0 {
+			fmt.Fprintln(tw)
+			fmt.Fprintln(tw, "RESOURCE\tCHANGE\tFIELDS")
+			for _, resource := range report.Resources {
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", resource.Key(), resource.Change, historyCell(strings.Join(resource.Fields, ", ")))
+			}
+		}
+		fmt.Fprintln(tw)
+		if report.Drifted {
+			fmt.Fprintf(tw, "Drift:\tDETECTED\n")
+		} else {
+			fmt.Fprintf(tw, "Drift:\tnone\n")
+		}
+		return tw.Flush()
+	case "json":
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetEscapeHTML(false)
+		return enc.Encode(report)
+	default:
+		return errUnsupportedOutput
+	}
+}
@@ -0,0 +1,142 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	appcmd "github.com/dobrovols/chainctl/cmd/chainctl/app"
+	"github.com/dobrovols/chainctl/internal/cli/exitcode"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+)
+
+type fakeObjects struct {
+	objects map[string]map[string]interface{}
+}
+
+func (f fakeObjects) LiveObject(_ context.Context, _, kind, namespace, name string) (map[string]interface{}, error) {
+	return f.objects[kind+"/"+namespace+"/"+name], nil
+}
+
+func liveWeb(replicas int) fakeObjects {
+	return fakeObjects{objects: map[string]map[string]interface{}{
+		"Deployment/demo/web": {
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "web", "namespace": "demo"},
+			"spec":       map[string]interface{}{"replicas": int64(replicas)},
+		},
+	}}
+}
+
+func verifiedState() pkgstate.Record {
+	record := recordedState()
+	record.ValuesHash = "sha256:values"
+	return record
+}
+
+func verifiedRelease() helm.ReleaseInfo {
+	release := deployedRelease("sha256:two")
+	release.ValuesHash = "sha256:values"
+	release.Manifest = "---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  replicas: 2\n"
+	return release
+}
+
+func TestAppVerifyCommand_InSync(t *testing.T) {
+	deps := appcmd.VerifyDeps{
+		Releases:         &fakeStatusReader{info: verifiedRelease()},
+		Objects:          liveWeb(2),
+		StateManager:     &readableStateStub{current: verifiedState()},
+		TelemetryEmitter: telemetryNoop,
+	}
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+
+	if err := appcmd.RunVerifyForTest(cmd, appcmd.VerifyOptions{Output: "text"}, deps); err != nil {
+		t.Fatalf("expected no drift, got %v", err)
+	}
+	for _, want := range []string{"chartDigest", "valuesHash", "match", "Drift:  none"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestAppVerifyCommand_ReportsDriftWithExitCode(t *testing.T) {
+	release := verifiedRelease()
+	release.ValuesHash = "sha256:edited"
+	deps := appcmd.VerifyDeps{
+		Releases:         &fakeStatusReader{info: release},
+		Objects:          liveWeb(5),
+		StateManager:     &readableStateStub{current: verifiedState()},
+		TelemetryEmitter: telemetryNoop,
+	}
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+
+	err := appcmd.RunVerifyForTest(cmd, appcmd.VerifyOptions{Output: "json"}, deps)
+	var exitErr *exitcode.Error
+	if !errors.As(err, &exitErr) || exitErr.Code != exitcode.DriftDetected || !errors.Is(err, appcmd.ErrDriftDetected()) {
+		t.Fatalf("expected drift exit code %d, got %v", exitcode.DriftDetected, err)
+	}
+
+	var report struct {
+		Drifted bool `json:"drifted"`
+		Checks  []struct {
+			Name   string `json:"name"`
+			Status string `json:"status"`
+		} `json:"checks"`
+		Resources []helm.ResourceDrift `json:"resources"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	statuses := map[string]string{}
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	if !report.Drifted || statuses["valuesHash"] != "drift" || statuses["chartDigest"] != "match" {
+		t.Fatalf("unexpected checks %+v", report.Checks)
+	}
+	if len(report.Resources) != 1 || report.Resources[0].Change != helm.DriftModified || report.Resources[0].Fields[0] != "spec.replicas" {
+		t.Fatalf("unexpected resource drift %+v", report.Resources)
+	}
+}
+
+func TestAppVerifyCommand_MissingReleaseIsDrift(t *testing.T) {
+	deps := appcmd.VerifyDeps{
+		Releases:         &fakeStatusReader{err: helm.ErrReleaseNotFound()},
+		StateManager:     &readableStateStub{current: verifiedState()},
+		TelemetryEmitter: telemetryNoop,
+	}
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	if err := appcmd.RunVerifyForTest(cmd, appcmd.VerifyOptions{Output: "text"}, deps); !errors.Is(err, appcmd.ErrDriftDetected()) {
+		t.Fatalf("expected drift for missing release, got %v", err)
+	}
+}
+
+func TestAppVerifyCommand_RequiresState(t *testing.T) {
+	deps := appcmd.VerifyDeps{
+		Releases:         &fakeStatusReader{info: verifiedRelease()},
+		StateManager:     &readableStateStub{readErr: pkgstate.ErrNotFound()},
+		TelemetryEmitter: telemetryNoop,
+	}
+	err := appcmd.RunVerifyForTest(&cobra.Command{}, appcmd.VerifyOptions{Output: "text"}, deps)
+	var exitErr *exitcode.Error
+	if !errors.Is(err, pkgstate.ErrNotFound()) || errors.As(err, &exitErr) {
+		t.Fatalf("expected state read failure with the default exit code, got %v", err)
+	}
+}
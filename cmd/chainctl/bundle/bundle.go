@@ -0,0 +1,26 @@
+package bundle
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+)
+
+var errUnsupportedOutput = errors.New("unsupported output format")
+
+// ErrUnsupportedOutput exposes the sentinel.
+func ErrUnsupportedOutput() error { return errUnsupportedOutput }
+
+// NewBundleCommand creates the `chainctl bundle` parent command.
+func NewBundleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Build, inspect, and verify air-gapped bundles",
+	}
+
+	cmd.AddCommand(NewCreateCommand())
+	cmd.AddCommand(NewInspectCommand())
+	cmd.AddCommand(NewVerifyCommand())
+
+	return cmd
+}
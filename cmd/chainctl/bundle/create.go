@@ -0,0 +1,186 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/chart/loader"
+
+	internalhelm "github.com/dobrovols/chainctl/internal/helm"
+	pkgbundle "github.com/dobrovols/chainctl/pkg/bundle"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+// CreateOptions holds CLI flags for bundle create.
+type CreateOptions struct {
+	SpecPath  string
+	File      string
+	Overwrite bool
+	Output    string
+}
+
+// CreateDeps defines dependencies required by the create command.
+type CreateDeps struct {
+	Charts pkgbundle.ChartFetcher
+}
+
+// ChartResolver resolves chart references the way app commands do.
+type ChartResolver interface {
+	Resolve(context.Context, helm.ResolveOptions) (helm.ResolveResult, error)
+}
+
+type createReport struct {
+	File     string                   `json:"file"`
+	Digest   string                   `json:"digest"`
+	Size     int64                    `json:"size"`
+	Version  string                   `json:"version"`
+	Charts   []pkgbundle.ChartRecord  `json:"charts"`
+	Images   []pkgbundle.ImageRecord  `json:"images"`
+	Binaries []pkgbundle.BinaryRecord `json:"binaries"`
+	Files    int                      `json:"files"`
+}
+
+var (
+	errSpecRequired = errors.New("--spec is required")
+	errOutputExists = errors.New("bundle file already exists; pass --overwrite to replace it")
+)
+
+// ErrSpecRequired exposes the missing spec sentinel.
+func ErrSpecRequired() error { return errSpecRequired }
+
+// ErrOutputExists exposes the sentinel returned when the bundle file would be replaced.
+func ErrOutputExists() error { return errOutputExists }
+
+// NewCreateCommand constructs the `chainctl bundle create` command.
+func NewCreateCommand() *cobra.Command {
+	opts := CreateOptions{}
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Build a deterministic air-gapped bundle from a bundle spec",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return runCreate(cmd, opts, CreateDeps{Charts: NewResolverFetcher(internalhelm.NewChartResolver(pkgbundle.Load))})
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.SpecPath, "spec", "", "Bundle spec listing charts, image archives, and binaries")
+	cmd.Flags().StringVar(&opts.File, "file", "bundle.tar", "Destination of the bundle tarball")
+	cmd.Flags().BoolVar(&opts.Overwrite, "overwrite", false, "Replace an existing bundle file")
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// RunCreateForTest executes the create flow with injected dependencies.
+func RunCreateForTest(cmd *cobra.Command, opts CreateOptions, deps CreateDeps) error {
+	cmd.SilenceUsage = true
+	return runCreate(cmd, opts, deps)
+}
+
+func runCreate(cmd *cobra.Command, opts CreateOptions, deps CreateDeps) (err error) {
+	if opts.Output != "text" && opts.Output != "json" {
+		return errUnsupportedOutput
+	}
+	if strings.TrimSpace(opts.SpecPath) == "" {
+		return errSpecRequired
+	}
+	if !opts.Overwrite {
+		if _, statErr := os.Stat(opts.File); statErr == nil {
+			return fmt.Errorf("%w: %s", errOutputExists, opts.File)
+		}
+	}
+
+	logger, err := telemetry.NewCommandLogger(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{"spec": opts.SpecPath, "file": opts.File}
+	logWorkflowStart(logger, stepBundleCreate, metadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, stepBundleCreate, metadata, err)
+		}
+	}()
+
+	spec, err := pkgbundle.LoadSpec(opts.SpecPath)
+	if err != nil {
+		return err
+	}
+	result, err := pkgbundle.Create(cmd.Context(), pkgbundle.CreateOptions{Spec: spec, OutputPath: opts.File, Charts: deps.Charts})
+	if err != nil {
+		return err
+	}
+	metadata["digest"] = result.Digest
+	metadata["files"] = strconv.Itoa(len(result.Manifest.Checksums))
+
+	logWorkflowSuccess(logger, stepBundleCreate, metadata)
+	return emitCreateOutput(cmd, createReport{
+		File:     result.Path,
+		Digest:   result.Digest,
+		Size:     result.Size,
+		Version:  result.Manifest.Version,
+		Charts:   result.Manifest.Charts,
+		Images:   result.Manifest.Images,
+		Binaries: result.Manifest.Binaries,
+		Files:    len(result.Manifest.Checksums),
+	}, opts.Output)
+}
+
+func emitCreateOutput(cmd *cobra.Command, report createReport, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetEscapeHTML(false)
+		return enc.Encode(report)
+	}
+
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Bundle:\t%s\n", report.File)
+	fmt.Fprintf(tw, "Version:\t%s\n", report.Version)
+	fmt.Fprintf(tw, "Digest:\t%s\n", report.Digest)
+	fmt.Fprintf(tw, "Size:\t%d bytes\n", report.Size)
+	fmt.Fprintf(tw, "Contents:\t%d charts, %d images, %d binaries (%d files)\n", len(report.Charts), len(report.Images), len(report.Binaries), report.Files)
+	return tw.Flush()
+}
+
+// resolverFetcher fetches bundle charts through the chart resolver used by app commands.
+type resolverFetcher struct {
+	resolver ChartResolver
+}
+
+// NewResolverFetcher adapts a chart resolver to the bundle chart fetcher.
+func NewResolverFetcher(resolver ChartResolver) pkgbundle.ChartFetcher {
+	return resolverFetcher{resolver: resolver}
+}
+
+// FetchChart resolves an oci://, http(s):// repository, or chart directory source and reads the
+// chart name and version from it.
+func (f resolverFetcher) FetchChart(ctx context.Context, source string) (pkgbundle.FetchedChart, error) {
+	opts := helm.ResolveOptions{}
+	switch helm.ClassifyChartReference(source) {
+	case pkgstate.SourceTypeRepo:
+		opts.RepoReference = source
+	case pkgstate.SourceTypeDir:
+		opts.ChartDirectory = source
+	default:
+		opts.OCIReference = source
+	}
+	res, err := f.resolver.Resolve(ctx, opts)
+	if err != nil {
+		return pkgbundle.FetchedChart{}, err
+	}
+	chart, err := loader.Load(res.ChartPath)
+	if err != nil {
+		return pkgbundle.FetchedChart{}, fmt.Errorf("load chart: %w", err)
+	}
+	return pkgbundle.FetchedChart{Path: res.ChartPath, Name: chart.Metadata.Name, Version: chart.Metadata.Version}, nil
+}
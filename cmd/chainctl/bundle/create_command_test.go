@@ -0,0 +1,129 @@
+package bundle_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	bundlecmd "github.com/dobrovols/chainctl/cmd/chainctl/bundle"
+	"github.com/dobrovols/chainctl/pkg/helm"
+)
+
+type resolverStub struct {
+	opts   helm.ResolveOptions
+	result helm.ResolveResult
+}
+
+func (r *resolverStub) Resolve(_ context.Context, opts helm.ResolveOptions) (helm.ResolveResult, error) {
+	r.opts = opts
+	return r.result, nil
+}
+
+func newCommand() (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetContext(context.Background())
+	return cmd, out
+}
+
+func writeSpec(t *testing.T) (string, *resolverStub) {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"build/demo/Chart.yaml":        "apiVersion: v2\nname: demo\nversion: 0.3.0\n",
+		"build/demo/templates/cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: demo\n",
+		"images/demo.tar":              "image archive",
+		"bin/k3s":                      "k3s binary",
+		"bundle-spec.yaml": `version: 0.3.0
+charts:
+  - source: oci://registry.example.com/apps/demo:0.3.0
+images:
+  - name: registry.example.com/apps/demo
+    tag: 0.3.0
+    archive: images/demo.tar
+binaries:
+  - name: k3s
+    version: v1.30.2+k3s1
+    os: linux
+    arch: amd64
+    path: bin/k3s
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	resolver := &resolverStub{result: helm.ResolveResult{ChartPath: filepath.Join(dir, "build", "demo")}}
+	return filepath.Join(dir, "bundle-spec.yaml"), resolver
+}
+
+func TestBundleCreate_JSONReport(t *testing.T) {
+	spec, resolver := writeSpec(t)
+	file := filepath.Join(t.TempDir(), "bundle.tar")
+	deps := bundlecmd.CreateDeps{Charts: bundlecmd.NewResolverFetcher(resolver)}
+
+	cmd, out := newCommand()
+	if err := bundlecmd.RunCreateForTest(cmd, bundlecmd.CreateOptions{SpecPath: spec, File: file, Output: "json"}, deps); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if resolver.opts.OCIReference != "oci://registry.example.com/apps/demo:0.3.0" {
+		t.Fatalf("expected OCI chart resolution, got %+v", resolver.opts)
+	}
+	var report struct {
+		File   string `json:"file"`
+		Digest string `json:"digest"`
+		Charts []struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+			Path    string `json:"path"`
+		} `json:"charts"`
+		Files int `json:"files"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if report.File != file || !strings.HasPrefix(report.Digest, "sha256:") || report.Files != 4 {
+		t.Fatalf("unexpected report %s", out.String())
+	}
+	if len(report.Charts) != 1 || report.Charts[0].Name != "demo" || report.Charts[0].Version != "0.3.0" {
+		t.Fatalf("expected chart metadata read from the chart, got %s", out.String())
+	}
+
+	cmd, _ = newCommand()
+	err := bundlecmd.RunCreateForTest(cmd, bundlecmd.CreateOptions{SpecPath: spec, File: file, Output: "text"}, deps)
+	if !errors.Is(err, bundlecmd.ErrOutputExists()) {
+		t.Fatalf("expected existing bundle to be kept, got %v", err)
+	}
+
+	cmd, out = newCommand()
+	if err := bundlecmd.RunCreateForTest(cmd, bundlecmd.CreateOptions{SpecPath: spec, File: file, Overwrite: true, Output: "text"}, deps); err != nil {
+		t.Fatalf("create with overwrite: %v", err)
+	}
+	if !strings.Contains(out.String(), report.Digest) {
+		t.Fatalf("expected rebuilt bundle with the same digest, got:\n%s", out.String())
+	}
+}
+
+func TestBundleCreate_RequiresSpec(t *testing.T) {
+	cmd, _ := newCommand()
+	if err := bundlecmd.RunCreateForTest(cmd, bundlecmd.CreateOptions{File: "bundle.tar", Output: "text"}, bundlecmd.CreateDeps{}); !errors.Is(err, bundlecmd.ErrSpecRequired()) {
+		t.Fatalf("expected missing spec error, got %v", err)
+	}
+	if err := bundlecmd.RunCreateForTest(cmd, bundlecmd.CreateOptions{SpecPath: "spec.yaml", Output: "yaml"}, bundlecmd.CreateDeps{}); !errors.Is(err, bundlecmd.ErrUnsupportedOutput()) {
+		t.Fatalf("expected unsupported output error, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	pkgbundle "github.com/dobrovols/chainctl/pkg/bundle"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+// InspectOptions holds CLI flags for bundle inspect.
+type InspectOptions struct {
+	File   string
+	Output string
+}
+
+type inspectReport struct {
+	File      string                   `json:"file"`
+	Version   string                   `json:"version"`
+	Charts    []pkgbundle.ChartRecord  `json:"charts"`
+	Images    []pkgbundle.ImageRecord  `json:"images"`
+	Binaries  []pkgbundle.BinaryRecord `json:"binaries"`
+	Checksums map[string]string        `json:"checksums"`
+}
+
+var errBundleRequired = errors.New("bundle file is required")
+
+// ErrBundleRequired exposes the missing bundle sentinel.
+func ErrBundleRequired() error { return errBundleRequired }
+
+// NewInspectCommand constructs the `chainctl bundle inspect` command.
+func NewInspectCommand() *cobra.Command {
+	opts := InspectOptions{}
+	cmd := &cobra.Command{
+		Use:   "inspect BUNDLE",
+		Short: "Show the manifest of a bundle tarball",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			opts.File = args[0]
+			return runInspect(cmd, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// RunInspectForTest executes the inspect flow.
+func RunInspectForTest(cmd *cobra.Command, opts InspectOptions) error {
+	cmd.SilenceUsage = true
+	return runInspect(cmd, opts)
+}
+
+func runInspect(cmd *cobra.Command, opts InspectOptions) (err error) {
+	if opts.Output != "text" && opts.Output != "json" {
+		return errUnsupportedOutput
+	}
+	if strings.TrimSpace(opts.File) == "" {
+		return errBundleRequired
+	}
+
+	logger, err := telemetry.NewCommandLogger(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{"file": opts.File}
+	logWorkflowStart(logger, stepBundleInspect, metadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, stepBundleInspect, metadata, err)
+		}
+	}()
+
+	manifest, err := pkgbundle.ReadManifest(opts.File)
+	if err != nil {
+		return err
+	}
+	metadata["version"] = manifest.Version
+	metadata["files"] = strconv.Itoa(len(manifest.Checksums))
+
+	logWorkflowSuccess(logger, stepBundleInspect, metadata)
+	return emitInspectOutput(cmd, inspectReport{
+		File:      opts.File,
+		Version:   manifest.Version,
+		Charts:    nonNil(manifest.Charts),
+		Images:    nonNil(manifest.Images),
+		Binaries:  nonNil(manifest.Binaries),
+		Checksums: manifest.Checksums,
+	}, opts.Output)
+}
+
+func emitInspectOutput(cmd *cobra.Command, report inspectReport, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetEscapeHTML(false)
+		return enc.Encode(report)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Bundle: %s\nVersion: %s\n", report.File, report.Version)
+
+	fmt.Fprintln(out, "\nCharts:")
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tVERSION\tPATH")
+	for _, chart := range report.Charts {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", chart.Name, chart.Version, chart.Path)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "\nImages:")
+	tw = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTAG\tDIGEST\tPATH")
+	for _, image := range report.Images {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", image.Name, image.Tag, orDash(image.Digest), orDash(image.Path))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "\nBinaries:")
+	tw = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tVERSION\tPLATFORM\tPATH")
+	for _, binary := range report.Binaries {
+		fmt.Fprintf(tw, "%s\t%s\t%s/%s\t%s\n", binary.Name, binary.Version, binary.OS, binary.Arch, binary.Path)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "\nChecksums:")
+	tw = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FILE\tSHA256")
+	files := make([]string, 0, len(report.Checksums))
+	for file := range report.Checksums {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		fmt.Fprintf(tw, "%s\t%s\n", file, report.Checksums[file])
+	}
+	return tw.Flush()
+}
+
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
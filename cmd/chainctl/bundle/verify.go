@@ -0,0 +1,146 @@
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	pkgbundle "github.com/dobrovols/chainctl/pkg/bundle"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+// VerifyOptions holds CLI flags for bundle verify.
+type VerifyOptions struct {
+	File   string
+	Output string
+}
+
+type verifyReport struct {
+	File       string   `json:"file"`
+	Digest     string   `json:"digest"`
+	Version    string   `json:"version"`
+	Files      int      `json:"files"`
+	Valid      bool     `json:"valid"`
+	Mismatched []string `json:"mismatched"`
+	Unlisted   []string `json:"unlisted"`
+	Missing    []string `json:"missing"`
+}
+
+var errVerificationFailed = errors.New("bundle failed verification")
+
+// ErrVerificationFailed exposes the sentinel returned when a bundle does not match its manifest.
+func ErrVerificationFailed() error { return errVerificationFailed }
+
+// NewVerifyCommand constructs the `chainctl bundle verify` command.
+func NewVerifyCommand() *cobra.Command {
+	opts := VerifyOptions{}
+	cmd := &cobra.Command{
+		Use:   "verify BUNDLE",
+		Short: "Check bundle contents against the manifest checksums without installing",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			opts.File = args[0]
+			return runVerify(cmd, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// RunVerifyForTest executes the verify flow.
+func RunVerifyForTest(cmd *cobra.Command, opts VerifyOptions) error {
+	cmd.SilenceUsage = true
+	return runVerify(cmd, opts)
+}
+
+func runVerify(cmd *cobra.Command, opts VerifyOptions) (err error) {
+	if opts.Output != "text" && opts.Output != "json" {
+		return errUnsupportedOutput
+	}
+	if strings.TrimSpace(opts.File) == "" {
+		return errBundleRequired
+	}
+
+	logger, err := telemetry.NewCommandLogger(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{"file": opts.File}
+	logWorkflowStart(logger, stepBundleVerify, metadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, stepBundleVerify, metadata, err)
+		}
+	}()
+
+	result, err := pkgbundle.Verify(opts.File)
+	if err != nil {
+		return err
+	}
+	discrepancies := result.Discrepancies()
+	metadata["digest"] = result.Digest
+	metadata["files"] = strconv.Itoa(result.Files)
+	metadata["discrepancies"] = strconv.Itoa(discrepancies)
+
+	report := verifyReport{
+		File:       opts.File,
+		Digest:     result.Digest,
+		Version:    result.Manifest.Version,
+		Files:      result.Files,
+		Valid:      discrepancies == 0,
+		Mismatched: result.Mismatched,
+		Unlisted:   result.Unlisted,
+		Missing:    result.Missing,
+	}
+	if err := emitVerifyOutput(cmd, report, opts.Output); err != nil {
+		return err
+	}
+	if discrepancies > 0 {
+		return fmt.Errorf("%w: %d discrepancies", errVerificationFailed, discrepancies)
+	}
+	logWorkflowSuccess(logger, stepBundleVerify, metadata)
+	return nil
+}
+
+func emitVerifyOutput(cmd *cobra.Command, report verifyReport, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetEscapeHTML(false)
+		return enc.Encode(report)
+	}
+
+	out := cmd.OutOrStdout()
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Bundle:\t%s\n", report.File)
+	fmt.Fprintf(tw, "Version:\t%s\n", report.Version)
+	fmt.Fprintf(tw, "Digest:\t%s\n", report.Digest)
+	fmt.Fprintf(tw, "Files checked:\t%d\n", report.Files)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if report.Valid {
+		fmt.Fprintln(out, "All files match the manifest checksums")
+		return nil
+	}
+
+	tw = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\nPROBLEM\tFILE")
+	for _, file := range report.Mismatched {
+		fmt.Fprintf(tw, "checksum mismatch\t%s\n", file)
+	}
+	for _, file := range report.Unlisted {
+		fmt.Fprintf(tw, "not in manifest\t%s\n", file)
+	}
+	for _, file := range report.Missing {
+		fmt.Fprintf(tw, "missing from archive\t%s\n", file)
+	}
+	return tw.Flush()
+}
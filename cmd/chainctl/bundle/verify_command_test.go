@@ -0,0 +1,142 @@
+package bundle_test
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	bundlecmd "github.com/dobrovols/chainctl/cmd/chainctl/bundle"
+)
+
+func createBundle(t *testing.T) string {
+	t.Helper()
+	spec, resolver := writeSpec(t)
+	file := filepath.Join(t.TempDir(), "bundle.tar")
+	cmd, _ := newCommand()
+	deps := bundlecmd.CreateDeps{Charts: bundlecmd.NewResolverFetcher(resolver)}
+	if err := bundlecmd.RunCreateForTest(cmd, bundlecmd.CreateOptions{SpecPath: spec, File: file, Output: "text"}, deps); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	return file
+}
+
+func TestBundleInspect_PrintsManifest(t *testing.T) {
+	file := createBundle(t)
+
+	cmd, out := newCommand()
+	if err := bundlecmd.RunInspectForTest(cmd, bundlecmd.InspectOptions{File: file, Output: "text"}); err != nil {
+		t.Fatalf("inspect: %v", err)
+	}
+	for _, want := range []string{"Version: 0.3.0", "charts/demo", "registry.example.com/apps/demo", "linux/amd64", "binaries/linux-amd64/k3s"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+
+	cmd, out = newCommand()
+	if err := bundlecmd.RunInspectForTest(cmd, bundlecmd.InspectOptions{File: file, Output: "json"}); err != nil {
+		t.Fatalf("inspect json: %v", err)
+	}
+	var report struct {
+		Version   string            `json:"version"`
+		Images    []json.RawMessage `json:"images"`
+		Checksums map[string]string `json:"checksums"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if report.Version != "0.3.0" || len(report.Images) != 1 || len(report.Checksums) != 4 {
+		t.Fatalf("unexpected report %s", out.String())
+	}
+}
+
+func TestBundleVerify_DetectsTampering(t *testing.T) {
+	file := createBundle(t)
+
+	cmd, out := newCommand()
+	if err := bundlecmd.RunVerifyForTest(cmd, bundlecmd.VerifyOptions{File: file, Output: "text"}); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if !strings.Contains(out.String(), "All files match") {
+		t.Fatalf("expected clean verification, got:\n%s", out.String())
+	}
+
+	appendTarEntry(t, file, "images/extra.tar", "unexpected")
+
+	cmd, out = newCommand()
+	err := bundlecmd.RunVerifyForTest(cmd, bundlecmd.VerifyOptions{File: file, Output: "json"})
+	if !errors.Is(err, bundlecmd.ErrVerificationFailed()) {
+		t.Fatalf("expected verification failure, got %v", err)
+	}
+	var report struct {
+		Valid    bool     `json:"valid"`
+		Unlisted []string `json:"unlisted"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if report.Valid || len(report.Unlisted) != 1 || report.Unlisted[0] != "images/extra.tar" {
+		t.Fatalf("unexpected report %s", out.String())
+	}
+}
+
+func TestBundleVerify_ValidatesOptions(t *testing.T) {
+	cmd, _ := newCommand()
+	if err := bundlecmd.RunVerifyForTest(cmd, bundlecmd.VerifyOptions{Output: "text"}); !errors.Is(err, bundlecmd.ErrBundleRequired()) {
+		t.Fatalf("expected missing bundle error, got %v", err)
+	}
+	if err := bundlecmd.RunInspectForTest(cmd, bundlecmd.InspectOptions{File: "bundle.tar", Output: "yaml"}); !errors.Is(err, bundlecmd.ErrUnsupportedOutput()) {
+		t.Fatalf("expected unsupported output error, got %v", err)
+	}
+}
+
+// appendTarEntry rewrites the tarball with an extra entry appended.
+func appendTarEntry(t *testing.T, path, name, content string) {
+	t.Helper()
+	src, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open bundle: %v", err)
+	}
+	defer src.Close()
+	dst, err := os.Create(path + ".new")
+	if err != nil {
+		t.Fatalf("create bundle: %v", err)
+	}
+	tr := tar.NewReader(src)
+	tw := tar.NewWriter(dst)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read bundle: %v", err)
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("write header: %v", err)
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			t.Fatalf("copy entry: %v", err)
+		}
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}); err != nil {
+		t.Fatalf("write header: %v", err)
+	}
+	if _, err := tw.Write([]byte(content)); err != nil {
+		t.Fatalf("write entry: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar: %v", err)
+	}
+	if err := dst.Close(); err != nil {
+		t.Fatalf("close bundle: %v", err)
+	}
+	if err := os.Rename(path+".new", path); err != nil {
+		t.Fatalf("replace bundle: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dobrovols/chainctl/internal/state"
+	"github.com/dobrovols/chainctl/pkg/chartcache"
+)
+
+// chartStore abstracts the chart cache for listing and garbage collection.
+type chartStore interface {
+	List() ([]chartcache.Entry, error)
+	Prune(chartcache.PruneOptions) (chartcache.PruneResult, error)
+}
+
+var errUnsupportedOutput = errors.New("unsupported output format")
+
+// ErrUnsupportedOutput exposes the sentinel.
+func ErrUnsupportedOutput() error { return errUnsupportedOutput }
+
+// NewCacheCommand creates the `chainctl cache` parent command.
+func NewCacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and garbage-collect the local chart cache",
+	}
+
+	cmd.AddCommand(NewListCommand())
+	cmd.AddCommand(NewPruneCommand())
+
+	return cmd
+}
+
+func bindCacheDirFlag(cmd *cobra.Command, dir *string) {
+	cmd.Flags().StringVar(dir, "cache-dir", "", "Chart cache directory (defaults to $XDG_CACHE_HOME/chainctl/charts)")
+}
+
+func defaultStore(dir string) (chartStore, error) {
+	if strings.TrimSpace(dir) == "" {
+		var err error
+		if dir, err = state.ChartCacheDirectory(); err != nil {
+			return nil, fmt.Errorf("determine chart cache directory: %w", err)
+		}
+	}
+	return chartcache.New(dir), nil
+}
+
+func shortDigest(digest string) string {
+	_, hex, found := strings.Cut(digest, ":")
+	if !found {
+		hex = digest
+	}
+	if len(hex) > 12 {
+		hex = hex[:12]
+	}
+	return hex
+}
+
+// formatBytes renders sizes with binary units, matching the quantities accepted by --max-size.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ci", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,127 @@
+package cache_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	cachecmd "github.com/dobrovols/chainctl/cmd/chainctl/cache"
+	"github.com/dobrovols/chainctl/internal/cli"
+	"github.com/dobrovols/chainctl/pkg/chartcache"
+)
+
+type storeStub struct {
+	entries   []chartcache.Entry
+	pruneOpts chartcache.PruneOptions
+	result    chartcache.PruneResult
+	err       error
+}
+
+func (s *storeStub) List() ([]chartcache.Entry, error) { return s.entries, s.err }
+
+func (s *storeStub) Prune(opts chartcache.PruneOptions) (chartcache.PruneResult, error) {
+	s.pruneOpts = opts
+	return s.result, s.err
+}
+
+func sampleEntry() chartcache.Entry {
+	used := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	return chartcache.Entry{
+		Digest:     "sha256:0123456789abcdef0123",
+		References: []string{"oci://example.com/demo:0.1.0"},
+		ChartFile:  "demo-0.1.0.tgz",
+		Size:       2048,
+		StoredAt:   used,
+		LastUsed:   used,
+	}
+}
+
+func TestCacheListCommand_TextOutput(t *testing.T) {
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+
+	store := &storeStub{entries: []chartcache.Entry{sampleEntry()}}
+	if err := cachecmd.RunListForTest(cmd, cachecmd.ListOptions{Output: "text"}, store); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	for _, want := range []string{"0123456789ab", "demo-0.1.0.tgz", "2.0Ki", "oci://example.com/demo:0.1.0", "Total: 1 charts"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestCacheListCommand_ReadsCacheDirectory(t *testing.T) {
+	dir := t.TempDir()
+	chartPath := filepath.Join(t.TempDir(), "demo-0.1.0.tgz")
+	if err := os.WriteFile(chartPath, []byte("chart"), 0o600); err != nil {
+		t.Fatalf("write chart: %v", err)
+	}
+	if _, err := chartcache.New(dir).Put("oci://example.com/demo:0.1.0", "sha256:abc123", chartPath); err != nil {
+		t.Fatalf("seed cache: %v", err)
+	}
+
+	root := cli.NewRootCommand()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(io.Discard)
+	root.SetArgs([]string{"cache", "list", "--cache-dir", dir, "--output", "json"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	var payload struct {
+		Entries    []map[string]any `json:"entries"`
+		TotalBytes int64            `json:"totalBytes"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, out.String())
+	}
+	if len(payload.Entries) != 1 || payload.Entries[0]["digest"] != "sha256:abc123" || payload.TotalBytes != 5 {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+}
+
+func TestCachePruneCommand_ParsesLimits(t *testing.T) {
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+
+	store := &storeStub{result: chartcache.PruneResult{Removed: []chartcache.Entry{sampleEntry()}, ReclaimedBytes: 2048}}
+	opts := cachecmd.PruneOptions{MaxAge: 72 * time.Hour, MaxSize: "1Mi", DryRun: true, Output: "text"}
+	if err := cachecmd.RunPruneForTest(cmd, opts, store); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if store.pruneOpts.MaxSize != 1<<20 || store.pruneOpts.MaxAge != 72*time.Hour || !store.pruneOpts.DryRun {
+		t.Fatalf("unexpected prune options %+v", store.pruneOpts)
+	}
+	if !strings.Contains(out.String(), "Would remove 0123456789ab") {
+		t.Fatalf("expected dry-run listing, got %s", out.String())
+	}
+}
+
+func TestCachePruneCommand_RequiresLimit(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	err := cachecmd.RunPruneForTest(cmd, cachecmd.PruneOptions{Output: "text"}, &storeStub{})
+	if !errors.Is(err, cachecmd.ErrPruneLimitRequired()) {
+		t.Fatalf("expected limit required error, got %v", err)
+	}
+	err = cachecmd.RunPruneForTest(cmd, cachecmd.PruneOptions{MaxSize: "lots", Output: "text"}, &storeStub{})
+	if !errors.Is(err, cachecmd.ErrInvalidMaxSize()) {
+		t.Fatalf("expected invalid size error, got %v", err)
+	}
+}
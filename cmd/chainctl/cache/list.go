@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dobrovols/chainctl/pkg/chartcache"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+// ListOptions holds CLI flags for cache list.
+type ListOptions struct {
+	CacheDir string
+	Output   string
+}
+
+type listedEntry struct {
+	Digest     string    `json:"digest"`
+	References []string  `json:"references"`
+	Chart      string    `json:"chart"`
+	Size       int64     `json:"size"`
+	StoredAt   time.Time `json:"storedAt"`
+	LastUsed   time.Time `json:"lastUsed"`
+}
+
+// NewListCommand constructs the `chainctl cache list` command.
+func NewListCommand() *cobra.Command {
+	opts := ListOptions{}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List cached charts with their digest, size, and last use",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			store, err := defaultStore(opts.CacheDir)
+			if err != nil {
+				return err
+			}
+			return runList(cmd, opts, store)
+		},
+	}
+
+	bindCacheDirFlag(cmd, &opts.CacheDir)
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// RunListForTest executes the list flow against the provided store.
+func RunListForTest(cmd *cobra.Command, opts ListOptions, store chartStore) error {
+	cmd.SilenceUsage = true
+	return runList(cmd, opts, store)
+}
+
+func runList(cmd *cobra.Command, opts ListOptions, store chartStore) (err error) {
+	if opts.Output != "text" && opts.Output != "json" {
+		return errUnsupportedOutput
+	}
+	logger, err := telemetry.NewCommandLogger(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{}
+	logWorkflowStart(logger, stepCacheList, metadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, stepCacheList, metadata, err)
+		}
+	}()
+
+	entries, err := store.List()
+	if err != nil {
+		return err
+	}
+	var total int64
+	for _, entry := range entries {
+		total += entry.Size
+	}
+	metadata["entries"] = fmt.Sprint(len(entries))
+	metadata["bytes"] = fmt.Sprint(total)
+
+	if err := renderList(cmd, opts.Output, entries, total); err != nil {
+		return err
+	}
+	logWorkflowSuccess(logger, stepCacheList, metadata)
+	return nil
+}
+
+func renderList(cmd *cobra.Command, output string, entries []chartcache.Entry, total int64) error {
+	if output == "json" {
+		listed := make([]listedEntry, 0, len(entries))
+		for _, entry := range entries {
+			listed = append(listed, toListedEntry(entry))
+		}
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{"entries": listed, "totalBytes": total})
+	}
+
+	out := cmd.OutOrStdout()
+	if len(entries) == 0 {
+		fmt.Fprintln(out, "Chart cache is empty")
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DIGEST\tCHART\tSIZE\tLAST USED\tREFERENCES")
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			shortDigest(entry.Digest), entry.ChartFile, formatBytes(entry.Size),
+			entry.LastUsed.Format(time.RFC3339), strings.Join(entry.References, ","))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Total: %d charts, %s\n", len(entries), formatBytes(total))
+	return nil
+}
+
+func toListedEntry(entry chartcache.Entry) listedEntry {
+	return listedEntry{
+		Digest:     entry.Digest,
+		References: entry.References,
+		Chart:      entry.ChartFile,
+		Size:       entry.Size,
+		StoredAt:   entry.StoredAt,
+		LastUsed:   entry.LastUsed,
+	}
+}
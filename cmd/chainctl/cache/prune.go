@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/dobrovols/chainctl/pkg/chartcache"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+// PruneOptions holds CLI flags for cache prune.
+type PruneOptions struct {
+	CacheDir string
+	MaxAge   time.Duration
+	MaxSize  string
+	DryRun   bool
+	Output   string
+}
+
+var (
+	errPruneLimitRequired = errors.New("at least one of --max-age or --max-size must be set")
+	errInvalidMaxSize     = errors.New("invalid --max-size")
+)
+
+// ErrPruneLimitRequired exposes the missing limit sentinel.
+func ErrPruneLimitRequired() error { return errPruneLimitRequired }
+
+// ErrInvalidMaxSize exposes the size parsing sentinel.
+func ErrInvalidMaxSize() error { return errInvalidMaxSize }
+
+// NewPruneCommand constructs the `chainctl cache prune` command.
+func NewPruneCommand() *cobra.Command {
+	opts := PruneOptions{}
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove cached charts by age and evict least recently used charts over a size budget",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			store, err := defaultStore(opts.CacheDir)
+			if err != nil {
+				return err
+			}
+			return runPrune(cmd, opts, store)
+		},
+	}
+
+	bindCacheDirFlag(cmd, &opts.CacheDir)
+	cmd.Flags().DurationVar(&opts.MaxAge, "max-age", 0, "Remove charts not used within this duration (e.g. 720h)")
+	cmd.Flags().StringVar(&opts.MaxSize, "max-size", "", "Evict least recently used charts until the cache fits this size (e.g. 500Mi, 2Gi)")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Report the charts that would be removed without deleting them")
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// RunPruneForTest executes the prune flow against the provided store.
+func RunPruneForTest(cmd *cobra.Command, opts PruneOptions, store chartStore) error {
+	cmd.SilenceUsage = true
+	return runPrune(cmd, opts, store)
+}
+
+func runPrune(cmd *cobra.Command, opts PruneOptions, store chartStore) (err error) {
+	if opts.Output != "text" && opts.Output != "json" {
+		return errUnsupportedOutput
+	}
+	maxSize, err := parseMaxSize(opts.MaxSize)
+	if err != nil {
+		return err
+	}
+	if opts.MaxAge <= 0 && maxSize <= 0 {
+		return errPruneLimitRequired
+	}
+
+	logger, err := telemetry.NewCommandLogger(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{"dryRun": fmt.Sprint(opts.DryRun)}
+	if opts.MaxAge > 0 {
+		metadata["maxAge"] = opts.MaxAge.String()
+	}
+	if maxSize > 0 {
+		metadata["maxSize"] = fmt.Sprint(maxSize)
+	}
+	logWorkflowStart(logger, stepCachePrune, metadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, stepCachePrune, metadata, err)
+		}
+	}()
+
+	result, err := store.Prune(chartcache.PruneOptions{MaxAge: opts.MaxAge, MaxSize: maxSize, DryRun: opts.DryRun})
+	if err != nil {
+		return err
+	}
+	metadata["removed"] = fmt.Sprint(len(result.Removed))
+	metadata["reclaimedBytes"] = fmt.Sprint(result.ReclaimedBytes)
+
+	if err := renderPrune(cmd, opts, result); err != nil {
+		return err
+	}
+	logWorkflowSuccess(logger, stepCachePrune, metadata)
+	return nil
+}
+
+func parseMaxSize(raw string) (int64, error) {
+	if strings.TrimSpace(raw) == "" {
+		return 0, nil
+	}
+	quantity, err := resource.ParseQuantity(strings.TrimSpace(raw))
+	if err != nil {
+		return 0, fmt.Errorf("%w %q: %v", errInvalidMaxSize, raw, err)
+	}
+	size := quantity.Value()
+	if size < 0 {
+		return 0, fmt.Errorf("%w %q: must not be negative", errInvalidMaxSize, raw)
+	}
+	return size, nil
+}
+
+func renderPrune(cmd *cobra.Command, opts PruneOptions, result chartcache.PruneResult) error {
+	if opts.Output == "json" {
+		removed := make([]listedEntry, 0, len(result.Removed))
+		for _, entry := range result.Removed {
+			removed = append(removed, toListedEntry(entry))
+		}
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{
+			"dryRun":         opts.DryRun,
+			"removed":        removed,
+			"reclaimedBytes": result.ReclaimedBytes,
+			"remainingBytes": result.RemainingBytes,
+		})
+	}
+
+	out := cmd.OutOrStdout()
+	verb := "Removed"
+	if opts.DryRun {
+		verb = "Would remove"
+	}
+	for _, entry := range result.Removed {
+		fmt.Fprintf(out, "%s %s (%s, %s)\n", verb, shortDigest(entry.Digest), entry.ChartFile, formatBytes(entry.Size))
+	}
+	fmt.Fprintf(out, "%s %d charts, reclaimed %s; %s remaining\n", verb, len(result.Removed), formatBytes(result.ReclaimedBytes), formatBytes(result.RemainingBytes))
+	return nil
+}
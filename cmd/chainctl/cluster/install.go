@@ -1,6 +1,7 @@
 package cluster
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,10 +16,12 @@ import (
 
 	"github.com/dobrovols/chainctl/cmd/chainctl/declarative"
 	"github.com/dobrovols/chainctl/internal/config"
+	"github.com/dobrovols/chainctl/internal/phases"
 	"github.com/dobrovols/chainctl/internal/validation"
 	"github.com/dobrovols/chainctl/pkg/bootstrap"
 	"github.com/dobrovols/chainctl/pkg/bundle"
 	"github.com/dobrovols/chainctl/pkg/helm"
+	"github.com/dobrovols/chainctl/pkg/readiness"
 	"github.com/dobrovols/chainctl/pkg/telemetry"
 )
 
@@ -27,12 +30,15 @@ type InstallOptions struct {
 	Bootstrap        bool
 	ClusterEndpoint  string
 	K3sVersion       string
-	ValuesFile       string
+	ValuesFiles      []string
+	SetValues        []string
+	SetStringValues  []string
 	ValuesPassphrase string
 	BundlePath       string
 	Airgapped        bool
 	DryRun           bool
 	Output           string
+	WaitTimeout      time.Duration
 }
 
 // Bootstrapper performs k3s bootstrap when required.
@@ -45,6 +51,11 @@ type HelmInstaller interface {
 	Install(*config.Profile, *bundle.Bundle) error
 }
 
+// ReadinessChecker evaluates the readiness of workloads owned by the release.
+type ReadinessChecker interface {
+	Check(context.Context, []readiness.Resource) ([]readiness.Status, error)
+}
+
 // InstallDeps configures dependencies for the install command.
 type InstallDeps struct {
 	Inspector           validation.SystemInspector
@@ -54,6 +65,7 @@ type InstallDeps struct {
 	TelemetryEmitter    func(io.Writer) (*telemetry.Emitter, error)
 	ClusterValidator    func(*rest.Config) error
 	ClusterConfigLoader func(*config.Profile) (*rest.Config, error)
+	Readiness           ReadinessChecker
 }
 
 var (
@@ -76,7 +88,7 @@ var defaultInstallDeps = InstallDeps{
 	Inspector:           validation.DefaultInspector{},
 	BundleLoader:        bundle.Load,
 	Bootstrapper:        noopBootstrap{},
-	HelmInstaller:       noopHelm{},
+	HelmInstaller:       helm.NewInstaller(helm.NewSDKExecutor()),
 	TelemetryEmitter:    telemetry.NewEmitter,
 	ClusterValidator:    validation.ValidateCluster,
 	ClusterConfigLoader: loadClusterConfig,
@@ -106,12 +118,15 @@ func NewInstallCommand() *cobra.Command {
 	cmd.Flags().BoolVar(&opts.Bootstrap, "bootstrap", false, "Bootstrap a new k3s cluster before install")
 	cmd.Flags().StringVar(&opts.ClusterEndpoint, "cluster-endpoint", "", "Existing cluster API endpoint (reuse mode)")
 	cmd.Flags().StringVar(&opts.K3sVersion, "k3s-version", "", "Target k3s version for bootstrap/upgrade")
-	cmd.Flags().StringVar(&opts.ValuesFile, "values-file", "", "Encrypted Helm values file path")
+	cmd.Flags().StringArrayVar(&opts.ValuesFiles, "values-file", nil, "Helm values file, encrypted or plaintext; repeatable, later files take precedence")
+	cmd.Flags().StringArrayVar(&opts.SetValues, "set", nil, "Set a Helm value (key=value); repeatable, applied after values files")
+	cmd.Flags().StringArrayVar(&opts.SetStringValues, "set-string", nil, "Set a Helm value as a string (key=value); repeatable, applied after --set")
 	cmd.Flags().StringVar(&opts.ValuesPassphrase, "values-passphrase", "", "Passphrase for encrypted values")
 	cmd.Flags().StringVar(&opts.BundlePath, "bundle-path", "", "Mounted bundle path when air-gapped")
 	cmd.Flags().BoolVar(&opts.Airgapped, "airgapped", false, "Use air-gapped mode (requires --bundle-path)")
 	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Run validations without applying changes")
 	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+	cmd.Flags().DurationVar(&opts.WaitTimeout, "wait-timeout", defaultWaitTimeout, "Maximum time to wait for release workloads to become ready (0 skips the check)")
 	markDeclarative(cmd)
 
 	return cmd
@@ -161,6 +176,11 @@ func runInstall(cmd *cobra.Command, opts InstallOptions, deps InstallDeps) (err
 		return err
 	}
 
+	validator, _ := helmInstaller.(helm.ValuesValidator)
+	if err = phases.ExecuteValidate(tel, validator, profile, bundleInstance, map[string]string{"mode": string(profile.Mode)}, logger); err != nil {
+		return err
+	}
+
 	helmArgsDryRun := buildHelmCommandArgs(profile, opts, true)
 	if opts.DryRun {
 		return handleInstallDryRun(cmd, profile, bundleInstance, opts, logger, commandMetadata, helmArgsDryRun, bootstrapHasLogging, helmHasLogging)
@@ -171,7 +191,19 @@ func runInstall(cmd *cobra.Command, opts InstallOptions, deps InstallDeps) (err
 	}
 
 	helmArgs := buildHelmCommandArgs(profile, opts, false)
-	if err = executeInstallHelmPhase(tel, helmInstaller, profile, bundleInstance, logger, commandMetadata, helmArgs, helmHasLogging); err != nil {
+	release, err := executeInstallHelmPhase(tel, helmInstaller, profile, bundleInstance, logger, commandMetadata, helmArgs, helmHasLogging)
+	if err != nil {
+		return err
+	}
+	err = phases.ExecuteVerify(cmd.Context(), tel, phases.VerifyOptions{
+		Manifest:  release.Manifest,
+		Namespace: profile.HelmNamespace,
+		Timeout:   opts.WaitTimeout,
+		Metadata:  map[string]string{"mode": string(profile.Mode)},
+		Prober:    func() (readiness.Prober, error) { return readinessChecker(profile, deps) },
+		Logger:    logger,
+	})
+	if err != nil {
 		return err
 	}
 
@@ -180,12 +212,21 @@ func runInstall(cmd *cobra.Command, opts InstallOptions, deps InstallDeps) (err
 }
 
 func validateInstallOptions(opts InstallOptions) error {
-	if strings.TrimSpace(opts.ValuesFile) == "" {
+	if !hasValue(opts.ValuesFiles) {
 		return errValuesFileRequired
 	}
 	return nil
 }
 
+func hasValue(values []string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func selectInspector(deps InstallDeps) validation.SystemInspector {
 	if deps.Inspector == nil {
 		return validation.DefaultInspector{}
@@ -350,21 +391,29 @@ func executeInstallHelmPhase(
 	metadata map[string]string,
 	helmArgs []string,
 	helmHasLogging bool,
-) error {
+) (helm.ReleaseInfo, error) {
+	var release helm.ReleaseInfo
 	phaseMetadata := map[string]string{"mode": string(profile.Mode)}
 	err := tel.EmitPhase(telemetry.PhaseHelm, phaseMetadata, func() error {
-		return installer.Install(profile, bundleInstance)
+		applier, ok := installer.(helm.ReleaseApplier)
+		if !ok {
+			return installer.Install(profile, bundleInstance)
+		}
+		var applyErr error
+		release, applyErr = applier.Apply(profile, bundleInstance)
+		helm.AddReleaseMetadata(phaseMetadata, release)
+		return applyErr
 	})
 	if err != nil {
 		if !helmHasLogging {
 			logCommandEntry(logger, stepHelm, helmArgs, err.Error(), telemetry.SeverityError, metadata, err)
 		}
-		return err
+		return release, err
 	}
 	if !helmHasLogging {
 		logCommandEntry(logger, stepHelm, helmArgs, "", telemetry.SeverityInfo, metadata, nil)
 	}
-	return nil
+	return release, nil
 }
 
 func prepareBundle(profile *config.Profile, opts InstallOptions, deps InstallDeps) (*bundle.Bundle, error) {
@@ -389,7 +438,9 @@ func buildProfile(opts InstallOptions) (*config.Profile, error) {
 		Mode:                mode,
 		ClusterEndpoint:     opts.ClusterEndpoint,
 		K3sVersion:          opts.K3sVersion,
-		EncryptedValuesPath: opts.ValuesFile,
+		ValuesFiles:         opts.ValuesFiles,
+		SetValues:           opts.SetValues,
+		SetStringValues:     opts.SetStringValues,
 		ValuesPassphrase:    opts.ValuesPassphrase,
 		AirgappedBundlePath: opts.BundlePath,
 		Offline:             opts.Airgapped,
@@ -445,7 +496,15 @@ func buildHelmCommandArgs(profile *config.Profile, opts InstallOptions, dryRun b
 	if ns := strings.TrimSpace(profile.HelmNamespace); ns != "" {
 		args = append(args, "--namespace", ns)
 	}
-	args = append(args, "--values", profile.EncryptedFile)
+	for _, path := range profile.ValuesLayers() {
+		args = append(args, "--values", path)
+	}
+	for _, expr := range profile.SetValues {
+		args = append(args, "--set", expr)
+	}
+	for _, expr := range profile.SetStringValues {
+		args = append(args, "--set-string", expr)
+	}
 	if profile.Airgapped && strings.TrimSpace(opts.BundlePath) != "" {
 		args = append(args, "--bundle-path", opts.BundlePath)
 	}
@@ -2,12 +2,14 @@ package cluster_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"k8s.io/client-go/rest"
@@ -15,6 +17,8 @@ import (
 	clustercmd "github.com/dobrovols/chainctl/cmd/chainctl/cluster"
 	"github.com/dobrovols/chainctl/internal/config"
 	"github.com/dobrovols/chainctl/pkg/bundle"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	"github.com/dobrovols/chainctl/pkg/readiness"
 	"github.com/dobrovols/chainctl/pkg/telemetry"
 )
 
@@ -63,7 +67,7 @@ func telemetryStub(w io.Writer) (*telemetry.Emitter, error) {
 func TestNewInstallCommandRegistersFlags(t *testing.T) {
 	cmd := clustercmd.NewInstallCommand()
 	for _, name := range []string{
-		"bootstrap", "cluster-endpoint", "k3s-version", "values-file", "values-passphrase", "bundle-path", "airgapped", "dry-run", "output",
+		"bootstrap", "cluster-endpoint", "k3s-version", "values-file", "values-passphrase", "bundle-path", "airgapped", "dry-run", "output", "wait-timeout", "set", "set-string",
 	} {
 		if cmd.Flag(name) == nil {
 			t.Fatalf("expected flag %s to be defined", name)
@@ -102,7 +106,7 @@ func TestClusterInstallCommand_TextSuccess(t *testing.T) {
 
 	opts := clustercmd.InstallOptions{
 		Bootstrap:        true,
-		ValuesFile:       "/tmp/values.enc",
+		ValuesFiles:      []string{"/tmp/values.enc"},
 		ValuesPassphrase: "secret",
 		Output:           "text",
 	}
@@ -146,7 +150,7 @@ func TestClusterInstallCommand_DryRunSkipsBootstrap(t *testing.T) {
 
 	opts := clustercmd.InstallOptions{
 		Bootstrap:        true,
-		ValuesFile:       "/tmp/values.enc",
+		ValuesFiles:      []string{"/tmp/values.enc"},
 		ValuesPassphrase: "secret",
 		DryRun:           true,
 		Output:           "text",
@@ -189,7 +193,7 @@ func TestClusterInstallCommand_JSONOutput(t *testing.T) {
 
 	opts := clustercmd.InstallOptions{
 		Bootstrap:        true,
-		ValuesFile:       "/tmp/values.enc",
+		ValuesFiles:      []string{"/tmp/values.enc"},
 		ValuesPassphrase: "secret",
 		Output:           "json",
 	}
@@ -224,7 +228,7 @@ func TestClusterInstallCommand_AirgappedRequiresBundle(t *testing.T) {
 
 	opts := clustercmd.InstallOptions{
 		Bootstrap:        true,
-		ValuesFile:       "/tmp/values.enc",
+		ValuesFiles:      []string{"/tmp/values.enc"},
 		ValuesPassphrase: "secret",
 		Airgapped:        true,
 	}
@@ -251,7 +255,7 @@ func TestClusterInstallCommand_PreflightFailure(t *testing.T) {
 
 	opts := clustercmd.InstallOptions{
 		Bootstrap:        true,
-		ValuesFile:       "/tmp/values.enc",
+		ValuesFiles:      []string{"/tmp/values.enc"},
 		ValuesPassphrase: "secret",
 	}
 
@@ -288,7 +292,7 @@ func TestClusterInstallCommand_ReuseValidatesCluster(t *testing.T) {
 	opts := clustercmd.InstallOptions{
 		Bootstrap:        false,
 		ClusterEndpoint:  "https://cluster.local",
-		ValuesFile:       "/tmp/values.enc",
+		ValuesFiles:      []string{"/tmp/values.enc"},
 		ValuesPassphrase: "secret",
 		Output:           "text",
 	}
@@ -319,7 +323,7 @@ func TestClusterInstallCommand_UnsupportedOutput(t *testing.T) {
 
 	opts := clustercmd.InstallOptions{
 		Bootstrap:        true,
-		ValuesFile:       "/tmp/values.enc",
+		ValuesFiles:      []string{"/tmp/values.enc"},
 		ValuesPassphrase: "secret",
 		Output:           "yaml",
 	}
@@ -351,7 +355,7 @@ func TestClusterInstallCommand_AirgappedLoadsBundle(t *testing.T) {
 
 	opts := clustercmd.InstallOptions{
 		Bootstrap:        true,
-		ValuesFile:       "/tmp/values.enc",
+		ValuesFiles:      []string{"/tmp/values.enc"},
 		ValuesPassphrase: "secret",
 		Airgapped:        true,
 		BundlePath:       "/mnt/airgap.tar",
@@ -388,7 +392,7 @@ func TestClusterInstallCommand_LoadClusterConfigError(t *testing.T) {
 	opts := clustercmd.InstallOptions{
 		Bootstrap:        false,
 		ClusterEndpoint:  "https://cluster.local",
-		ValuesFile:       "/tmp/values.enc",
+		ValuesFiles:      []string{"/tmp/values.enc"},
 		ValuesPassphrase: "secret",
 		Output:           "text",
 	}
@@ -398,3 +402,141 @@ func TestClusterInstallCommand_LoadClusterConfigError(t *testing.T) {
 		t.Fatalf("expected cluster config error, got %v", err)
 	}
 }
+
+type releaseHelm struct {
+	fakeHelm
+	manifest string
+}
+
+func (r *releaseHelm) Apply(p *config.Profile, b *bundle.Bundle) (helm.ReleaseInfo, error) {
+	r.called = true
+	return helm.ReleaseInfo{Name: p.HelmRelease, Revision: 1, Status: "deployed", Manifest: r.manifest}, nil
+}
+
+type stubReadiness struct {
+	ready bool
+}
+
+func (s stubReadiness) Check(_ context.Context, resources []readiness.Resource) ([]readiness.Status, error) {
+	statuses := make([]readiness.Status, 0, len(resources))
+	for _, res := range resources {
+		statuses = append(statuses, readiness.Status{Resource: res, Ready: s.ready, Message: "0/1 ready"})
+	}
+	return statuses, nil
+}
+
+func TestClusterInstallCommand_VerifyPhaseReportsUnreadyWorkloads(t *testing.T) {
+	inspector := stubInspector{cpu: 8, memory: 16, modules: map[string]bool{"br_netfilter": true, "overlay": true}, sudo: true}
+	deps := clustercmd.InstallDeps{
+		Inspector:           inspector,
+		Bootstrapper:        &fakeBootstrap{},
+		HelmInstaller:       &releaseHelm{manifest: "---\napiVersion: apps/v1\nkind: StatefulSet\nmetadata:\n  name: db\n  namespace: data\n"},
+		TelemetryEmitter:    telemetryStub,
+		ClusterValidator:    func(*rest.Config) error { return nil },
+		ClusterConfigLoader: func(*config.Profile) (*rest.Config, error) { return nil, nil },
+		Readiness:           stubReadiness{},
+	}
+	opts := clustercmd.InstallOptions{
+		Bootstrap:        true,
+		ValuesFiles:      []string{"/tmp/values.enc"},
+		ValuesPassphrase: "secret",
+		Output:           "text",
+		WaitTimeout:      20 * time.Millisecond,
+	}
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+
+	err := clustercmd.RunInstallForTest(cmd, opts, deps)
+	if !errors.Is(err, readiness.ErrNotReady()) {
+		t.Fatalf("expected not ready error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "StatefulSet data/db (0/1 ready)") {
+		t.Fatalf("expected unhealthy workload listed, got %v", err)
+	}
+	if !strings.Contains(out.String(), `"phase":"verify","outcome":"failure"`) {
+		t.Fatalf("expected failed verify phase event, got %s", out.String())
+	}
+	if !strings.Contains(out.String(), `"unready":"statefulset/db"`) {
+		t.Fatalf("expected unready workloads in phase metadata, got %s", out.String())
+	}
+}
+
+func TestClusterInstallCommand_VerifyPhasePassesWhenReady(t *testing.T) {
+	inspector := stubInspector{cpu: 8, memory: 16, modules: map[string]bool{"br_netfilter": true, "overlay": true}, sudo: true}
+	deps := clustercmd.InstallDeps{
+		Inspector:           inspector,
+		Bootstrapper:        &fakeBootstrap{},
+		HelmInstaller:       &releaseHelm{manifest: "---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\n"},
+		TelemetryEmitter:    telemetryStub,
+		ClusterValidator:    func(*rest.Config) error { return nil },
+		ClusterConfigLoader: func(*config.Profile) (*rest.Config, error) { return nil, nil },
+		Readiness:           stubReadiness{ready: true},
+	}
+	opts := clustercmd.InstallOptions{
+		Bootstrap:        true,
+		ValuesFiles:      []string{"/tmp/values.enc"},
+		ValuesPassphrase: "secret",
+		Output:           "text",
+		WaitTimeout:      time.Second,
+	}
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+
+	if err := clustercmd.RunInstallForTest(cmd, opts, deps); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if !strings.Contains(out.String(), `"phase":"verify","outcome":"success"`) {
+		t.Fatalf("expected successful verify phase event, got %s", out.String())
+	}
+}
+
+type schemaRejectingHelm struct {
+	fakeHelm
+}
+
+func (s *schemaRejectingHelm) ValidateValues(*config.Profile, *bundle.Bundle) error {
+	return &helm.SchemaError{Violations: []helm.SchemaViolation{{Chart: "demo", Pointer: "/db/password", Keyword: "minLength", Message: "length must be at least 12"}}}
+}
+
+func TestClusterInstallCommand_ValidatePhaseStopsBeforeBootstrap(t *testing.T) {
+	inspector := stubInspector{cpu: 8, memory: 16, modules: map[string]bool{"br_netfilter": true, "overlay": true}, sudo: true}
+	bootstrap := &fakeBootstrap{}
+	installer := &schemaRejectingHelm{}
+	deps := clustercmd.InstallDeps{
+		Inspector:        inspector,
+		Bootstrapper:     bootstrap,
+		HelmInstaller:    installer,
+		TelemetryEmitter: telemetryStub,
+	}
+	opts := clustercmd.InstallOptions{
+		Bootstrap:        true,
+		ValuesFiles:      []string{"/tmp/values.enc"},
+		ValuesPassphrase: "secret",
+		Output:           "text",
+	}
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+
+	err := clustercmd.RunInstallForTest(cmd, opts, deps)
+	if !errors.Is(err, helm.ErrValuesSchema()) {
+		t.Fatalf("expected schema error, got %v", err)
+	}
+	if bootstrap.called || installer.called {
+		t.Fatalf("expected validation failure to stop before bootstrap and helm")
+	}
+	if !strings.Contains(out.String(), `"phase":"validate","outcome":"failure"`) {
+		t.Fatalf("expected failed validate phase event, got %s", out.String())
+	}
+	if !strings.Contains(out.String(), `"pointers":"/db/password"`) {
+		t.Fatalf("expected violation pointers in phase metadata, got %s", out.String())
+	}
+}
@@ -19,7 +19,7 @@ const (
 func TestBuildProfileReuseMode(t *testing.T) {
 	opts := InstallOptions{
 		ClusterEndpoint:  clusterTestClusterEndpoint,
-		ValuesFile:       clusterTestValuesFile,
+		ValuesFiles:      []string{clusterTestValuesFile},
 		ValuesPassphrase: clusterTestSecret,
 	}
 	profile, err := buildProfile(opts)
@@ -37,7 +37,7 @@ func TestBuildProfileReuseMode(t *testing.T) {
 func TestBuildProfileAirgappedRequiresBundle(t *testing.T) {
 	opts := InstallOptions{
 		Bootstrap:        true,
-		ValuesFile:       clusterTestValuesFile,
+		ValuesFiles:      []string{clusterTestValuesFile},
 		ValuesPassphrase: clusterTestSecret,
 		Airgapped:        true,
 	}
@@ -0,0 +1,33 @@
+package cluster
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/dobrovols/chainctl/internal/config"
+	"github.com/dobrovols/chainctl/pkg/readiness"
+)
+
+const defaultWaitTimeout = 5 * time.Minute
+
+// readinessChecker returns the injected checker or one built against the target cluster.
+func readinessChecker(profile *config.Profile, deps InstallDeps) (ReadinessChecker, error) {
+	if deps.Readiness != nil {
+		return deps.Readiness, nil
+	}
+	loader := deps.ClusterConfigLoader
+	if loader == nil {
+		loader = loadClusterConfig
+	}
+	cfg, err := loader(profile)
+	if err != nil {
+		return nil, fmt.Errorf("load cluster config: %w", err)
+	}
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create kubernetes client: %w", err)
+	}
+	return readiness.NewChecker(client), nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	"github.com/dobrovols/chainctl/internal/cli/logging"
 	internalconfig "github.com/dobrovols/chainctl/internal/config"
 	pkgconfig "github.com/dobrovols/chainctl/pkg/config"
 	"github.com/dobrovols/chainctl/pkg/telemetry"
@@ -126,7 +127,7 @@ func (m *Manager) buildResolvedInvocation(configPath string, cmd *cobra.Command,
 
 func emitInvocationSummary(cmd *cobra.Command, flagSet *pflag.FlagSet, resolved *pkgconfig.ResolvedInvocation) error {
 	format := determineOutputFormat(flagSet)
-	summary, err := pkgconfig.FormatSummary(resolved, format)
+	summary, err := pkgconfig.FormatSummary(redactInvocation(resolved), format)
 	if err != nil {
 		return err
 	}
@@ -195,12 +196,20 @@ func readRuntimeFlagValue(cmd *cobra.Command, flag *pflag.Flag) (any, error) {
 	switch flag.Value.Type() {
 	case "bool":
 		return cmd.Flags().GetBool(flag.Name)
-	case "stringSlice", "stringArray":
+	case "stringSlice":
 		value, err := cmd.Flags().GetStringSlice(flag.Name)
 		if err != nil {
 			return nil, err
 		}
 		return append([]string(nil), value...), nil
+	case "stringArray":
+		value, err := cmd.Flags().GetStringArray(flag.Name)
+		if err != nil {
+			return nil, err
+		}
+		return append([]string(nil), value...), nil
+	case "duration":
+		return flag.Value.String(), nil
 	default:
 		return cmd.Flags().GetString(flag.Name)
 	}
@@ -215,6 +224,9 @@ func applyResolvedFlag(cmd *cobra.Command, resolved *pkgconfig.ResolvedInvocatio
 		resolved.Warnings = append(resolved.Warnings, fmt.Sprintf("flag %q ignored (not recognised by command)", name))
 		return nil
 	}
+	if slice, ok := flag.Value.(pflag.SliceValue); ok {
+		return replaceSliceFlag(flag, slice, flagValue.Value, name)
+	}
 	value, err := formatResolvedFlagValue(flag, flagValue.Value, name)
 	if err != nil {
 		return err
@@ -225,6 +237,21 @@ func applyResolvedFlag(cmd *cobra.Command, resolved *pkgconfig.ResolvedInvocatio
 	return nil
 }
 
+// replaceSliceFlag overwrites list flags element by element. Joining the values and calling
+// Set would append to values already parsed from the command line, and would split
+// stringArray entries such as --set a=1,b=2 on their commas.
+func replaceSliceFlag(flag *pflag.Flag, slice pflag.SliceValue, raw any, name string) error {
+	values, err := toStringSlice(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %s expects string list", internalconfig.ErrInvalidFlagType, name)
+	}
+	if err := slice.Replace(values); err != nil {
+		return fmt.Errorf("apply flag %q: %w", name, err)
+	}
+	flag.Changed = true
+	return nil
+}
+
 func formatResolvedFlagValue(flag *pflag.Flag, raw any, name string) (string, error) {
 	switch flag.Value.Type() {
 	case "bool":
@@ -233,12 +260,6 @@ func formatResolvedFlagValue(flag *pflag.Flag, raw any, name string) (string, er
 			return "", fmt.Errorf("%w: %s expects boolean", internalconfig.ErrInvalidFlagType, name)
 		}
 		return fmt.Sprintf("%t", boolVal), nil
-	case "stringSlice", "stringArray":
-		slice, err := toStringSlice(raw)
-		if err != nil {
-			return "", fmt.Errorf("%w: %s expects string list", internalconfig.ErrInvalidFlagType, name)
-		}
-		return strings.Join(slice, ","), nil
 	default:
 		return fmt.Sprint(raw), nil
 	}
@@ -311,7 +332,7 @@ func EmitTelemetry(logger telemetry.StructuredLogger, resolved *pkgconfig.Resolv
 		metadata["overrides"] = strings.Join(resolved.Overrides, ",")
 	}
 	for name, value := range resolved.Flags {
-		metadata["flag."+name] = fmt.Sprint(value.Value)
+		metadata["flag."+name] = fmt.Sprint(sanitizeFlagValue(name, value.Value))
 		metadata["flag."+name+".source"] = string(value.Source)
 	}
 
@@ -322,3 +343,39 @@ func EmitTelemetry(logger telemetry.StructuredLogger, resolved *pkgconfig.Resolv
 		Metadata: metadata,
 	})
 }
+
+// redactInvocation returns a copy of resolved whose flag values are safe to print.
+func redactInvocation(resolved *pkgconfig.ResolvedInvocation) *pkgconfig.ResolvedInvocation {
+	if resolved == nil {
+		return nil
+	}
+	redacted := *resolved
+	redacted.Flags = make(pkgconfig.FlagSet, len(resolved.Flags))
+	for name, value := range resolved.Flags {
+		value.Value = sanitizeFlagValue(name, value.Value)
+		redacted.Flags[name] = value
+	}
+	return &redacted
+}
+
+// sanitizeFlagValue runs flag values through the command sanitizer so that secrets passed
+// via --set, --set-string or sensitive flags never reach summaries or telemetry.
+func sanitizeFlagValue(name string, value any) any {
+	switch v := value.(type) {
+	case string:
+		return sanitizeFlagArg(name, v)
+	case []string:
+		out := make([]string, len(v))
+		for i, item := range v {
+			out[i] = sanitizeFlagArg(name, item)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+func sanitizeFlagArg(name, value string) string {
+	flag := "--" + name
+	return strings.TrimPrefix(logging.SanitizeCommand([]string{flag, value}), flag+" ")
+}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -149,6 +150,67 @@ commands:
 	}
 }
 
+func TestBinderAppliesStringArrayFlagsAndRedactsSetValues(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "chainctl.yaml")
+	yaml := `
+commands:
+  chainctl install:
+    flags:
+      set:
+        - image.tag=1.2,replicaCount=3
+        - db.password=hunter2
+`
+	if err := os.WriteFile(configPath, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	root := &cobra.Command{Use: "chainctl"}
+	errBuf := new(bytes.Buffer)
+	root.SetErr(errBuf)
+	root.SetOut(&bytes.Buffer{})
+
+	var sets []string
+	install := &cobra.Command{
+		Use: "install",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			timeout, err := cmd.Flags().GetDuration("wait-timeout")
+			if err != nil {
+				return err
+			}
+			if timeout != time.Minute {
+				t.Fatalf("wait-timeout = %s, want 1m", timeout)
+			}
+			return nil
+		},
+	}
+	install.Annotations = map[string]string{AnnotationEnabled: "true"}
+	install.Flags().StringArrayVar(&sets, "set", nil, "")
+	install.Flags().Duration("wait-timeout", 5*time.Minute, "")
+	root.AddCommand(install)
+
+	t.Setenv("CHAINCTL_CONFIG", configPath)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("HOME", "")
+
+	NewManager(root).Bind(root)
+	root.SetArgs([]string{"install", "--wait-timeout", "1m"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute command: %v", err)
+	}
+
+	want := []string{"image.tag=1.2,replicaCount=3", "db.password=hunter2"}
+	if !reflect.DeepEqual(sets, want) {
+		t.Fatalf("set = %#v, want %#v", sets, want)
+	}
+	summary := errBuf.String()
+	if strings.Contains(summary, "hunter2") {
+		t.Fatalf("summary leaked --set secret:\n%s", summary)
+	}
+	if !strings.Contains(summary, "image.tag=1.2,replicaCount=3") {
+		t.Fatalf("summary missing non-sensitive --set value:\n%s", summary)
+	}
+}
+
 func TestEmitTelemetryIncludesFlagMetadata(t *testing.T) {
 	logger := &stubStructuredLogger{}
 	resolved := &pkgconfig.ResolvedInvocation{
@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/dobrovols/chainctl/internal/cli"
+	"github.com/dobrovols/chainctl/internal/cli/exitcode"
 	telemetryinit "github.com/dobrovols/chainctl/internal/telemetry"
 	secreterrors "github.com/dobrovols/chainctl/pkg/secrets"
 )
@@ -40,6 +41,10 @@ func main() {
 			osExit(encErr.Code)
 		}
 		fmt.Fprintln(os.Stderr, err)
+		var exitErr *exitcode.Error
+		if errors.As(err, &exitErr) {
+			osExit(exitErr.Code)
+		}
 		osExit(1)
 	}
 }
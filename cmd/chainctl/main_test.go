@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/dobrovols/chainctl/internal/cli"
+	"github.com/dobrovols/chainctl/internal/cli/exitcode"
 	telemetryinit "github.com/dobrovols/chainctl/internal/telemetry"
 	secreterrors "github.com/dobrovols/chainctl/pkg/secrets"
 )
@@ -145,3 +146,44 @@ func TestMainSecretErrorExit(t *testing.T) {
 		t.Fatalf("expected exit code %d, got %d", secreterrors.ErrCodeValidation, exitCode)
 	}
 }
+
+func TestMainExitCodeError(t *testing.T) {
+	t.Cleanup(func() {
+		resetMainGlobals()
+		os.Args = []string{"chainctl"}
+	})
+
+	telemetryInit = func(context.Context) (func(context.Context) error, error) {
+		return nil, nil
+	}
+
+	rootCommand = func() *cobra.Command {
+		return &cobra.Command{SilenceErrors: true, RunE: func(cmd *cobra.Command, args []string) error {
+			return exitcode.New(exitcode.ChangesDetected, errors.New("changes detected"))
+		}}
+	}
+
+	osExit = func(code int) {
+		panic(exitPanic{code: code})
+	}
+
+	os.Args = []string{"chainctl"}
+
+	exitCode := func() (code int) {
+		defer func() {
+			if r := recover(); r != nil {
+				if ep, ok := r.(exitPanic); ok {
+					code = ep.code
+					return
+				}
+				panic(r)
+			}
+		}()
+		main()
+		return 0
+	}()
+
+	if exitCode != exitcode.ChangesDetected {
+		t.Fatalf("expected exit code %d, got %d", exitcode.ChangesDetected, exitCode)
+	}
+}
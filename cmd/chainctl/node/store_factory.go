@@ -3,26 +3,12 @@ package node
 import (
 	"os"
 
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
-
+	"github.com/dobrovols/chainctl/internal/kubeclient"
 	"github.com/dobrovols/chainctl/pkg/tokens"
 )
 
 func kubeTokenStore() *tokens.KubeStore {
-	cfg, err := rest.InClusterConfig()
-	if err != nil {
-		loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-		overrides := &clientcmd.ConfigOverrides{}
-		clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
-		cfg, err = clientConfig.ClientConfig()
-		if err != nil {
-			return nil
-		}
-	}
-
-	clientset, err := kubernetes.NewForConfig(cfg)
+	clientset, err := kubeclient.Clientset()
 	if err != nil {
 		return nil
 	}
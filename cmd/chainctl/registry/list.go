@@ -0,0 +1,98 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dobrovols/chainctl/pkg/registryauth"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+// ListOptions holds CLI flags for registry list.
+type ListOptions struct {
+	CredentialsFile string
+	Output          string
+}
+
+// NewListCommand constructs the `chainctl registry list` command.
+func NewListCommand() *cobra.Command {
+	opts := ListOptions{}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List registries with stored credentials (passwords are never shown)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return runList(cmd, opts)
+		},
+	}
+
+	bindCredentialsFileFlag(cmd, &opts.CredentialsFile)
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// RunListForTest executes the list flow.
+func RunListForTest(cmd *cobra.Command, opts ListOptions) error {
+	cmd.SilenceUsage = true
+	return runList(cmd, opts)
+}
+
+func runList(cmd *cobra.Command, opts ListOptions) (err error) {
+	if opts.Output != "text" && opts.Output != "json" {
+		return errUnsupportedOutput
+	}
+	path, err := credentialsPath(opts.CredentialsFile)
+	if err != nil {
+		return err
+	}
+
+	logger, err := telemetry.NewCommandLogger(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{}
+	logWorkflowStart(logger, stepRegistryList, metadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, stepRegistryList, metadata, err)
+		}
+	}()
+
+	entries, err := registryauth.NewStore(path, "").List()
+	if err != nil {
+		return err
+	}
+	metadata["entries"] = fmt.Sprint(len(entries))
+
+	if err := renderList(cmd, opts.Output, path, entries); err != nil {
+		return err
+	}
+	logWorkflowSuccess(logger, stepRegistryList, metadata)
+	return nil
+}
+
+func renderList(cmd *cobra.Command, output, path string, entries []registryauth.Entry) error {
+	if output == "json" {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{"registries": entries, "credentialsFile": path})
+	}
+
+	out := cmd.OutOrStdout()
+	if len(entries) == 0 {
+		fmt.Fprintln(out, "No registry credentials stored")
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "HOST\tUSERNAME\tSOURCE\tUPDATED")
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.Host, entry.Username, entry.Source, entry.UpdatedAt.Format(time.RFC3339))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,44 @@
+package registry
+
+import "github.com/dobrovols/chainctl/pkg/telemetry"
+
+const (
+	stepRegistryLogin  = "registry-login"
+	stepRegistryLogout = "registry-logout"
+	stepRegistryList   = "registry-list"
+)
+
+func logWorkflowStart(logger telemetry.StructuredLogger, step string, metadata map[string]string) {
+	logWorkflowEntry(logger, step, step+" workflow started", telemetry.SeverityInfo, metadata, nil)
+}
+
+func logWorkflowSuccess(logger telemetry.StructuredLogger, step string, metadata map[string]string) {
+	logWorkflowEntry(logger, step, step+" workflow completed", telemetry.SeverityInfo, metadata, nil)
+}
+
+func logWorkflowFailure(logger telemetry.StructuredLogger, step string, metadata map[string]string, err error) {
+	logWorkflowEntry(logger, step, step+" workflow failed", telemetry.SeverityError, metadata, err)
+}
+
+// logWorkflowEntry records hosts and sources only; usernames and passwords never enter metadata.
+func logWorkflowEntry(logger telemetry.StructuredLogger, step, message string, severity telemetry.Severity, metadata map[string]string, err error) {
+	if logger == nil {
+		return
+	}
+	_ = logger.Emit(telemetry.Entry{
+		Category: telemetry.CategoryWorkflow,
+		Message:  message,
+		Severity: severity,
+		Step:     step,
+		Metadata: cloneMetadata(metadata),
+		Error:    err,
+	})
+}
+
+func cloneMetadata(src map[string]string) map[string]string {
+	out := make(map[string]string, len(src))
+	for k, v := range src {
+		out[k] = v
+	}
+	return out
+}
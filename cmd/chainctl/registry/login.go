@@ -0,0 +1,264 @@
+package registry
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dobrovols/chainctl/pkg/registryauth"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+// LoginOptions holds CLI flags for registry login.
+type LoginOptions struct {
+	Host             string
+	Username         string
+	Password         string
+	PasswordStdin    bool
+	Passphrase       string
+	CredentialsFile  string
+	FromDockerConfig bool
+	DockerConfig     string
+	FromPullSecret   string
+	Output           string
+}
+
+// LoginDeps defines dependencies required by the login command.
+type LoginDeps struct {
+	PullSecrets PullSecretReader
+	Stdin       io.Reader
+}
+
+var (
+	errLoginSource         = errors.New("choose one credential source: --username, --from-docker-config, or --from-pull-secret")
+	errLoginHostRequired   = errors.New("registry host is required with --username")
+	errPasswordRequired    = errors.New("password is required (use --password-stdin)")
+	errNoCredentials       = errors.New("no registry credentials found")
+	errInvalidPullSecret   = errors.New("--from-pull-secret must be namespace/name")
+	errConflictingPassword = errors.New("--password and --password-stdin are mutually exclusive")
+)
+
+// ErrLoginSource exposes the credential source validation sentinel.
+func ErrLoginSource() error { return errLoginSource }
+
+// ErrNoCredentials exposes the empty import sentinel.
+func ErrNoCredentials() error { return errNoCredentials }
+
+// NewLoginCommand constructs the `chainctl registry login` command.
+func NewLoginCommand() *cobra.Command {
+	opts := LoginOptions{}
+	cmd := &cobra.Command{
+		Use:   "login [HOST]",
+		Short: "Store encrypted registry credentials, entered directly or imported from docker config or a pull secret",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			if len(args) == 1 {
+				opts.Host = args[0]
+			}
+			return runLogin(cmd, opts, LoginDeps{PullSecrets: defaultPullSecretReader, Stdin: os.Stdin})
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Username, "username", "u", "", "Registry username")
+	cmd.Flags().StringVarP(&opts.Password, "password", "p", "", "Registry password (prefer --password-stdin)")
+	cmd.Flags().BoolVar(&opts.PasswordStdin, "password-stdin", false, "Read the registry password from stdin")
+	cmd.Flags().BoolVar(&opts.FromDockerConfig, "from-docker-config", false, "Import inline credentials from a docker config.json")
+	cmd.Flags().StringVar(&opts.DockerConfig, "docker-config", "", "Docker config file to import (defaults to $DOCKER_CONFIG/config.json or ~/.docker/config.json)")
+	cmd.Flags().StringVar(&opts.FromPullSecret, "from-pull-secret", "", "Import credentials from a Kubernetes image pull secret (namespace/name)")
+	cmd.Flags().StringVar(&opts.Passphrase, "passphrase", "", "Credential store passphrase (defaults to $"+registryauth.PassphraseEnv+", prompts when interactive)")
+	bindCredentialsFileFlag(cmd, &opts.CredentialsFile)
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// RunLoginForTest executes the login flow with injected dependencies.
+func RunLoginForTest(cmd *cobra.Command, opts LoginOptions, deps LoginDeps) error {
+	cmd.SilenceUsage = true
+	return runLogin(cmd, opts, deps)
+}
+
+func runLogin(cmd *cobra.Command, opts LoginOptions, deps LoginDeps) (err error) {
+	if opts.Output != "text" && opts.Output != "json" {
+		return errUnsupportedOutput
+	}
+	source, err := loginSource(opts)
+	if err != nil {
+		return err
+	}
+	path, err := credentialsPath(opts.CredentialsFile)
+	if err != nil {
+		return err
+	}
+
+	logger, err := telemetry.NewCommandLogger(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{"source": source}
+	if opts.Host != "" {
+		metadata["host"] = registryauth.NormalizeHost(opts.Host)
+	}
+	logWorkflowStart(logger, stepRegistryLogin, metadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, stepRegistryLogin, metadata, err)
+		}
+	}()
+
+	creds, err := collectCredentials(cmd, opts, deps, source)
+	if err != nil {
+		return err
+	}
+	passphrase, err := resolvePassphrase(cmd, opts.Passphrase)
+	if err != nil {
+		return err
+	}
+
+	store := registryauth.NewStore(path, passphrase)
+	hosts := make([]string, 0, len(creds))
+	for _, cred := range creds {
+		if err := store.Login(cred, source); err != nil {
+			return err
+		}
+		hosts = append(hosts, cred.Host)
+	}
+	metadata["hosts"] = strings.Join(hosts, ",")
+
+	if err := renderLogin(cmd, opts.Output, path, source, creds); err != nil {
+		return err
+	}
+	logWorkflowSuccess(logger, stepRegistryLogin, metadata)
+	return nil
+}
+
+func loginSource(opts LoginOptions) (string, error) {
+	var sources []string
+	if opts.Username != "" || opts.Password != "" || opts.PasswordStdin {
+		sources = append(sources, registryauth.SourceLogin)
+	}
+	if opts.FromDockerConfig {
+		sources = append(sources, registryauth.SourceDockerConfig)
+	}
+	if opts.FromPullSecret != "" {
+		sources = append(sources, registryauth.SourcePullSecret)
+	}
+	if len(sources) != 1 {
+		return "", errLoginSource
+	}
+	if sources[0] == registryauth.SourceLogin {
+		if strings.TrimSpace(opts.Host) == "" {
+			return "", errLoginHostRequired
+		}
+		if opts.Password != "" && opts.PasswordStdin {
+			return "", errConflictingPassword
+		}
+	}
+	return sources[0], nil
+}
+
+func collectCredentials(cmd *cobra.Command, opts LoginOptions, deps LoginDeps, source string) ([]registryauth.Credential, error) {
+	var creds []registryauth.Credential
+	switch source {
+	case registryauth.SourceLogin:
+		password, err := loginPassword(opts, deps.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		return []registryauth.Credential{{Host: registryauth.NormalizeHost(opts.Host), Username: opts.Username, Password: password}}, nil
+	case registryauth.SourceDockerConfig:
+		path := opts.DockerConfig
+		if path == "" {
+			path = registryauth.DefaultDockerConfigPath()
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read docker config: %w", err)
+		}
+		if creds, err = registryauth.ParseDockerConfig(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	case registryauth.SourcePullSecret:
+		namespace, name, found := strings.Cut(opts.FromPullSecret, "/")
+		if !found || namespace == "" || name == "" {
+			return nil, errInvalidPullSecret
+		}
+		if deps.PullSecrets == nil {
+			return nil, errors.New("pull secret reader not configured")
+		}
+		secret, err := deps.PullSecrets(cmd.Context(), namespace, name)
+		if err != nil {
+			return nil, fmt.Errorf("read pull secret %s: %w", opts.FromPullSecret, err)
+		}
+		if creds, err = registryauth.FromPullSecret(secret); err != nil {
+			return nil, err
+		}
+	}
+	return filterHost(creds, opts.Host)
+}
+
+func loginPassword(opts LoginOptions, stdin io.Reader) (string, error) {
+	if !opts.PasswordStdin {
+		if opts.Password == "" {
+			return "", errPasswordRequired
+		}
+		return opts.Password, nil
+	}
+	if stdin == nil {
+		return "", errPasswordRequired
+	}
+	line, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read password from stdin: %w", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errPasswordRequired
+	}
+	return password, nil
+}
+
+// filterHost keeps only the requested registry when importing; all entries are kept otherwise.
+func filterHost(creds []registryauth.Credential, host string) ([]registryauth.Credential, error) {
+	if strings.TrimSpace(host) != "" {
+		host = registryauth.NormalizeHost(host)
+		var matched []registryauth.Credential
+		for _, cred := range creds {
+			if cred.Host == host {
+				matched = append(matched, cred)
+			}
+		}
+		creds = matched
+	}
+	if len(creds) == 0 {
+		if host != "" {
+			return nil, fmt.Errorf("%w for %s", errNoCredentials, host)
+		}
+		return nil, errNoCredentials
+	}
+	return creds, nil
+}
+
+func renderLogin(cmd *cobra.Command, output, path, source string, creds []registryauth.Credential) error {
+	if output == "json" {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{
+			"credentialsFile": path,
+			"source":          source,
+			"registries":      creds,
+		})
+	}
+	for _, cred := range creds {
+		fmt.Fprintf(cmd.OutOrStdout(), "Stored credentials for %s (user %s, source %s)\n", cred.Host, cred.Username, source)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Credential store: %s\n", path)
+	return nil
+}
@@ -0,0 +1,93 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dobrovols/chainctl/pkg/registryauth"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+// LogoutOptions holds CLI flags for registry logout.
+type LogoutOptions struct {
+	Host            string
+	CredentialsFile string
+	Output          string
+}
+
+var errNotLoggedIn = errors.New("no stored credentials for registry")
+
+// ErrNotLoggedIn exposes the missing credential sentinel.
+func ErrNotLoggedIn() error { return errNotLoggedIn }
+
+// NewLogoutCommand constructs the `chainctl registry logout` command.
+func NewLogoutCommand() *cobra.Command {
+	opts := LogoutOptions{}
+	cmd := &cobra.Command{
+		Use:   "logout HOST",
+		Short: "Remove stored credentials for a registry host",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			opts.Host = args[0]
+			return runLogout(cmd, opts)
+		},
+	}
+
+	bindCredentialsFileFlag(cmd, &opts.CredentialsFile)
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// RunLogoutForTest executes the logout flow.
+func RunLogoutForTest(cmd *cobra.Command, opts LogoutOptions) error {
+	cmd.SilenceUsage = true
+	return runLogout(cmd, opts)
+}
+
+func runLogout(cmd *cobra.Command, opts LogoutOptions) (err error) {
+	if opts.Output != "text" && opts.Output != "json" {
+		return errUnsupportedOutput
+	}
+	path, err := credentialsPath(opts.CredentialsFile)
+	if err != nil {
+		return err
+	}
+	host := registryauth.NormalizeHost(opts.Host)
+
+	logger, err := telemetry.NewCommandLogger(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{"host": host}
+	logWorkflowStart(logger, stepRegistryLogout, metadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, stepRegistryLogout, metadata, err)
+		}
+	}()
+
+	removed, err := registryauth.NewStore(path, "").Logout(host)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("%w: %s", errNotLoggedIn, host)
+	}
+
+	if opts.Output == "json" {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(map[string]any{"removed": host, "credentialsFile": path}); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(cmd.OutOrStdout(), "Removed credentials for %s\n", host)
+	}
+	logWorkflowSuccess(logger, stepRegistryLogout, metadata)
+	return nil
+}
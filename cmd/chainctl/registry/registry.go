@@ -0,0 +1,97 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dobrovols/chainctl/internal/kubeclient"
+	"github.com/dobrovols/chainctl/internal/state"
+	"github.com/dobrovols/chainctl/pkg/registryauth"
+)
+
+var (
+	isTerminal   = term.IsTerminal
+	readPassword = term.ReadPassword
+	stdinFD      = func() int { return int(os.Stdin.Fd()) }
+)
+
+var errUnsupportedOutput = errors.New("unsupported output format")
+
+// ErrUnsupportedOutput exposes the sentinel.
+func ErrUnsupportedOutput() error { return errUnsupportedOutput }
+
+// PullSecretReader fetches a Kubernetes image pull secret.
+type PullSecretReader func(ctx context.Context, namespace, name string) (*corev1.Secret, error)
+
+// NewRegistryCommand creates the `chainctl registry` parent command.
+func NewRegistryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "registry",
+		Short: "Manage OCI registry credentials used for chart pulls",
+	}
+
+	cmd.AddCommand(NewLoginCommand())
+	cmd.AddCommand(NewLogoutCommand())
+	cmd.AddCommand(NewListCommand())
+
+	return cmd
+}
+
+func bindCredentialsFileFlag(cmd *cobra.Command, path *string) {
+	cmd.Flags().StringVar(path, "credentials-file", "", "Registry credential store (defaults to $XDG_CONFIG_HOME/chainctl/registries.json)")
+}
+
+func credentialsPath(override string) (string, error) {
+	if strings.TrimSpace(override) != "" {
+		return override, nil
+	}
+	path, err := state.RegistryCredentialsPath()
+	if err != nil {
+		return "", fmt.Errorf("determine registry credential store: %w", err)
+	}
+	return path, nil
+}
+
+// resolvePassphrase prefers the flag, then CHAINCTL_REGISTRY_PASSPHRASE, then an interactive prompt.
+func resolvePassphrase(cmd *cobra.Command, provided string) (string, error) {
+	if provided != "" {
+		return provided, nil
+	}
+	if env := os.Getenv(registryauth.PassphraseEnv); env != "" {
+		return env, nil
+	}
+	fd := stdinFD()
+	if !isTerminal(fd) {
+		return "", registryauth.ErrPassphraseRequired()
+	}
+	fmt.Fprint(cmd.ErrOrStderr(), "Credential store passphrase: ")
+	pass, err := readPassword(fd)
+	fmt.Fprintln(cmd.ErrOrStderr())
+	if err != nil {
+		return "", err
+	}
+	passphrase := string(pass)
+	for i := range pass {
+		pass[i] = 0
+	}
+	if passphrase == "" {
+		return "", registryauth.ErrPassphraseRequired()
+	}
+	return passphrase, nil
+}
+
+func defaultPullSecretReader(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	client, err := kubeclient.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes client: %w", err)
+	}
+	return client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+}
@@ -0,0 +1,193 @@
+package registry_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+
+	registrycmd "github.com/dobrovols/chainctl/cmd/chainctl/registry"
+	"github.com/dobrovols/chainctl/pkg/registryauth"
+)
+
+const testPassphrase = "store-passphrase"
+
+func newCommand() (*cobra.Command, *bytes.Buffer, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetContext(context.Background())
+	return cmd, &out, &errOut
+}
+
+func dockerConfig(host, user, pass string) []byte {
+	auth := base64.StdEncoding.EncodeToString([]byte(user + ":" + pass))
+	return []byte(fmt.Sprintf(`{"auths":{%q:{"auth":%q},"other.example.com":{"auth":%q}}}`, host, auth, auth))
+}
+
+func TestRegistryLogin_PasswordStdinIsEncryptedAndNeverPrinted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registries.json")
+	cmd, out, errOut := newCommand()
+
+	opts := registrycmd.LoginOptions{
+		Host:            "ghcr.io",
+		Username:        "bot",
+		PasswordStdin:   true,
+		Passphrase:      testPassphrase,
+		CredentialsFile: path,
+		Output:          "json",
+	}
+	if err := registrycmd.RunLoginForTest(cmd, opts, registrycmd.LoginDeps{Stdin: strings.NewReader("hunter2\n")}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read store: %v", err)
+	}
+	for name, content := range map[string]string{"stdout": out.String(), "stderr": errOut.String(), "store": string(raw)} {
+		if strings.Contains(content, "hunter2") {
+			t.Fatalf("password leaked to %s: %s", name, content)
+		}
+	}
+
+	var payload struct {
+		Registries []registryauth.Credential `json:"registries"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, out.String())
+	}
+	if len(payload.Registries) != 1 || payload.Registries[0].Host != "ghcr.io" {
+		t.Fatalf("unexpected output %+v", payload)
+	}
+
+	cred, ok, err := registryauth.NewStore(path, testPassphrase).Credential(context.Background(), "ghcr.io")
+	if err != nil || !ok || cred.Password != "hunter2" {
+		t.Fatalf("expected stored credential, ok=%v err=%v", ok, err)
+	}
+}
+
+func TestRegistryLogin_RequiresSingleSource(t *testing.T) {
+	cmd, _, _ := newCommand()
+	opts := registrycmd.LoginOptions{
+		Host:             "ghcr.io",
+		Username:         "bot",
+		Password:         "pw",
+		FromDockerConfig: true,
+		Passphrase:       testPassphrase,
+		CredentialsFile:  filepath.Join(t.TempDir(), "registries.json"),
+		Output:           "text",
+	}
+	if err := registrycmd.RunLoginForTest(cmd, opts, registrycmd.LoginDeps{}); !errors.Is(err, registrycmd.ErrLoginSource()) {
+		t.Fatalf("expected source error, got %v", err)
+	}
+}
+
+func TestRegistryLogin_ImportsDockerConfigForHost(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(configPath, dockerConfig("registry.example.com", "ci", "token"), 0o600); err != nil {
+		t.Fatalf("write docker config: %v", err)
+	}
+	path := filepath.Join(dir, "registries.json")
+	cmd, out, _ := newCommand()
+	t.Setenv(registryauth.PassphraseEnv, testPassphrase)
+
+	opts := registrycmd.LoginOptions{
+		Host:             "registry.example.com",
+		FromDockerConfig: true,
+		DockerConfig:     configPath,
+		CredentialsFile:  path,
+		Output:           "text",
+	}
+	if err := registrycmd.RunLoginForTest(cmd, opts, registrycmd.LoginDeps{}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if !strings.Contains(out.String(), "Stored credentials for registry.example.com") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	entries, err := registryauth.NewStore(path, "").List()
+	if err != nil || len(entries) != 1 || entries[0].Source != registryauth.SourceDockerConfig {
+		t.Fatalf("expected only the requested host, got %+v (%v)", entries, err)
+	}
+
+	opts.Host = "missing.example.com"
+	if err := registrycmd.RunLoginForTest(cmd, opts, registrycmd.LoginDeps{}); !errors.Is(err, registrycmd.ErrNoCredentials()) {
+		t.Fatalf("expected no credentials error, got %v", err)
+	}
+}
+
+func TestRegistryLogin_ImportsPullSecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registries.json")
+	cmd, _, _ := newCommand()
+
+	var requested string
+	reader := func(_ context.Context, namespace, name string) (*corev1.Secret, error) {
+		requested = namespace + "/" + name
+		return &corev1.Secret{
+			Type: corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{corev1.DockerConfigJsonKey: dockerConfig("quay.io", "robot", "secret")},
+		}, nil
+	}
+	opts := registrycmd.LoginOptions{
+		FromPullSecret:  "apps/regcred",
+		Passphrase:      testPassphrase,
+		CredentialsFile: path,
+		Output:          "text",
+	}
+	if err := registrycmd.RunLoginForTest(cmd, opts, registrycmd.LoginDeps{PullSecrets: reader}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if requested != "apps/regcred" {
+		t.Fatalf("unexpected secret requested %q", requested)
+	}
+	entries, err := registryauth.NewStore(path, "").List()
+	if err != nil || len(entries) != 2 || entries[0].Source != registryauth.SourcePullSecret {
+		t.Fatalf("expected both secret entries, got %+v (%v)", entries, err)
+	}
+}
+
+func TestRegistryListAndLogout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registries.json")
+	store := registryauth.NewStore(path, testPassphrase)
+	if err := store.Login(registryauth.Credential{Host: "ghcr.io", Username: "bot", Password: "hunter2"}, registryauth.SourceLogin); err != nil {
+		t.Fatalf("seed store: %v", err)
+	}
+
+	cmd, out, _ := newCommand()
+	if err := registrycmd.RunListForTest(cmd, registrycmd.ListOptions{CredentialsFile: path, Output: "text"}); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	for _, want := range []string{"HOST", "ghcr.io", "bot", registryauth.SourceLogin} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "hunter2") {
+		t.Fatalf("password leaked in list output")
+	}
+
+	cmd, out, _ = newCommand()
+	if err := registrycmd.RunLogoutForTest(cmd, registrycmd.LogoutOptions{Host: "https://ghcr.io", CredentialsFile: path, Output: "text"}); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+	if !strings.Contains(out.String(), "Removed credentials for ghcr.io") {
+		t.Fatalf("unexpected logout output:\n%s", out.String())
+	}
+
+	cmd, _, _ = newCommand()
+	if err := registrycmd.RunLogoutForTest(cmd, registrycmd.LogoutOptions{Host: "ghcr.io", CredentialsFile: path, Output: "text"}); !errors.Is(err, registrycmd.ErrNotLoggedIn()) {
+		t.Fatalf("expected not logged in error, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+// ExportOptions holds CLI flags for state export.
+type ExportOptions struct {
+	ReleaseName     string
+	Namespace       string
+	ClusterEndpoint string
+	StateFileName   string
+	StateFilePath   string
+	// File receives the exported document; empty or "-" writes to stdout.
+	File string
+}
+
+var errNothingToExport = errors.New("no recorded releases match the selection")
+
+// ErrNothingToExport exposes the empty export sentinel.
+func ErrNothingToExport() error { return errNothingToExport }
+
+// NewExportCommand constructs the `chainctl state export` command.
+func NewExportCommand() *cobra.Command {
+	opts := ExportOptions{}
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write recorded state as a schema-validated JSON document",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return runExport(cmd, opts, defaultStore())
+		},
+	}
+
+	bindSelectorFlags(cmd, &opts.ReleaseName, &opts.Namespace, &opts.ClusterEndpoint)
+	bindStateFileFlags(cmd, &opts.StateFilePath, &opts.StateFileName)
+	cmd.Flags().StringVar(&opts.File, "file", "-", "Destination file for the exported document (- for stdout)")
+
+	return cmd
+}
+
+// RunExportForTest executes the export flow against the provided store.
+func RunExportForTest(cmd *cobra.Command, opts ExportOptions, store DocumentStore) error {
+	cmd.SilenceUsage = true
+	return runExport(cmd, opts, store)
+}
+
+func runExport(cmd *cobra.Command, opts ExportOptions, store DocumentStore) (err error) {
+	logger, err := telemetry.NewCommandLogger(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{}
+	logWorkflowStart(logger, stepStateExport, metadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, stepStateExport, metadata, err)
+		}
+	}()
+
+	overrides := pkgstate.Overrides{
+		StateFileName:   opts.StateFileName,
+		StateFilePath:   opts.StateFilePath,
+		Release:         opts.ReleaseName,
+		Namespace:       opts.Namespace,
+		ClusterEndpoint: opts.ClusterEndpoint,
+	}
+	doc, path, err := store.Load(overrides)
+	if err != nil {
+		return err
+	}
+	exported := pkgstate.Document{SchemaVersion: pkgstate.SchemaVersion, Records: doc.Select(overrides)}
+	if len(exported.Records) == 0 {
+		return fmt.Errorf("%w: %s", errNothingToExport, path)
+	}
+
+	data, err := json.MarshalIndent(exported, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode state document: %w", err)
+	}
+	data = append(data, '\n')
+	if err := pkgstate.ValidateDocument(data); err != nil {
+		return err
+	}
+	metadata["records"] = strconv.Itoa(len(exported.Records))
+
+	if opts.File == "" || opts.File == "-" {
+		if _, err := cmd.OutOrStdout().Write(data); err != nil {
+			return err
+		}
+	} else {
+		if err := os.WriteFile(opts.File, data, 0o600); err != nil {
+			return fmt.Errorf("write export: %w", err)
+		}
+		metadata["file"] = opts.File
+		fmt.Fprintf(cmd.OutOrStdout(), "Exported %d records from %s to %s\n", len(exported.Records), path, opts.File)
+	}
+
+	logWorkflowSuccess(logger, stepStateExport, metadata)
+	return nil
+}
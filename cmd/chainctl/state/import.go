@@ -0,0 +1,167 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+// ImportOptions holds CLI flags for state import.
+type ImportOptions struct {
+	// File holds the document to import; "-" reads stdin.
+	File          string
+	Overwrite     bool
+	StateFileName string
+	StateFilePath string
+	HistoryLimit  int
+	Output        string
+}
+
+var (
+	errImportFileRequired = errors.New("--file is required")
+	errImportConflict     = errors.New("state already records these releases; pass --overwrite to replace them")
+	errImportEmpty        = errors.New("import document contains no records")
+)
+
+// ErrImportFileRequired exposes the missing --file sentinel.
+func ErrImportFileRequired() error { return errImportFileRequired }
+
+// ErrImportConflict exposes the sentinel returned when imported records already exist.
+func ErrImportConflict() error { return errImportConflict }
+
+// ErrImportEmpty exposes the empty import sentinel.
+func ErrImportEmpty() error { return errImportEmpty }
+
+type importReport struct {
+	StateFile string   `json:"stateFile"`
+	Imported  []string `json:"imported"`
+	Replaced  []string `json:"replaced"`
+}
+
+// NewImportCommand constructs the `chainctl state import` command.
+func NewImportCommand() *cobra.Command {
+	opts := ImportOptions{}
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Merge a schema-validated state document into the state file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return runImport(cmd, opts, defaultStore(pkgstate.WithHistoryLimit(opts.HistoryLimit)))
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.File, "file", "", "State document to import (- for stdin)")
+	cmd.Flags().BoolVar(&opts.Overwrite, "overwrite", false, "Replace records already stored for the imported releases")
+	bindStateFileFlags(cmd, &opts.StateFilePath, &opts.StateFileName)
+	bindHistoryLimitFlag(cmd, &opts.HistoryLimit)
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// RunImportForTest executes the import flow against the provided store.
+func RunImportForTest(cmd *cobra.Command, opts ImportOptions, store DocumentStore) error {
+	cmd.SilenceUsage = true
+	return runImport(cmd, opts, store)
+}
+
+func runImport(cmd *cobra.Command, opts ImportOptions, store DocumentStore) (err error) {
+	if opts.Output != "text" && opts.Output != "json" {
+		return errUnsupportedOutput
+	}
+	if strings.TrimSpace(opts.File) == "" {
+		return errImportFileRequired
+	}
+	logger, err := telemetry.NewCommandLogger(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{"overwrite": strconv.FormatBool(opts.Overwrite)}
+	logWorkflowStart(logger, stepStateImport, metadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, stepStateImport, metadata, err)
+		}
+	}()
+
+	data, err := readImport(cmd, opts.File)
+	if err != nil {
+		return err
+	}
+	if err := pkgstate.ValidateDocument(data); err != nil {
+		return err
+	}
+	incoming, err := pkgstate.DecodeDocument(data)
+	if err != nil {
+		return fmt.Errorf("decode import: %w", err)
+	}
+	if len(incoming.Records) == 0 {
+		return errImportEmpty
+	}
+
+	report := importReport{Imported: []string{}, Replaced: []string{}}
+	report.StateFile, err = store.Update(pkgstate.Overrides{StateFileName: opts.StateFileName, StateFilePath: opts.StateFilePath}, func(doc *pkgstate.Document) error {
+		var conflicts []string
+		for _, record := range incoming.Records {
+			if _, exists := doc.Lookup(record.Key()); exists {
+				conflicts = append(conflicts, recordLabel(record))
+			}
+		}
+		if len(conflicts) > 0 && !opts.Overwrite {
+			return fmt.Errorf("%w: %s", errImportConflict, strings.Join(conflicts, ", "))
+		}
+		for _, record := range incoming.Records {
+			doc.Put(record)
+			report.Imported = append(report.Imported, recordLabel(record))
+		}
+		report.Replaced = append(report.Replaced, conflicts...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	metadata["imported"] = strconv.Itoa(len(report.Imported))
+	metadata["replaced"] = strconv.Itoa(len(report.Replaced))
+
+	logWorkflowSuccess(logger, stepStateImport, metadata)
+	return emitImportOutput(cmd, report, opts.Output)
+}
+
+func readImport(cmd *cobra.Command, file string) ([]byte, error) {
+	if file == "-" {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return nil, fmt.Errorf("read import from stdin: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read import: %w", err)
+	}
+	return data, nil
+}
+
+func emitImportOutput(cmd *cobra.Command, report importReport, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetEscapeHTML(false)
+		return enc.Encode(report)
+	}
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Imported %d records into %s\n", len(report.Imported), report.StateFile)
+	for _, label := range report.Replaced {
+		fmt.Fprintf(out, "Replaced %s\n", label)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+// ListOptions holds CLI flags for state list.
+type ListOptions struct {
+	ReleaseName     string
+	Namespace       string
+	ClusterEndpoint string
+	StateFileName   string
+	StateFilePath   string
+	Output          string
+}
+
+type listedRecord struct {
+	Release         string `json:"release"`
+	Namespace       string `json:"namespace"`
+	ClusterEndpoint string `json:"clusterEndpoint,omitempty"`
+	LastAction      string `json:"lastAction"`
+	Version         string `json:"version"`
+	Revision        int    `json:"revision,omitempty"`
+	Status          string `json:"status,omitempty"`
+	Timestamp       string `json:"timestamp"`
+	HistoryEntries  int    `json:"historyEntries"`
+}
+
+// NewListCommand constructs the `chainctl state list` command.
+func NewListCommand() *cobra.Command {
+	opts := ListOptions{}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the releases recorded in the state file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return runList(cmd, opts, defaultStore())
+		},
+	}
+
+	bindSelectorFlags(cmd, &opts.ReleaseName, &opts.Namespace, &opts.ClusterEndpoint)
+	bindStateFileFlags(cmd, &opts.StateFilePath, &opts.StateFileName)
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// RunListForTest executes the list flow against the provided store.
+func RunListForTest(cmd *cobra.Command, opts ListOptions, store DocumentStore) error {
+	cmd.SilenceUsage = true
+	return runList(cmd, opts, store)
+}
+
+func runList(cmd *cobra.Command, opts ListOptions, store DocumentStore) (err error) {
+	if opts.Output != "text" && opts.Output != "json" {
+		return errUnsupportedOutput
+	}
+	logger, err := telemetry.NewCommandLogger(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{}
+	logWorkflowStart(logger, stepStateList, metadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, stepStateList, metadata, err)
+		}
+	}()
+
+	overrides := pkgstate.Overrides{
+		StateFileName:   opts.StateFileName,
+		StateFilePath:   opts.StateFilePath,
+		Release:         opts.ReleaseName,
+		Namespace:       opts.Namespace,
+		ClusterEndpoint: opts.ClusterEndpoint,
+	}
+	doc, path, err := store.Load(overrides)
+	if err != nil && !errors.Is(err, pkgstate.ErrNotFound()) {
+		return err
+	}
+	records := make([]listedRecord, 0, len(doc.Records))
+	for _, record := range doc.Select(overrides) {
+		records = append(records, listedRecord{
+			Release:         record.Release,
+			Namespace:       record.Namespace,
+			ClusterEndpoint: record.ClusterEndpoint,
+			LastAction:      record.LastAction,
+			Version:         record.Version,
+			Revision:        record.Revision,
+			Status:          record.Status,
+			Timestamp:       record.Timestamp,
+			HistoryEntries:  len(record.History),
+		})
+	}
+	metadata["records"] = strconv.Itoa(len(records))
+
+	logWorkflowSuccess(logger, stepStateList, metadata)
+	return emitListOutput(cmd, path, records, opts.Output)
+}
+
+func emitListOutput(cmd *cobra.Command, path string, records []listedRecord, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetEscapeHTML(false)
+		return enc.Encode(map[string]any{"stateFile": path, "records": records})
+	}
+
+	if len(records) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "No releases recorded in %s\n", path)
+		return nil
+	}
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "RELEASE\tNAMESPACE\tCLUSTER\tACTION\tVERSION\tREVISION\tUPDATED")
+	for _, record := range records {
+		revision := "-"
+		if record.Revision > 0 {
+			revision = strconv.Itoa(record.Revision)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			record.Release,
+			record.Namespace,
+			cell(record.ClusterEndpoint),
+			cell(record.LastAction),
+			cell(record.Version),
+			revision,
+			cell(record.Timestamp),
+		)
+	}
+	return tw.Flush()
+}
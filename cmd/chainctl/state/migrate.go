@@ -0,0 +1,219 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	internalstate "github.com/dobrovols/chainctl/internal/state"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+// MigrateOptions holds CLI flags for state migrate.
+type MigrateOptions struct {
+	From          string
+	To            string
+	ReleaseName   string
+	Namespace     string
+	StateFileName string
+	StateFilePath string
+	HistoryLimit  int
+	Force         bool
+	RemoveSource  bool
+	Output        string
+}
+
+// MigrateDeps defines the state backends used by migrate; nil backends are built from --from and --to.
+type MigrateDeps struct {
+	Source      pkgstate.Store
+	Destination pkgstate.Store
+}
+
+var (
+	errSameBackend       = errors.New("--from and --to must name different state backends")
+	errDestinationExists = errors.New("destination already holds state for the release; pass --force to overwrite it")
+	errMigrateRelease    = errors.New("source state records a different release")
+)
+
+// ErrSameBackend exposes the identical backend sentinel.
+func ErrSameBackend() error { return errSameBackend }
+
+// ErrDestinationExists exposes the sentinel returned when migrate would overwrite existing state.
+func ErrDestinationExists() error { return errDestinationExists }
+
+// ErrMigrateRelease exposes the release mismatch sentinel.
+func ErrMigrateRelease() error { return errMigrateRelease }
+
+type migrateReport struct {
+	Status        string `json:"status"`
+	Release       string `json:"release"`
+	Namespace     string `json:"namespace"`
+	From          string `json:"from"`
+	To            string `json:"to"`
+	Source        string `json:"source"`
+	Destination   string `json:"destination"`
+	Entries       int    `json:"historyEntries"`
+	RemovedSource bool   `json:"removedSource"`
+}
+
+// NewMigrateCommand constructs the `chainctl state migrate` command.
+func NewMigrateCommand() *cobra.Command {
+	opts := MigrateOptions{}
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Copy the recorded state and its history from one backend to another",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return runMigrate(cmd, opts, MigrateDeps{})
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.From, "from", pkgstate.BackendFile, "Backend holding the state today: file or secret")
+	cmd.Flags().StringVar(&opts.To, "to", pkgstate.BackendSecret, "Backend to copy the state into: file or secret")
+	cmd.Flags().StringVar(&opts.ReleaseName, "release-name", "", "Helm release whose state is migrated (defaults to the release recorded in the source)")
+	cmd.Flags().StringVar(&opts.Namespace, "namespace", "", "Release namespace; required when reading from the secret backend")
+	cmd.Flags().StringVar(&opts.StateFilePath, "state-file", "", "Absolute path of the state JSON for the file backend")
+	cmd.Flags().StringVar(&opts.StateFileName, "state-file-name", "", "Custom state file name within the config directory for the file backend")
+	bindHistoryLimitFlag(cmd, &opts.HistoryLimit)
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "Overwrite state already recorded in the destination")
+	cmd.Flags().BoolVar(&opts.RemoveSource, "remove-source", false, "Remove the source state after a successful copy")
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// RunMigrateForTest executes the migrate flow with injected backends.
+func RunMigrateForTest(cmd *cobra.Command, opts MigrateOptions, deps MigrateDeps) error {
+	cmd.SilenceUsage = true
+	return runMigrate(cmd, opts, deps)
+}
+
+func runMigrate(cmd *cobra.Command, opts MigrateOptions, deps MigrateDeps) (err error) {
+	if opts.Output != "text" && opts.Output != "json" {
+		return errUnsupportedOutput
+	}
+	for _, backend := range []string{opts.From, opts.To} {
+		if err := pkgstate.ValidateBackend(backend); err != nil {
+			return err
+		}
+	}
+	if backendName(opts.From) == backendName(opts.To) {
+		return errSameBackend
+	}
+
+	overrides := pkgstate.Overrides{Release: opts.ReleaseName, Namespace: opts.Namespace}
+	filePath := ""
+	if backendName(opts.From) == pkgstate.BackendFile || backendName(opts.To) == pkgstate.BackendFile {
+		filePath, err = internalstate.NewResolver().Resolve(pkgstate.Overrides{StateFileName: opts.StateFileName, StateFilePath: opts.StateFilePath})
+		if err != nil {
+			return err
+		}
+		overrides.StateFilePath = filePath
+	}
+	if deps.Source == nil {
+		if deps.Source, err = internalstate.NewStore(opts.From, internalstate.WithHistoryLimit(opts.HistoryLimit)); err != nil {
+			return err
+		}
+	}
+	if deps.Destination == nil {
+		if deps.Destination, err = internalstate.NewStore(opts.To, internalstate.WithHistoryLimit(opts.HistoryLimit)); err != nil {
+			return err
+		}
+	}
+
+	logger, err := telemetry.NewCommandLogger(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{"from": backendName(opts.From), "to": backendName(opts.To)}
+	logWorkflowStart(logger, stepStateMigrate, metadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, stepStateMigrate, metadata, err)
+		}
+	}()
+
+	record, err := deps.Source.Read(overrides)
+	if err != nil {
+		return fmt.Errorf("read %s state: %w", backendName(opts.From), err)
+	}
+	if (opts.ReleaseName != "" && opts.ReleaseName != record.Release) || (opts.Namespace != "" && opts.Namespace != record.Namespace) {
+		return fmt.Errorf("%w: %s/%s recorded", errMigrateRelease, record.Namespace, record.Release)
+	}
+	overrides.Release, overrides.Namespace = record.Release, record.Namespace
+	metadata["release"], metadata["namespace"] = record.Release, record.Namespace
+
+	_, err = deps.Destination.Read(overrides)
+	switch {
+	case err == nil && !opts.Force:
+		return errDestinationExists
+	case err != nil && !errors.Is(err, pkgstate.ErrNotFound()):
+		return fmt.Errorf("read %s state: %w", backendName(opts.To), err)
+	}
+
+	// Copy the history verbatim; records written before history was tracked seed it with their own entry.
+	if len(record.History) == 0 && record.LastAction != "" {
+		record.History = []pkgstate.HistoryEntry{record.Entry()}
+	}
+	destination, err := deps.Destination.Write(record, overrides)
+	if err != nil {
+		return fmt.Errorf("write %s state: %w", backendName(opts.To), err)
+	}
+
+	source := describeLocation(opts.From, filePath, record)
+	if opts.RemoveSource {
+		if source, err = deps.Source.Remove(overrides); err != nil {
+			return fmt.Errorf("remove %s state: %w", backendName(opts.From), err)
+		}
+	}
+	metadata["entries"] = strconv.Itoa(len(record.History))
+
+	logWorkflowSuccess(logger, stepStateMigrate, metadata)
+	return emitMigrateOutput(cmd, migrateReport{
+		Status:        "success",
+		Release:       record.Release,
+		Namespace:     record.Namespace,
+		From:          backendName(opts.From),
+		To:            backendName(opts.To),
+		Source:        source,
+		Destination:   destination,
+		Entries:       len(record.History),
+		RemovedSource: opts.RemoveSource,
+	}, opts.Output)
+}
+
+func emitMigrateOutput(cmd *cobra.Command, report migrateReport, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetEscapeHTML(false)
+		return enc.Encode(report)
+	}
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Migrated state for release %s in namespace %s (%d history entries)\n", report.Release, report.Namespace, report.Entries)
+	fmt.Fprintf(out, "From: %s\n", report.Source)
+	fmt.Fprintf(out, "To:   %s\n", report.Destination)
+	if report.RemovedSource {
+		fmt.Fprintln(out, "Source state removed")
+	}
+	return nil
+}
+
+func backendName(backend string) string {
+	if strings.TrimSpace(backend) == "" {
+		return pkgstate.BackendFile
+	}
+	return backend
+}
+
+func describeLocation(backend, filePath string, record pkgstate.Record) string {
+	if backendName(backend) == pkgstate.BackendSecret {
+		return "secret://" + record.Namespace + "/" + pkgstate.StateSecretName(record.Release)
+	}
+	return filePath
+}
@@ -0,0 +1,103 @@
+package state_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes/fake"
+
+	statecmd "github.com/dobrovols/chainctl/cmd/chainctl/state"
+	internalstate "github.com/dobrovols/chainctl/internal/state"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+)
+
+func newCommand() (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetContext(context.Background())
+	return cmd, &out
+}
+
+func seedFileState(t *testing.T, path string) *pkgstate.Manager {
+	t.Helper()
+	manager := pkgstate.NewManager(internalstate.NewResolver())
+	for _, version := range []string{"1.2.3", "1.3.0"} {
+		record := pkgstate.Record{
+			Release:    "myapp",
+			Namespace:  "demo",
+			Chart:      pkgstate.ChartSource{Type: "oci", Reference: "oci://registry.example.com/apps/myapp:" + version, Digest: "sha256:abc"},
+			Version:    version,
+			LastAction: "upgrade",
+			Timestamp:  "2025-01-01T00:00:00Z",
+		}
+		if _, err := manager.Write(record, pkgstate.Overrides{StateFilePath: path}); err != nil {
+			t.Fatalf("seed state: %v", err)
+		}
+	}
+	return manager
+}
+
+func TestStateMigrate_FileToSecretPreservesHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	source := seedFileState(t, path)
+	destination := pkgstate.NewSecretStore(fake.NewSimpleClientset())
+	deps := statecmd.MigrateDeps{Source: source, Destination: destination}
+	opts := statecmd.MigrateOptions{From: "file", To: "secret", StateFilePath: path, Output: "json"}
+
+	cmd, out := newCommand()
+	if err := statecmd.RunMigrateForTest(cmd, opts, deps); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	var report map[string]any
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v (%s)", err, out.String())
+	}
+	if report["destination"] != "secret://demo/chainctl-state-myapp" || report["source"] != path || report["historyEntries"] != float64(2) {
+		t.Fatalf("unexpected report %v", report)
+	}
+
+	migrated, err := destination.Read(pkgstate.Overrides{Namespace: "demo"})
+	if err != nil {
+		t.Fatalf("read secret state: %v", err)
+	}
+	if migrated.Version != "1.3.0" || len(migrated.History) != 2 || migrated.History[0].Version != "1.2.3" {
+		t.Fatalf("expected history preserved, got %+v", migrated)
+	}
+
+	cmd, _ = newCommand()
+	if err := statecmd.RunMigrateForTest(cmd, opts, deps); !errors.Is(err, statecmd.ErrDestinationExists()) {
+		t.Fatalf("expected destination exists error, got %v", err)
+	}
+
+	opts.Force, opts.RemoveSource, opts.Output = true, true, "text"
+	cmd, out = newCommand()
+	if err := statecmd.RunMigrateForTest(cmd, opts, deps); err != nil {
+		t.Fatalf("forced migrate: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected source state removed, stat returned %v", err)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("Source state removed")) {
+		t.Fatalf("expected removal notice, got %s", out.String())
+	}
+}
+
+func TestStateMigrate_ValidatesBackends(t *testing.T) {
+	cmd, _ := newCommand()
+	err := statecmd.RunMigrateForTest(cmd, statecmd.MigrateOptions{From: "secret", To: "secret", Output: "text"}, statecmd.MigrateDeps{})
+	if !errors.Is(err, statecmd.ErrSameBackend()) {
+		t.Fatalf("expected same backend error, got %v", err)
+	}
+	err = statecmd.RunMigrateForTest(cmd, statecmd.MigrateOptions{From: "file", To: "configmap", Output: "text"}, statecmd.MigrateDeps{})
+	if !errors.Is(err, pkgstate.ErrUnknownBackend()) {
+		t.Fatalf("expected unknown backend error, got %v", err)
+	}
+}
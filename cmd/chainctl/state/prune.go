@@ -0,0 +1,227 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dobrovols/chainctl/internal/kubeclient"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+// PruneOptions holds CLI flags for state prune.
+type PruneOptions struct {
+	Namespace       string
+	ClusterEndpoint string
+	StateFileName   string
+	StateFilePath   string
+	HistoryLimit    int
+	DryRun          bool
+	Output          string
+}
+
+// PruneDeps defines dependencies required by the prune command.
+type PruneDeps struct {
+	Store DocumentStore
+	// Releases looks up Helm releases in the target cluster selected by the active kubeconfig.
+	Releases helm.StatusReader
+	// ActiveCluster reports the API server endpoint Releases talks to; only records for that
+	// cluster are looked up.
+	ActiveCluster func() (string, error)
+}
+
+var errPruneClusterMismatch = errors.New("--cluster-endpoint does not match the cluster of the active kubeconfig")
+
+// ErrPruneClusterMismatch exposes the sentinel returned when --cluster-endpoint names a cluster
+// other than the one prune would query.
+func ErrPruneClusterMismatch() error { return errPruneClusterMismatch }
+
+type prunedRecord struct {
+	Release         string `json:"release"`
+	Namespace       string `json:"namespace"`
+	ClusterEndpoint string `json:"clusterEndpoint,omitempty"`
+}
+
+type pruneReport struct {
+	DryRun    bool           `json:"dryRun"`
+	StateFile string         `json:"stateFile"`
+	Cluster   string         `json:"cluster"`
+	Removed   []prunedRecord `json:"removed"`
+	// Skipped lists records of other clusters, or without a cluster endpoint, that were not looked up.
+	Skipped []prunedRecord `json:"skipped"`
+	Kept    int            `json:"kept"`
+}
+
+// NewPruneCommand constructs the `chainctl state prune` command.
+func NewPruneCommand() *cobra.Command {
+	opts := PruneOptions{}
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove state records for releases that no longer exist in the target cluster",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return runPrune(cmd, opts, PruneDeps{})
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Namespace, "namespace", "", "Only consider records in this namespace")
+	cmd.Flags().StringVar(&opts.ClusterEndpoint, "cluster-endpoint", "", "Only consider records for this cluster endpoint; it must be the cluster of the active kubeconfig")
+	bindStateFileFlags(cmd, &opts.StateFilePath, &opts.StateFileName)
+	bindHistoryLimitFlag(cmd, &opts.HistoryLimit)
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Report the records that would be removed without changing the state file")
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// RunPruneForTest executes the prune flow with injected dependencies.
+func RunPruneForTest(cmd *cobra.Command, opts PruneOptions, deps PruneDeps) error {
+	cmd.SilenceUsage = true
+	return runPrune(cmd, opts, deps)
+}
+
+func runPrune(cmd *cobra.Command, opts PruneOptions, deps PruneDeps) (err error) {
+	if opts.Output != "text" && opts.Output != "json" {
+		return errUnsupportedOutput
+	}
+	if deps.Store == nil {
+		deps.Store = defaultStore(pkgstate.WithHistoryLimit(opts.HistoryLimit))
+	}
+	if deps.Releases == nil {
+		deps.Releases = helm.NewSDKExecutor()
+	}
+	if deps.ActiveCluster == nil {
+		deps.ActiveCluster = activeClusterEndpoint
+	}
+	logger, err := telemetry.NewCommandLogger(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{"dryRun": strconv.FormatBool(opts.DryRun)}
+	logWorkflowStart(logger, stepStatePrune, metadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, stepStatePrune, metadata, err)
+		}
+	}()
+
+	overrides := pkgstate.Overrides{
+		StateFileName:   opts.StateFileName,
+		StateFilePath:   opts.StateFilePath,
+		Namespace:       opts.Namespace,
+		ClusterEndpoint: opts.ClusterEndpoint,
+	}
+	cluster, err := deps.ActiveCluster()
+	if err != nil {
+		return err
+	}
+	metadata["cluster"] = cluster
+	if opts.ClusterEndpoint != "" && !sameClusterEndpoint(opts.ClusterEndpoint, cluster) {
+		return fmt.Errorf("%w: %s requested, %s active", errPruneClusterMismatch, opts.ClusterEndpoint, cluster)
+	}
+	doc, path, err := deps.Store.Load(overrides)
+	if err != nil && !errors.Is(err, pkgstate.ErrNotFound()) {
+		return err
+	}
+
+	report := pruneReport{DryRun: opts.DryRun, StateFile: path, Cluster: cluster, Removed: []prunedRecord{}, Skipped: []prunedRecord{}}
+	var stale []pkgstate.RecordKey
+	for _, record := range doc.Select(overrides) {
+		// A release missing from the active cluster says nothing about records of other clusters.
+		if !sameClusterEndpoint(record.ClusterEndpoint, cluster) {
+			report.Skipped = append(report.Skipped, prunedRecord{Release: record.Release, Namespace: record.Namespace, ClusterEndpoint: record.ClusterEndpoint})
+			continue
+		}
+		_, err := deps.Releases.ReleaseStatus(record.Release, record.Namespace)
+		switch {
+		case errors.Is(err, helm.ErrReleaseNotFound()):
+			stale = append(stale, record.Key())
+			report.Removed = append(report.Removed, prunedRecord{Release: record.Release, Namespace: record.Namespace, ClusterEndpoint: record.ClusterEndpoint})
+		case err != nil:
+			return fmt.Errorf("look up release %s: %w", recordLabel(record), err)
+		}
+	}
+	report.Kept = len(doc.Records) - len(stale)
+
+	if !opts.DryRun && len(stale) > 0 {
+		_, err = deps.Store.Update(overrides, func(current *pkgstate.Document) error {
+			for _, key := range stale {
+				current.Delete(key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	metadata["removed"] = strconv.Itoa(len(report.Removed))
+	metadata["skipped"] = strconv.Itoa(len(report.Skipped))
+
+	logWorkflowSuccess(logger, stepStatePrune, metadata)
+	return emitPruneOutput(cmd, report, opts.Output)
+}
+
+func emitPruneOutput(cmd *cobra.Command, report pruneReport, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetEscapeHTML(false)
+		return enc.Encode(report)
+	}
+	out := cmd.OutOrStdout()
+	verb := "Removed"
+	if report.DryRun {
+		verb = "Would remove"
+	}
+	for _, record := range report.Removed {
+		fmt.Fprintf(out, "%s state for %s\n", verb, recordLabel(pkgstate.Record{Release: record.Release, Namespace: record.Namespace, ClusterEndpoint: record.ClusterEndpoint}))
+	}
+	for _, record := range report.Skipped {
+		fmt.Fprintf(out, "Skipped %s: not recorded for the active cluster %s\n", recordLabel(pkgstate.Record{Release: record.Release, Namespace: record.Namespace, ClusterEndpoint: record.ClusterEndpoint}), report.Cluster)
+	}
+	fmt.Fprintf(out, "%s %d records from %s; %d kept\n", verb, len(report.Removed), report.StateFile, report.Kept)
+	return nil
+}
+
+// activeClusterEndpoint returns the API server of the in-cluster configuration or the active
+// kubeconfig context, the cluster the default Helm executor talks to.
+func activeClusterEndpoint() (string, error) {
+	cfg, err := kubeclient.RESTConfig()
+	if err != nil {
+		return "", fmt.Errorf("kubernetes client: %w", err)
+	}
+	return cfg.Host, nil
+}
+
+// sameClusterEndpoint compares API server endpoints ignoring case, trailing slashes, and default
+// ports. An empty endpoint matches nothing, since its cluster cannot be confirmed.
+func sameClusterEndpoint(a, b string) bool {
+	left, right := normalizeEndpoint(a), normalizeEndpoint(b)
+	return left != "" && left == right
+}
+
+func normalizeEndpoint(endpoint string) string {
+	endpoint = strings.ToLower(strings.TrimSpace(endpoint))
+	if endpoint == "" {
+		return ""
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return strings.TrimRight(endpoint, "/")
+	}
+	port := parsed.Port()
+	if port == "" {
+		port = map[string]string{"https": "443", "http": "80"}[parsed.Scheme]
+	}
+	return parsed.Scheme + "://" + parsed.Hostname() + ":" + port + strings.TrimRight(parsed.Path, "/")
+}
@@ -0,0 +1,153 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+// ShowOptions holds CLI flags for state show.
+type ShowOptions struct {
+	ReleaseName     string
+	Namespace       string
+	ClusterEndpoint string
+	StateFileName   string
+	StateFilePath   string
+	Passphrase      string
+	Output          string
+}
+
+type showReport struct {
+	StateFile string          `json:"stateFile"`
+	Record    pkgstate.Record `json:"record"`
+}
+
+// NewShowCommand constructs the `chainctl state show` command.
+func NewShowCommand() *cobra.Command {
+	opts := ShowOptions{}
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the state recorded for one release",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return runShow(cmd, opts, defaultStore())
+		},
+	}
+
+	bindSelectorFlags(cmd, &opts.ReleaseName, &opts.Namespace, &opts.ClusterEndpoint)
+	bindStateFileFlags(cmd, &opts.StateFilePath, &opts.StateFileName)
+	cmd.Flags().StringVar(&opts.Passphrase, "passphrase", "", "Passphrase for an encrypted state file (defaults to $"+pkgstate.PassphraseEnv+", prompts when interactive)")
+	cmd.Flags().StringVar(&opts.Output, "output", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// RunShowForTest executes the show flow against the provided store.
+func RunShowForTest(cmd *cobra.Command, opts ShowOptions, store DocumentStore) error {
+	cmd.SilenceUsage = true
+	return runShow(cmd, opts, store)
+}
+
+func runShow(cmd *cobra.Command, opts ShowOptions, store DocumentStore) (err error) {
+	if opts.Output != "text" && opts.Output != "json" {
+		return errUnsupportedOutput
+	}
+	logger, err := telemetry.NewCommandLogger(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{"release": opts.ReleaseName, "namespace": opts.Namespace}
+	logWorkflowStart(logger, stepStateShow, metadata)
+	defer func() {
+		if err != nil {
+			logWorkflowFailure(logger, stepStateShow, metadata, err)
+		}
+	}()
+
+	overrides := pkgstate.Overrides{
+		StateFileName:   opts.StateFileName,
+		StateFilePath:   opts.StateFilePath,
+		Release:         opts.ReleaseName,
+		Namespace:       opts.Namespace,
+		ClusterEndpoint: opts.ClusterEndpoint,
+	}
+	doc, path, err := loadDecrypted(cmd, store, overrides, opts.Passphrase)
+	if err != nil {
+		return err
+	}
+	record, err := doc.Find(overrides)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, path)
+	}
+
+	logWorkflowSuccess(logger, stepStateShow, metadata)
+	return emitShowOutput(cmd, showReport{StateFile: path, Record: record}, opts.Output)
+}
+
+// loadDecrypted loads the state document, opening an encrypted file with the given passphrase or,
+// when none is configured and stdin is a terminal, one prompted for on demand.
+func loadDecrypted(cmd *cobra.Command, store DocumentStore, overrides pkgstate.Overrides, passphrase string) (pkgstate.Document, string, error) {
+	manager, ok := store.(*pkgstate.Manager)
+	if !ok {
+		return store.Load(overrides)
+	}
+	if passphrase != "" {
+		return manager.WithPassphrase(passphrase).Load(overrides)
+	}
+	doc, path, err := manager.Load(overrides)
+	if !errors.Is(err, pkgstate.ErrEncrypted()) {
+		return doc, path, err
+	}
+	fd := stdinFD()
+	if !isTerminal(fd) {
+		return doc, path, err
+	}
+	fmt.Fprint(cmd.ErrOrStderr(), "State passphrase: ")
+	pass, readErr := readPassword(fd)
+	fmt.Fprintln(cmd.ErrOrStderr())
+	if readErr != nil {
+		return doc, path, readErr
+	}
+	passphrase = string(pass)
+	for i := range pass {
+		pass[i] = 0
+	}
+	if passphrase == "" {
+		return doc, path, err
+	}
+	return manager.WithPassphrase(passphrase).Load(overrides)
+}
+
+func emitShowOutput(cmd *cobra.Command, report showReport, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetEscapeHTML(false)
+		return enc.Encode(report)
+	}
+
+	record := report.Record
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Release:\t%s\n", record.Release)
+	fmt.Fprintf(tw, "Namespace:\t%s\n", record.Namespace)
+	fmt.Fprintf(tw, "Cluster:\t%s\n", cell(record.ClusterEndpoint))
+	fmt.Fprintf(tw, "Chart:\t%s (%s)\n", record.Chart.Reference, record.Chart.Type)
+	fmt.Fprintf(tw, "Digest:\t%s\n", cell(record.Chart.Digest))
+	fmt.Fprintf(tw, "Version:\t%s\n", cell(record.Version))
+	fmt.Fprintf(tw, "Last action:\t%s at %s\n", record.LastAction, record.Timestamp)
+	if record.Revision > 0 {
+		fmt.Fprintf(tw, "Revision:\t%d (%s)\n", record.Revision, cell(record.Status))
+	}
+	if record.Operator != "" || record.WorkflowID != "" {
+		fmt.Fprintf(tw, "Operator:\t%s (workflow %s)\n", cell(record.Operator), cell(record.WorkflowID))
+	}
+	fmt.Fprintf(tw, "History:\t%d entries\n", len(record.History))
+	fmt.Fprintf(tw, "State file:\t%s\n", report.StateFile)
+	return tw.Flush()
+}
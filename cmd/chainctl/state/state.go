@@ -0,0 +1,80 @@
+package state
+
+import (
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	internalstate "github.com/dobrovols/chainctl/internal/state"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+)
+
+var (
+	isTerminal   = term.IsTerminal
+	readPassword = term.ReadPassword
+	stdinFD      = func() int { return int(os.Stdin.Fd()) }
+)
+
+var errUnsupportedOutput = errors.New("unsupported output format")
+
+// ErrUnsupportedOutput exposes the sentinel.
+func ErrUnsupportedOutput() error { return errUnsupportedOutput }
+
+// DocumentStore reads and rewrites the state document; *pkgstate.Manager implements it.
+type DocumentStore interface {
+	Load(pkgstate.Overrides) (pkgstate.Document, string, error)
+	Update(pkgstate.Overrides, func(*pkgstate.Document) error) (string, error)
+}
+
+// NewStateCommand creates the `chainctl state` parent command.
+func NewStateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Inspect, export, import, and prune recorded application state",
+	}
+
+	cmd.AddCommand(NewShowCommand())
+	cmd.AddCommand(NewListCommand())
+	cmd.AddCommand(NewExportCommand())
+	cmd.AddCommand(NewImportCommand())
+	cmd.AddCommand(NewPruneCommand())
+	cmd.AddCommand(NewMigrateCommand())
+
+	return cmd
+}
+
+func defaultStore(opts ...pkgstate.ManagerOption) DocumentStore {
+	return internalstate.NewFileManager(opts...)
+}
+
+func bindStateFileFlags(cmd *cobra.Command, path, name *string) {
+	cmd.Flags().StringVar(path, "state-file", "", "Absolute path of the state JSON")
+	cmd.Flags().StringVar(name, "state-file-name", "", "Custom state file name within the config directory")
+}
+
+func bindHistoryLimitFlag(cmd *cobra.Command, limit *int) {
+	cmd.Flags().IntVar(limit, "state-history-limit", pkgstate.DefaultHistoryLimit, "History entries retained per release when the state is rewritten, oldest dropped first (0 keeps the full history)")
+}
+
+func bindSelectorFlags(cmd *cobra.Command, release, namespace, cluster *string) {
+	cmd.Flags().StringVar(release, "release-name", "", "Select records for this Helm release")
+	cmd.Flags().StringVar(namespace, "namespace", "", "Select records in this namespace")
+	cmd.Flags().StringVar(cluster, "cluster-endpoint", "", "Select records for this cluster endpoint")
+}
+
+func recordLabel(record pkgstate.Record) string {
+	label := record.Namespace + "/" + record.Release
+	if record.ClusterEndpoint != "" {
+		label += " (" + record.ClusterEndpoint + ")"
+	}
+	return label
+}
+
+func cell(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,289 @@
+package state_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	statecmd "github.com/dobrovols/chainctl/cmd/chainctl/state"
+	internalstate "github.com/dobrovols/chainctl/internal/state"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+)
+
+type fakeReleases struct {
+	deployed map[string]bool
+	err      error
+}
+
+func (f fakeReleases) ReleaseStatus(release, namespace string) (helm.ReleaseInfo, error) {
+	if f.err != nil {
+		return helm.ReleaseInfo{}, f.err
+	}
+	if !f.deployed[namespace+"/"+release] {
+		return helm.ReleaseInfo{}, helm.ErrReleaseNotFound()
+	}
+	return helm.ReleaseInfo{Name: release, Namespace: namespace, Revision: 1, Status: "deployed"}, nil
+}
+
+func activeCluster() (string, error) { return "https://cluster.local:6443", nil }
+
+func seedReleases(t *testing.T, path string, releases ...string) *pkgstate.Manager {
+	t.Helper()
+	manager := pkgstate.NewManager(internalstate.NewResolver())
+	for _, release := range releases {
+		record := pkgstate.Record{
+			Release:         release,
+			Namespace:       "demo",
+			ClusterEndpoint: "https://cluster.local:6443",
+			Chart:           pkgstate.ChartSource{Type: "oci", Reference: "oci://registry.example.com/apps/" + release + ":1.0.0"},
+			Version:         "1.0.0",
+			LastAction:      "install",
+			Timestamp:       "2025-01-01T00:00:00Z",
+		}
+		if _, err := manager.Write(record, pkgstate.Overrides{StateFilePath: path}); err != nil {
+			t.Fatalf("seed state: %v", err)
+		}
+	}
+	return manager
+}
+
+func TestStateShowAndList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	store := seedReleases(t, path, "api", "web")
+
+	cmd, out := newCommand()
+	if err := statecmd.RunListForTest(cmd, statecmd.ListOptions{StateFilePath: path, Output: "text"}, store); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if !strings.Contains(out.String(), "RELEASE") || !strings.Contains(out.String(), "api") || !strings.Contains(out.String(), "web") {
+		t.Fatalf("unexpected list output:\n%s", out.String())
+	}
+
+	cmd, _ = newCommand()
+	err := statecmd.RunShowForTest(cmd, statecmd.ShowOptions{StateFilePath: path, Output: "text"}, store)
+	if !errors.Is(err, pkgstate.ErrAmbiguousState()) {
+		t.Fatalf("expected ambiguous state without a release selector, got %v", err)
+	}
+
+	cmd, out = newCommand()
+	if err := statecmd.RunShowForTest(cmd, statecmd.ShowOptions{StateFilePath: path, ReleaseName: "web", Namespace: "demo", Output: "json"}, store); err != nil {
+		t.Fatalf("show: %v", err)
+	}
+	var report struct {
+		StateFile string          `json:"stateFile"`
+		Record    pkgstate.Record `json:"record"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("decode show output: %v", err)
+	}
+	if report.StateFile != path || report.Record.Release != "web" {
+		t.Fatalf("unexpected show report %+v", report)
+	}
+}
+
+func TestStateShow_DecryptsOnDemand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	encrypted := pkgstate.NewManager(internalstate.NewResolver()).WithPassphrase("s3cret")
+	record := pkgstate.Record{Release: "api", Namespace: "demo", Chart: pkgstate.ChartSource{Type: "oci", Reference: "oci://registry.example.com/apps/api:1.0.0"}, LastAction: "install"}
+	if _, err := encrypted.Write(record, pkgstate.Overrides{StateFilePath: path}); err != nil {
+		t.Fatalf("seed encrypted state: %v", err)
+	}
+	store := pkgstate.NewManager(internalstate.NewResolver())
+
+	cmd, _ := newCommand()
+	err := statecmd.RunShowForTest(cmd, statecmd.ShowOptions{StateFilePath: path, Output: "text"}, store)
+	if !errors.Is(err, pkgstate.ErrEncrypted()) {
+		t.Fatalf("expected encrypted state to need a passphrase, got %v", err)
+	}
+
+	cmd, out := newCommand()
+	if err := statecmd.RunShowForTest(cmd, statecmd.ShowOptions{StateFilePath: path, Passphrase: "s3cret", Output: "text"}, store); err != nil {
+		t.Fatalf("show with passphrase: %v", err)
+	}
+	if !strings.Contains(out.String(), "oci://registry.example.com/apps/api:1.0.0") {
+		t.Fatalf("unexpected show output:\n%s", out.String())
+	}
+}
+
+func TestStateList_MissingFileIsEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	cmd, out := newCommand()
+	store := pkgstate.NewManager(internalstate.NewResolver())
+	if err := statecmd.RunListForTest(cmd, statecmd.ListOptions{StateFilePath: path, Output: "text"}, store); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if !strings.Contains(out.String(), "No releases recorded") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
+
+func TestStateExportImportRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "source.json")
+	store := seedReleases(t, source, "api", "web")
+
+	cmd, out := newCommand()
+	if err := statecmd.RunExportForTest(cmd, statecmd.ExportOptions{StateFilePath: source, ReleaseName: "api"}, store); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	exported := out.Bytes()
+	if err := pkgstate.ValidateDocument(exported); err != nil {
+		t.Fatalf("export does not satisfy schema: %v", err)
+	}
+
+	destination := filepath.Join(dir, "destination.json")
+	cmd, out = newCommand()
+	cmd.SetIn(bytes.NewReader(exported))
+	if err := statecmd.RunImportForTest(cmd, statecmd.ImportOptions{File: "-", StateFilePath: destination, Output: "text"}, store); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if !strings.Contains(out.String(), "Imported 1 records into "+destination) {
+		t.Fatalf("unexpected import output %q", out.String())
+	}
+	doc, _, err := store.Load(pkgstate.Overrides{StateFilePath: destination})
+	if err != nil {
+		t.Fatalf("load imported state: %v", err)
+	}
+	if len(doc.Records) != 1 || doc.Records[0].Release != "api" {
+		t.Fatalf("unexpected imported records %+v", doc.Records)
+	}
+
+	cmd, _ = newCommand()
+	cmd.SetIn(bytes.NewReader(exported))
+	err = statecmd.RunImportForTest(cmd, statecmd.ImportOptions{File: "-", StateFilePath: destination, Output: "text"}, store)
+	if !errors.Is(err, statecmd.ErrImportConflict()) {
+		t.Fatalf("expected conflict, got %v", err)
+	}
+
+	cmd, out = newCommand()
+	cmd.SetIn(bytes.NewReader(exported))
+	if err := statecmd.RunImportForTest(cmd, statecmd.ImportOptions{File: "-", StateFilePath: destination, Overwrite: true, Output: "json"}, store); err != nil {
+		t.Fatalf("import with overwrite: %v", err)
+	}
+	var report struct {
+		Replaced []string `json:"replaced"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil || len(report.Replaced) != 1 {
+		t.Fatalf("expected one replaced record, got %s (%v)", out.String(), err)
+	}
+}
+
+func TestStateImport_RejectsInvalidDocument(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "import.json")
+	if err := os.WriteFile(file, []byte(`{"schemaVersion":2,"records":[{"release":"api"}]}`), 0o600); err != nil {
+		t.Fatalf("write import: %v", err)
+	}
+	cmd, _ := newCommand()
+	store := pkgstate.NewManager(internalstate.NewResolver())
+	err := statecmd.RunImportForTest(cmd, statecmd.ImportOptions{File: file, StateFilePath: filepath.Join(dir, "state.json"), Output: "text"}, store)
+	if !errors.Is(err, pkgstate.ErrSchemaViolation()) {
+		t.Fatalf("expected schema violation, got %v", err)
+	}
+
+	cmd, _ = newCommand()
+	if err := statecmd.RunImportForTest(cmd, statecmd.ImportOptions{Output: "text"}, store); !errors.Is(err, statecmd.ErrImportFileRequired()) {
+		t.Fatalf("expected missing file error, got %v", err)
+	}
+}
+
+func TestStatePrune_RemovesMissingReleases(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	store := seedReleases(t, path, "api", "web")
+	// The same release recorded for another cluster is absent from the active one but must survive.
+	other := pkgstate.Record{
+		Release:         "api",
+		Namespace:       "demo",
+		ClusterEndpoint: "https://other.example.com:6443",
+		Chart:           pkgstate.ChartSource{Type: "oci", Reference: "oci://registry.example.com/apps/api:1.0.0"},
+		Version:         "1.0.0",
+		LastAction:      "install",
+		Timestamp:       "2025-01-01T00:00:00Z",
+	}
+	if _, err := store.Write(other, pkgstate.Overrides{StateFilePath: path}); err != nil {
+		t.Fatalf("seed other cluster: %v", err)
+	}
+	deps := statecmd.PruneDeps{Store: store, Releases: fakeReleases{deployed: map[string]bool{"demo/web": true}}, ActiveCluster: activeCluster}
+
+	cmd, out := newCommand()
+	if err := statecmd.RunPruneForTest(cmd, statecmd.PruneOptions{StateFilePath: path, DryRun: true, Output: "text"}, deps); err != nil {
+		t.Fatalf("prune dry-run: %v", err)
+	}
+	if !strings.Contains(out.String(), "Would remove state for demo/api (https://cluster.local:6443)") || !strings.Contains(out.String(), "Skipped demo/api (https://other.example.com:6443)") {
+		t.Fatalf("unexpected dry-run output %q", out.String())
+	}
+	if doc, _, _ := store.Load(pkgstate.Overrides{StateFilePath: path}); len(doc.Records) != 3 {
+		t.Fatalf("dry-run must not modify state, got %d records", len(doc.Records))
+	}
+
+	cmd, out = newCommand()
+	if err := statecmd.RunPruneForTest(cmd, statecmd.PruneOptions{StateFilePath: path, Output: "json"}, deps); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	var report struct {
+		Cluster string `json:"cluster"`
+		Removed []struct {
+			Release string `json:"release"`
+		} `json:"removed"`
+		Skipped []struct {
+			Release         string `json:"release"`
+			ClusterEndpoint string `json:"clusterEndpoint"`
+		} `json:"skipped"`
+		Kept int `json:"kept"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("decode prune report: %v", err)
+	}
+	if len(report.Removed) != 1 || report.Removed[0].Release != "api" || report.Kept != 2 || report.Cluster != "https://cluster.local:6443" {
+		t.Fatalf("unexpected prune report %s", out.String())
+	}
+	if len(report.Skipped) != 1 || report.Skipped[0].ClusterEndpoint != other.ClusterEndpoint {
+		t.Fatalf("expected the other cluster's record to be skipped, got %s", out.String())
+	}
+	doc, _, err := store.Load(pkgstate.Overrides{StateFilePath: path})
+	if err != nil || len(doc.Records) != 2 {
+		t.Fatalf("expected web and the other cluster's api to remain, got %+v (%v)", doc.Records, err)
+	}
+	if _, found := doc.Lookup(other.Key()); !found {
+		t.Fatalf("expected the other cluster's record to be kept, got %+v", doc.Records)
+	}
+}
+
+func TestStatePrune_RejectsInactiveClusterEndpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	store := seedReleases(t, path, "api")
+	deps := statecmd.PruneDeps{Store: store, Releases: fakeReleases{}, ActiveCluster: activeCluster}
+
+	cmd, _ := newCommand()
+	opts := statecmd.PruneOptions{StateFilePath: path, ClusterEndpoint: "https://other.example.com:6443", Output: "text"}
+	if err := statecmd.RunPruneForTest(cmd, opts, deps); !errors.Is(err, statecmd.ErrPruneClusterMismatch()) {
+		t.Fatalf("expected cluster mismatch error, got %v", err)
+	}
+
+	// The endpoint matches regardless of case, trailing slash, or an explicit default port.
+	deps.ActiveCluster = func() (string, error) { return "HTTPS://Cluster.Local:6443/", nil }
+	cmd, _ = newCommand()
+	opts.ClusterEndpoint = "https://cluster.local:6443"
+	if err := statecmd.RunPruneForTest(cmd, opts, deps); err != nil {
+		t.Fatalf("expected matching endpoint to be accepted, got %v", err)
+	}
+}
+
+func TestStatePrune_StopsOnLookupFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	store := seedReleases(t, path, "api")
+	deps := statecmd.PruneDeps{Store: store, Releases: fakeReleases{err: errors.New("cluster unreachable")}, ActiveCluster: activeCluster}
+
+	cmd, _ := newCommand()
+	if err := statecmd.RunPruneForTest(cmd, statecmd.PruneOptions{StateFilePath: path, Output: "text"}, deps); err == nil {
+		t.Fatal("expected lookup failure to abort prune")
+	}
+	if doc, _, _ := store.Load(pkgstate.Overrides{StateFilePath: path}); len(doc.Records) != 1 {
+		t.Fatalf("state must be untouched after a failed lookup, got %d records", len(doc.Records))
+	}
+}
@@ -0,0 +1,29 @@
+// Package exitcode carries process exit codes from commands back to the chainctl entrypoint.
+package exitcode
+
+// Exit codes reserved by commands that report outcomes through the process status.
+const (
+	// ChangesDetected signals that a preview command found pending changes.
+	ChangesDetected = 2
+	// DriftDetected signals that a verification command found the live release diverging from state.
+	DriftDetected = 3
+)
+
+// Error represents a command outcome that should terminate the process with a specific exit code.
+type Error struct {
+	Code int
+	Err  error
+}
+
+// New constructs an Error wrapper.
+func New(code int, err error) *Error {
+	return &Error{Code: code, Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
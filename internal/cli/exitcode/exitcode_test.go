@@ -0,0 +1,20 @@
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorUnwrapsAndCarriesCode(t *testing.T) {
+	base := errors.New("changes detected")
+	err := fmt.Errorf("diff: %w", New(ChangesDetected, base))
+
+	var exitErr *Error
+	if !errors.As(err, &exitErr) || exitErr.Code != ChangesDetected {
+		t.Fatalf("expected exit code %d, got %v", ChangesDetected, err)
+	}
+	if !errors.Is(err, base) || exitErr.Error() != "changes detected" {
+		t.Fatalf("expected wrapped error, got %v", exitErr)
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"github.com/spf13/cobra"
 
 	appcmd "github.com/dobrovols/chainctl/cmd/chainctl/app"
+	bundlecmd "github.com/dobrovols/chainctl/cmd/chainctl/bundle"
+	cachecmd "github.com/dobrovols/chainctl/cmd/chainctl/cache"
 	clustercmd "github.com/dobrovols/chainctl/cmd/chainctl/cluster"
 	"github.com/dobrovols/chainctl/cmd/chainctl/declarative"
 	nodecmd "github.com/dobrovols/chainctl/cmd/chainctl/node"
+	registrycmd "github.com/dobrovols/chainctl/cmd/chainctl/registry"
 	secretcmd "github.com/dobrovols/chainctl/cmd/chainctl/secrets"
+	statecmd "github.com/dobrovols/chainctl/cmd/chainctl/state"
 )
 
 // NewRootCommand constructs the root chainctl command.
@@ -21,6 +25,10 @@ func NewRootCommand() *cobra.Command {
 	cmd.AddCommand(nodecmd.NewNodeCommand())
 	cmd.AddCommand(clustercmd.NewClusterCommand())
 	cmd.AddCommand(appcmd.NewAppCommand())
+	cmd.AddCommand(cachecmd.NewCacheCommand())
+	cmd.AddCommand(registrycmd.NewRegistryCommand())
+	cmd.AddCommand(statecmd.NewStateCommand())
+	cmd.AddCommand(bundlecmd.NewBundleCommand())
 	declarative.NewManager(cmd).Bind(cmd)
 
 	return cmd
@@ -15,7 +15,7 @@ func TestNewRootCommandRegistersSubcommands(t *testing.T) {
 	for _, sub := range cmd.Commands() {
 		names[sub.Name()] = true
 	}
-	for _, expected := range []string{"encrypt-values", "node", "cluster", "app"} {
+	for _, expected := range []string{"encrypt-values", "node", "cluster", "app", "cache", "registry", "state", "bundle"} {
 		if !names[expected] {
 			t.Fatalf("expected subcommand %s to be registered", expected)
 		}
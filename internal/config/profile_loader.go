@@ -21,6 +21,9 @@ type LoadOptions struct {
 	ClusterEndpoint     string
 	AirgappedBundlePath string
 	EncryptedValuesPath string
+	ValuesFiles         []string
+	SetValues           []string
+	SetStringValues     []string
 	ValuesPassphrase    string
 	K3sVersion          string
 	HelmReleaseName     string
@@ -35,10 +38,15 @@ type Profile struct {
 	Airgapped       bool
 	BundlePath      string
 	EncryptedFile   string
+	ValuesFiles     []string
+	SetValues       []string
+	SetStringValues []string
 	Passphrase      string
 	K3sVersion      string
 	HelmRelease     string
 	HelmNamespace   string
+	ChartPath       string
+	ChartDigest     string
 }
 
 var (
@@ -76,18 +84,22 @@ func (o LoadOptions) Validate() (*Profile, error) {
 		return nil, errClusterEndpointReq
 	}
 
-	if strings.TrimSpace(o.EncryptedValuesPath) == "" {
+	valuesFiles := cleanPaths(append([]string{o.EncryptedValuesPath}, o.ValuesFiles...))
+	if len(valuesFiles) == 0 {
 		return nil, errEncryptedFileReq
 	}
 
 	profile := &Profile{
-		Mode:          o.Mode,
-		Airgapped:     o.Offline,
-		EncryptedFile: filepath.Clean(o.EncryptedValuesPath),
-		Passphrase:    o.ValuesPassphrase,
-		K3sVersion:    o.K3sVersion,
-		HelmRelease:   defaultString(o.HelmReleaseName, "chainapp"),
-		HelmNamespace: defaultString(o.HelmNamespace, "chain-system"),
+		Mode:            o.Mode,
+		Airgapped:       o.Offline,
+		EncryptedFile:   valuesFiles[0],
+		ValuesFiles:     valuesFiles,
+		SetValues:       append([]string(nil), o.SetValues...),
+		SetStringValues: append([]string(nil), o.SetStringValues...),
+		Passphrase:      o.ValuesPassphrase,
+		K3sVersion:      o.K3sVersion,
+		HelmRelease:     defaultString(o.HelmReleaseName, "chainapp"),
+		HelmNamespace:   defaultString(o.HelmNamespace, "chain-system"),
 	}
 
 	if o.Mode == ModeReuse {
@@ -104,6 +116,30 @@ func (o LoadOptions) Validate() (*Profile, error) {
 	return profile, nil
 }
 
+// cleanPaths drops blank entries and cleans the remaining paths, preserving order.
+func cleanPaths(paths []string) []string {
+	var out []string
+	for _, path := range paths {
+		if strings.TrimSpace(path) == "" {
+			continue
+		}
+		out = append(out, filepath.Clean(path))
+	}
+	return out
+}
+
+// ValuesLayers returns the values files in merge order. Profiles built without
+// ValuesFiles fall back to the single EncryptedFile.
+func (p *Profile) ValuesLayers() []string {
+	if len(p.ValuesFiles) > 0 {
+		return append([]string(nil), p.ValuesFiles...)
+	}
+	if strings.TrimSpace(p.EncryptedFile) == "" {
+		return nil
+	}
+	return []string{p.EncryptedFile}
+}
+
 func defaultString(val, fallback string) string {
 	if strings.TrimSpace(val) == "" {
 		return fallback
@@ -118,5 +154,5 @@ func (p *Profile) String() string {
 	if p.Passphrase == "" {
 		pass = "<none>"
 	}
-	return fmt.Sprintf("mode=%s endpoint=%s airgapped=%t bundle=%s encrypted=%s passphrase=%s", p.Mode, p.ClusterEndpoint, p.Airgapped, p.BundlePath, p.EncryptedFile, pass)
+	return fmt.Sprintf("mode=%s endpoint=%s airgapped=%t bundle=%s values=%s overrides=%d passphrase=%s", p.Mode, p.ClusterEndpoint, p.Airgapped, p.BundlePath, strings.Join(p.ValuesLayers(), ","), len(p.SetValues)+len(p.SetStringValues), pass)
 }
@@ -86,6 +86,33 @@ func TestValidateProfileReuseSuccess(t *testing.T) {
 	}
 }
 
+func TestValidateProfileLayersValuesFiles(t *testing.T) {
+	profile, err := (config.LoadOptions{
+		EncryptedValuesPath: "/etc/chain/base.yaml",
+		ValuesFiles:         []string{"", "/etc/chain/../chain/prod.yaml", "/etc/chain/secrets.enc"},
+		SetValues:           []string{"replicaCount=3"},
+		SetStringValues:     []string{"image.tag=1.10"},
+	}).Validate()
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	want := []string{"/etc/chain/base.yaml", "/etc/chain/prod.yaml", "/etc/chain/secrets.enc"}
+	if got := profile.ValuesLayers(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected values layers %v, got %v", want, got)
+	}
+	if profile.EncryptedFile != want[0] {
+		t.Fatalf("expected first layer to populate EncryptedFile, got %s", profile.EncryptedFile)
+	}
+	if len(profile.SetValues) != 1 || len(profile.SetStringValues) != 1 {
+		t.Fatalf("expected overrides carried through, got %v %v", profile.SetValues, profile.SetStringValues)
+	}
+
+	if _, err := (config.LoadOptions{ValuesFiles: []string{" "}}).Validate(); !errors.Is(err, config.ErrEncryptedFileRequired()) {
+		t.Fatalf("expected values file required error, got %v", err)
+	}
+}
+
 func TestErrEncryptedFileRequiredExposed(t *testing.T) {
 	if config.ErrEncryptedFileRequired() == nil {
 		t.Fatalf("expected non-nil error sentinel")
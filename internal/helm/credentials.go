@@ -0,0 +1,69 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"helm.sh/helm/v3/pkg/cli"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+
+	"github.com/dobrovols/chainctl/internal/state"
+	"github.com/dobrovols/chainctl/pkg/registryauth"
+)
+
+// registryCredentials returns the chainctl encrypted credential store, unlocked with the
+// passphrase from the environment when one is set.
+func registryCredentials() registryauth.Provider {
+	path, err := state.RegistryCredentialsPath()
+	if err != nil {
+		return registryauth.Chain{}
+	}
+	return registryauth.NewStore(path, os.Getenv(registryauth.PassphraseEnv))
+}
+
+// helmCredentials mirrors the lookup the Helm registry client performs on its own: Helm's
+// registry config with a docker config fallback, including credential helpers.
+func helmCredentials(settings *cli.EnvSettings) auth.CredentialFunc {
+	opts := credentials.StoreOptions{DetectDefaultNativeStore: true}
+	store, err := credentials.NewStore(settings.RegistryConfig, opts)
+	if err != nil {
+		return nil
+	}
+	if docker, err := credentials.NewStoreFromDocker(opts); err == nil {
+		return credentials.Credential(credentials.NewStoreWithFallbacks(store, docker))
+	}
+	return credentials.Credential(store)
+}
+
+// registryAuthorizer resolves credentials per registry host, preferring chainctl's store over the
+// Helm and docker configs. Credentials go straight to the HTTP authorizer and never reach telemetry.
+// Stored credentials that cannot be unlocked without a passphrase are skipped with a warning, once
+// per host, so the Helm and docker configs still apply.
+func registryAuthorizer(primary registryauth.Provider, fallback auth.CredentialFunc, warn io.Writer) auth.Client {
+	var warned sync.Map
+	return auth.Client{
+		Cache: auth.NewCache(),
+		Credential: func(ctx context.Context, hostport string) (auth.Credential, error) {
+			cred, ok, err := primary.Credential(ctx, hostport)
+			switch {
+			case errors.Is(err, registryauth.ErrPassphraseRequired()):
+				if _, seen := warned.LoadOrStore(hostport, true); !seen {
+					fmt.Fprintf(warn, "warning: skipping stored credentials: %v; falling back to Helm and docker registry configs\n", err)
+				}
+			case err != nil:
+				return auth.EmptyCredential, err
+			case ok:
+				return auth.Credential{Username: cred.Username, Password: cred.Password}, nil
+			}
+			if fallback == nil {
+				return auth.EmptyCredential, nil
+			}
+			return fallback(ctx, hostport)
+		},
+	}
+}
@@ -0,0 +1,147 @@
+// Package helm builds the chart resolver shared by chainctl commands, wiring Helm registry and
+// repository access to chainctl's registry credentials and chart cache.
+package helm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/registry"
+
+	"github.com/dobrovols/chainctl/internal/state"
+	"github.com/dobrovols/chainctl/pkg/chartcache"
+	pkghelm "github.com/dobrovols/chainctl/pkg/helm"
+)
+
+// NewChartResolver builds the chart resolver used by app and bundle commands: OCI charts are
+// pulled with the Helm registry configuration and chainctl registry credentials, repository
+// charts through index.yaml, and pulls are shared through the chart cache.
+func NewChartResolver(loader pkghelm.BundleLoader) *pkghelm.Resolver {
+	opts := []pkghelm.ResolverOption{pkghelm.WithRepoPuller(pkghelm.NewRepoPuller(getter.All(cli.New())))}
+	if dir, err := state.ChartCacheDirectory(); err == nil {
+		opts = append(opts, pkghelm.WithChartCache(chartcache.New(dir)))
+	}
+	puller, err := newOCIPuller()
+	if err != nil {
+		return pkghelm.NewResolver(nil, loader, opts...)
+	}
+	return pkghelm.NewResolver(puller, loader, opts...)
+}
+
+type ociPuller struct {
+	locate        func(chartRef, version string) (string, error)
+	fetchManifest func(ref string) (digest string, provenance []byte, err error)
+	chartVersion  func(path string) (string, error)
+}
+
+func newOCIPuller() (pkghelm.OCIPuller, error) {
+	settings := cli.New()
+	client, err := registry.NewClient(
+		registry.ClientOptCredentialsFile(settings.RegistryConfig),
+		registry.ClientOptEnableCache(true),
+		registry.ClientOptAuthorizer(registryAuthorizer(registryCredentials(), helmCredentials(settings), os.Stderr)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("initialise registry client: %w", err)
+	}
+
+	locator := action.NewInstall(&action.Configuration{RegistryClient: client})
+	locator.PassCredentialsAll = true
+
+	return &ociPuller{
+		locate: func(chartRef, version string) (string, error) {
+			locator.Version = version
+			return locator.LocateChart(chartRef, settings)
+		},
+		fetchManifest: func(ref string) (string, []byte, error) {
+			result, err := client.Pull(ref,
+				registry.PullOptWithChart(false),
+				registry.PullOptWithProv(true),
+				registry.PullOptIgnoreMissingProv(true),
+			)
+			if err != nil {
+				return "", nil, err
+			}
+			return result.Manifest.Digest, result.Prov.Data, nil
+		},
+		chartVersion: func(path string) (string, error) {
+			chrt, err := loader.Load(path)
+			if err != nil {
+				return "", err
+			}
+			return chrt.Metadata.Version, nil
+		},
+	}, nil
+}
+
+// Pull downloads the chart and records the manifest digest the registry served for it.
+// A provenance layer, when published, is stored next to the chart archive for verification.
+func (p *ociPuller) Pull(ctx context.Context, ref string) (pkghelm.PullResult, error) {
+	var path, lookup string
+	var err error
+	if strings.Contains(ref, "@") {
+		if path, err = p.locate(ref, ""); err != nil {
+			return pkghelm.PullResult{}, err
+		}
+		lookup = strings.TrimPrefix(ref, "oci://")
+	} else {
+		chartRef, version := splitOCIReference(ref)
+		if path, err = p.locate(chartRef, version); err != nil {
+			return pkghelm.PullResult{}, err
+		}
+		if version == "" {
+			// Without a tag Helm picks the latest version; resolve the tag it actually pulled.
+			if version, err = p.chartVersion(path); err != nil {
+				return pkghelm.PullResult{}, fmt.Errorf("read pulled chart version: %w", err)
+			}
+		}
+		lookup = strings.TrimPrefix(chartRef, "oci://") + ":" + strings.ReplaceAll(version, "+", "_")
+	}
+
+	digest, prov, err := p.fetchManifest(lookup)
+	if err != nil {
+		return pkghelm.PullResult{}, fmt.Errorf("resolve manifest digest for %s: %w", ref, err)
+	}
+	if len(prov) > 0 {
+		if err := os.WriteFile(path+".prov", prov, 0o644); err != nil {
+			return pkghelm.PullResult{}, fmt.Errorf("store chart provenance: %w", err)
+		}
+	}
+
+	return pkghelm.PullResult{
+		ChartPath: path,
+		Digest:    digest,
+	}, nil
+}
+
+// ResolveDigest looks up the manifest digest for a tagged reference without downloading the chart,
+// so the resolver can serve a cached copy. Untagged references resolve to no digest.
+func (p *ociPuller) ResolveDigest(_ context.Context, ref string) (string, error) {
+	if idx := strings.Index(ref, "@"); idx != -1 {
+		return ref[idx+1:], nil
+	}
+	chartRef, version := splitOCIReference(ref)
+	if version == "" {
+		return "", nil
+	}
+	digest, _, err := p.fetchManifest(strings.TrimPrefix(chartRef, "oci://") + ":" + strings.ReplaceAll(version, "+", "_"))
+	return digest, err
+}
+
+func splitOCIReference(ref string) (string, string) {
+	idx := strings.LastIndex(ref, ":")
+	if idx == -1 {
+		return ref, ""
+	}
+	lastSlash := strings.LastIndex(ref, "/")
+	if idx > lastSlash {
+		return ref[:idx], ref[idx+1:]
+	}
+	return ref, ""
+}
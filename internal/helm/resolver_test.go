@@ -0,0 +1,134 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"oras.land/oras-go/v2/registry/remote/auth"
+
+	"github.com/dobrovols/chainctl/pkg/registryauth"
+)
+
+func TestSplitOCIReferenceParsesVersion(t *testing.T) {
+	chart, version := splitOCIReference("oci://registry.local/apps/app:1.2.3")
+	if version != "1.2.3" {
+		t.Fatalf("expected version 1.2.3, got %s", version)
+	}
+	if chart != "oci://registry.local/apps/app" {
+		t.Fatalf("unexpected chart reference %s", chart)
+	}
+
+	chart, version = splitOCIReference("oci://registry.local:5000/apps/app")
+	if version != "" {
+		t.Fatalf("expected empty version when colon belongs to host, got %s", version)
+	}
+	if chart != "oci://registry.local:5000/apps/app" {
+		t.Fatalf("unexpected chart reference %s", chart)
+	}
+}
+
+func TestOCIPullerResolvesTagDigest(t *testing.T) {
+	var resolved string
+	puller := &ociPuller{
+		locate: func(chartRef, version string) (string, error) {
+			if chartRef != "oci://registry.local/apps/app" || version != "" {
+				t.Fatalf("unexpected locate arguments %s %s", chartRef, version)
+			}
+			return "/tmp/app-1.2.3+build.tgz", nil
+		},
+		chartVersion: func(string) (string, error) { return "1.2.3+build", nil },
+		fetchManifest: func(ref string) (string, []byte, error) {
+			resolved = ref
+			return "sha256:feed", nil, nil
+		},
+	}
+
+	result, err := puller.Pull(context.Background(), "oci://registry.local/apps/app")
+	if err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if resolved != "registry.local/apps/app:1.2.3_build" {
+		t.Fatalf("expected digest lookup for pulled tag, got %s", resolved)
+	}
+	if result.Digest != "sha256:feed" || result.ChartPath != "/tmp/app-1.2.3+build.tgz" {
+		t.Fatalf("unexpected pull result %+v", result)
+	}
+}
+
+func TestOCIPullerStoresProvenanceForPinnedDigest(t *testing.T) {
+	chartPath := filepath.Join(t.TempDir(), "app-1.0.0.tgz")
+	var resolved string
+	puller := &ociPuller{
+		locate: func(chartRef, version string) (string, error) {
+			if version != "" {
+				t.Fatalf("expected pinned reference to be located without a version, got %s", version)
+			}
+			return chartPath, nil
+		},
+		fetchManifest: func(ref string) (string, []byte, error) {
+			resolved = ref
+			return "sha256:abc", []byte("provenance"), nil
+		},
+	}
+
+	result, err := puller.Pull(context.Background(), "oci://registry.local/apps/app@sha256:abc")
+	if err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if resolved != "registry.local/apps/app@sha256:abc" {
+		t.Fatalf("expected pinned reference lookup, got %s", resolved)
+	}
+	if result.Digest != "sha256:abc" {
+		t.Fatalf("expected pinned digest, got %s", result.Digest)
+	}
+	if data, err := os.ReadFile(chartPath + ".prov"); err != nil || string(data) != "provenance" {
+		t.Fatalf("expected provenance stored beside chart, got %q (%v)", data, err)
+	}
+}
+
+func TestNewOCIPullerConstructsInstance(t *testing.T) {
+	puller, err := newOCIPuller()
+	if err != nil {
+		t.Fatalf("expected puller without error, got %v", err)
+	}
+	if puller == nil {
+		t.Fatal("expected non-nil puller")
+	}
+}
+
+func TestRegistryAuthorizerPrefersChainctlStore(t *testing.T) {
+	store := registryauth.NewStore(filepath.Join(t.TempDir(), "registries.json"), "pass")
+	if err := store.Login(registryauth.Credential{Host: "ghcr.io", Username: "bot", Password: "stored"}, registryauth.SourceLogin); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	fallback := func(_ context.Context, host string) (auth.Credential, error) {
+		return auth.Credential{Username: "helm", Password: "from-helm-" + host}, nil
+	}
+	client := registryAuthorizer(store, fallback, io.Discard)
+
+	cred, err := client.Credential(context.Background(), "ghcr.io")
+	if err != nil || cred.Username != "bot" || cred.Password != "stored" {
+		t.Fatalf("expected stored credential, got %+v (%v)", cred.Username, err)
+	}
+	cred, err = client.Credential(context.Background(), "quay.io")
+	if err != nil || cred.Username != "helm" || cred.Password != "from-helm-quay.io" {
+		t.Fatalf("expected fallback credential, got %+v (%v)", cred.Username, err)
+	}
+
+	var warnings bytes.Buffer
+	locked := registryAuthorizer(registryauth.NewStore(store.Path(), ""), fallback, &warnings)
+	for i := 0; i < 2; i++ {
+		cred, err = locked.Credential(context.Background(), "ghcr.io")
+		if err != nil || cred.Username != "helm" || cred.Password != "from-helm-ghcr.io" {
+			t.Fatalf("expected fallback credential for a locked stored host, got %+v (%v)", cred.Username, err)
+		}
+	}
+	if strings.Count(warnings.String(), "warning:") != 1 || !strings.Contains(warnings.String(), "ghcr.io") {
+		t.Fatalf("expected one passphrase warning for ghcr.io, got %q", warnings.String())
+	}
+}
@@ -0,0 +1,28 @@
+// Package kubeclient builds Kubernetes clients for chainctl commands.
+package kubeclient
+
+import (
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// RESTConfig returns the in-cluster configuration or, outside a cluster, the active kubeconfig.
+func RESTConfig() (*rest.Config, error) {
+	cfg, err := rest.InClusterConfig()
+	if err == nil {
+		return cfg, nil
+	}
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+	return clientConfig.ClientConfig()
+}
+
+// Clientset returns a typed client for the configuration selected by RESTConfig.
+func Clientset() (kubernetes.Interface, error) {
+	cfg, err := RESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(cfg)
+}
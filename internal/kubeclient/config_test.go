@@ -0,0 +1,60 @@
+package kubeclient_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dobrovols/chainctl/internal/kubeclient"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com:6443
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: dev
+current-context: dev
+users:
+- name: dev
+  user:
+    token: secret
+`
+
+func TestRESTConfigFallsBackToKubeconfig(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "")
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatalf("write kubeconfig: %v", err)
+	}
+	t.Setenv("KUBECONFIG", path)
+
+	cfg, err := kubeclient.RESTConfig()
+	if err != nil {
+		t.Fatalf("rest config: %v", err)
+	}
+	if cfg.Host != "https://dev.example.com:6443" {
+		t.Fatalf("expected kubeconfig host, got %q", cfg.Host)
+	}
+
+	if _, err := kubeclient.Clientset(); err != nil {
+		t.Fatalf("clientset: %v", err)
+	}
+}
+
+func TestRESTConfigFailsWithoutConfiguration(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "")
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+	t.Setenv("HOME", t.TempDir())
+
+	if _, err := kubeclient.RESTConfig(); err == nil {
+		t.Fatalf("expected error without cluster configuration")
+	}
+}
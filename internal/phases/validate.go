@@ -0,0 +1,67 @@
+package phases
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dobrovols/chainctl/internal/config"
+	"github.com/dobrovols/chainctl/pkg/bundle"
+	"github.com/dobrovols/chainctl/pkg/helm"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+// ExecuteValidate checks the merged values against the chart's values.schema.json before the
+// Helm phase runs. A nil validator skips the phase. metadata seeds the phase telemetry metadata.
+func ExecuteValidate(
+	tel *telemetry.Emitter,
+	validator helm.ValuesValidator,
+	profile *config.Profile,
+	bundleInstance *bundle.Bundle,
+	metadata map[string]string,
+	logger telemetry.StructuredLogger,
+) error {
+	if validator == nil {
+		return nil
+	}
+
+	phaseMetadata := make(map[string]string, len(metadata)+4)
+	for k, v := range metadata {
+		phaseMetadata[k] = v
+	}
+	phaseMetadata["valuesFiles"] = strconv.Itoa(len(profile.ValuesLayers()))
+	phaseMetadata["overrides"] = strconv.Itoa(len(profile.SetValues) + len(profile.SetStringValues))
+	return tel.EmitPhase(telemetry.PhaseValidate, phaseMetadata, func() error {
+		err := validator.ValidateValues(profile, bundleInstance)
+		var schemaErr *helm.SchemaError
+		if errors.As(err, &schemaErr) {
+			pointers := make([]string, 0, len(schemaErr.Violations))
+			for _, violation := range schemaErr.Violations {
+				pointers = append(pointers, violation.Location())
+				LogSchemaViolation(logger, violation)
+			}
+			phaseMetadata["violations"] = strconv.Itoa(len(schemaErr.Violations))
+			phaseMetadata["pointers"] = strings.Join(pointers, ",")
+		}
+		return err
+	})
+}
+
+// LogSchemaViolation records a single values schema violation without echoing the value.
+func LogSchemaViolation(logger telemetry.StructuredLogger, violation helm.SchemaViolation) {
+	if logger == nil {
+		return
+	}
+	_ = logger.Emit(telemetry.Entry{
+		Category: telemetry.CategoryDiagnostic,
+		Message:  fmt.Sprintf("values schema violation in chart %s at %s: %s", violation.Chart, violation.Location(), violation.Message),
+		Severity: telemetry.SeverityError,
+		Step:     StepValidate,
+		Metadata: map[string]string{
+			"chart":   violation.Chart,
+			"pointer": violation.Pointer,
+			"keyword": violation.Keyword,
+		},
+	})
+}
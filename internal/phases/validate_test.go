@@ -0,0 +1,43 @@
+package phases_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dobrovols/chainctl/internal/config"
+	"github.com/dobrovols/chainctl/internal/phases"
+	"github.com/dobrovols/chainctl/pkg/bundle"
+	"github.com/dobrovols/chainctl/pkg/helm"
+)
+
+type validatorStub struct {
+	err error
+}
+
+func (v validatorStub) ValidateValues(*config.Profile, *bundle.Bundle) error { return v.err }
+
+func TestExecuteValidateReportsViolations(t *testing.T) {
+	tel, buf := newEmitter(t)
+	schemaErr := &helm.SchemaError{Violations: []helm.SchemaViolation{{Chart: "demo", Pointer: "/replicas", Keyword: "type", Message: "expected integer"}}}
+
+	err := phases.ExecuteValidate(tel, validatorStub{err: schemaErr}, &config.Profile{}, nil, map[string]string{"release": "demo"}, tel.StructuredLogger())
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected schema error, got %v", err)
+	}
+	for _, want := range []string{"values schema violation in chart demo at /replicas", `"pointers":"/replicas"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("expected %q in telemetry:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestExecuteValidateSkipsWithoutValidator(t *testing.T) {
+	tel, buf := newEmitter(t)
+	if err := phases.ExecuteValidate(tel, nil, &config.Profile{}, nil, nil, nil); err != nil {
+		t.Fatalf("expected phase to be skipped, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected no telemetry for a skipped phase, got:\n%s", buf.String())
+	}
+}
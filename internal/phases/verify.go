@@ -0,0 +1,101 @@
+// Package phases implements the workflow phases shared by the app and cluster commands.
+package phases
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dobrovols/chainctl/pkg/readiness"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+// Step names used by structured log entries emitted from the shared phases.
+const (
+	StepVerify   = "verify"
+	StepValidate = "validate"
+)
+
+// VerifyOptions configures ExecuteVerify.
+type VerifyOptions struct {
+	// Manifest is the manifest of the applied release.
+	Manifest string
+	// Namespace is attributed to manifest resources without an explicit namespace.
+	Namespace string
+	// Timeout bounds the wait; zero skips the phase.
+	Timeout time.Duration
+	// Metadata seeds the phase telemetry metadata.
+	Metadata map[string]string
+	// Prober builds the readiness prober. It is only called when the manifest declares workloads.
+	Prober func() (readiness.Prober, error)
+	Logger telemetry.StructuredLogger
+}
+
+// ExecuteVerify waits for the workloads in the applied manifest to become ready.
+// A zero timeout skips the phase; releases without workloads complete immediately.
+func ExecuteVerify(ctx context.Context, tel *telemetry.Emitter, opts VerifyOptions) error {
+	if opts.Timeout <= 0 {
+		return nil
+	}
+	resources, err := readiness.WorkloadsFromManifest(opts.Manifest, opts.Namespace)
+	if err != nil {
+		return err
+	}
+
+	metadata := make(map[string]string, len(opts.Metadata)+4)
+	for k, v := range opts.Metadata {
+		metadata[k] = v
+	}
+	metadata["resources"] = strconv.Itoa(len(resources))
+	metadata["timeout"] = opts.Timeout.String()
+	return tel.EmitPhase(telemetry.PhaseVerify, metadata, func() error {
+		if len(resources) == 0 {
+			return nil
+		}
+		prober, err := opts.Prober()
+		if err != nil {
+			return err
+		}
+		statuses, waitErr := readiness.Wait(ctx, prober, resources, opts.Timeout,
+			readiness.WithProgress(func(status readiness.Status) {
+				LogReadinessProgress(opts.Logger, status)
+			}),
+		)
+		unready := readiness.Unready(statuses)
+		metadata["ready"] = strconv.Itoa(len(statuses) - len(unready))
+		if waitErr != nil && len(unready) > 0 {
+			names := make([]string, 0, len(unready))
+			for _, status := range unready {
+				names = append(names, strings.ToLower(status.Kind)+"/"+status.Name)
+			}
+			metadata["unready"] = strings.Join(names, ",")
+		}
+		return waitErr
+	})
+}
+
+// LogReadinessProgress records a workload readiness transition observed while waiting.
+func LogReadinessProgress(logger telemetry.StructuredLogger, status readiness.Status) {
+	if logger == nil {
+		return
+	}
+	state := "pending"
+	if status.Ready {
+		state = "ready"
+	}
+	_ = logger.Emit(telemetry.Entry{
+		Category: telemetry.CategoryDiagnostic,
+		Message:  fmt.Sprintf("%s %s/%s %s: %s", status.Kind, status.Namespace, status.Name, state, status.Message),
+		Severity: telemetry.SeverityInfo,
+		Step:     StepVerify,
+		Metadata: map[string]string{
+			"kind":      status.Kind,
+			"namespace": status.Namespace,
+			"name":      status.Name,
+			"ready":     strconv.FormatBool(status.Ready),
+			"message":   status.Message,
+		},
+	})
+}
@@ -0,0 +1,79 @@
+package phases_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dobrovols/chainctl/internal/phases"
+	"github.com/dobrovols/chainctl/pkg/readiness"
+	"github.com/dobrovols/chainctl/pkg/telemetry"
+)
+
+const deploymentManifest = "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\n"
+
+type proberStub struct {
+	ready bool
+}
+
+func (p proberStub) Check(_ context.Context, resources []readiness.Resource) ([]readiness.Status, error) {
+	statuses := make([]readiness.Status, 0, len(resources))
+	for _, res := range resources {
+		statuses = append(statuses, readiness.Status{Resource: res, Ready: p.ready, Message: "rollout"})
+	}
+	return statuses, nil
+}
+
+func newEmitter(t *testing.T) (*telemetry.Emitter, *bytes.Buffer) {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	tel, err := telemetry.NewEmitter(buf)
+	if err != nil {
+		t.Fatalf("emitter: %v", err)
+	}
+	return tel, buf
+}
+
+func TestExecuteVerifyWaitsForWorkloads(t *testing.T) {
+	tel, buf := newEmitter(t)
+	err := phases.ExecuteVerify(context.Background(), tel, phases.VerifyOptions{
+		Manifest:  deploymentManifest,
+		Namespace: "apps",
+		Timeout:   time.Second,
+		Metadata:  map[string]string{"release": "demo"},
+		Prober:    func() (readiness.Prober, error) { return proberStub{ready: true}, nil },
+		Logger:    tel.StructuredLogger(),
+	})
+	if err != nil {
+		t.Fatalf("ExecuteVerify: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Deployment apps/api ready") {
+		t.Fatalf("expected readiness progress to be logged, got:\n%s", buf.String())
+	}
+
+	tel, _ = newEmitter(t)
+	err = phases.ExecuteVerify(context.Background(), tel, phases.VerifyOptions{
+		Manifest: deploymentManifest,
+		Timeout:  50 * time.Millisecond,
+		Prober:   func() (readiness.Prober, error) { return proberStub{}, nil },
+	})
+	if !errors.Is(err, readiness.ErrNotReady()) {
+		t.Fatalf("expected not ready error, got %v", err)
+	}
+}
+
+func TestExecuteVerifySkipsWithoutTimeoutOrWorkloads(t *testing.T) {
+	tel, _ := newEmitter(t)
+	unexpected := func() (readiness.Prober, error) { return nil, errors.New("prober should not be built") }
+
+	if err := phases.ExecuteVerify(context.Background(), tel, phases.VerifyOptions{Manifest: deploymentManifest, Prober: unexpected}); err != nil {
+		t.Fatalf("expected zero timeout to skip the phase, got %v", err)
+	}
+	configMap := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n"
+	if err := phases.ExecuteVerify(context.Background(), tel, phases.VerifyOptions{Manifest: configMap, Timeout: time.Second, Prober: unexpected}); err != nil {
+		t.Fatalf("expected release without workloads to complete, got %v", err)
+	}
+}
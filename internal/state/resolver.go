@@ -11,9 +11,15 @@ import (
 )
 
 const (
-	defaultFileName = "app.json"
-	configDirName   = "chainctl"
-	stateDirName    = "state"
+	defaultFileName    = "app.json"
+	configDirName      = "chainctl"
+	stateDirName       = "state"
+	cacheDirName       = "cache"
+	chartCacheDirName  = "charts"
+	registriesFileName = "registries.json"
+	locksDirName       = "locks"
+	xdgConfigHome      = "XDG_CONFIG_HOME"
+	xdgCacheHome       = "XDG_CACHE_HOME"
 )
 
 var (
@@ -97,8 +103,41 @@ func invalidFileName(name string) bool {
 }
 
 func defaultStateDirectory() (string, error) {
-	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
-		return filepath.Join(filepath.Clean(xdg), configDirName, stateDirName), nil
+	return baseDirectory(xdgConfigHome, stateDirName)
+}
+
+// ErrConflictingOverrides exposes the override validation error.
+func ErrConflictingOverrides() error { return errConflictingOverrides }
+
+// ErrRelativeStateFile exposes the relative path validation error.
+func ErrRelativeStateFile() error { return errRelativeStateFile }
+
+// ErrInvalidFileName exposes invalid filename validation error.
+func ErrInvalidFileName() error { return errInvalidFileName }
+
+// ChartCacheDirectory returns the default location of the content-addressed chart cache:
+// $XDG_CACHE_HOME/chainctl/charts, falling back to ~/.chainctl/cache/charts.
+func ChartCacheDirectory() (string, error) {
+	return baseDirectory(xdgCacheHome, chartCacheDirName)
+}
+
+// RegistryCredentialsPath returns the default location of the encrypted registry credential
+// store: $XDG_CONFIG_HOME/chainctl/registries.json, falling back to ~/.chainctl/registries.json.
+func RegistryCredentialsPath() (string, error) {
+	return baseDirectory(xdgConfigHome, registriesFileName)
+}
+
+// LockDirectory returns the default location of workflow lock files:
+// $XDG_CONFIG_HOME/chainctl/locks, falling back to ~/.chainctl/locks.
+func LockDirectory() (string, error) {
+	return baseDirectory(xdgConfigHome, locksDirName)
+}
+
+// baseDirectory joins elems onto chainctl's directory under the XDG base directory named by
+// xdgEnv, falling back to ~/.chainctl when it is unset. Cache data falls back to ~/.chainctl/cache.
+func baseDirectory(xdgEnv string, elems ...string) (string, error) {
+	if xdg := os.Getenv(xdgEnv); xdg != "" {
+		return filepath.Join(append([]string{filepath.Clean(xdg), configDirName}, elems...)...), nil
 	}
 
 	home, err := os.UserHomeDir()
@@ -109,14 +148,9 @@ func defaultStateDirectory() (string, error) {
 		return "", errors.New("unable to determine user home directory")
 	}
 
-	return filepath.Join(filepath.Clean(home), "."+configDirName, stateDirName), nil
+	base := filepath.Join(filepath.Clean(home), "."+configDirName)
+	if xdgEnv == xdgCacheHome {
+		base = filepath.Join(base, cacheDirName)
+	}
+	return filepath.Join(append([]string{base}, elems...)...), nil
 }
-
-// ErrConflictingOverrides exposes the override validation error.
-func ErrConflictingOverrides() error { return errConflictingOverrides }
-
-// ErrRelativeStateFile exposes the relative path validation error.
-func ErrRelativeStateFile() error { return errRelativeStateFile }
-
-// ErrInvalidFileName exposes invalid filename validation error.
-func ErrInvalidFileName() error { return errInvalidFileName }
@@ -53,6 +53,54 @@ func TestResolverDefaultsToConfigDirectory(t *testing.T) {
 	}
 }
 
+func TestChartCacheDirectoryHonoursXDGCacheHome(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "cache")
+	t.Setenv("XDG_CACHE_HOME", root)
+
+	dir, err := ChartCacheDirectory()
+	if err != nil {
+		t.Fatalf("cache directory: %v", err)
+	}
+	if expected := filepath.Join(root, "chainctl", "charts"); dir != expected {
+		t.Fatalf("expected %s, got %s", expected, dir)
+	}
+}
+
+func TestRegistryCredentialsPathUsesConfigDirectory(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "cfg")
+	t.Setenv("XDG_CONFIG_HOME", root)
+
+	path, err := RegistryCredentialsPath()
+	if err != nil {
+		t.Fatalf("registry credentials path: %v", err)
+	}
+	if expected := filepath.Join(root, "chainctl", "registries.json"); path != expected {
+		t.Fatalf("expected %s, got %s", expected, path)
+	}
+}
+
+func TestBaseDirectoriesFallBackToHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("HOME", home)
+
+	for name, tc := range map[string]struct {
+		get  func() (string, error)
+		want string
+	}{
+		"state":      {defaultStateDirectory, filepath.Join(home, ".chainctl", "state")},
+		"chartCache": {ChartCacheDirectory, filepath.Join(home, ".chainctl", "cache", "charts")},
+		"registries": {RegistryCredentialsPath, filepath.Join(home, ".chainctl", "registries.json")},
+		"locks":      {LockDirectory, filepath.Join(home, ".chainctl", "locks")},
+	} {
+		got, err := tc.get()
+		if err != nil || got != tc.want {
+			t.Fatalf("%s: expected %s, got %s (%v)", name, tc.want, got, err)
+		}
+	}
+}
+
 func TestResolverConflictingOverrides(t *testing.T) {
 	r := NewResolver()
 	if _, err := r.Resolve(pkgstate.Overrides{StateFilePath: "/tmp/custom.json", StateFileName: "custom.json"}); err == nil {
@@ -0,0 +1,52 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/dobrovols/chainctl/internal/kubeclient"
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+)
+
+// NewFileManager constructs the file state manager for the managed config directory. State is
+// encrypted at rest when a passphrase is configured through pkgstate.PassphraseEnv or
+// pkgstate.PassphraseFileEnv.
+func NewFileManager(opts ...pkgstate.ManagerOption) *pkgstate.Manager {
+	opts = append([]pkgstate.ManagerOption{pkgstate.WithPassphraseSource(pkgstate.EnvPassphrase)}, opts...)
+	return pkgstate.NewManager(NewResolver(), opts...)
+}
+
+// StoreOption customises the state backend constructed by NewStore.
+type StoreOption func(*storeConfig)
+
+type storeConfig struct {
+	historyLimit int
+}
+
+// WithHistoryLimit caps the history entries either backend retains per release, dropping the
+// oldest first. A limit of zero or less keeps the full history.
+func WithHistoryLimit(limit int) StoreOption {
+	return func(c *storeConfig) {
+		c.historyLimit = limit
+	}
+}
+
+// NewStore constructs the state backend selected by --state-backend. The secret backend connects
+// with the in-cluster configuration or, outside a cluster, the active kubeconfig.
+func NewStore(backend string, opts ...StoreOption) (pkgstate.Store, error) {
+	if err := pkgstate.ValidateBackend(backend); err != nil {
+		return nil, err
+	}
+	cfg := storeConfig{historyLimit: pkgstate.DefaultHistoryLimit}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if backend != pkgstate.BackendSecret {
+		return NewFileManager(pkgstate.WithHistoryLimit(cfg.historyLimit)), nil
+	}
+
+	client, err := kubeclient.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("secret state backend: kubernetes client: %w", err)
+	}
+	return pkgstate.NewSecretStore(client, pkgstate.WithSecretHistoryLimit(cfg.historyLimit)), nil
+}
@@ -0,0 +1,46 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+)
+
+func TestNewStoreAppliesHistoryLimitToFileBackend(t *testing.T) {
+	t.Setenv(pkgstate.PassphraseEnv, "")
+	t.Setenv(pkgstate.PassphraseFileEnv, "")
+	store, err := NewStore(pkgstate.BackendFile, WithHistoryLimit(2))
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	overrides := pkgstate.Overrides{StateFilePath: filepath.Join(t.TempDir(), "app.json")}
+	for _, version := range []string{"1.0.0", "1.1.0", "1.2.0"} {
+		record := pkgstate.Record{
+			Release:    "myapp",
+			Namespace:  "demo",
+			Chart:      pkgstate.ChartSource{Type: "oci", Reference: "oci://registry.example.com/apps/myapp:" + version},
+			Version:    version,
+			LastAction: "upgrade",
+			Timestamp:  "2025-10-07T12:34:56Z",
+		}
+		if _, err := store.Write(record, overrides); err != nil {
+			t.Fatalf("write %s: %v", version, err)
+		}
+	}
+
+	loaded, err := store.Read(overrides)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(loaded.History) != 2 || loaded.History[0].Version != "1.1.0" {
+		t.Fatalf("expected history capped at the two newest entries, got %+v", loaded.History)
+	}
+}
+
+func TestNewStoreRejectsUnknownBackend(t *testing.T) {
+	if _, err := NewStore("etcd"); err == nil {
+		t.Fatal("expected unknown backend to be rejected")
+	}
+}
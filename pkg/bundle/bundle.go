@@ -27,34 +27,36 @@ const ManifestFileName = "bundle.yaml"
 
 // Manifest describes the structure of the bundle.
 type Manifest struct {
-	Version   string            `yaml:"version"`
-	Images    []ImageRecord     `yaml:"images"`
-	Charts    []ChartRecord     `yaml:"helmCharts"`
-	Binaries  []BinaryRecord    `yaml:"binaries"`
-	Checksums map[string]string `yaml:"checksums"`
+	Version   string            `yaml:"version" json:"version"`
+	Images    []ImageRecord     `yaml:"images" json:"images"`
+	Charts    []ChartRecord     `yaml:"helmCharts" json:"helmCharts"`
+	Binaries  []BinaryRecord    `yaml:"binaries" json:"binaries"`
+	Checksums map[string]string `yaml:"checksums" json:"checksums"`
 }
 
 // ImageRecord captures a container image entry.
 type ImageRecord struct {
-	Name   string `yaml:"name"`
-	Tag    string `yaml:"tag"`
-	Digest string `yaml:"digest"`
+	Name   string `yaml:"name" json:"name"`
+	Tag    string `yaml:"tag" json:"tag"`
+	Digest string `yaml:"digest" json:"digest"`
+	// Path locates the image archive inside the bundle.
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
 }
 
 // ChartRecord captures Helm chart metadata.
 type ChartRecord struct {
-	Name    string `yaml:"name"`
-	Version string `yaml:"version"`
-	Path    string `yaml:"path"`
+	Name    string `yaml:"name" json:"name"`
+	Version string `yaml:"version" json:"version"`
+	Path    string `yaml:"path" json:"path"`
 }
 
 // BinaryRecord captures auxiliary binary information.
 type BinaryRecord struct {
-	Name    string `yaml:"name"`
-	Version string `yaml:"version"`
-	Path    string `yaml:"path"`
-	OS      string `yaml:"os"`
-	Arch    string `yaml:"arch"`
+	Name    string `yaml:"name" json:"name"`
+	Version string `yaml:"version" json:"version"`
+	Path    string `yaml:"path" json:"path"`
+	OS      string `yaml:"os" json:"os"`
+	Arch    string `yaml:"arch" json:"arch"`
 }
 
 // Marshal serialises the manifest to YAML.
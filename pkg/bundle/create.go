@@ -0,0 +1,343 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/ignore"
+)
+
+// Directories holding each asset type inside a bundle.
+const (
+	ChartsDir   = "charts"
+	ImagesDir   = "images"
+	BinariesDir = "binaries"
+)
+
+// ErrDuplicateEntry is returned when two spec entries map to the same path inside the bundle.
+var ErrDuplicateEntry = errors.New("bundle entry listed twice")
+
+// FetchedChart is a chart made available on local disk for packaging.
+type FetchedChart struct {
+	// Path is a packaged chart archive or an unpacked chart directory.
+	Path    string
+	Name    string
+	Version string
+}
+
+// ChartFetcher makes the chart named by a spec source available on local disk.
+type ChartFetcher interface {
+	FetchChart(ctx context.Context, source string) (FetchedChart, error)
+}
+
+// CreateOptions configures Create.
+type CreateOptions struct {
+	Spec Spec
+	// OutputPath receives the bundle tarball.
+	OutputPath string
+	Charts     ChartFetcher
+}
+
+// CreateResult describes a bundle written by Create.
+type CreateResult struct {
+	Path     string
+	Manifest Manifest
+	// Digest is the sha256 digest of the tarball.
+	Digest string
+	Size   int64
+}
+
+// archiveEpoch is the modification time of every bundle entry, so rebuilding a bundle from the
+// same inputs yields an identical tarball.
+var archiveEpoch = time.Unix(0, 0)
+
+// bundleEntry maps a file inside the bundle to its source on disk.
+type bundleEntry struct {
+	name   string
+	source string
+	mode   int64
+}
+
+// Create collects the charts, image archives, and binaries listed in the spec into a bundle
+// tarball that Load accepts. The tarball is deterministic: bundle.yaml comes first, the other
+// entries follow in name order, and ownership and modification times are zeroed.
+func Create(ctx context.Context, opts CreateOptions) (CreateResult, error) {
+	if err := opts.Spec.Validate(); err != nil {
+		return CreateResult{}, err
+	}
+	if strings.TrimSpace(opts.OutputPath) == "" {
+		return CreateResult{}, fmt.Errorf("output path required")
+	}
+	if len(opts.Spec.Charts) > 0 && opts.Charts == nil {
+		return CreateResult{}, fmt.Errorf("chart fetcher not configured")
+	}
+
+	manifest := Manifest{
+		Version:   opts.Spec.Version,
+		Images:    []ImageRecord{},
+		Charts:    []ChartRecord{},
+		Binaries:  []BinaryRecord{},
+		Checksums: map[string]string{},
+	}
+	entries := map[string]bundleEntry{}
+	add := func(entry bundleEntry) error {
+		if _, exists := entries[entry.name]; exists {
+			return fmt.Errorf("%w: %s", ErrDuplicateEntry, entry.name)
+		}
+		entries[entry.name] = entry
+		return nil
+	}
+
+	for _, spec := range opts.Spec.Charts {
+		record, files, err := collectChart(ctx, opts.Charts, spec)
+		if err != nil {
+			return CreateResult{}, err
+		}
+		for _, entry := range files {
+			if err := add(entry); err != nil {
+				return CreateResult{}, err
+			}
+		}
+		manifest.Charts = append(manifest.Charts, record)
+	}
+	for _, spec := range opts.Spec.Images {
+		name := path.Join(ImagesDir, entryName(spec.Name)+"_"+entryName(spec.Tag)+archiveExtension(spec.Archive))
+		if err := add(bundleEntry{name: name, source: spec.Archive, mode: 0o644}); err != nil {
+			return CreateResult{}, err
+		}
+		manifest.Images = append(manifest.Images, ImageRecord{Name: spec.Name, Tag: spec.Tag, Digest: spec.Digest, Path: name})
+	}
+	for _, spec := range opts.Spec.Binaries {
+		name := path.Join(BinariesDir, entryName(spec.OS)+"-"+entryName(spec.Arch), entryName(spec.Name))
+		if err := add(bundleEntry{name: name, source: spec.Path, mode: 0o755}); err != nil {
+			return CreateResult{}, err
+		}
+		manifest.Binaries = append(manifest.Binaries, BinaryRecord{Name: spec.Name, Version: spec.Version, Path: name, OS: spec.OS, Arch: spec.Arch})
+	}
+
+	ordered := make([]bundleEntry, 0, len(entries))
+	for _, entry := range entries {
+		ordered = append(ordered, entry)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].name < ordered[j].name })
+	for _, entry := range ordered {
+		sum, err := fileChecksum(entry.source)
+		if err != nil {
+			return CreateResult{}, fmt.Errorf("hash %s: %w", entry.source, err)
+		}
+		manifest.Checksums[entry.name] = sum
+	}
+
+	digest, size, err := writeArchive(opts.OutputPath, manifest, ordered)
+	if err != nil {
+		return CreateResult{}, err
+	}
+	return CreateResult{Path: opts.OutputPath, Manifest: manifest, Digest: digest, Size: size}, nil
+}
+
+// collectChart fetches a chart and lists the bundle entries holding it: the archive (plus its
+// provenance file, when present) or the files of a chart directory.
+func collectChart(ctx context.Context, fetcher ChartFetcher, spec ChartSpec) (ChartRecord, []bundleEntry, error) {
+	chart, err := fetcher.FetchChart(ctx, spec.Source)
+	if err != nil {
+		return ChartRecord{}, nil, fmt.Errorf("fetch chart %s: %w", spec.Source, err)
+	}
+	name := spec.Name
+	if name == "" {
+		name = chart.Name
+	}
+	if name == "" {
+		return ChartRecord{}, nil, fmt.Errorf("%w: chart %s has no name", ErrInvalidSpec, spec.Source)
+	}
+	record := ChartRecord{Name: name, Version: chart.Version}
+
+	info, err := os.Stat(chart.Path)
+	if err != nil {
+		return ChartRecord{}, nil, fmt.Errorf("fetch chart %s: %w", spec.Source, err)
+	}
+	if !info.IsDir() {
+		base := entryName(name)
+		if chart.Version != "" {
+			base += "-" + entryName(chart.Version)
+		}
+		record.Path = path.Join(ChartsDir, base+".tgz")
+		files := []bundleEntry{{name: record.Path, source: chart.Path, mode: 0o644}}
+		if _, err := os.Stat(chart.Path + ".prov"); err == nil {
+			files = append(files, bundleEntry{name: record.Path + ".prov", source: chart.Path + ".prov", mode: 0o644})
+		}
+		return record, files, nil
+	}
+
+	record.Path = path.Join(ChartsDir, entryName(name))
+	files, err := chartDirectoryEntries(chart.Path, record.Path)
+	if err != nil {
+		return ChartRecord{}, nil, fmt.Errorf("collect chart directory %s: %w", chart.Path, err)
+	}
+	return record, files, nil
+}
+
+// chartDirectoryEntries lists the files Helm would package from a chart directory, honouring
+// .helmignore.
+func chartDirectoryEntries(dir, prefix string) ([]bundleEntry, error) {
+	rules, err := ignore.ParseFile(filepath.Join(dir, ignore.HelmIgnore))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("read %s: %w", ignore.HelmIgnore, err)
+		}
+		rules = ignore.Empty()
+	}
+	rules.AddDefaults()
+
+	var files []bundleEntry
+	err = filepath.WalkDir(dir, func(source string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		rel, err := filepath.Rel(dir, source)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if rules.Ignore(rel, info) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Mode().IsRegular() {
+			files = append(files, bundleEntry{name: path.Join(prefix, rel), source: source, mode: 0o644})
+		}
+		return nil
+	})
+	return files, err
+}
+
+// writeArchive writes the manifest and entries to a temporary file next to output and renames
+// it into place, returning the tarball digest and size.
+func writeArchive(output string, manifest Manifest, entries []bundleEntry) (string, int64, error) {
+	manifestBytes, err := manifest.Marshal()
+	if err != nil {
+		return "", 0, fmt.Errorf("marshal manifest: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(output), ".bundle-*.tar")
+	if err != nil {
+		return "", 0, fmt.Errorf("create bundle: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	counter := &countingWriter{}
+	tw := tar.NewWriter(io.MultiWriter(tmp, hash, counter))
+	if err := writeTarEntry(tw, ManifestFileName, 0o644, int64(len(manifestBytes)), bytes.NewReader(manifestBytes)); err != nil {
+		tmp.Close()
+		return "", 0, err
+	}
+	for _, entry := range entries {
+		if err := copyTarEntry(tw, entry); err != nil {
+			tmp.Close()
+			return "", 0, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		tmp.Close()
+		return "", 0, fmt.Errorf("finish bundle: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", 0, fmt.Errorf("finish bundle: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return "", 0, fmt.Errorf("finish bundle: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), output); err != nil {
+		return "", 0, fmt.Errorf("write bundle: %w", err)
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), counter.n, nil
+}
+
+func copyTarEntry(tw *tar.Writer, entry bundleEntry) error {
+	file, err := os.Open(entry.source)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", entry.source, err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("read %s: %w", entry.source, err)
+	}
+	return writeTarEntry(tw, entry.name, entry.mode, info.Size(), file)
+}
+
+func writeTarEntry(tw *tar.Writer, name string, mode, size int64, content io.Reader) error {
+	hdr := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     mode,
+		Size:     size,
+		ModTime:  archiveEpoch,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if _, err := io.CopyN(tw, content, size); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// entryName makes a spec value safe to use as a single path element inside the bundle.
+func entryName(value string) string {
+	replacer := strings.NewReplacer("/", "_", "\\", "_", ":", "_", "@", "_", " ", "_")
+	name := replacer.Replace(strings.TrimSpace(value))
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
+
+// archiveExtension keeps compressed image archive extensions, defaulting to .tar.
+func archiveExtension(archive string) string {
+	lower := strings.ToLower(archive)
+	for _, ext := range []string{".tar.gz", ".tar.zst", ".tgz", ".tar"} {
+		if strings.HasSuffix(lower, ext) {
+			return archive[len(archive)-len(ext):]
+		}
+	}
+	return ".tar"
+}
+
+type countingWriter struct{ n int64 }
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
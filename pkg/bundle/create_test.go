@@ -0,0 +1,211 @@
+package bundle_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/dobrovols/chainctl/pkg/bundle"
+)
+
+type fakeFetcher struct {
+	charts map[string]bundle.FetchedChart
+}
+
+func (f fakeFetcher) FetchChart(_ context.Context, source string) (bundle.FetchedChart, error) {
+	chart, ok := f.charts[source]
+	if !ok {
+		return bundle.FetchedChart{}, errors.New("unknown chart " + source)
+	}
+	return chart, nil
+}
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+}
+
+func createSpec(t *testing.T) (bundle.Spec, fakeFetcher) {
+	t.Helper()
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"chart/Chart.yaml":            "apiVersion: v2\nname: demo\nversion: 0.1.0\n",
+		"chart/templates/cm.yaml":     "kind: ConfigMap\n",
+		"chart/.helmignore":           "*.bak\n",
+		"chart/values.yaml.bak":       "ignored",
+		"archives/api-1.2.3.tgz":      "packaged chart",
+		"archives/api-1.2.3.tgz.prov": "provenance",
+		"images/app.tar":              "image archive",
+		"bin/k3s":                     "k3s binary",
+	})
+	spec := bundle.Spec{
+		Version: "1.2.3",
+		Charts: []bundle.ChartSpec{
+			{Source: "oci://registry.example.com/apps/api:1.2.3"},
+			{Source: filepath.Join(dir, "chart")},
+		},
+		Images:   []bundle.ImageSpec{{Name: "registry.example.com/apps/app", Tag: "1.2.3", Digest: "sha256:abc", Archive: filepath.Join(dir, "images", "app.tar")}},
+		Binaries: []bundle.BinarySpec{{Name: "k3s", Version: "v1.30.2+k3s1", OS: "linux", Arch: "amd64", Path: filepath.Join(dir, "bin", "k3s")}},
+	}
+	fetcher := fakeFetcher{charts: map[string]bundle.FetchedChart{
+		"oci://registry.example.com/apps/api:1.2.3": {Path: filepath.Join(dir, "archives", "api-1.2.3.tgz"), Name: "api", Version: "1.2.3"},
+		filepath.Join(dir, "chart"):                 {Path: filepath.Join(dir, "chart"), Name: "demo", Version: "0.1.0"},
+	}}
+	return spec, fetcher
+}
+
+func TestCreateBuildsLoadableBundle(t *testing.T) {
+	spec, fetcher := createSpec(t)
+	out := filepath.Join(t.TempDir(), "bundle.tar")
+
+	result, err := bundle.Create(context.Background(), bundle.CreateOptions{Spec: spec, OutputPath: out, Charts: fetcher})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(result.Manifest.Charts) != 2 || result.Manifest.Charts[0].Path != "charts/api-1.2.3.tgz" || result.Manifest.Charts[1].Path != "charts/demo" {
+		t.Fatalf("unexpected charts %+v", result.Manifest.Charts)
+	}
+	if result.Manifest.Images[0].Path != "images/registry.example.com_apps_app_1.2.3.tar" || result.Manifest.Binaries[0].Path != "binaries/linux-amd64/k3s" {
+		t.Fatalf("unexpected asset paths %+v %+v", result.Manifest.Images, result.Manifest.Binaries)
+	}
+	if _, ok := result.Manifest.Checksums["charts/demo/values.yaml.bak"]; ok {
+		t.Fatal("expected .helmignore patterns to be honoured")
+	}
+	if len(result.Manifest.Checksums) != 7 {
+		t.Fatalf("expected a checksum for every file, got %v", result.Manifest.Checksums)
+	}
+
+	loaded, err := bundle.Load(out, filepath.Join(t.TempDir(), "cache"))
+	if err != nil {
+		t.Fatalf("load created bundle: %v", err)
+	}
+	if loaded.Manifest.Version != "1.2.3" || len(loaded.Manifest.Checksums) != 7 {
+		t.Fatalf("unexpected loaded manifest %+v", loaded.Manifest)
+	}
+	if data, err := os.ReadFile(loaded.AssetPath("charts/demo/Chart.yaml")); err != nil || !bytes.Contains(data, []byte("name: demo")) {
+		t.Fatalf("expected chart directory in bundle, got %q (%v)", data, err)
+	}
+
+	names := tarHeaders(t, out)
+	if names[0].Name != bundle.ManifestFileName {
+		t.Fatalf("expected manifest first, got %s", names[0].Name)
+	}
+	rest := make([]string, 0, len(names)-1)
+	for _, hdr := range names[1:] {
+		rest = append(rest, hdr.Name)
+		if !hdr.ModTime.Equal(time.Unix(0, 0)) || hdr.Uid != 0 || hdr.Gid != 0 {
+			t.Fatalf("expected zeroed metadata for %s, got %+v", hdr.Name, hdr)
+		}
+		wantMode := int64(0o644)
+		if hdr.Name == "binaries/linux-amd64/k3s" {
+			wantMode = 0o755
+		}
+		if hdr.Mode != wantMode {
+			t.Fatalf("unexpected mode %o for %s", hdr.Mode, hdr.Name)
+		}
+	}
+	if !sort.StringsAreSorted(rest) {
+		t.Fatalf("expected entries in name order, got %v", rest)
+	}
+}
+
+func TestCreateIsDeterministic(t *testing.T) {
+	spec, fetcher := createSpec(t)
+	dir := t.TempDir()
+	first, err := bundle.Create(context.Background(), bundle.CreateOptions{Spec: spec, OutputPath: filepath.Join(dir, "first.tar"), Charts: fetcher})
+	if err != nil {
+		t.Fatalf("first create: %v", err)
+	}
+
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(spec.Binaries[0].Path, later, later); err != nil {
+		t.Fatalf("touch binary: %v", err)
+	}
+	second, err := bundle.Create(context.Background(), bundle.CreateOptions{Spec: spec, OutputPath: filepath.Join(dir, "second.tar"), Charts: fetcher})
+	if err != nil {
+		t.Fatalf("second create: %v", err)
+	}
+	a, _ := os.ReadFile(first.Path)
+	b, _ := os.ReadFile(second.Path)
+	if first.Digest != second.Digest || !bytes.Equal(a, b) {
+		t.Fatalf("expected identical bundles, got %s and %s", first.Digest, second.Digest)
+	}
+}
+
+func TestCreateRejectsDuplicateEntries(t *testing.T) {
+	spec, fetcher := createSpec(t)
+	spec.Images = append(spec.Images, spec.Images[0])
+	_, err := bundle.Create(context.Background(), bundle.CreateOptions{Spec: spec, OutputPath: filepath.Join(t.TempDir(), "bundle.tar"), Charts: fetcher})
+	if !errors.Is(err, bundle.ErrDuplicateEntry) {
+		t.Fatalf("expected duplicate entry error, got %v", err)
+	}
+}
+
+func TestLoadSpecResolvesRelativePaths(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"bundle-spec.yaml": `version: 1.0.0
+charts:
+  - source: ./charts/demo
+  - source: oci://registry.example.com/apps/api:1.2.3
+images:
+  - name: app
+    tag: "1.0"
+    archive: images/app.tar
+binaries:
+  - name: k3s
+    os: linux
+    arch: arm64
+    path: /opt/k3s
+`})
+	spec, err := bundle.LoadSpec(filepath.Join(dir, "bundle-spec.yaml"))
+	if err != nil {
+		t.Fatalf("load spec: %v", err)
+	}
+	if spec.Charts[0].Source != filepath.Join(dir, "charts", "demo") || spec.Charts[1].Source != "oci://registry.example.com/apps/api:1.2.3" {
+		t.Fatalf("unexpected chart sources %+v", spec.Charts)
+	}
+	if spec.Images[0].Archive != filepath.Join(dir, "images", "app.tar") || spec.Binaries[0].Path != "/opt/k3s" {
+		t.Fatalf("unexpected asset paths %+v %+v", spec.Images, spec.Binaries)
+	}
+
+	writeFiles(t, dir, map[string]string{"invalid.yaml": "version: 1.0.0\nimages:\n  - name: app\n"})
+	if _, err := bundle.LoadSpec(filepath.Join(dir, "invalid.yaml")); !errors.Is(err, bundle.ErrInvalidSpec) {
+		t.Fatalf("expected invalid spec error, got %v", err)
+	}
+}
+
+func tarHeaders(t *testing.T, path string) []*tar.Header {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open bundle: %v", err)
+	}
+	defer file.Close()
+	var headers []*tar.Header
+	tr := tar.NewReader(file)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return headers
+		}
+		if err != nil {
+			t.Fatalf("read bundle: %v", err)
+		}
+		headers = append(headers, hdr)
+	}
+}
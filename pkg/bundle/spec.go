@@ -0,0 +1,112 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ErrInvalidSpec is returned for bundle specs that cannot be built.
+var ErrInvalidSpec = errors.New("invalid bundle spec")
+
+// Spec describes the contents of a bundle built by Create.
+type Spec struct {
+	Version  string       `yaml:"version"`
+	Charts   []ChartSpec  `yaml:"charts"`
+	Images   []ImageSpec  `yaml:"images"`
+	Binaries []BinarySpec `yaml:"binaries"`
+}
+
+// ChartSpec selects a Helm chart to include. Source is an oci:// reference, an http(s)://
+// repository chart, or a chart directory.
+type ChartSpec struct {
+	Source string `yaml:"source"`
+	// Name overrides the chart name recorded in the manifest, which selects the chart for a release.
+	Name string `yaml:"name,omitempty"`
+}
+
+// ImageSpec points at a container image archive, such as the output of `docker save` or an OCI
+// image layout tarball.
+type ImageSpec struct {
+	Name    string `yaml:"name"`
+	Tag     string `yaml:"tag"`
+	Digest  string `yaml:"digest,omitempty"`
+	Archive string `yaml:"archive"`
+}
+
+// BinarySpec points at a binary, such as k3s, built for one OS and architecture.
+type BinarySpec struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	OS      string `yaml:"os"`
+	Arch    string `yaml:"arch"`
+	Path    string `yaml:"path"`
+}
+
+// LoadSpec reads a bundle spec from YAML. Relative file paths and chart directories are resolved
+// against the directory holding the spec.
+func LoadSpec(path string) (Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Spec{}, fmt.Errorf("read bundle spec: %w", err)
+	}
+	var spec Spec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return Spec{}, fmt.Errorf("%w: %s: %w", ErrInvalidSpec, path, err)
+	}
+
+	base := filepath.Dir(path)
+	for i := range spec.Charts {
+		if source := strings.TrimSpace(spec.Charts[i].Source); source != "" && !strings.Contains(source, "://") {
+			spec.Charts[i].Source = resolveSpecPath(base, source)
+		}
+	}
+	for i := range spec.Images {
+		spec.Images[i].Archive = resolveSpecPath(base, spec.Images[i].Archive)
+	}
+	for i := range spec.Binaries {
+		spec.Binaries[i].Path = resolveSpecPath(base, spec.Binaries[i].Path)
+	}
+	if err := spec.Validate(); err != nil {
+		return Spec{}, err
+	}
+	return spec, nil
+}
+
+func resolveSpecPath(base, path string) string {
+	path = strings.TrimSpace(path)
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(base, path)
+}
+
+// Validate reports the first missing required field.
+func (s Spec) Validate() error {
+	if strings.TrimSpace(s.Version) == "" {
+		return fmt.Errorf("%w: version is required", ErrInvalidSpec)
+	}
+	if len(s.Charts)+len(s.Images)+len(s.Binaries) == 0 {
+		return fmt.Errorf("%w: no charts, images, or binaries listed", ErrInvalidSpec)
+	}
+	for i, chart := range s.Charts {
+		if strings.TrimSpace(chart.Source) == "" {
+			return fmt.Errorf("%w: charts[%d]: source is required", ErrInvalidSpec, i)
+		}
+	}
+	for i, image := range s.Images {
+		if strings.TrimSpace(image.Name) == "" || strings.TrimSpace(image.Tag) == "" || strings.TrimSpace(image.Archive) == "" {
+			return fmt.Errorf("%w: images[%d]: name, tag, and archive are required", ErrInvalidSpec, i)
+		}
+	}
+	for i, binary := range s.Binaries {
+		if strings.TrimSpace(binary.Name) == "" || strings.TrimSpace(binary.OS) == "" || strings.TrimSpace(binary.Arch) == "" || strings.TrimSpace(binary.Path) == "" {
+			return fmt.Errorf("%w: binaries[%d]: name, os, arch, and path are required", ErrInvalidSpec, i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package bundle
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Verification reports how the files inside a bundle tarball compare with its manifest checksums.
+type Verification struct {
+	Manifest Manifest
+	// Digest is the sha256 digest of the tarball.
+	Digest string
+	// Files is the number of archive files checked, excluding the manifest.
+	Files int
+	// Mismatched lists files whose content does not match the manifest checksum.
+	Mismatched []string
+	// Unlisted lists files present in the archive but missing from the manifest checksums.
+	Unlisted []string
+	// Missing lists files with a manifest checksum that are absent from the archive.
+	Missing []string
+}
+
+// Discrepancies returns the number of mismatched, unlisted, and missing files.
+func (v Verification) Discrepancies() int {
+	return len(v.Mismatched) + len(v.Unlisted) + len(v.Missing)
+}
+
+// ReadManifest returns the manifest of a bundle tarball without extracting or validating it.
+func ReadManifest(tarballPath string) (Manifest, error) {
+	var manifest *Manifest
+	err := walkTarball(tarballPath, func(name string, content io.Reader) error {
+		if name != ManifestFileName {
+			return nil
+		}
+		m, err := readManifestEntry(content)
+		if err != nil {
+			return err
+		}
+		manifest = &m
+		return errStopWalk
+	})
+	if err != nil {
+		return Manifest{}, err
+	}
+	if manifest == nil {
+		return Manifest{}, ErrManifestMissing
+	}
+	return *manifest, nil
+}
+
+// Verify applies the checksum validation performed by Load to a bundle tarball without
+// extracting it, reporting every discrepancy instead of stopping at the first one.
+func Verify(tarballPath string) (Verification, error) {
+	var manifest *Manifest
+	actual := map[string]string{}
+	hash := sha256.New()
+
+	err := walkTarball(tarballPath, func(name string, content io.Reader) error {
+		if name == ManifestFileName {
+			m, err := readManifestEntry(content)
+			if err != nil {
+				return err
+			}
+			manifest = &m
+			return nil
+		}
+		sum := sha256.New()
+		if _, err := io.Copy(sum, content); err != nil {
+			return fmt.Errorf("read tar entry %s: %w", name, err)
+		}
+		actual[name] = hex.EncodeToString(sum.Sum(nil))
+		return nil
+	}, hash)
+	if err != nil {
+		return Verification{}, err
+	}
+	if manifest == nil {
+		return Verification{}, ErrManifestMissing
+	}
+
+	result := Verification{
+		Manifest:   *manifest,
+		Digest:     "sha256:" + hex.EncodeToString(hash.Sum(nil)),
+		Files:      len(actual),
+		Mismatched: []string{},
+		Unlisted:   []string{},
+		Missing:    []string{},
+	}
+	expected := make(map[string]string, len(manifest.Checksums))
+	for rel, sum := range manifest.Checksums {
+		name, err := entryPath(rel)
+		if err != nil {
+			return Verification{}, err
+		}
+		expected[name] = sum
+	}
+	for name, sum := range actual {
+		want, listed := expected[name]
+		switch {
+		case !listed:
+			result.Unlisted = append(result.Unlisted, name)
+		case !strings.EqualFold(sum, want):
+			result.Mismatched = append(result.Mismatched, name)
+		}
+	}
+	for name := range expected {
+		if _, present := actual[name]; !present {
+			result.Missing = append(result.Missing, name)
+		}
+	}
+	sort.Strings(result.Mismatched)
+	sort.Strings(result.Unlisted)
+	sort.Strings(result.Missing)
+	return result, nil
+}
+
+// errStopWalk ends walkTarball early without reporting an error.
+var errStopWalk = errors.New("stop walking tarball")
+
+// walkTarball calls visit with the cleaned name and content of every non-directory entry, the
+// same entries Load extracts. Extra writers receive the raw tarball bytes.
+func walkTarball(tarballPath string, visit func(name string, content io.Reader) error, raw ...io.Writer) error {
+	if tarballPath == "" {
+		return fmt.Errorf("tarball path required")
+	}
+	file, err := os.Open(tarballPath)
+	if err != nil {
+		return fmt.Errorf("read bundle: %w", err)
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if len(raw) > 0 {
+		reader = io.TeeReader(file, io.MultiWriter(raw...))
+	}
+	tr := tar.NewReader(reader)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read tar entry: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+		name, err := entryPath(hdr.Name)
+		if err != nil {
+			return err
+		}
+		if err := visit(name, tr); err != nil {
+			if errors.Is(err, errStopWalk) {
+				return nil
+			}
+			return err
+		}
+	}
+	// Drain trailing padding so raw writers see the whole tarball.
+	_, err = io.Copy(io.Discard, reader)
+	return err
+}
+
+func readManifestEntry(content io.Reader) (Manifest, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("read manifest: %w", err)
+	}
+	m, err := unmarshalManifest(data)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("parse manifest: %w", err)
+	}
+	return m, nil
+}
+
+// entryPath normalises an archive or checksum path, rejecting paths that escape the bundle.
+func entryPath(name string) (string, error) {
+	if _, err := safeJoin(".", name); err != nil {
+		return "", err
+	}
+	return path.Clean(strings.ReplaceAll(name, "\\", "/")), nil
+}
@@ -0,0 +1,99 @@
+package bundle_test
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dobrovols/chainctl/pkg/bundle"
+)
+
+func TestVerifyAcceptsCreatedBundle(t *testing.T) {
+	spec, fetcher := createSpec(t)
+	out := filepath.Join(t.TempDir(), "bundle.tar")
+	created, err := bundle.Create(context.Background(), bundle.CreateOptions{Spec: spec, OutputPath: out, Charts: fetcher})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	result, err := bundle.Verify(out)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if result.Discrepancies() != 0 {
+		t.Fatalf("expected no discrepancies, got %+v", result)
+	}
+	if result.Digest != created.Digest || result.Files != len(created.Manifest.Checksums) {
+		t.Fatalf("expected digest %s and %d files, got %+v", created.Digest, len(created.Manifest.Checksums), result)
+	}
+
+	manifest, err := bundle.ReadManifest(out)
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	if !reflect.DeepEqual(manifest, created.Manifest) {
+		t.Fatalf("expected manifest %+v, got %+v", created.Manifest, manifest)
+	}
+}
+
+func TestVerifyReportsEveryDiscrepancy(t *testing.T) {
+	bundlePath, _ := tempBundlePaths(t)
+	sum := sha256.Sum256([]byte("good"))
+	manifest := bundle.Manifest{
+		Version: testManifestVersion,
+		Checksums: map[string]string{
+			"charts/good.tgz":    hex.EncodeToString(sum[:]),
+			"charts/changed.tgz": hex.EncodeToString(sum[:]),
+			"./images/gone.tar":  hex.EncodeToString(sum[:]),
+		},
+	}
+	createBundle(t, bundlePath, manifest, map[string][]byte{
+		"charts/good.tgz":    []byte("good"),
+		"charts/changed.tgz": []byte("tampered"),
+		"binaries/extra":     []byte("extra"),
+	})
+
+	result, err := bundle.Verify(bundlePath)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if result.Discrepancies() != 3 || result.Files != 3 {
+		t.Fatalf("expected three discrepancies across three files, got %+v", result)
+	}
+	if !reflect.DeepEqual(result.Mismatched, []string{"charts/changed.tgz"}) ||
+		!reflect.DeepEqual(result.Unlisted, []string{"binaries/extra"}) ||
+		!reflect.DeepEqual(result.Missing, []string{"images/gone.tar"}) {
+		t.Fatalf("unexpected discrepancies %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(bundlePath), testCacheDirName)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected verify not to extract the bundle, got %v", err)
+	}
+}
+
+func TestVerifyRejectsInvalidBundles(t *testing.T) {
+	bundlePath, _ := tempBundlePaths(t)
+	createBundle(t, bundlePath, bundle.Manifest{Version: testManifestVersion}, map[string][]byte{"../escape": []byte("x")})
+	if _, err := bundle.Verify(bundlePath); !errors.Is(err, bundle.ErrPathOutsideBundle) {
+		t.Fatalf("expected path escape error, got %v", err)
+	}
+
+	withTarWriter(t, bundlePath, func(tw *tar.Writer) {
+		writeTarFile(t, tw, testChartPath, []byte(testChartContent))
+	})
+	if _, err := bundle.Verify(bundlePath); !errors.Is(err, bundle.ErrManifestMissing) {
+		t.Fatalf("expected manifest missing from Verify, got %v", err)
+	}
+	if _, err := bundle.ReadManifest(bundlePath); !errors.Is(err, bundle.ErrManifestMissing) {
+		t.Fatalf("expected manifest missing from ReadManifest, got %v", err)
+	}
+	if _, err := bundle.Verify(filepath.Join(t.TempDir(), "absent.tar")); err == nil || !strings.Contains(err.Error(), "read bundle") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+}
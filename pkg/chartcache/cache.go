@@ -0,0 +1,415 @@
+// Package chartcache stores pulled Helm chart archives keyed by their OCI manifest digest.
+package chartcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const entryFileName = "entry.json"
+
+// sidecarSuffixes lists the files stored next to a chart archive so cached charts can be
+// verified exactly like freshly pulled ones.
+var sidecarSuffixes = []string{".prov", ".sig"}
+
+var (
+	errInvalidDigest = errors.New("invalid chart digest")
+	errCacheRoot     = errors.New("chart cache directory not configured")
+	digestPattern    = regexp.MustCompile(`^[a-z0-9]+:[a-f0-9]+$`)
+)
+
+// ErrInvalidDigest exposes the malformed digest sentinel.
+func ErrInvalidDigest() error { return errInvalidDigest }
+
+// Entry describes one cached chart, addressed by the OCI manifest digest it was pulled with.
+type Entry struct {
+	Digest      string    `json:"digest"`
+	References  []string  `json:"references"`
+	ChartFile   string    `json:"chartFile"`
+	ChartSHA256 string    `json:"chartSha256"`
+	Size        int64     `json:"size"`
+	StoredAt    time.Time `json:"storedAt"`
+	LastUsed    time.Time `json:"lastUsed"`
+	// ChartPath is the absolute path of the cached archive; it is derived, not persisted.
+	ChartPath string `json:"-"`
+}
+
+// PruneOptions selects entries for garbage collection. Zero values disable the matching limit.
+type PruneOptions struct {
+	// MaxAge removes entries not used within the given duration.
+	MaxAge time.Duration
+	// MaxSize evicts least recently used entries until the cache fits in this many bytes.
+	MaxSize int64
+	// DryRun reports the entries that would be removed without deleting them.
+	DryRun bool
+}
+
+// PruneResult summarises a garbage collection run.
+type PruneResult struct {
+	Removed        []Entry
+	ReclaimedBytes int64
+	RemainingBytes int64
+}
+
+// Cache is a content-addressed store of pulled chart archives.
+type Cache struct {
+	root string
+	now  func() time.Time
+}
+
+// Option configures a Cache.
+type Option func(*Cache)
+
+// WithClock overrides the time source used for usage tracking and age based pruning.
+func WithClock(now func() time.Time) Option {
+	return func(c *Cache) {
+		if now != nil {
+			c.now = now
+		}
+	}
+}
+
+// New constructs a cache rooted at dir. The directory is created lazily on first write.
+func New(dir string, opts ...Option) *Cache {
+	c := &Cache{root: filepath.Clean(dir), now: time.Now}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Root returns the cache directory.
+func (c *Cache) Root() string { return c.root }
+
+// Get returns the entry stored for digest and records the access. Entries whose archive no
+// longer matches the recorded checksum are discarded and reported as missing.
+func (c *Cache) Get(digest string) (Entry, bool, error) {
+	dir, err := c.entryDir(digest)
+	if err != nil {
+		return Entry{}, false, err
+	}
+	entry, err := readEntry(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Entry{}, false, nil
+		}
+		return Entry{}, false, err
+	}
+	sum, _, err := fileSHA256(entry.ChartPath)
+	if err != nil || sum != entry.ChartSHA256 {
+		if rmErr := os.RemoveAll(dir); rmErr != nil {
+			return Entry{}, false, fmt.Errorf("discard corrupt cache entry %s: %w", entry.Digest, rmErr)
+		}
+		return Entry{}, false, nil
+	}
+
+	entry.LastUsed = c.now().UTC()
+	if err := writeEntry(dir, entry); err != nil {
+		return Entry{}, false, err
+	}
+	return entry, true, nil
+}
+
+// Lookup finds the most recently stored entry pulled through ref. It backs offline resolution
+// of tag references, whose digest is unknown without contacting the registry.
+func (c *Cache) Lookup(ref string) (Entry, bool, error) {
+	entries, err := c.List()
+	if err != nil {
+		return Entry{}, false, err
+	}
+	var match *Entry
+	for i := range entries {
+		if !containsString(entries[i].References, ref) {
+			continue
+		}
+		if match == nil || entries[i].StoredAt.After(match.StoredAt) {
+			match = &entries[i]
+		}
+	}
+	if match == nil {
+		return Entry{}, false, nil
+	}
+	return c.Get(match.Digest)
+}
+
+// Put copies the chart archive and its provenance or signature files into the cache under
+// digest. Storing an existing digest only records the additional reference.
+func (c *Cache) Put(ref, digest, chartPath string) (Entry, error) {
+	dir, err := c.entryDir(digest)
+	if err != nil {
+		return Entry{}, err
+	}
+	now := c.now().UTC()
+
+	if existing, err := readEntry(dir); err == nil {
+		if !containsString(existing.References, ref) {
+			existing.References = append(existing.References, ref)
+		}
+		existing.LastUsed = now
+		if err := writeEntry(dir, existing); err != nil {
+			return Entry{}, err
+		}
+		return existing, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0o700); err != nil {
+		return Entry{}, fmt.Errorf("create chart cache: %w", err)
+	}
+	staging, err := os.MkdirTemp(filepath.Dir(dir), ".staging-")
+	if err != nil {
+		return Entry{}, fmt.Errorf("create chart cache staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	name := filepath.Base(chartPath)
+	sum, size, err := copyFile(chartPath, filepath.Join(staging, name))
+	if err != nil {
+		return Entry{}, fmt.Errorf("cache chart %s: %w", name, err)
+	}
+	for _, suffix := range sidecarSuffixes {
+		n, err := copyOptional(chartPath+suffix, filepath.Join(staging, name+suffix))
+		if err != nil {
+			return Entry{}, fmt.Errorf("cache chart %s%s: %w", name, suffix, err)
+		}
+		size += n
+	}
+
+	entry := Entry{
+		Digest:      NormalizeDigest(digest),
+		References:  []string{ref},
+		ChartFile:   name,
+		ChartSHA256: sum,
+		Size:        size,
+		StoredAt:    now,
+		LastUsed:    now,
+	}
+	if err := writeEntry(staging, entry); err != nil {
+		return Entry{}, err
+	}
+	if err := os.Rename(staging, dir); err != nil {
+		// A concurrent run may have stored the same digest first; its copy is equivalent.
+		if existing, readErr := readEntry(dir); readErr == nil {
+			return existing, nil
+		}
+		return Entry{}, fmt.Errorf("commit chart cache entry %s: %w", entry.Digest, err)
+	}
+	entry.ChartPath = filepath.Join(dir, name)
+	return entry, nil
+}
+
+// List returns every cache entry, most recently used first.
+func (c *Cache) List() ([]Entry, error) {
+	if c.root == "" || c.root == "." {
+		return nil, errCacheRoot
+	}
+	algorithms, err := os.ReadDir(c.root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read chart cache: %w", err)
+	}
+
+	var entries []Entry
+	for _, algorithm := range algorithms {
+		if !algorithm.IsDir() || strings.HasPrefix(algorithm.Name(), ".") {
+			continue
+		}
+		digests, err := os.ReadDir(filepath.Join(c.root, algorithm.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read chart cache: %w", err)
+		}
+		for _, d := range digests {
+			if !d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+				continue
+			}
+			entry, err := readEntry(filepath.Join(c.root, algorithm.Name(), d.Name()))
+			if err != nil {
+				// Entries without readable metadata are partial writes; skip them.
+				continue
+			}
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].LastUsed.After(entries[j].LastUsed) })
+	return entries, nil
+}
+
+// Remove deletes the entry stored for digest, if any.
+func (c *Cache) Remove(digest string) error {
+	dir, err := c.entryDir(digest)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("remove chart cache entry %s: %w", digest, err)
+	}
+	return nil
+}
+
+// Prune removes entries older than MaxAge, then evicts the least recently used entries until
+// the cache is no larger than MaxSize.
+func (c *Cache) Prune(opts PruneOptions) (PruneResult, error) {
+	entries, err := c.List()
+	if err != nil {
+		return PruneResult{}, err
+	}
+
+	var total int64
+	for _, entry := range entries {
+		total += entry.Size
+	}
+
+	var result PruneResult
+	cutoff := c.now().Add(-opts.MaxAge)
+	keep := entries[:0:0]
+	for _, entry := range entries {
+		if opts.MaxAge > 0 && entry.LastUsed.Before(cutoff) {
+			result.Removed = append(result.Removed, entry)
+			total -= entry.Size
+			continue
+		}
+		keep = append(keep, entry)
+	}
+	// keep is ordered most recently used first, so evict from the tail.
+	for opts.MaxSize > 0 && total > opts.MaxSize && len(keep) > 0 {
+		last := keep[len(keep)-1]
+		keep = keep[:len(keep)-1]
+		result.Removed = append(result.Removed, last)
+		total -= last.Size
+	}
+
+	for _, entry := range result.Removed {
+		result.ReclaimedBytes += entry.Size
+		if opts.DryRun {
+			continue
+		}
+		if err := c.Remove(entry.Digest); err != nil {
+			return result, err
+		}
+	}
+	result.RemainingBytes = total
+	return result, nil
+}
+
+func (c *Cache) entryDir(digest string) (string, error) {
+	if c.root == "" || c.root == "." {
+		return "", errCacheRoot
+	}
+	digest = NormalizeDigest(digest)
+	if !digestPattern.MatchString(digest) {
+		return "", fmt.Errorf("%w: %q", errInvalidDigest, digest)
+	}
+	algorithm, hexPart, _ := strings.Cut(digest, ":")
+	return filepath.Join(c.root, algorithm, hexPart), nil
+}
+
+// NormalizeDigest lower-cases a digest and prefixes bare hex values with sha256:.
+func NormalizeDigest(digest string) string {
+	digest = strings.ToLower(strings.TrimSpace(digest))
+	if digest == "" || strings.Contains(digest, ":") {
+		return digest
+	}
+	return "sha256:" + digest
+}
+
+func readEntry(dir string) (Entry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, entryFileName))
+	if err != nil {
+		return Entry{}, err
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return Entry{}, fmt.Errorf("decode chart cache entry %s: %w", dir, err)
+	}
+	if entry.ChartFile == "" || filepath.Base(entry.ChartFile) != entry.ChartFile {
+		return Entry{}, fmt.Errorf("chart cache entry %s: invalid chart file %q", dir, entry.ChartFile)
+	}
+	entry.ChartPath = filepath.Join(dir, entry.ChartFile)
+	return entry, nil
+}
+
+func writeEntry(dir string, entry Entry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode chart cache entry: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".entry-*.json")
+	if err != nil {
+		return fmt.Errorf("write chart cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write chart cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write chart cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, entryFileName)); err != nil {
+		return fmt.Errorf("write chart cache entry: %w", err)
+	}
+	return nil
+}
+
+func copyFile(src, dst string) (string, int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", 0, err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", 0, err
+	}
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(out, hash), in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), n, nil
+}
+
+func copyOptional(src, dst string) (int64, error) {
+	if _, err := os.Stat(src); errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	_, n, err := copyFile(src, dst)
+	return n, err
+}
+
+func fileSHA256(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	n, err := io.Copy(hash, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), n, nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
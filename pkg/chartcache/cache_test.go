@@ -0,0 +1,144 @@
+package chartcache_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dobrovols/chainctl/pkg/chartcache"
+)
+
+const (
+	digestA = "sha256:aaaa"
+	digestB = "sha256:bbbb"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func writeChart(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write chart: %v", err)
+	}
+	return path
+}
+
+func TestCachePutAndGetKeepsSidecarFiles(t *testing.T) {
+	cache := chartcache.New(t.TempDir())
+	chartPath := writeChart(t, "demo-0.1.0.tgz", "chart-bytes")
+	if err := os.WriteFile(chartPath+".prov", []byte("prov"), 0o600); err != nil {
+		t.Fatalf("write prov: %v", err)
+	}
+
+	stored, err := cache.Put("oci://example.com/demo:0.1.0", digestA, chartPath)
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if filepath.Base(stored.ChartPath) != "demo-0.1.0.tgz" || stored.Size != int64(len("chart-bytes")+len("prov")) {
+		t.Fatalf("unexpected entry %+v", stored)
+	}
+	if _, err := os.Stat(stored.ChartPath + ".prov"); err != nil {
+		t.Fatalf("expected provenance stored next to chart: %v", err)
+	}
+
+	entry, ok, err := cache.Get("AAAA")
+	if err != nil || !ok {
+		t.Fatalf("expected cache hit for bare digest, got ok=%v err=%v", ok, err)
+	}
+	if entry.ChartPath != stored.ChartPath {
+		t.Fatalf("expected %s, got %s", stored.ChartPath, entry.ChartPath)
+	}
+
+	if _, ok, _ := cache.Get(digestB); ok {
+		t.Fatal("expected miss for unknown digest")
+	}
+}
+
+func TestCacheLookupByReferenceAndDedupesDigests(t *testing.T) {
+	cache := chartcache.New(t.TempDir())
+	chartPath := writeChart(t, "demo-0.1.0.tgz", "chart-bytes")
+	if _, err := cache.Put("oci://example.com/demo:0.1.0", digestA, chartPath); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := cache.Put("oci://example.com/demo:stable", digestA, chartPath); err != nil {
+		t.Fatalf("Put alias: %v", err)
+	}
+
+	entry, ok, err := cache.Lookup("oci://example.com/demo:stable")
+	if err != nil || !ok {
+		t.Fatalf("expected lookup hit, got ok=%v err=%v", ok, err)
+	}
+	if entry.Digest != digestA || len(entry.References) != 2 {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+	entries, err := cache.List()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected a single entry, got %d (%v)", len(entries), err)
+	}
+}
+
+func TestCacheDiscardsCorruptEntries(t *testing.T) {
+	cache := chartcache.New(t.TempDir())
+	stored, err := cache.Put("oci://example.com/demo:0.1.0", digestA, writeChart(t, "demo-0.1.0.tgz", "chart-bytes"))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := os.WriteFile(stored.ChartPath, []byte("tampered"), 0o600); err != nil {
+		t.Fatalf("tamper: %v", err)
+	}
+
+	if _, ok, err := cache.Get(digestA); ok || err != nil {
+		t.Fatalf("expected corrupt entry to be treated as a miss, got ok=%v err=%v", ok, err)
+	}
+	if _, err := os.Stat(filepath.Dir(stored.ChartPath)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected corrupt entry removed, stat err=%v", err)
+	}
+}
+
+func TestCacheRejectsInvalidDigest(t *testing.T) {
+	cache := chartcache.New(t.TempDir())
+	if _, _, err := cache.Get("sha256:../../etc"); !errors.Is(err, chartcache.ErrInvalidDigest()) {
+		t.Fatalf("expected invalid digest error, got %v", err)
+	}
+}
+
+func TestCachePruneByAgeAndSize(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cache := chartcache.New(t.TempDir(), chartcache.WithClock(clock.Now))
+
+	if _, err := cache.Put("oci://example.com/old:1", digestA, writeChart(t, "old-1.tgz", "0123456789")); err != nil {
+		t.Fatalf("Put old: %v", err)
+	}
+	clock.now = clock.now.Add(48 * time.Hour)
+	if _, err := cache.Put("oci://example.com/new:1", digestB, writeChart(t, "new-1.tgz", "0123456789")); err != nil {
+		t.Fatalf("Put new: %v", err)
+	}
+
+	dry, err := cache.Prune(chartcache.PruneOptions{MaxAge: 24 * time.Hour, DryRun: true})
+	if err != nil || len(dry.Removed) != 1 || dry.Removed[0].Digest != digestA {
+		t.Fatalf("unexpected dry run %+v (%v)", dry, err)
+	}
+	if entries, _ := cache.List(); len(entries) != 2 {
+		t.Fatalf("dry run must not delete entries, have %d", len(entries))
+	}
+
+	clock.now = clock.now.Add(time.Hour)
+	if _, _, err := cache.Get(digestA); err != nil {
+		t.Fatalf("touch old entry: %v", err)
+	}
+	result, err := cache.Prune(chartcache.PruneOptions{MaxSize: 15})
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if len(result.Removed) != 1 || result.Removed[0].Digest != digestB {
+		t.Fatalf("expected least recently used entry evicted, got %+v", result.Removed)
+	}
+	if result.ReclaimedBytes != 10 || result.RemainingBytes != 10 {
+		t.Fatalf("unexpected byte accounting %+v", result)
+	}
+}
@@ -11,6 +11,43 @@ type Executor interface {
 	UpgradeRelease(*config.Profile, *bundle.Bundle) error
 }
 
+// ReleaseInfo summarises the Helm release produced by an install or upgrade.
+type ReleaseInfo struct {
+	Name         string
+	Namespace    string
+	Revision     int
+	Status       string
+	Notes        string
+	ChartName    string
+	ChartVersion string
+	ChartDigest  string
+	// ValuesHash is the HashValues digest of the user-supplied values stored on the release.
+	ValuesHash string
+	Manifest   string
+}
+
+// ReleaseExecutor is implemented by executors that report the release they applied.
+type ReleaseExecutor interface {
+	Executor
+	ApplyRelease(*config.Profile, *bundle.Bundle) (ReleaseInfo, error)
+}
+
+// ReleaseApplier is implemented by installers that report the Helm release they applied.
+type ReleaseApplier interface {
+	Apply(*config.Profile, *bundle.Bundle) (ReleaseInfo, error)
+}
+
+// ValuesValidator is implemented by executors that can check the merged values against the
+// chart's values.schema.json before anything is applied.
+type ValuesValidator interface {
+	ValidateValues(*config.Profile, *bundle.Bundle) error
+}
+
+// StatusReader reports the deployed state of a release.
+type StatusReader interface {
+	ReleaseStatus(release, namespace string) (ReleaseInfo, error)
+}
+
 // Installer orchestrates Helm install/upgrade logic.
 type Installer struct {
 	exec Executor
@@ -37,6 +74,32 @@ func (i *Installer) Install(profile *config.Profile, b *bundle.Bundle) error {
 	return i.exec.UpgradeRelease(profile, b)
 }
 
+// Apply applies the Helm release and returns the resulting release metadata.
+// Executors that do not report release details yield an empty ReleaseInfo.
+func (i *Installer) Apply(profile *config.Profile, b *bundle.Bundle) (ReleaseInfo, error) {
+	return applyRelease(i.exec, profile, b)
+}
+
+// ValidateValues checks the release values against the chart schema. Executors that cannot
+// validate values accept them unchanged.
+func (i *Installer) ValidateValues(profile *config.Profile, b *bundle.Bundle) error {
+	return validateValues(i.exec, profile, b)
+}
+
+func validateValues(exec Executor, profile *config.Profile, b *bundle.Bundle) error {
+	if validator, ok := exec.(ValuesValidator); ok {
+		return validator.ValidateValues(profile, b)
+	}
+	return nil
+}
+
+func applyRelease(exec Executor, profile *config.Profile, b *bundle.Bundle) (ReleaseInfo, error) {
+	if rel, ok := exec.(ReleaseExecutor); ok {
+		return rel.ApplyRelease(profile, b)
+	}
+	return ReleaseInfo{}, exec.UpgradeRelease(profile, b)
+}
+
 type noopExecutor struct{}
 
 func (noopExecutor) UpgradeRelease(*config.Profile, *bundle.Bundle) error { return nil }
@@ -0,0 +1,217 @@
+package helm
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"sigs.k8s.io/yaml"
+
+	"github.com/dobrovols/chainctl/internal/config"
+	"github.com/dobrovols/chainctl/pkg/bundle"
+)
+
+// ResourceChange classifies how a desired resource differs from the deployed release.
+type ResourceChange string
+
+const (
+	ChangeAdded    ResourceChange = "added"
+	ChangeRemoved  ResourceChange = "removed"
+	ChangeModified ResourceChange = "modified"
+)
+
+const (
+	maskedValue        = "*** (masked)"
+	maskedValueBefore  = "*** (masked, before)"
+	maskedValueAfter   = "*** (masked, after)"
+	unifiedDiffContext = 3
+)
+
+var documentSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+// SplitManifest splits a multi-document YAML manifest on `---` separators, dropping empty
+// documents.
+func SplitManifest(manifest string) []string {
+	var docs []string
+	for _, doc := range documentSeparator.Split(manifest, -1) {
+		if strings.TrimSpace(doc) != "" {
+			docs = append(docs, doc)
+		}
+	}
+	return docs
+}
+
+// ManifestRenderer renders desired release manifests and fetches the manifest stored with the
+// deployed release. The stored manifest is what Helm last applied, so changes made to cluster
+// objects out of band are not visible through it; ObjectReader reads the live objects instead.
+type ManifestRenderer interface {
+	RenderManifest(*config.Profile, *bundle.Bundle) (string, error)
+	ReleaseManifest(release, namespace string) (string, error)
+}
+
+// ResourceDiff describes the change to a single Kubernetes resource.
+type ResourceDiff struct {
+	Kind      string         `json:"kind"`
+	Namespace string         `json:"namespace,omitempty"`
+	Name      string         `json:"name"`
+	Change    ResourceChange `json:"change"`
+	Diff      string         `json:"diff"`
+}
+
+// Key identifies the resource as kind/namespace/name.
+func (d ResourceDiff) Key() string {
+	return resourceKey(d.Kind, d.Namespace, d.Name)
+}
+
+type manifestResource struct {
+	kind      string
+	namespace string
+	name      string
+	object    map[string]interface{}
+}
+
+// DiffManifests compares the deployed and desired manifests resource by resource and returns
+// unified diffs for every added, removed, or modified resource. Secret data is masked so that
+// only the fact that a value changed is reported.
+func DiffManifests(deployed, desired string) ([]ResourceDiff, error) {
+	deployedResources, err := parseManifest(deployed)
+	if err != nil {
+		return nil, fmt.Errorf("parse deployed manifest: %w", err)
+	}
+	desiredResources, err := parseManifest(desired)
+	if err != nil {
+		return nil, fmt.Errorf("parse desired manifest: %w", err)
+	}
+
+	keys := make([]string, 0, len(deployedResources)+len(desiredResources))
+	for key := range deployedResources {
+		keys = append(keys, key)
+	}
+	for key := range desiredResources {
+		if _, ok := deployedResources[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var diffs []ResourceDiff
+	for _, key := range keys {
+		before, hasBefore := deployedResources[key]
+		after, hasAfter := desiredResources[key]
+
+		var ref manifestResource
+		change := ChangeModified
+		switch {
+		case !hasBefore:
+			ref, change = after, ChangeAdded
+		case !hasAfter:
+			ref, change = before, ChangeRemoved
+		default:
+			ref = after
+		}
+		if ref.kind == "Secret" {
+			maskSecretPair(before.object, after.object)
+		}
+
+		beforeText, err := renderObject(before.object)
+		if err != nil {
+			return nil, err
+		}
+		afterText, err := renderObject(after.object)
+		if err != nil {
+			return nil, err
+		}
+		if beforeText == afterText {
+			continue
+		}
+
+		text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(beforeText),
+			B:        difflib.SplitLines(afterText),
+			FromFile: "deployed/" + key,
+			ToFile:   "desired/" + key,
+			Context:  unifiedDiffContext,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("diff %s: %w", key, err)
+		}
+		diffs = append(diffs, ResourceDiff{
+			Kind:      ref.kind,
+			Namespace: ref.namespace,
+			Name:      ref.name,
+			Change:    change,
+			Diff:      text,
+		})
+	}
+	return diffs, nil
+}
+
+func parseManifest(manifest string) (map[string]manifestResource, error) {
+	resources := map[string]manifestResource{}
+	for _, doc := range SplitManifest(manifest) {
+		var object map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &object); err != nil {
+			return nil, err
+		}
+		if len(object) == 0 {
+			continue
+		}
+		res := manifestResource{object: object}
+		res.kind, _ = object["kind"].(string)
+		if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+			res.name, _ = metadata["name"].(string)
+			res.namespace, _ = metadata["namespace"].(string)
+		}
+		resources[resourceKey(res.kind, res.namespace, res.name)] = res
+	}
+	return resources, nil
+}
+
+// maskSecretPair replaces Secret values on both sides, marking keys whose values differ.
+func maskSecretPair(before, after map[string]interface{}) {
+	for _, field := range []string{"data", "stringData"} {
+		beforeData, _ := nestedMap(before, field)
+		afterData, _ := nestedMap(after, field)
+		for key, value := range beforeData {
+			if other, ok := afterData[key]; ok && fmt.Sprint(other) != fmt.Sprint(value) {
+				beforeData[key] = maskedValueBefore
+				afterData[key] = maskedValueAfter
+				continue
+			}
+			beforeData[key] = maskedValue
+			if _, ok := afterData[key]; ok {
+				afterData[key] = maskedValue
+			}
+		}
+		for key := range afterData {
+			if _, ok := beforeData[key]; !ok {
+				afterData[key] = maskedValue
+			}
+		}
+	}
+}
+
+func nestedMap(object map[string]interface{}, field string) (map[string]interface{}, bool) {
+	if object == nil {
+		return nil, false
+	}
+	value, ok := object[field].(map[string]interface{})
+	return value, ok
+}
+
+func renderObject(object map[string]interface{}) (string, error) {
+	if object == nil {
+		return "", nil
+	}
+	data, err := yaml.Marshal(object)
+	if err != nil {
+		return "", fmt.Errorf("render resource: %w", err)
+	}
+	return string(data), nil
+}
+
+func resourceKey(kind, namespace, name string) string {
+	return strings.Join([]string{kind, namespace, name}, "/")
+}
@@ -0,0 +1,126 @@
+package helm
+
+import (
+	"strings"
+	"testing"
+)
+
+const liveManifest = `---
+# Source: demo/templates/config.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: demo-config
+data:
+  replicas: "1"
+---
+# Source: demo/templates/secret.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: demo-secret
+data:
+  password: b2xk
+  token: c2FtZQ==
+---
+# Source: demo/templates/legacy.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: demo-legacy
+`
+
+const desiredManifest = `---
+# Source: demo/templates/config.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: demo-config
+data:
+  replicas: "3"
+---
+# Source: demo/templates/secret.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: demo-secret
+data:
+  password: bmV3
+  token: c2FtZQ==
+---
+# Source: demo/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: demo
+  namespace: apps
+`
+
+func TestDiffManifestsReportsPerResourceChanges(t *testing.T) {
+	diffs, err := DiffManifests(liveManifest, desiredManifest)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+
+	got := map[string]ResourceChange{}
+	for _, d := range diffs {
+		got[d.Key()] = d.Change
+	}
+	want := map[string]ResourceChange{
+		"ConfigMap//demo-config": ChangeModified,
+		"ConfigMap//demo-legacy": ChangeRemoved,
+		"Secret//demo-secret":    ChangeModified,
+		"Service/apps/demo":      ChangeAdded,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d diffs, got %v", len(want), got)
+	}
+	for key, change := range want {
+		if got[key] != change {
+			t.Fatalf("expected %s to be %s, got %s", key, change, got[key])
+		}
+	}
+
+	for _, d := range diffs {
+		if d.Kind == "ConfigMap" && d.Change == ChangeModified {
+			if !strings.Contains(d.Diff, `-  replicas: "1"`) || !strings.Contains(d.Diff, `+  replicas: "3"`) {
+				t.Fatalf("expected unified diff of replicas, got\n%s", d.Diff)
+			}
+		}
+	}
+}
+
+func TestDiffManifestsMasksSecretValues(t *testing.T) {
+	diffs, err := DiffManifests(liveManifest, desiredManifest)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	for _, d := range diffs {
+		if d.Kind != "Secret" {
+			continue
+		}
+		for _, secret := range []string{"b2xk", "bmV3", "c2FtZQ=="} {
+			if strings.Contains(d.Diff, secret) {
+				t.Fatalf("secret value %q leaked into diff:\n%s", secret, d.Diff)
+			}
+		}
+		if !strings.Contains(d.Diff, "-  password: '*** (masked, before)'") || !strings.Contains(d.Diff, "+  password: '*** (masked, after)'") {
+			t.Fatalf("expected masked change markers, got\n%s", d.Diff)
+		}
+		if strings.Contains(d.Diff, "-  token") {
+			t.Fatalf("expected unchanged secret key to stay out of the diff, got\n%s", d.Diff)
+		}
+		return
+	}
+	t.Fatal("expected secret diff")
+}
+
+func TestDiffManifestsIgnoresIdenticalManifests(t *testing.T) {
+	diffs, err := DiffManifests(liveManifest, liveManifest)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Fatalf("expected no changes, got %+v", diffs)
+	}
+}
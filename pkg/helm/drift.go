@@ -0,0 +1,255 @@
+package helm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// DriftChange classifies how a live resource departs from the release manifest.
+type DriftChange string
+
+const (
+	DriftMissing  DriftChange = "missing"
+	DriftModified DriftChange = "modified"
+)
+
+// ObjectReader fetches the live cluster object for a resource declared in a release manifest.
+// Implementations return a nil object without error when the resource does not exist.
+type ObjectReader interface {
+	LiveObject(ctx context.Context, apiVersion, kind, namespace, name string) (map[string]interface{}, error)
+}
+
+// ResourceDrift describes a release resource that no longer matches its rendered manifest.
+type ResourceDrift struct {
+	Kind      string      `json:"kind"`
+	Namespace string      `json:"namespace,omitempty"`
+	Name      string      `json:"name"`
+	Change    DriftChange `json:"change"`
+	// Fields lists the manifest field paths whose live values differ. Values are never reported
+	// so that Secret data does not leak into drift reports.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Key identifies the resource as kind/namespace/name.
+func (d ResourceDrift) Key() string {
+	return resourceKey(d.Kind, d.Namespace, d.Name)
+}
+
+// HashValues returns a stable sha256 digest of release values, as stored on the Helm release.
+func HashValues(values map[string]interface{}) string {
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// DetectResourceDrift compares every resource in the release manifest with its live object and
+// reports resources that were deleted or whose declared fields were changed out of band. Fields
+// the manifest does not set, such as defaults and status, are ignored. Resources without a
+// namespace are looked up in defaultNamespace.
+func DetectResourceDrift(ctx context.Context, manifest, defaultNamespace string, reader ObjectReader) ([]ResourceDrift, error) {
+	resources, err := parseManifest(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("parse release manifest: %w", err)
+	}
+	keys := make([]string, 0, len(resources))
+	for key := range resources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var drifts []ResourceDrift
+	for _, key := range keys {
+		res := resources[key]
+		apiVersion, _ := res.object["apiVersion"].(string)
+		namespace := res.namespace
+		if namespace == "" {
+			namespace = defaultNamespace
+		}
+		live, err := reader.LiveObject(ctx, apiVersion, res.kind, namespace, res.name)
+		if err != nil {
+			return nil, fmt.Errorf("read live %s: %w", res.kind+"/"+res.name, err)
+		}
+		drift := ResourceDrift{Kind: res.kind, Namespace: namespace, Name: res.name}
+		if live == nil {
+			drift.Change = DriftMissing
+			drifts = append(drifts, drift)
+			continue
+		}
+		if liveMeta, ok := live["metadata"].(map[string]interface{}); ok {
+			drift.Namespace, _ = liveMeta["namespace"].(string)
+		}
+		normalized, err := normalizeObject(live)
+		if err != nil {
+			return nil, fmt.Errorf("normalise live %s: %w", res.kind+"/"+res.name, err)
+		}
+		desired := res.object
+		if res.kind == "Secret" {
+			desired = foldStringData(desired)
+		}
+		var fields []string
+		collectDriftedFields("", desired, normalized, &fields)
+		if len(fields) > 0 {
+			drift.Change = DriftModified
+			drift.Fields = fields
+			drifts = append(drifts, drift)
+		}
+	}
+	return drifts, nil
+}
+
+// collectDriftedFields appends the paths of desired fields whose live values differ. Lists must
+// keep their length; their elements are compared positionally.
+func collectDriftedFields(path string, desired, live interface{}, fields *[]string) {
+	switch want := desired.(type) {
+	case nil:
+		return
+	case map[string]interface{}:
+		got, ok := live.(map[string]interface{})
+		if !ok {
+			*fields = append(*fields, fieldPath(path))
+			return
+		}
+		keys := make([]string, 0, len(want))
+		for key := range want {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			value, present := got[key]
+			if !present {
+				if want[key] != nil {
+					*fields = append(*fields, child)
+				}
+				continue
+			}
+			collectDriftedFields(child, want[key], value, fields)
+		}
+	case []interface{}:
+		got, ok := live.([]interface{})
+		if !ok || len(got) != len(want) {
+			*fields = append(*fields, fieldPath(path))
+			return
+		}
+		for i := range want {
+			collectDriftedFields(path+"["+strconv.Itoa(i)+"]", want[i], got[i], fields)
+		}
+	default:
+		if !scalarEqual(want, live) {
+			*fields = append(*fields, fieldPath(path))
+		}
+	}
+}
+
+func fieldPath(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
+
+// scalarEqual treats values that render identically as equal, since the API server normalises
+// numbers and quantities that manifests may spell as strings.
+func scalarEqual(desired, live interface{}) bool {
+	if reflect.DeepEqual(desired, live) {
+		return true
+	}
+	return fmt.Sprint(desired) == fmt.Sprint(live)
+}
+
+// normalizeObject round-trips the live object through JSON so numbers compare like manifest values.
+func normalizeObject(object map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// foldStringData converts Secret stringData into the base64 data the API server stores.
+func foldStringData(object map[string]interface{}) map[string]interface{} {
+	stringData, ok := nestedMap(object, "stringData")
+	if !ok {
+		return object
+	}
+	folded := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		folded[key] = value
+	}
+	delete(folded, "stringData")
+	data := map[string]interface{}{}
+	if existing, ok := nestedMap(object, "data"); ok {
+		for key, value := range existing {
+			data[key] = value
+		}
+	}
+	for key, value := range stringData {
+		data[key] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(value)))
+	}
+	folded["data"] = data
+	return folded
+}
+
+// ClusterObjectReader reads live objects through the dynamic client, resolving kinds with a REST mapper.
+type ClusterObjectReader struct {
+	client dynamic.Interface
+	mapper meta.RESTMapper
+}
+
+// NewClusterObjectReader constructs an ObjectReader backed by the provided clients.
+func NewClusterObjectReader(client dynamic.Interface, mapper meta.RESTMapper) *ClusterObjectReader {
+	return &ClusterObjectReader{client: client, mapper: mapper}
+}
+
+// LiveObject returns the live object, or nil when it or its kind no longer exists in the cluster.
+func (r *ClusterObjectReader) LiveObject(ctx context.Context, apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := r.mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: kind}, gv.Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var resource dynamic.ResourceInterface = r.client.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		resource = r.client.Resource(mapping.Resource).Namespace(namespace)
+	}
+	object, err := resource.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return object.Object, nil
+}
@@ -0,0 +1,168 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+const driftManifest = `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+        - name: web
+          image: registry.example.com/web:1.0.0
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+stringData:
+  token: s3cret
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  mode: fast
+`
+
+type fakeObjectReader struct {
+	objects map[string]map[string]interface{}
+	err     error
+}
+
+func (f fakeObjectReader) LiveObject(_ context.Context, _, kind, namespace, name string) (map[string]interface{}, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.objects[resourceKey(kind, namespace, name)], nil
+}
+
+func liveDeployment(replicas int64, image string) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "demo", "resourceVersion": "42"},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "web", "image": image, "imagePullPolicy": "IfNotPresent"},
+					},
+				},
+			},
+		},
+		"status": map[string]interface{}{"readyReplicas": int64(2)},
+	}
+}
+
+func liveSecret() map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "creds", "namespace": "demo"},
+		"data":       map[string]interface{}{"token": "czNjcmV0"},
+		"type":       "Opaque",
+	}
+}
+
+func TestDetectResourceDriftIgnoresDefaultedFields(t *testing.T) {
+	reader := fakeObjectReader{objects: map[string]map[string]interface{}{
+		"Deployment/demo/web": liveDeployment(2, "registry.example.com/web:1.0.0"),
+		"Secret/demo/creds":   liveSecret(),
+		"ConfigMap/demo/settings": {
+			"apiVersion": "v1", "kind": "ConfigMap",
+			"metadata": map[string]interface{}{"name": "settings", "namespace": "demo"},
+			"data":     map[string]interface{}{"mode": "fast"},
+		},
+	}}
+
+	drifts, err := DetectResourceDrift(context.Background(), driftManifest, "demo", reader)
+	if err != nil {
+		t.Fatalf("detect drift: %v", err)
+	}
+	if len(drifts) != 0 {
+		t.Fatalf("expected no drift, got %+v", drifts)
+	}
+}
+
+func TestDetectResourceDriftReportsModifiedAndMissingResources(t *testing.T) {
+	reader := fakeObjectReader{objects: map[string]map[string]interface{}{
+		"Deployment/demo/web": liveDeployment(5, "registry.example.com/web:hotfix"),
+		"Secret/demo/creds":   liveSecret(),
+	}}
+
+	drifts, err := DetectResourceDrift(context.Background(), driftManifest, "demo", reader)
+	if err != nil {
+		t.Fatalf("detect drift: %v", err)
+	}
+	want := []ResourceDrift{
+		{Kind: "ConfigMap", Namespace: "demo", Name: "settings", Change: DriftMissing},
+		{Kind: "Deployment", Namespace: "demo", Name: "web", Change: DriftModified,
+			Fields: []string{"spec.replicas", "spec.template.spec.containers[0].image"}},
+	}
+	if !reflect.DeepEqual(drifts, want) {
+		t.Fatalf("unexpected drift\nwant %+v\ngot  %+v", want, drifts)
+	}
+}
+
+func TestDetectResourceDriftPropagatesReadErrors(t *testing.T) {
+	wantErr := errors.New("forbidden")
+	if _, err := DetectResourceDrift(context.Background(), driftManifest, "demo", fakeObjectReader{err: wantErr}); !errors.Is(err, wantErr) {
+		t.Fatalf("expected read error, got %v", err)
+	}
+}
+
+func TestHashValuesIsStable(t *testing.T) {
+	first := HashValues(map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": "d"}})
+	second := HashValues(map[string]interface{}{"b": map[string]interface{}{"c": "d"}, "a": 1})
+	if first != second || len(first) != len("sha256:")+64 {
+		t.Fatalf("expected stable sha256 digest, got %q and %q", first, second)
+	}
+	if HashValues(nil) != HashValues(map[string]interface{}{}) {
+		t.Fatal("expected nil and empty values to hash identically")
+	}
+	if HashValues(map[string]interface{}{"a": 2}) == first {
+		t.Fatal("expected different values to hash differently")
+	}
+}
+
+func TestClusterObjectReaderResolvesKindsAndMissingObjects(t *testing.T) {
+	configMaps := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(configMaps, meta.RESTScopeNamespace)
+	live := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "settings", "namespace": "demo"},
+	}}
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), live)
+	reader := NewClusterObjectReader(client, mapper)
+
+	object, err := reader.LiveObject(context.Background(), "v1", "ConfigMap", "demo", "settings")
+	if err != nil || object == nil {
+		t.Fatalf("expected live ConfigMap, got %v (%v)", object, err)
+	}
+	for _, tc := range []struct{ kind, name string }{{"ConfigMap", "absent"}, {"Widget", "settings"}} {
+		object, err := reader.LiveObject(context.Background(), "v1", tc.kind, "demo", tc.name)
+		if err != nil || object != nil {
+			t.Fatalf("expected %s/%s to be reported missing, got %v (%v)", tc.kind, tc.name, object, err)
+		}
+	}
+}
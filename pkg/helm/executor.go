@@ -1,6 +1,8 @@
 package helm
 
 import (
+	"strconv"
+
 	"github.com/dobrovols/chainctl/internal/cli/logging"
 	"github.com/dobrovols/chainctl/internal/config"
 	"github.com/dobrovols/chainctl/pkg/bundle"
@@ -29,6 +31,25 @@ func NewLoggingExecutor(exec Executor, logger telemetry.StructuredLogger) Execut
 }
 
 func (l *loggingExecutor) UpgradeRelease(profile *config.Profile, b *bundle.Bundle) error {
+	_, err := l.run(profile, b, func() (ReleaseInfo, error) {
+		return ReleaseInfo{}, l.next.UpgradeRelease(profile, b)
+	})
+	return err
+}
+
+// ApplyRelease delegates to the wrapped executor and records the resulting release revision and status.
+func (l *loggingExecutor) ApplyRelease(profile *config.Profile, b *bundle.Bundle) (ReleaseInfo, error) {
+	return l.run(profile, b, func() (ReleaseInfo, error) {
+		return applyRelease(l.next, profile, b)
+	})
+}
+
+// ValidateValues delegates schema validation to the wrapped executor.
+func (l *loggingExecutor) ValidateValues(profile *config.Profile, b *bundle.Bundle) error {
+	return validateValues(l.next, profile, b)
+}
+
+func (l *loggingExecutor) run(profile *config.Profile, b *bundle.Bundle, fn func() (ReleaseInfo, error)) (ReleaseInfo, error) {
 	args := buildHelmArgs(profile)
 	metadata := map[string]string{}
 	if profile.HelmNamespace != "" {
@@ -55,7 +76,7 @@ func (l *loggingExecutor) UpgradeRelease(profile *config.Profile, b *bundle.Bund
 	}
 	_ = l.logger.Emit(entry)
 
-	err := l.next.UpgradeRelease(profile, b)
+	info, err := fn()
 	severity := telemetry.SeverityInfo
 	if err != nil {
 		severity = telemetry.SeverityError
@@ -64,19 +85,37 @@ func (l *loggingExecutor) UpgradeRelease(profile *config.Profile, b *bundle.Bund
 	if err != nil {
 		sanitizedErr = logging.SanitizeText(err.Error())
 	}
+	completion := cloneMetadata(metadata)
+	AddReleaseMetadata(completion, info)
 	entry = telemetry.Entry{
 		Category:      telemetry.CategoryCommand,
 		Message:       "helm upgrade complete",
 		Severity:      severity,
 		Command:       sanitized,
-		Metadata:      cloneMetadata(metadata),
+		Metadata:      completion,
 		StderrExcerpt: sanitizedErr,
 		Error:         err,
 		Step:          "helm",
 	}
 	_ = l.logger.Emit(entry)
 
-	return err
+	return info, err
+}
+
+// AddReleaseMetadata copies release revision details into telemetry metadata.
+func AddReleaseMetadata(metadata map[string]string, info ReleaseInfo) {
+	if info.Revision > 0 {
+		metadata["revision"] = strconv.Itoa(info.Revision)
+	}
+	if info.Status != "" {
+		metadata["status"] = info.Status
+	}
+	if info.ChartVersion != "" {
+		metadata["chartVersion"] = info.ChartVersion
+	}
+	if info.Notes != "" {
+		metadata["notes"] = logging.SanitizeText(info.Notes)
+	}
 }
 
 func buildHelmArgs(profile *config.Profile) []string {
@@ -87,12 +126,18 @@ func buildHelmArgs(profile *config.Profile) []string {
 	if profile.HelmNamespace != "" {
 		args = append(args, "--namespace", profile.HelmNamespace)
 	}
-	if profile.EncryptedFile != "" {
-		args = append(args, "--values", profile.EncryptedFile)
+	for _, path := range profile.ValuesLayers() {
+		args = append(args, "--values", path)
 	}
 	if profile.Passphrase != "" {
 		args = append(args, "--values-passphrase", profile.Passphrase)
 	}
+	for _, expr := range profile.SetValues {
+		args = append(args, "--set", expr)
+	}
+	for _, expr := range profile.SetStringValues {
+		args = append(args, "--set-string", expr)
+	}
 	if profile.BundlePath != "" {
 		args = append(args, "--bundle-path", profile.BundlePath)
 	}
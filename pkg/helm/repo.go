@@ -0,0 +1,140 @@
+package helm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+var (
+	errRepoChartNotFound  = errors.New("chart not found in repository index")
+	errRepoDigestMismatch = errors.New("downloaded chart does not match the repository index digest")
+)
+
+// ErrRepoChartNotFound exposes the missing repository chart error.
+func ErrRepoChartNotFound() error { return errRepoChartNotFound }
+
+// ErrRepoDigestMismatch exposes the repository archive digest error.
+func ErrRepoDigestMismatch() error { return errRepoDigestMismatch }
+
+// HTTPRepoPuller downloads charts from classic index.yaml chart repositories.
+type HTTPRepoPuller struct {
+	getters getter.Providers
+}
+
+// NewRepoPuller constructs a repository puller using the given Helm getters. Nil providers fall
+// back to the plain HTTP(S) getter.
+func NewRepoPuller(getters getter.Providers) *HTTPRepoPuller {
+	if len(getters) == 0 {
+		getters = getter.Providers{{Schemes: []string{"http", "https"}, New: getter.NewHTTPGetter}}
+	}
+	return &HTTPRepoPuller{getters: getters}
+}
+
+// PullRepoChart reads the repository index, selects the requested version (latest when empty),
+// downloads the archive, and checks it against the digest published in the index. A provenance
+// file published next to the archive is downloaded alongside it when available.
+func (p *HTTPRepoPuller) PullRepoChart(_ context.Context, chart RepoChart) (PullResult, error) {
+	workDir, err := os.MkdirTemp("", "chainctl-repo-*")
+	if err != nil {
+		return PullResult{}, fmt.Errorf("create chart download directory: %w", err)
+	}
+
+	repoURL := strings.TrimSuffix(chart.RepoURL, "/")
+	index, err := p.loadIndex(repoURL, workDir)
+	if err != nil {
+		return PullResult{}, err
+	}
+	version, err := index.Get(chart.Name, chart.Version)
+	if err != nil || len(version.URLs) == 0 {
+		return PullResult{}, fmt.Errorf("%w: %s %s in %s", errRepoChartNotFound, chart.Name, displayVersion(chart.Version), repoURL)
+	}
+
+	chartURL, err := repo.ResolveReferenceURL(repoURL+"/", version.URLs[0])
+	if err != nil {
+		return PullResult{}, fmt.Errorf("resolve chart URL for %s: %w", chart.Name, err)
+	}
+	data, err := p.get(chartURL)
+	if err != nil {
+		return PullResult{}, fmt.Errorf("download chart %s: %w", chartURL, err)
+	}
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	if expected := strings.ToLower(strings.TrimPrefix(version.Digest, "sha256:")); expected != "" && expected != digest {
+		return PullResult{}, fmt.Errorf("%w: %s expected sha256:%s, got sha256:%s", errRepoDigestMismatch, chartURL, expected, digest)
+	}
+
+	chartPath := filepath.Join(workDir, archiveName(chartURL, version.Name, version.Version))
+	if err := os.WriteFile(chartPath, data, 0o644); err != nil {
+		return PullResult{}, fmt.Errorf("store chart archive: %w", err)
+	}
+	if prov, err := p.get(chartURL + ".prov"); err == nil {
+		if err := os.WriteFile(chartPath+".prov", prov, 0o644); err != nil {
+			return PullResult{}, fmt.Errorf("store chart provenance: %w", err)
+		}
+	}
+
+	return PullResult{
+		ChartPath: chartPath,
+		Digest:    "sha256:" + digest,
+		Version:   version.Version,
+	}, nil
+}
+
+func (p *HTTPRepoPuller) loadIndex(repoURL, workDir string) (*repo.IndexFile, error) {
+	data, err := p.get(repoURL + "/index.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("download repository index %s: %w", repoURL, err)
+	}
+	indexPath := filepath.Join(workDir, "index.yaml")
+	if err := os.WriteFile(indexPath, data, 0o600); err != nil {
+		return nil, fmt.Errorf("store repository index: %w", err)
+	}
+	index, err := repo.LoadIndexFile(indexPath)
+	if err != nil {
+		return nil, fmt.Errorf("load repository index %s: %w", repoURL, err)
+	}
+	return index, nil
+}
+
+func (p *HTTPRepoPuller) get(rawURL string) ([]byte, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	g, err := p.getters.ByScheme(parsed.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := g.Get(rawURL, getter.WithURL(rawURL))
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func archiveName(chartURL, name, version string) string {
+	if parsed, err := url.Parse(chartURL); err == nil {
+		if base := path.Base(parsed.Path); strings.HasSuffix(base, ".tgz") {
+			return base
+		}
+	}
+	return fmt.Sprintf("%s-%s.tgz", name, version)
+}
+
+func displayVersion(version string) string {
+	if version == "" {
+		return "(latest)"
+	}
+	return version
+}
@@ -3,17 +3,22 @@ package helm
 import (
 	"context"
 	"errors"
+	"fmt"
 	"path/filepath"
 	"strings"
 
+	"github.com/dobrovols/chainctl/internal/config"
 	"github.com/dobrovols/chainctl/pkg/bundle"
+	"github.com/dobrovols/chainctl/pkg/chartcache"
 	"github.com/dobrovols/chainctl/pkg/state"
 )
 
-// PullResult captures the outcome of fetching a chart from an OCI registry.
+// PullResult captures the outcome of fetching a chart from an OCI registry or chart repository.
 type PullResult struct {
 	ChartPath string
 	Digest    string
+	// Version is the chart version that was pulled, when the puller knows it.
+	Version string
 }
 
 // OCIPuller downloads Helm charts from OCI registries.
@@ -21,27 +26,75 @@ type OCIPuller interface {
 	Pull(ctx context.Context, ref string) (PullResult, error)
 }
 
+// DigestResolver is implemented by pullers that can look up the manifest digest of a
+// reference without downloading the chart, which lets the resolver serve cached charts.
+type DigestResolver interface {
+	ResolveDigest(ctx context.Context, ref string) (string, error)
+}
+
+// ChartCache stores pulled charts keyed by OCI manifest digest.
+type ChartCache interface {
+	Get(digest string) (chartcache.Entry, bool, error)
+	Lookup(ref string) (chartcache.Entry, bool, error)
+	Put(ref, digest, chartPath string) (chartcache.Entry, error)
+}
+
 // BundleLoader loads a local bundle from disk.
 type BundleLoader func(bundlePath, cacheRoot string) (*bundle.Bundle, error)
 
 // ResolveOptions define the user inputs guiding chart resolution.
 type ResolveOptions struct {
-	OCIReference   string
+	OCIReference string
+	// RepoReference selects a chart from an index.yaml HTTP repository (<repo-url>/<chart>[:<version>]).
+	RepoReference string
+	// ChartDirectory points at a local unpacked chart.
+	ChartDirectory string
 	BundlePath     string
 	BundleCacheDir string
+	// ExpectedDigest pins the OCI manifest digest; resolution fails when the registry reports another.
+	ExpectedDigest string
+	// ReleaseName selects the bundle chart to verify when a bundle carries several charts.
+	ReleaseName string
+	// Verify enables signature verification of the resolved chart.
+	Verify VerifyOptions
+	// Offline resolves OCI references from the chart cache only, without contacting the registry.
+	// Repository charts cannot be resolved offline; chart directories are always local.
+	Offline bool
 }
 
 // ResolveResult describes the selected chart source and auxiliary data required to apply it.
 type ResolveResult struct {
-	Source    state.ChartSource
-	ChartPath string
-	Bundle    *bundle.Bundle
+	Source       state.ChartSource
+	ChartPath    string
+	Bundle       *bundle.Bundle
+	Verification *state.ChartVerification
+	// Cached reports that the OCI chart was served from the chart cache.
+	Cached bool
 }
 
 // Resolver normalises user input into a chart source usable by the Helm installer.
 type Resolver struct {
 	puller       OCIPuller
+	repoPuller   RepoPuller
 	bundleLoader BundleLoader
+	cache        ChartCache
+}
+
+// ResolverOption configures a Resolver.
+type ResolverOption func(*Resolver)
+
+// WithChartCache reuses charts from cache when the manifest digest matches and stores new pulls in it.
+func WithChartCache(cache ChartCache) ResolverOption {
+	return func(r *Resolver) {
+		r.cache = cache
+	}
+}
+
+// WithRepoPuller enables charts from index.yaml HTTP repositories.
+func WithRepoPuller(puller RepoPuller) ResolverOption {
+	return func(r *Resolver) {
+		r.repoPuller = puller
+	}
 }
 
 var (
@@ -50,11 +103,19 @@ var (
 	errResolverInvalidOCI          = errors.New("invalid OCI artifact reference")
 	errResolverPullerMissing       = errors.New("oci puller not configured")
 	errResolverBundleLoaderMissing = errors.New("bundle loader not configured")
+	errResolverDigestMismatch      = errors.New("chart digest does not match the expected digest")
+	errResolverChartNotCached      = errors.New("chart is not available in the local cache")
+	errResolverCacheMissing        = errors.New("chart cache not configured")
+	errResolverSignatureOCI        = errors.New("detached signature verification (--verify-key) is not supported for oci:// charts; verify them with --verify-keyring and a provenance layer")
 )
 
 // NewResolver constructs a Resolver with the provided dependencies.
-func NewResolver(puller OCIPuller, loader BundleLoader) *Resolver {
-	return &Resolver{puller: puller, bundleLoader: loader}
+func NewResolver(puller OCIPuller, loader BundleLoader, opts ...ResolverOption) *Resolver {
+	r := &Resolver{puller: puller, bundleLoader: loader}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // ErrResolverConflictingSources exposes the mutual exclusion error.
@@ -66,17 +127,42 @@ func ErrResolverMissingSource() error { return errResolverMissingSource }
 // ErrResolverInvalidOCI exposes the invalid OCI error.
 func ErrResolverInvalidOCI() error { return errResolverInvalidOCI }
 
+// ErrResolverDigestMismatch exposes the digest pinning error.
+func ErrResolverDigestMismatch() error { return errResolverDigestMismatch }
+
+// ErrResolverChartNotCached exposes the offline cache miss error.
+func ErrResolverChartNotCached() error { return errResolverChartNotCached }
+
+// ErrResolverSignatureOCI exposes the error returned when detached signatures are requested for an OCI chart.
+func ErrResolverSignatureOCI() error { return errResolverSignatureOCI }
+
 func (r *Resolver) Resolve(ctx context.Context, opts ResolveOptions) (ResolveResult, error) {
-	hasChart := strings.TrimSpace(opts.OCIReference) != ""
-	hasBundle := strings.TrimSpace(opts.BundlePath) != ""
+	sources := map[string]bool{
+		state.SourceTypeOCI:    strings.TrimSpace(opts.OCIReference) != "",
+		state.SourceTypeRepo:   strings.TrimSpace(opts.RepoReference) != "",
+		state.SourceTypeDir:    strings.TrimSpace(opts.ChartDirectory) != "",
+		state.SourceTypeBundle: strings.TrimSpace(opts.BundlePath) != "",
+	}
+	selected := ""
+	for sourceType, set := range sources {
+		if !set {
+			continue
+		}
+		if selected != "" {
+			return ResolveResult{}, errResolverConflictingSources
+		}
+		selected = sourceType
+	}
 
-	switch {
-	case hasChart && hasBundle:
-		return ResolveResult{}, errResolverConflictingSources
-	case !hasChart && !hasBundle:
+	switch selected {
+	case "":
 		return ResolveResult{}, errResolverMissingSource
-	case hasChart:
+	case state.SourceTypeOCI:
 		return r.resolveOCI(ctx, opts)
+	case state.SourceTypeRepo:
+		return r.resolveRepo(ctx, opts)
+	case state.SourceTypeDir:
+		return r.resolveDir(opts)
 	default:
 		return r.resolveBundle(ctx, opts)
 	}
@@ -86,25 +172,167 @@ func (r *Resolver) resolveOCI(ctx context.Context, opts ResolveOptions) (Resolve
 	if !strings.HasPrefix(strings.ToLower(opts.OCIReference), "oci://") {
 		return ResolveResult{}, errResolverInvalidOCI
 	}
+	if len(opts.Verify.PublicKeys) > 0 {
+		// Registries publish no detached .sig beside the chart, so these keys could never match.
+		return ResolveResult{}, errResolverSignatureOCI
+	}
+	if opts.Offline {
+		return r.resolveOffline(opts)
+	}
 	if r.puller == nil {
 		return ResolveResult{}, errResolverPullerMissing
 	}
 
-	result, err := r.puller.Pull(ctx, opts.OCIReference)
+	digest, err := r.manifestDigest(ctx, opts)
+	if err != nil {
+		return ResolveResult{}, err
+	}
+	if r.cache != nil && digest != "" {
+		cached, ok, err := r.cache.Get(digest)
+		if err != nil {
+			return ResolveResult{}, err
+		}
+		if ok {
+			return finishOCI(opts, cached.ChartPath, cached.Digest, true)
+		}
+	}
+
+	// Pull by digest so the chart installed is the one whose digest is recorded and verified,
+	// even if the tag moves between the lookup and the download.
+	result, err := r.puller.Pull(ctx, digestReference(opts.OCIReference, digest))
+	if err != nil {
+		return ResolveResult{}, err
+	}
+	if err := verifyDigest(digest, result.Digest); err != nil {
+		return ResolveResult{}, err
+	}
+	chartPath := result.ChartPath
+	if r.cache != nil && result.Digest != "" {
+		// Caching is best effort: a failed store still leaves the freshly pulled chart usable.
+		if entry, err := r.cache.Put(opts.OCIReference, result.Digest, result.ChartPath); err == nil {
+			chartPath = entry.ChartPath
+		}
+	}
+	return finishOCI(opts, chartPath, result.Digest, false)
+}
+
+// manifestDigest returns the digest the reference must resolve to: the one pinned by
+// --expect-digest or an @digest reference, otherwise the one the registry reports for the tag.
+// Pullers that cannot look digests up yield no digest.
+func (r *Resolver) manifestDigest(ctx context.Context, opts ResolveOptions) (string, error) {
+	if digest := pinnedDigest(opts); digest != "" {
+		return digest, nil
+	}
+	resolver, ok := r.puller.(DigestResolver)
+	if !ok {
+		return "", nil
+	}
+	resolved, err := resolver.ResolveDigest(ctx, opts.OCIReference)
+	if err != nil {
+		return "", fmt.Errorf("resolve manifest digest for %s: %w", opts.OCIReference, err)
+	}
+	return chartcache.NormalizeDigest(resolved), nil
+}
+
+// digestReference appends the digest to a tagged reference that does not already carry one.
+func digestReference(ref, digest string) string {
+	if digest == "" || strings.Contains(ref, "@") {
+		return ref
+	}
+	return ref + "@" + digest
+}
+
+func (r *Resolver) resolveOffline(opts ResolveOptions) (ResolveResult, error) {
+	if r.cache == nil {
+		return ResolveResult{}, errResolverCacheMissing
+	}
+	var entry chartcache.Entry
+	var ok bool
+	var err error
+	if digest := pinnedDigest(opts); digest != "" {
+		entry, ok, err = r.cache.Get(digest)
+	} else {
+		entry, ok, err = r.cache.Lookup(opts.OCIReference)
+	}
+	if err != nil {
+		return ResolveResult{}, err
+	}
+	if !ok {
+		return ResolveResult{}, fmt.Errorf("%w: %s", errResolverChartNotCached, opts.OCIReference)
+	}
+	return finishOCI(opts, entry.ChartPath, entry.Digest, true)
+}
+
+func finishOCI(opts ResolveOptions, chartPath, digest string, cached bool) (ResolveResult, error) {
+	if err := verifyDigest(opts.ExpectedDigest, digest); err != nil {
+		return ResolveResult{}, err
+	}
+	verification, err := verifyResolvedChart(chartPath, opts.Verify)
 	if err != nil {
 		return ResolveResult{}, err
 	}
 
 	return ResolveResult{
 		Source: state.ChartSource{
-			Type:      "oci",
+			Type:      state.SourceTypeOCI,
 			Reference: opts.OCIReference,
-			Digest:    result.Digest,
+			Version:   ociTag(opts.OCIReference),
+			Digest:    digest,
 		},
-		ChartPath: result.ChartPath,
+		ChartPath:    chartPath,
+		Verification: verification,
+		Cached:       cached,
 	}, nil
 }
 
+// ociTag returns the tag of an OCI reference, if it carries one.
+func ociTag(ref string) string {
+	if idx := strings.Index(ref, "@"); idx != -1 {
+		ref = ref[:idx]
+	}
+	idx := strings.LastIndex(ref, ":")
+	if idx == -1 || idx < strings.LastIndex(ref, "/") {
+		return ""
+	}
+	return ref[idx+1:]
+}
+
+// pinnedDigest returns the digest fixed by the user, if any, without contacting the registry.
+func pinnedDigest(opts ResolveOptions) string {
+	if idx := strings.LastIndex(opts.OCIReference, "@"); idx != -1 {
+		return chartcache.NormalizeDigest(opts.OCIReference[idx+1:])
+	}
+	return chartcache.NormalizeDigest(opts.ExpectedDigest)
+}
+
+func verifyResolvedChart(chartPath string, opts VerifyOptions) (*state.ChartVerification, error) {
+	if !opts.Enabled() {
+		return nil, nil
+	}
+	verification, err := VerifyChart(chartPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// verifyDigest compares the pulled manifest digest with the pinned one, if any.
+// A missing algorithm prefix on the expected digest is read as sha256.
+func verifyDigest(expected, actual string) error {
+	expected = chartcache.NormalizeDigest(expected)
+	if expected == "" {
+		return nil
+	}
+	actual = chartcache.NormalizeDigest(actual)
+	if actual == "" {
+		return fmt.Errorf("%w: expected %s, registry reported no digest", errResolverDigestMismatch, expected)
+	}
+	if actual != expected {
+		return fmt.Errorf("%w: expected %s, got %s", errResolverDigestMismatch, expected, actual)
+	}
+	return nil
+}
+
 func (r *Resolver) resolveBundle(_ context.Context, opts ResolveOptions) (ResolveResult, error) {
 	if r.bundleLoader == nil {
 		return ResolveResult{}, errResolverBundleLoaderMissing
@@ -123,11 +351,23 @@ func (r *Resolver) resolveBundle(_ context.Context, opts ResolveOptions) (Resolv
 		return ResolveResult{}, err
 	}
 
+	var verification *state.ChartVerification
+	if opts.Verify.Enabled() {
+		chartPath, err := LocateChartPath(&config.Profile{HelmRelease: opts.ReleaseName}, tb)
+		if err != nil {
+			return ResolveResult{}, err
+		}
+		if verification, err = verifyResolvedChart(chartPath, opts.Verify); err != nil {
+			return ResolveResult{}, err
+		}
+	}
+
 	return ResolveResult{
 		Source: state.ChartSource{
-			Type:      "bundle",
+			Type:      state.SourceTypeBundle,
 			Reference: opts.BundlePath,
 		},
-		Bundle: tb,
+		Bundle:       tb,
+		Verification: verification,
 	}, nil
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/dobrovols/chainctl/pkg/bundle"
+	"github.com/dobrovols/chainctl/pkg/chartcache"
 	"github.com/dobrovols/chainctl/pkg/helm"
 )
 
@@ -170,3 +171,142 @@ func TestResolverSurfacesBundleLoaderErrors(t *testing.T) {
 		t.Fatalf("expected loader error to propagate, got %v", err)
 	}
 }
+
+func TestResolverAcceptsMatchingExpectedDigest(t *testing.T) {
+	puller := &stubPuller{result: helm.PullResult{ChartPath: "/tmp/chart", Digest: "sha256:ABC123"}}
+	resolver := helm.NewResolver(puller, (&stubBundleLoader{}).Load)
+
+	result, err := resolver.Resolve(context.Background(), helm.ResolveOptions{
+		OCIReference:   "oci://example.com/app:1.0.0",
+		ExpectedDigest: "abc123",
+	})
+	if err != nil {
+		t.Fatalf("expected pinned digest to match, got %v", err)
+	}
+	if result.Source.Digest != "sha256:ABC123" {
+		t.Fatalf("expected registry digest to be recorded, got %q", result.Source.Digest)
+	}
+}
+
+func TestResolverRejectsDigestMismatch(t *testing.T) {
+	puller := &stubPuller{result: helm.PullResult{ChartPath: "/tmp/chart", Digest: "sha256:def456"}}
+	resolver := helm.NewResolver(puller, (&stubBundleLoader{}).Load)
+
+	_, err := resolver.Resolve(context.Background(), helm.ResolveOptions{
+		OCIReference:   "oci://example.com/app:1.0.0",
+		ExpectedDigest: "sha256:abc123",
+	})
+	if !errors.Is(err, helm.ErrResolverDigestMismatch()) {
+		t.Fatalf("expected digest mismatch error, got %v", err)
+	}
+}
+
+func TestResolverRejectsMissingDigestWhenPinned(t *testing.T) {
+	puller := &stubPuller{result: helm.PullResult{ChartPath: "/tmp/chart"}}
+	resolver := helm.NewResolver(puller, (&stubBundleLoader{}).Load)
+
+	_, err := resolver.Resolve(context.Background(), helm.ResolveOptions{
+		OCIReference:   "oci://example.com/app:1.0.0",
+		ExpectedDigest: "sha256:abc123",
+	})
+	if !errors.Is(err, helm.ErrResolverDigestMismatch()) {
+		t.Fatalf("expected digest mismatch error, got %v", err)
+	}
+}
+
+type digestPuller struct {
+	stubPuller
+	digest string
+}
+
+func (d *digestPuller) ResolveDigest(context.Context, string) (string, error) {
+	return d.digest, nil
+}
+
+func TestResolverStoresPulledChartAndReusesItByDigest(t *testing.T) {
+	cache := chartcache.New(t.TempDir())
+	chartPath := packageTestChart(t)
+	puller := &digestPuller{
+		stubPuller: stubPuller{result: helm.PullResult{ChartPath: chartPath, Digest: "sha256:abc123"}},
+		digest:     "sha256:abc123",
+	}
+	resolver := helm.NewResolver(puller, (&stubBundleLoader{}).Load, helm.WithChartCache(cache))
+	opts := helm.ResolveOptions{OCIReference: "oci://example.com/demo:0.1.0"}
+
+	first, err := resolver.Resolve(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("first Resolve: %v", err)
+	}
+	if first.Cached || !puller.called {
+		t.Fatalf("expected first resolution to pull, got %+v", first)
+	}
+	if puller.ref != "oci://example.com/demo:0.1.0@sha256:abc123" {
+		t.Fatalf("expected the chart to be pulled by the resolved digest, got %s", puller.ref)
+	}
+
+	puller.called = false
+	second, err := resolver.Resolve(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("second Resolve: %v", err)
+	}
+	if !second.Cached || puller.called {
+		t.Fatalf("expected cache hit without pulling, got %+v (pulled=%v)", second, puller.called)
+	}
+	if second.ChartPath != first.ChartPath || second.Source.Digest != "sha256:abc123" {
+		t.Fatalf("unexpected cached result %+v", second)
+	}
+
+	puller.digest = "sha256:fff000"
+	puller.result.Digest = "sha256:fff000"
+	third, err := resolver.Resolve(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("third Resolve: %v", err)
+	}
+	if third.Cached || !puller.called {
+		t.Fatalf("expected a moved tag to be pulled again, got %+v", third)
+	}
+}
+
+func TestResolverRejectsPullThatDiffersFromResolvedDigest(t *testing.T) {
+	puller := &digestPuller{
+		stubPuller: stubPuller{result: helm.PullResult{ChartPath: "/tmp/chart", Digest: "sha256:fff000"}},
+		digest:     "sha256:abc123",
+	}
+	resolver := helm.NewResolver(puller, (&stubBundleLoader{}).Load)
+
+	_, err := resolver.Resolve(context.Background(), helm.ResolveOptions{OCIReference: "oci://example.com/demo:0.1.0"})
+	if !errors.Is(err, helm.ErrResolverDigestMismatch()) {
+		t.Fatalf("expected digest mismatch error, got %v", err)
+	}
+}
+
+func TestResolverOfflineUsesCacheOnly(t *testing.T) {
+	cache := chartcache.New(t.TempDir())
+	ref := "oci://example.com/demo:0.1.0"
+	if _, err := cache.Put(ref, "sha256:abc123", packageTestChart(t)); err != nil {
+		t.Fatalf("seed cache: %v", err)
+	}
+	puller := &stubPuller{err: errors.New("registry unreachable")}
+	resolver := helm.NewResolver(puller, (&stubBundleLoader{}).Load, helm.WithChartCache(cache))
+
+	result, err := resolver.Resolve(context.Background(), helm.ResolveOptions{OCIReference: ref, Offline: true})
+	if err != nil {
+		t.Fatalf("offline Resolve by reference: %v", err)
+	}
+	if !result.Cached || result.Source.Digest != "sha256:abc123" || puller.called {
+		t.Fatalf("unexpected offline result %+v (pulled=%v)", result, puller.called)
+	}
+
+	if _, err := resolver.Resolve(context.Background(), helm.ResolveOptions{
+		OCIReference:   "oci://example.com/demo:0.2.0",
+		ExpectedDigest: "sha256:abc123",
+		Offline:        true,
+	}); err != nil {
+		t.Fatalf("offline Resolve by pinned digest: %v", err)
+	}
+
+	_, err = resolver.Resolve(context.Background(), helm.ResolveOptions{OCIReference: "oci://example.com/other:1.0.0", Offline: true})
+	if !errors.Is(err, helm.ErrResolverChartNotCached()) {
+		t.Fatalf("expected not cached error, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+)
+
+// RollbackOptions selects the release and revision to restore.
+type RollbackOptions struct {
+	Release   string
+	Namespace string
+	// Revision is the target revision; zero restores the revision preceding the current one.
+	Revision int
+}
+
+// Rollbacker restores a release to a previously deployed revision.
+type Rollbacker interface {
+	Rollback(RollbackOptions) (ReleaseInfo, error)
+}
+
+// Rollback restores the release through the Helm rollback action and reports the new revision.
+func (e *SDKExecutor) Rollback(opts RollbackOptions) (ReleaseInfo, error) {
+	if strings.TrimSpace(opts.Release) == "" {
+		return ReleaseInfo{}, errReleaseNameEmpty
+	}
+	if opts.Revision < 0 {
+		return ReleaseInfo{}, errors.New("rollback revision must not be negative")
+	}
+
+	cfg, err := e.configFactory(opts.Namespace)
+	if err != nil {
+		return ReleaseInfo{}, err
+	}
+
+	rollback := action.NewRollback(cfg)
+	rollback.Version = opts.Revision
+	rollback.Timeout = e.timeout
+	if err := rollback.Run(opts.Release); err != nil {
+		return ReleaseInfo{}, fmt.Errorf("helm rollback %s: %w", opts.Release, err)
+	}
+
+	rel, err := action.NewStatus(cfg).Run(opts.Release)
+	if err != nil {
+		return ReleaseInfo{}, fmt.Errorf("query release status: %w", err)
+	}
+	return releaseInfoFrom(rel), nil
+}
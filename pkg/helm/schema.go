@@ -0,0 +1,218 @@
+package helm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	jsonschema "github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+const schemaResource = "file:///values.schema.json"
+
+var errValuesSchema = errors.New("values do not match chart schema")
+
+// ErrValuesSchema exposes the sentinel matched by SchemaError.
+func ErrValuesSchema() error { return errValuesSchema }
+
+// SchemaViolation describes a single values.schema.json failure. Messages are built from
+// the schema constraint only and never include the offending value, so secrets from
+// encrypted values layers are not echoed.
+type SchemaViolation struct {
+	Chart   string `json:"chart"`
+	Pointer string `json:"pointer"`
+	Keyword string `json:"keyword"`
+	Message string `json:"message"`
+}
+
+// Location renders the JSON pointer, using "(root)" for the document itself.
+func (v SchemaViolation) Location() string {
+	if v.Pointer == "" {
+		return "(root)"
+	}
+	return v.Pointer
+}
+
+// SchemaError lists every schema violation found in the merged values.
+type SchemaError struct {
+	Violations []SchemaViolation
+}
+
+func (e *SchemaError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, fmt.Sprintf("%s %s: %s", v.Chart, v.Location(), v.Message))
+	}
+	return fmt.Sprintf("%s: %s", errValuesSchema, strings.Join(parts, "; "))
+}
+
+// Is lets errors.Is match ErrValuesSchema.
+func (e *SchemaError) Is(target error) bool { return target == errValuesSchema }
+
+// ValidateValuesSchema coalesces vals with the chart defaults and validates the result against
+// the values.schema.json of the chart and each of its subcharts. Charts without a schema pass.
+func ValidateValuesSchema(chrt *chart.Chart, vals map[string]interface{}) error {
+	coalesced, err := chartutil.CoalesceValues(chrt, vals)
+	if err != nil {
+		return fmt.Errorf("coalesce values for chart %s: %w", chrt.Name(), err)
+	}
+	violations, err := collectSchemaViolations(chrt, coalesced.AsMap(), "")
+	if err != nil {
+		return err
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return &SchemaError{Violations: violations}
+}
+
+func collectSchemaViolations(chrt *chart.Chart, vals map[string]interface{}, prefix string) ([]SchemaViolation, error) {
+	var violations []SchemaViolation
+	if len(chrt.Schema) > 0 {
+		found, err := validateAgainstSchema(chrt, vals, prefix)
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, found...)
+	}
+	for _, sub := range chrt.Dependencies() {
+		subVals, ok := vals[sub.Name()].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		found, err := collectSchemaViolations(sub, subVals, prefix+"/"+escapePointerToken(sub.Name()))
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, found...)
+	}
+	return violations, nil
+}
+
+func validateAgainstSchema(chrt *chart.Chart, vals map[string]interface{}, prefix string) ([]SchemaViolation, error) {
+	schemaDoc, err := jsonschema.UnmarshalJSON(bytes.NewReader(chrt.Schema))
+	if err != nil {
+		return nil, fmt.Errorf("parse values schema for chart %s: %w", chrt.Name(), err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaResource, schemaDoc); err != nil {
+		return nil, fmt.Errorf("load values schema for chart %s: %w", chrt.Name(), err)
+	}
+	schema, err := compiler.Compile(schemaResource)
+	if err != nil {
+		return nil, fmt.Errorf("compile values schema for chart %s: %w", chrt.Name(), err)
+	}
+
+	// Round-trip through JSON so numbers and nested maps use the types the validator expects.
+	raw, err := json.Marshal(vals)
+	if err != nil {
+		return nil, fmt.Errorf("encode values for chart %s: %w", chrt.Name(), err)
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("decode values for chart %s: %w", chrt.Name(), err)
+	}
+
+	err = schema.Validate(instance)
+	if err == nil {
+		return nil, nil
+	}
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil, fmt.Errorf("validate values for chart %s: %w", chrt.Name(), err)
+	}
+
+	var violations []SchemaViolation
+	for _, leaf := range leafErrors(validationErr) {
+		violations = append(violations, SchemaViolation{
+			Chart:   chrt.Name(),
+			Pointer: prefix + jsonPointer(leaf.InstanceLocation),
+			Keyword: strings.Join(leaf.ErrorKind.KeywordPath(), "/"),
+			Message: describeViolation(leaf.ErrorKind),
+		})
+	}
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Pointer < violations[j].Pointer })
+	return violations, nil
+}
+
+func leafErrors(err *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(err.Causes) == 0 {
+		return []*jsonschema.ValidationError{err}
+	}
+	var leaves []*jsonschema.ValidationError
+	for _, cause := range err.Causes {
+		leaves = append(leaves, leafErrors(cause)...)
+	}
+	return leaves
+}
+
+var schemaPrinter = message.NewPrinter(language.English)
+
+// describeViolation renders the failed constraint. Kinds whose library message embeds the
+// instance value are rewritten to mention only the schema side of the constraint.
+func describeViolation(k jsonschema.ErrorKind) string {
+	switch v := k.(type) {
+	case *kind.Enum:
+		return "value is not one of the allowed values"
+	case *kind.Const:
+		return "value does not match the required constant"
+	case *kind.Format:
+		return fmt.Sprintf("value is not a valid %s", v.Want)
+	case *kind.Pattern:
+		return fmt.Sprintf("value does not match pattern %q", v.Want)
+	case *kind.MinLength:
+		return fmt.Sprintf("length must be at least %d", v.Want)
+	case *kind.MaxLength:
+		return fmt.Sprintf("length must be at most %d", v.Want)
+	case *kind.Minimum:
+		return fmt.Sprintf("must be >= %s", formatRat(v.Want))
+	case *kind.Maximum:
+		return fmt.Sprintf("must be <= %s", formatRat(v.Want))
+	case *kind.ExclusiveMinimum:
+		return fmt.Sprintf("must be > %s", formatRat(v.Want))
+	case *kind.ExclusiveMaximum:
+		return fmt.Sprintf("must be < %s", formatRat(v.Want))
+	case *kind.MultipleOf:
+		return fmt.Sprintf("must be a multiple of %s", formatRat(v.Want))
+	case *kind.PropertyNames:
+		return "property name does not satisfy propertyNames"
+	case *kind.ContentEncoding:
+		return fmt.Sprintf("value is not valid %s", v.Want)
+	case *kind.ContentMediaType:
+		return fmt.Sprintf("value is not valid %s", v.Want)
+	case *kind.Type, *kind.Required, *kind.AdditionalProperties, *kind.Dependency, *kind.DependentRequired,
+		*kind.MinProperties, *kind.MaxProperties, *kind.MinItems, *kind.MaxItems, *kind.AdditionalItems,
+		*kind.UniqueItems, *kind.FalseSchema, *kind.Not, *kind.OneOf, *kind.AnyOf, *kind.AllOf, *kind.Contains:
+		return k.LocalizedString(schemaPrinter)
+	default:
+		return fmt.Sprintf("value does not satisfy %s", strings.Join(k.KeywordPath(), "/"))
+	}
+}
+
+func formatRat(r *big.Rat) string {
+	f, _ := r.Float64()
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func jsonPointer(tokens []string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteString("/")
+		b.WriteString(escapePointerToken(token))
+	}
+	return b.String()
+}
+
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,105 @@
+package helm_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+
+	"github.com/dobrovols/chainctl/pkg/helm"
+)
+
+const testValuesSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["image"],
+  "properties": {
+    "replicaCount": {"type": "integer", "minimum": 1},
+    "image": {
+      "type": "object",
+      "required": ["repository"],
+      "properties": {
+        "repository": {"type": "string"},
+        "tag": {"type": "string", "pattern": "^[0-9.]+$"}
+      }
+    },
+    "db": {
+      "type": "object",
+      "properties": {"password": {"type": "string", "minLength": 12}}
+    }
+  }
+}`
+
+func schemaChart(defaults map[string]interface{}) *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "demo", Version: "0.1.0"},
+		Values:   defaults,
+		Schema:   []byte(testValuesSchema),
+	}
+}
+
+func TestValidateValuesSchemaAcceptsValidValues(t *testing.T) {
+	chrt := schemaChart(map[string]interface{}{"image": map[string]interface{}{"repository": "chain/app"}})
+	if err := helm.ValidateValuesSchema(chrt, map[string]interface{}{"replicaCount": 2}); err != nil {
+		t.Fatalf("expected values to pass schema, got %v", err)
+	}
+}
+
+func TestValidateValuesSchemaReportsPointersWithoutValues(t *testing.T) {
+	chrt := schemaChart(nil)
+	vals := map[string]interface{}{
+		"replicaCount": 0,
+		"image":        map[string]interface{}{"tag": "latest-hunter2"},
+		"db":           map[string]interface{}{"password": "hunter2"},
+	}
+
+	err := helm.ValidateValuesSchema(chrt, vals)
+	if !errors.Is(err, helm.ErrValuesSchema()) {
+		t.Fatalf("expected schema error, got %v", err)
+	}
+	var schemaErr *helm.SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected *SchemaError, got %T", err)
+	}
+
+	pointers := map[string]string{}
+	for _, v := range schemaErr.Violations {
+		pointers[v.Pointer] = v.Keyword
+	}
+	for pointer, keyword := range map[string]string{
+		"/replicaCount": "minimum",
+		"/image":        "required",
+		"/image/tag":    "pattern",
+		"/db/password":  "minLength",
+	} {
+		if pointers[pointer] != keyword {
+			t.Fatalf("expected %s violation at %s, got %v", keyword, pointer, schemaErr.Violations)
+		}
+	}
+	if strings.Contains(err.Error(), "hunter2") {
+		t.Fatalf("schema error echoed a value: %s", err)
+	}
+}
+
+func TestValidateValuesSchemaChecksSubcharts(t *testing.T) {
+	parent := &chart.Chart{Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "umbrella", Version: "1.0.0"}}
+	sub := schemaChart(map[string]interface{}{"image": map[string]interface{}{"repository": "chain/app"}})
+	parent.AddDependency(sub)
+
+	err := helm.ValidateValuesSchema(parent, map[string]interface{}{"demo": map[string]interface{}{"replicaCount": "three"}})
+	var schemaErr *helm.SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected subchart schema error, got %v", err)
+	}
+	if got := schemaErr.Violations[0]; got.Chart != "demo" || got.Pointer != "/demo/replicaCount" || got.Keyword != "type" {
+		t.Fatalf("unexpected violation %+v", got)
+	}
+}
+
+func TestValidateValuesSchemaSkipsChartsWithoutSchema(t *testing.T) {
+	chrt := &chart.Chart{Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "plain", Version: "0.1.0"}}
+	if err := helm.ValidateValuesSchema(chrt, map[string]interface{}{"anything": true}); err != nil {
+		t.Fatalf("expected charts without schema to pass, got %v", err)
+	}
+}
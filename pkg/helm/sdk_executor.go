@@ -0,0 +1,403 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"helm.sh/helm/v3/pkg/strvals"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/dobrovols/chainctl/internal/config"
+	"github.com/dobrovols/chainctl/pkg/bundle"
+	"github.com/dobrovols/chainctl/pkg/secrets"
+)
+
+const (
+	// AnnotationChartDigest records the resolved chart digest on the deployed chart metadata.
+	AnnotationChartDigest = "chainctl.io/chart-digest"
+
+	defaultSDKTimeout = 5 * time.Minute
+)
+
+var (
+	errChartUnavailable = errors.New("helm chart unavailable: no chart path resolved and bundle contains no charts")
+	errReleaseNameEmpty = errors.New("helm release name must not be empty")
+)
+
+// ErrChartUnavailable exposes the missing chart sentinel.
+func ErrChartUnavailable() error { return errChartUnavailable }
+
+// ActionConfigFactory builds Helm action configurations scoped to a namespace.
+type ActionConfigFactory func(namespace string) (*action.Configuration, error)
+
+// SDKExecutor applies releases through the Helm SDK with install-or-upgrade semantics.
+type SDKExecutor struct {
+	configFactory ActionConfigFactory
+	decrypt       func(secrets.DecryptOptions) ([]byte, error)
+	loadChart     func(string) (*chart.Chart, error)
+	clientset     func(*action.Configuration) (kubernetes.Interface, error)
+	timeout       time.Duration
+}
+
+// SDKOption configures an SDKExecutor.
+type SDKOption func(*SDKExecutor)
+
+// WithActionConfigFactory overrides how Helm action configurations are created (tests).
+func WithActionConfigFactory(factory ActionConfigFactory) SDKOption {
+	return func(e *SDKExecutor) {
+		if factory != nil {
+			e.configFactory = factory
+		}
+	}
+}
+
+// WithDecrypter overrides the values decrypter (tests).
+func WithDecrypter(decrypt func(secrets.DecryptOptions) ([]byte, error)) SDKOption {
+	return func(e *SDKExecutor) {
+		if decrypt != nil {
+			e.decrypt = decrypt
+		}
+	}
+}
+
+// WithTimeout bounds how long a single Helm operation may run.
+func WithTimeout(timeout time.Duration) SDKOption {
+	return func(e *SDKExecutor) {
+		if timeout > 0 {
+			e.timeout = timeout
+		}
+	}
+}
+
+// NewSDKExecutor constructs an executor backed by the Helm action package.
+func NewSDKExecutor(opts ...SDKOption) *SDKExecutor {
+	exec := &SDKExecutor{
+		configFactory: NewActionConfigFactory(),
+		decrypt:       secrets.DecryptFile,
+		loadChart:     loader.Load,
+		clientset:     (*action.Configuration).KubernetesClientSet,
+		timeout:       defaultSDKTimeout,
+	}
+	for _, opt := range opts {
+		opt(exec)
+	}
+	return exec
+}
+
+// NewActionConfigFactory returns a factory that initialises Helm against the active kubeconfig.
+// The storage driver honours HELM_DRIVER, defaulting to Kubernetes secrets.
+func NewActionConfigFactory() ActionConfigFactory {
+	return func(namespace string) (*action.Configuration, error) {
+		settings := cli.New()
+		if strings.TrimSpace(namespace) != "" {
+			settings.SetNamespace(namespace)
+		}
+		cfg := new(action.Configuration)
+		if err := cfg.Init(settings.RESTClientGetter(), settings.Namespace(), os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
+			return nil, fmt.Errorf("initialise helm configuration: %w", err)
+		}
+		return cfg, nil
+	}
+}
+
+// UpgradeRelease satisfies Executor by applying the release and discarding its metadata.
+func (e *SDKExecutor) UpgradeRelease(profile *config.Profile, b *bundle.Bundle) error {
+	_, err := e.ApplyRelease(profile, b)
+	return err
+}
+
+// ApplyRelease installs the release when absent, otherwise upgrades it in place.
+func (e *SDKExecutor) ApplyRelease(profile *config.Profile, b *bundle.Bundle) (ReleaseInfo, error) {
+	return e.runRelease(profile, b, false)
+}
+
+// RenderManifest renders the manifest an apply would produce without changing the cluster.
+func (e *SDKExecutor) RenderManifest(profile *config.Profile, b *bundle.Bundle) (string, error) {
+	info, err := e.runRelease(profile, b, true)
+	if err != nil {
+		return "", err
+	}
+	return info.Manifest, nil
+}
+
+// ValidateValues loads the resolved chart and merged values and validates them against the
+// chart's values.schema.json, returning a *SchemaError listing every violation.
+func (e *SDKExecutor) ValidateValues(profile *config.Profile, b *bundle.Bundle) error {
+	if profile == nil {
+		return errors.New("helm profile must not be nil")
+	}
+	chartPath, err := LocateChartPath(profile, b)
+	if err != nil {
+		return err
+	}
+	chrt, err := e.loadChart(chartPath)
+	if err != nil {
+		return fmt.Errorf("load chart %s: %w", chartPath, err)
+	}
+	vals, err := e.loadValues(profile)
+	if err != nil {
+		return err
+	}
+	return ValidateValuesSchema(chrt, vals)
+}
+
+// ReleaseManifest returns the manifest stored with the deployed release, or an empty string when
+// the release does not exist. It reflects what Helm applied, not the current cluster objects.
+func (e *SDKExecutor) ReleaseManifest(release, namespace string) (string, error) {
+	if strings.TrimSpace(release) == "" {
+		return "", errReleaseNameEmpty
+	}
+	cfg, err := e.configFactory(namespace)
+	if err != nil {
+		return "", err
+	}
+	rel, err := action.NewGet(cfg).Run(release)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("get release %s: %w", release, err)
+	}
+	return rel.Manifest, nil
+}
+
+// ReleaseStatus reports the latest revision of the deployed release.
+func (e *SDKExecutor) ReleaseStatus(release, namespace string) (ReleaseInfo, error) {
+	if strings.TrimSpace(release) == "" {
+		return ReleaseInfo{}, errReleaseNameEmpty
+	}
+	cfg, err := e.configFactory(namespace)
+	if err != nil {
+		return ReleaseInfo{}, err
+	}
+	rel, err := action.NewStatus(cfg).Run(release)
+	if err != nil {
+		return ReleaseInfo{}, fmt.Errorf("release status %s: %w", release, err)
+	}
+	return releaseInfoFrom(rel), nil
+}
+
+func (e *SDKExecutor) runRelease(profile *config.Profile, b *bundle.Bundle, dryRun bool) (ReleaseInfo, error) {
+	if profile == nil {
+		return ReleaseInfo{}, errors.New("helm profile must not be nil")
+	}
+	if strings.TrimSpace(profile.HelmRelease) == "" {
+		return ReleaseInfo{}, errReleaseNameEmpty
+	}
+
+	chartPath, err := LocateChartPath(profile, b)
+	if err != nil {
+		return ReleaseInfo{}, err
+	}
+	chrt, err := e.loadChart(chartPath)
+	if err != nil {
+		return ReleaseInfo{}, fmt.Errorf("load chart %s: %w", chartPath, err)
+	}
+	annotateChart(chrt, profile.ChartDigest)
+
+	vals, err := e.loadValues(profile)
+	if err != nil {
+		return ReleaseInfo{}, err
+	}
+
+	cfg, err := e.configFactory(profile.HelmNamespace)
+	if err != nil {
+		return ReleaseInfo{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	installed, err := releaseInstalled(cfg, profile.HelmRelease)
+	if err != nil {
+		return ReleaseInfo{}, err
+	}
+
+	var rel *release.Release
+	if installed {
+		upgrade := action.NewUpgrade(cfg)
+		upgrade.Namespace = profile.HelmNamespace
+		upgrade.Timeout = e.timeout
+		upgrade.DryRun = dryRun
+		rel, err = upgrade.RunWithContext(ctx, profile.HelmRelease, chrt, vals)
+		if err != nil {
+			return releaseInfoFrom(rel), fmt.Errorf("helm upgrade %s: %w", profile.HelmRelease, err)
+		}
+	} else {
+		install := action.NewInstall(cfg)
+		install.ReleaseName = profile.HelmRelease
+		install.Namespace = profile.HelmNamespace
+		install.CreateNamespace = true
+		// Reuse the name of a release uninstalled with --keep-history, continuing its revisions.
+		install.Replace = true
+		install.Timeout = e.timeout
+		install.DryRun = dryRun
+		rel, err = install.RunWithContext(ctx, chrt, vals)
+		if err != nil {
+			return releaseInfoFrom(rel), fmt.Errorf("helm install %s: %w", profile.HelmRelease, err)
+		}
+	}
+
+	return releaseInfoFrom(rel), nil
+}
+
+// LocateChartPath returns the chart archive or directory to apply. A resolved chart path
+// takes precedence; otherwise the bundle chart matching the release name (or the first chart) is used.
+func LocateChartPath(profile *config.Profile, b *bundle.Bundle) (string, error) {
+	if path := strings.TrimSpace(profile.ChartPath); path != "" {
+		return path, nil
+	}
+	if b == nil || len(b.Manifest.Charts) == 0 {
+		return "", errChartUnavailable
+	}
+	selected := b.Manifest.Charts[0]
+	for _, candidate := range b.Manifest.Charts {
+		if candidate.Name == profile.HelmRelease {
+			selected = candidate
+			break
+		}
+	}
+	if strings.TrimSpace(selected.Path) == "" {
+		return "", errChartUnavailable
+	}
+	return b.AssetPath(selected.Path), nil
+}
+
+// loadValues merges the profile's values layers in order: each values file (decrypted in
+// memory when it carries the encryption envelope), then --set and --set-string overrides.
+// Later layers win.
+func (e *SDKExecutor) loadValues(profile *config.Profile) (map[string]interface{}, error) {
+	// A profile carrying only EncryptedFile predates layering and is always encrypted.
+	legacy := len(profile.ValuesFiles) == 0
+	vals := map[string]interface{}{}
+	for _, path := range profile.ValuesLayers() {
+		layer, err := e.readValuesLayer(path, profile.Passphrase, legacy)
+		if err != nil {
+			return nil, err
+		}
+		vals = mergeValues(vals, layer)
+	}
+	for _, expr := range profile.SetValues {
+		if err := strvals.ParseInto(expr, vals); err != nil {
+			return nil, fmt.Errorf("parse --set override: %w", err)
+		}
+	}
+	for _, expr := range profile.SetStringValues {
+		if err := strvals.ParseIntoString(expr, vals); err != nil {
+			return nil, fmt.Errorf("parse --set-string override: %w", err)
+		}
+	}
+	return vals, nil
+}
+
+func (e *SDKExecutor) readValuesLayer(path, passphrase string, encrypted bool) (map[string]interface{}, error) {
+	if !encrypted {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read values file %s: %w", path, err)
+		}
+		if !secrets.IsEnvelope(raw) {
+			vals, err := chartutil.ReadValues(raw)
+			if err != nil {
+				return nil, fmt.Errorf("parse values file %s: %w", path, err)
+			}
+			return vals.AsMap(), nil
+		}
+	}
+
+	plaintext, err := e.decrypt(secrets.DecryptOptions{
+		InputPath:  path,
+		Passphrase: passphrase,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer zero(plaintext)
+
+	vals, err := chartutil.ReadValues(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("parse decrypted values: %w", err)
+	}
+	return vals.AsMap(), nil
+}
+
+// mergeValues deep-merges override into base, matching Helm's handling of repeated --values.
+func mergeValues(base, override map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range override {
+		if nested, ok := v.(map[string]interface{}); ok {
+			if existing, ok := out[k].(map[string]interface{}); ok {
+				out[k] = mergeValues(existing, nested)
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// releaseInstalled reports whether the release has a revision to upgrade. Releases uninstalled
+// with --keep-history still have history but must be installed again.
+func releaseInstalled(cfg *action.Configuration, name string) (bool, error) {
+	last, err := cfg.Releases.Last(name)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("query release history: %w", err)
+	}
+	return last.Info == nil || last.Info.Status != release.StatusUninstalled, nil
+}
+
+func annotateChart(chrt *chart.Chart, digest string) {
+	if chrt == nil || chrt.Metadata == nil || strings.TrimSpace(digest) == "" {
+		return
+	}
+	if chrt.Metadata.Annotations == nil {
+		chrt.Metadata.Annotations = map[string]string{}
+	}
+	chrt.Metadata.Annotations[AnnotationChartDigest] = digest
+}
+
+func releaseInfoFrom(rel *release.Release) ReleaseInfo {
+	if rel == nil {
+		return ReleaseInfo{}
+	}
+	info := ReleaseInfo{
+		Name:       rel.Name,
+		Namespace:  rel.Namespace,
+		Revision:   rel.Version,
+		ValuesHash: HashValues(rel.Config),
+		Manifest:   rel.Manifest,
+	}
+	if rel.Info != nil {
+		info.Status = rel.Info.Status.String()
+		info.Notes = rel.Info.Notes
+	}
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		info.ChartName = rel.Chart.Metadata.Name
+		info.ChartVersion = rel.Chart.Metadata.Version
+		info.ChartDigest = rel.Chart.Metadata.Annotations[AnnotationChartDigest]
+	}
+	return info
+}
+
+func zero(buf []byte) {
+	for i := range buf {
+		buf[i] = 0
+	}
+}
@@ -0,0 +1,353 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/dobrovols/chainctl/internal/config"
+	"github.com/dobrovols/chainctl/pkg/bundle"
+	"github.com/dobrovols/chainctl/pkg/secrets"
+)
+
+const testChartNotes = "Thank you for installing {{ .Chart.Name }}."
+
+func memoryActionConfig() *action.Configuration {
+	return &action.Configuration{
+		Releases:     storage.Init(driver.NewMemory()),
+		KubeClient:   &kubefake.PrintingKubeClient{Out: io.Discard},
+		Capabilities: chartutil.DefaultCapabilities,
+		Log:          func(string, ...interface{}) {},
+	}
+}
+
+func staticConfigFactory(cfg *action.Configuration) ActionConfigFactory {
+	return func(string) (*action.Configuration, error) { return cfg, nil }
+}
+
+func writeTestChart(t *testing.T, version string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "demo")
+	if err := os.MkdirAll(filepath.Join(dir, "templates"), 0o755); err != nil {
+		t.Fatalf("mkdir chart: %v", err)
+	}
+	files := map[string]string{
+		"Chart.yaml":            "apiVersion: v2\nname: demo\nversion: " + version + "\n",
+		"values.yaml":           "replicaCount: 1\n",
+		"templates/config.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}-config\ndata:\n  replicas: \"{{ .Values.replicaCount }}\"\n",
+		"templates/NOTES.txt":   testChartNotes,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func plainDecrypter(content string) func(secrets.DecryptOptions) ([]byte, error) {
+	return func(secrets.DecryptOptions) ([]byte, error) { return []byte(content), nil }
+}
+
+func TestSDKExecutorInstallsThenUpgrades(t *testing.T) {
+	cfg := memoryActionConfig()
+	exec := NewSDKExecutor(WithActionConfigFactory(staticConfigFactory(cfg)), WithDecrypter(plainDecrypter("replicaCount: 3\n")))
+	profile := &config.Profile{
+		HelmRelease:   "demo",
+		HelmNamespace: "apps",
+		EncryptedFile: "/tmp/values.enc",
+		Passphrase:    "secret",
+		ChartPath:     writeTestChart(t, "0.1.0"),
+		ChartDigest:   "sha256:abc",
+	}
+
+	first, err := exec.ApplyRelease(profile, nil)
+	if err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if first.Revision != 1 || first.Status != "deployed" {
+		t.Fatalf("expected revision 1 deployed, got %+v", first)
+	}
+	if first.Notes != "Thank you for installing demo." {
+		t.Fatalf("unexpected notes %q", first.Notes)
+	}
+
+	profile.ChartPath = writeTestChart(t, "0.2.0")
+	second, err := exec.ApplyRelease(profile, nil)
+	if err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	if second.Revision != 2 || second.ChartVersion != "0.2.0" {
+		t.Fatalf("expected revision 2 of chart 0.2.0, got %+v", second)
+	}
+
+	rel, err := cfg.Releases.Last("demo")
+	if err != nil {
+		t.Fatalf("last release: %v", err)
+	}
+	if rel.Config["replicaCount"] != float64(3) {
+		t.Fatalf("expected decrypted values to be applied, got %v", rel.Config)
+	}
+	if rel.Chart.Metadata.Annotations[AnnotationChartDigest] != "sha256:abc" {
+		t.Fatalf("expected digest annotation, got %v", rel.Chart.Metadata.Annotations)
+	}
+
+	status, err := exec.ReleaseStatus("demo", "apps")
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if status.Revision != 2 || status.ChartDigest != "sha256:abc" || status.Manifest == "" {
+		t.Fatalf("expected status of revision 2 with digest, got %+v", status)
+	}
+}
+
+func TestSDKExecutorUsesBundleChart(t *testing.T) {
+	chartDir := writeTestChart(t, "1.0.0")
+	b := &bundle.Bundle{
+		Extracted: filepath.Dir(chartDir),
+		Manifest: bundle.Manifest{Charts: []bundle.ChartRecord{
+			{Name: "other", Path: "missing"},
+			{Name: "demo", Path: filepath.Base(chartDir)},
+		}},
+	}
+	exec := NewSDKExecutor(WithActionConfigFactory(staticConfigFactory(memoryActionConfig())), WithDecrypter(plainDecrypter("{}")))
+
+	info, err := exec.ApplyRelease(&config.Profile{HelmRelease: "demo", HelmNamespace: "apps", EncryptedFile: "/tmp/values.enc"}, b)
+	if err != nil {
+		t.Fatalf("apply from bundle: %v", err)
+	}
+	if info.ChartVersion != "1.0.0" {
+		t.Fatalf("expected bundle chart version, got %q", info.ChartVersion)
+	}
+}
+
+func TestSDKExecutorRequiresChart(t *testing.T) {
+	exec := NewSDKExecutor(WithActionConfigFactory(staticConfigFactory(memoryActionConfig())))
+	_, err := exec.ApplyRelease(&config.Profile{HelmRelease: "demo"}, &bundle.Bundle{})
+	if !errors.Is(err, ErrChartUnavailable()) {
+		t.Fatalf("expected chart unavailable error, got %v", err)
+	}
+}
+
+func TestSDKExecutorPropagatesDecryptErrors(t *testing.T) {
+	wantErr := errors.New("bad passphrase")
+	exec := NewSDKExecutor(
+		WithActionConfigFactory(staticConfigFactory(memoryActionConfig())),
+		WithDecrypter(func(secrets.DecryptOptions) ([]byte, error) { return nil, wantErr }),
+	)
+	err := exec.UpgradeRelease(&config.Profile{HelmRelease: "demo", EncryptedFile: "/tmp/values.enc", ChartPath: writeTestChart(t, "0.1.0")}, nil)
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected decrypt error, got %v", err)
+	}
+}
+
+func TestInstallerApplyReportsRelease(t *testing.T) {
+	exec := NewSDKExecutor(WithActionConfigFactory(staticConfigFactory(memoryActionConfig())), WithDecrypter(plainDecrypter("{}")))
+	installer := NewInstaller(exec)
+
+	info, err := installer.Apply(&config.Profile{HelmRelease: "demo", HelmNamespace: "apps", EncryptedFile: "/tmp/values.enc", ChartPath: writeTestChart(t, "0.1.0")}, nil)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if info.Revision != 1 {
+		t.Fatalf("expected revision 1, got %d", info.Revision)
+	}
+
+	fallback, err := NewInstaller(&recordingExecutor{}).Apply(&config.Profile{}, nil)
+	if err != nil || fallback.Revision != 0 {
+		t.Fatalf("expected empty release info from plain executor, got %+v (%v)", fallback, err)
+	}
+}
+
+func TestSDKExecutorRollsBackToRevision(t *testing.T) {
+	cfg := memoryActionConfig()
+	exec := NewSDKExecutor(WithActionConfigFactory(staticConfigFactory(cfg)), WithDecrypter(plainDecrypter("{}")))
+	profile := &config.Profile{HelmRelease: "demo", HelmNamespace: "apps", EncryptedFile: "/tmp/values.enc", ChartPath: writeTestChart(t, "0.1.0")}
+	if _, err := exec.ApplyRelease(profile, nil); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	profile.ChartPath = writeTestChart(t, "0.2.0")
+	if _, err := exec.ApplyRelease(profile, nil); err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+
+	info, err := exec.Rollback(RollbackOptions{Release: "demo", Namespace: "apps", Revision: 1})
+	if err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if info.Revision != 3 || info.ChartVersion != "0.1.0" || info.Status != "deployed" {
+		t.Fatalf("expected revision 3 restoring chart 0.1.0, got %+v", info)
+	}
+}
+
+func TestSDKExecutorRollbackRequiresRelease(t *testing.T) {
+	exec := NewSDKExecutor(WithActionConfigFactory(staticConfigFactory(memoryActionConfig())))
+	if _, err := exec.Rollback(RollbackOptions{}); !errors.Is(err, errReleaseNameEmpty) {
+		t.Fatalf("expected release name error, got %v", err)
+	}
+}
+
+func TestSDKExecutorMergesValuesLayers(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	if err := os.WriteFile(base, []byte("replicaCount: 1\nimage:\n  repository: chain/app\n  tag: latest\n"), 0o600); err != nil {
+		t.Fatalf("write base values: %v", err)
+	}
+	plainSecrets := filepath.Join(dir, "secrets.yaml")
+	if err := os.WriteFile(plainSecrets, []byte("image:\n  tag: \"1.0\"\ndb:\n  password: hunter2\n"), 0o600); err != nil {
+		t.Fatalf("write secret values: %v", err)
+	}
+	encrypted := filepath.Join(dir, "secrets.enc")
+	if _, err := secrets.EncryptFile(secrets.EncryptOptions{InputPath: plainSecrets, OutputPath: encrypted, Passphrase: "secret"}); err != nil {
+		t.Fatalf("encrypt values: %v", err)
+	}
+
+	exec := NewSDKExecutor()
+	vals, err := exec.loadValues(&config.Profile{
+		EncryptedFile:   base,
+		ValuesFiles:     []string{base, encrypted},
+		SetValues:       []string{"replicaCount=4"},
+		SetStringValues: []string{"image.tag=007"},
+		Passphrase:      "secret",
+	})
+	if err != nil {
+		t.Fatalf("loadValues: %v", err)
+	}
+
+	image := vals["image"].(map[string]interface{})
+	if image["repository"] != "chain/app" {
+		t.Fatalf("expected base layer to survive merge, got %v", image)
+	}
+	if image["tag"] != "007" {
+		t.Fatalf("expected --set-string to win over files, got %v", image["tag"])
+	}
+	if vals["replicaCount"] != int64(4) {
+		t.Fatalf("expected --set to override base layer, got %#v", vals["replicaCount"])
+	}
+	if vals["db"].(map[string]interface{})["password"] != "hunter2" {
+		t.Fatalf("expected encrypted layer to be decrypted, got %v", vals["db"])
+	}
+
+	if _, err := exec.loadValues(&config.Profile{ValuesFiles: []string{encrypted}, Passphrase: "wrong"}); err == nil {
+		t.Fatalf("expected wrong passphrase to fail")
+	}
+}
+
+func TestSDKExecutorValidatesValuesAgainstChartSchema(t *testing.T) {
+	chartPath := writeTestChart(t, "0.1.0")
+	schema := `{"type":"object","properties":{"replicaCount":{"type":"integer","maximum":5}}}`
+	if err := os.WriteFile(filepath.Join(chartPath, "values.schema.json"), []byte(schema), 0o600); err != nil {
+		t.Fatalf("write schema: %v", err)
+	}
+	profile := &config.Profile{HelmRelease: "demo", HelmNamespace: "apps", EncryptedFile: "/tmp/values.enc", ChartPath: chartPath}
+
+	valid := NewSDKExecutor(WithActionConfigFactory(staticConfigFactory(memoryActionConfig())), WithDecrypter(plainDecrypter("replicaCount: 3\n")))
+	if err := valid.ValidateValues(profile, nil); err != nil {
+		t.Fatalf("expected values to pass schema, got %v", err)
+	}
+
+	invalid := NewSDKExecutor(WithActionConfigFactory(staticConfigFactory(memoryActionConfig())), WithDecrypter(plainDecrypter("replicaCount: 9\n")))
+	err := NewInstaller(invalid).ValidateValues(profile, nil)
+	if !errors.Is(err, ErrValuesSchema()) || !strings.Contains(err.Error(), "/replicaCount") {
+		t.Fatalf("expected schema violation at /replicaCount, got %v", err)
+	}
+}
+
+func TestSDKExecutorRendersWithoutApplying(t *testing.T) {
+	cfg := memoryActionConfig()
+	exec := NewSDKExecutor(WithActionConfigFactory(staticConfigFactory(cfg)), WithDecrypter(plainDecrypter("replicaCount: 2\n")))
+	profile := &config.Profile{HelmRelease: "demo", HelmNamespace: "apps", EncryptedFile: "/tmp/values.enc", ChartPath: writeTestChart(t, "0.1.0")}
+
+	stored, err := exec.ReleaseManifest("demo", "apps")
+	if err != nil || stored != "" {
+		t.Fatalf("expected empty release manifest before install, got %q (%v)", stored, err)
+	}
+
+	manifest, err := exec.RenderManifest(profile, nil)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(manifest, `replicas: "2"`) {
+		t.Fatalf("expected rendered values in manifest, got %s", manifest)
+	}
+	if _, err := cfg.Releases.Last("demo"); !errors.Is(err, driver.ErrReleaseNotFound) {
+		t.Fatalf("expected render to leave no release behind, got %v", err)
+	}
+}
+
+func TestSDKExecutorUninstallsAndDeletesPVCs(t *testing.T) {
+	cfg := memoryActionConfig()
+	clientset := k8sfake.NewSimpleClientset(
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-demo-0", Namespace: "apps", Labels: map[string]string{LabelInstance: "demo"}}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-other-0", Namespace: "apps", Labels: map[string]string{LabelInstance: "other"}}},
+	)
+	exec := NewSDKExecutor(
+		WithActionConfigFactory(staticConfigFactory(cfg)),
+		WithDecrypter(plainDecrypter("{}")),
+		WithClientsetFactory(func(*action.Configuration) (kubernetes.Interface, error) { return clientset, nil }),
+	)
+	if _, err := exec.ApplyRelease(&config.Profile{HelmRelease: "demo", HelmNamespace: "apps", EncryptedFile: "/tmp/values.enc", ChartPath: writeTestChart(t, "0.1.0")}, nil); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+
+	result, err := exec.Uninstall(UninstallOptions{Release: "demo", Namespace: "apps", KeepHistory: true, Wait: true, DeletePVCs: true})
+	if err != nil {
+		t.Fatalf("uninstall: %v", err)
+	}
+	if result.Release.Status != "uninstalled" || len(result.DeletedPVCs) != 1 || result.DeletedPVCs[0] != "data-demo-0" {
+		t.Fatalf("unexpected uninstall result %+v", result)
+	}
+	remaining, _ := clientset.CoreV1().PersistentVolumeClaims("apps").List(context.Background(), metav1.ListOptions{})
+	if len(remaining.Items) != 1 || remaining.Items[0].Name != "data-other-0" {
+		t.Fatalf("expected unrelated PVC to remain, got %+v", remaining.Items)
+	}
+	if _, err := cfg.Releases.History("demo"); err != nil {
+		t.Fatalf("expected history to be kept, got %v", err)
+	}
+
+	if _, err := exec.Uninstall(UninstallOptions{Release: "missing", Namespace: "apps"}); !errors.Is(err, ErrReleaseNotFound()) {
+		t.Fatalf("expected release not found, got %v", err)
+	}
+}
+
+func TestSDKExecutorReinstallsReleaseUninstalledWithKeptHistory(t *testing.T) {
+	cfg := memoryActionConfig()
+	exec := NewSDKExecutor(
+		WithActionConfigFactory(staticConfigFactory(cfg)),
+		WithDecrypter(plainDecrypter("{}")),
+		WithClientsetFactory(func(*action.Configuration) (kubernetes.Interface, error) { return k8sfake.NewSimpleClientset(), nil }),
+	)
+	profile := &config.Profile{HelmRelease: "demo", HelmNamespace: "apps", EncryptedFile: "/tmp/values.enc", ChartPath: writeTestChart(t, "0.1.0")}
+	if _, err := exec.ApplyRelease(profile, nil); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if _, err := exec.Uninstall(UninstallOptions{Release: "demo", Namespace: "apps", KeepHistory: true}); err != nil {
+		t.Fatalf("uninstall: %v", err)
+	}
+
+	reinstalled, err := exec.ApplyRelease(profile, nil)
+	if err != nil {
+		t.Fatalf("reinstall: %v", err)
+	}
+	if reinstalled.Revision != 2 || reinstalled.Status != "deployed" {
+		t.Fatalf("expected revision 2 deployed after reinstall, got %+v", reinstalled)
+	}
+	history, err := cfg.Releases.History("demo")
+	if err != nil || len(history) != 2 {
+		t.Fatalf("expected kept history plus the new revision, got %d (%v)", len(history), err)
+	}
+}
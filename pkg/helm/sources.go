@@ -0,0 +1,210 @@
+package helm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/ignore"
+
+	"github.com/dobrovols/chainctl/pkg/state"
+)
+
+// RepoChart identifies a chart in an index.yaml HTTP repository.
+type RepoChart struct {
+	RepoURL string
+	Name    string
+	// Version is an exact version or semver constraint; empty selects the latest release.
+	Version string
+}
+
+// RepoPuller downloads charts from index.yaml HTTP repositories.
+type RepoPuller interface {
+	PullRepoChart(ctx context.Context, chart RepoChart) (PullResult, error)
+}
+
+var (
+	errResolverInvalidRepo       = errors.New("invalid chart repository reference")
+	errResolverRepoPullerMissing = errors.New("chart repository puller not configured")
+	errResolverInvalidChartDir   = errors.New("not a chart directory")
+	errResolverRepoOffline       = errors.New("repository charts cannot be resolved offline")
+)
+
+// ErrResolverInvalidRepo exposes the invalid repository reference error.
+func ErrResolverInvalidRepo() error { return errResolverInvalidRepo }
+
+// ErrResolverInvalidChartDir exposes the invalid chart directory error.
+func ErrResolverInvalidChartDir() error { return errResolverInvalidChartDir }
+
+// ClassifyChartReference reports the source type of a --chart value: oci:// references are OCI
+// charts, http(s):// references are repository charts, and anything else is a local chart directory.
+func ClassifyChartReference(ref string) string {
+	lower := strings.ToLower(strings.TrimSpace(ref))
+	switch {
+	case strings.HasPrefix(lower, "oci://"):
+		return state.SourceTypeOCI
+	case strings.HasPrefix(lower, "http://"), strings.HasPrefix(lower, "https://"):
+		return state.SourceTypeRepo
+	default:
+		return state.SourceTypeDir
+	}
+}
+
+// ParseRepoReference splits <repo-url>/<chart>[:<version>] into the repository URL, chart name,
+// and optional version. Ports in the repository host are not mistaken for versions.
+func ParseRepoReference(ref string) (RepoChart, error) {
+	ref = strings.TrimSpace(ref)
+	if ClassifyChartReference(ref) != state.SourceTypeRepo {
+		return RepoChart{}, fmt.Errorf("%w: %s must start with http:// or https://", errResolverInvalidRepo, ref)
+	}
+	schemeEnd := strings.Index(ref, "://") + len("://")
+	slash := strings.LastIndex(ref, "/")
+	if slash <= schemeEnd || slash == len(ref)-1 {
+		return RepoChart{}, fmt.Errorf("%w: %s must name a chart after the repository URL", errResolverInvalidRepo, ref)
+	}
+	chart := RepoChart{RepoURL: ref[:slash], Name: ref[slash+1:]}
+	if name, version, found := strings.Cut(chart.Name, ":"); found {
+		chart.Name, chart.Version = name, version
+	}
+	if chart.Name == "" {
+		return RepoChart{}, fmt.Errorf("%w: %s", errResolverInvalidRepo, ref)
+	}
+	return chart, nil
+}
+
+func (r *Resolver) resolveRepo(ctx context.Context, opts ResolveOptions) (ResolveResult, error) {
+	chart, err := ParseRepoReference(opts.RepoReference)
+	if err != nil {
+		return ResolveResult{}, err
+	}
+	if opts.Offline {
+		return ResolveResult{}, fmt.Errorf("%w: %s", errResolverRepoOffline, opts.RepoReference)
+	}
+	if r.repoPuller == nil {
+		return ResolveResult{}, errResolverRepoPullerMissing
+	}
+
+	result, err := r.repoPuller.PullRepoChart(ctx, chart)
+	if err != nil {
+		return ResolveResult{}, err
+	}
+	return finishLocal(opts, state.ChartSource{
+		Type:      state.SourceTypeRepo,
+		Reference: opts.RepoReference,
+		Version:   result.Version,
+		Digest:    result.Digest,
+	}, result.ChartPath)
+}
+
+func (r *Resolver) resolveDir(opts ResolveOptions) (ResolveResult, error) {
+	dir := strings.TrimPrefix(strings.TrimSpace(opts.ChartDirectory), "file://")
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return ResolveResult{}, fmt.Errorf("%w: %s (expected oci://, http(s):// repository chart, or a chart directory)", errResolverInvalidChartDir, dir)
+	}
+	metadata, err := chartutil.LoadChartfile(filepath.Join(dir, chartutil.ChartfileName))
+	if err != nil {
+		return ResolveResult{}, fmt.Errorf("%w: %s: %v", errResolverInvalidChartDir, dir, err)
+	}
+	digest, err := DirectoryDigest(dir)
+	if err != nil {
+		return ResolveResult{}, err
+	}
+	return finishLocal(opts, state.ChartSource{
+		Type:      state.SourceTypeDir,
+		Reference: opts.ChartDirectory,
+		Version:   metadata.Version,
+		Digest:    digest,
+	}, dir)
+}
+
+// finishLocal applies digest pinning and signature verification to repository and directory charts.
+func finishLocal(opts ResolveOptions, source state.ChartSource, chartPath string) (ResolveResult, error) {
+	if err := verifyDigest(opts.ExpectedDigest, source.Digest); err != nil {
+		return ResolveResult{}, err
+	}
+	verification, err := verifyResolvedChart(chartPath, opts.Verify)
+	if err != nil {
+		return ResolveResult{}, err
+	}
+	return ResolveResult{
+		Source:       source,
+		ChartPath:    chartPath,
+		Verification: verification,
+	}, nil
+}
+
+// DirectoryDigest hashes the files Helm would package from a chart directory (honouring
+// .helmignore), so an unchanged checkout always yields the same sha256 digest.
+func DirectoryDigest(dir string) (string, error) {
+	rules, err := ignore.ParseFile(filepath.Join(dir, ignore.HelmIgnore))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("read %s: %w", ignore.HelmIgnore, err)
+		}
+		rules = ignore.Empty()
+	}
+	rules.AddDefaults()
+
+	var files []string
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if rules.Ignore(rel, info) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Mode().IsRegular() {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("hash chart directory %s: %w", dir, err)
+	}
+	sort.Strings(files)
+
+	tree := sha256.New()
+	for _, rel := range files {
+		sum, err := fileSHA256(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return "", fmt.Errorf("hash chart directory %s: %w", dir, err)
+		}
+		fmt.Fprintf(tree, "%s\x00%s\n", rel, sum)
+	}
+	return "sha256:" + hex.EncodeToString(tree.Sum(nil)), nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,179 @@
+package helm_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/dobrovols/chainctl/pkg/helm"
+	"github.com/dobrovols/chainctl/pkg/state"
+)
+
+func writeChartDir(t *testing.T, version string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "demo")
+	if err := os.MkdirAll(filepath.Join(dir, "templates"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	files := map[string]string{
+		"Chart.yaml":                   "apiVersion: v2\nname: demo\nversion: " + version + "\n",
+		"values.yaml":                  "replicas: 1\n",
+		"templates/configmap.yaml":     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: demo\n",
+		".helmignore":                  "*.bak\n",
+		"templates/configmap.yaml.bak": "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestClassifyChartReference(t *testing.T) {
+	cases := map[string]string{
+		"oci://registry.example.com/app:1.0.0":   state.SourceTypeOCI,
+		"https://charts.example.com/stable/app":  state.SourceTypeRepo,
+		"http://localhost:8879/charts/app:1.2.0": state.SourceTypeRepo,
+		"./charts/app":                           state.SourceTypeDir,
+		"file:///srv/charts/app":                 state.SourceTypeDir,
+	}
+	for ref, want := range cases {
+		if got := helm.ClassifyChartReference(ref); got != want {
+			t.Fatalf("%s: expected %s, got %s", ref, want, got)
+		}
+	}
+}
+
+func TestParseRepoReference(t *testing.T) {
+	chart, err := helm.ParseRepoReference("http://localhost:8879/charts/app:^1.2")
+	if err != nil {
+		t.Fatalf("ParseRepoReference: %v", err)
+	}
+	if chart.RepoURL != "http://localhost:8879/charts" || chart.Name != "app" || chart.Version != "^1.2" {
+		t.Fatalf("unexpected repository chart %+v", chart)
+	}
+	for _, invalid := range []string{"https://charts.example.com", "https://charts.example.com/", "oci://example.com/app"} {
+		if _, err := helm.ParseRepoReference(invalid); !errors.Is(err, helm.ErrResolverInvalidRepo()) {
+			t.Fatalf("%s: expected invalid repo error, got %v", invalid, err)
+		}
+	}
+}
+
+func TestResolverResolvesChartDirectory(t *testing.T) {
+	dir := writeChartDir(t, "0.3.0")
+	resolver := helm.NewResolver(&stubPuller{}, (&stubBundleLoader{}).Load)
+
+	result, err := resolver.Resolve(context.Background(), helm.ResolveOptions{ChartDirectory: dir})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if result.Source.Type != state.SourceTypeDir || result.Source.Version != "0.3.0" || result.ChartPath != dir {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	digest := result.Source.Digest
+
+	// Files excluded by .helmignore do not affect the digest; chart content does.
+	if err := os.WriteFile(filepath.Join(dir, "templates", "configmap.yaml.bak"), []byte("changed"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if again, err := helm.DirectoryDigest(dir); err != nil || again != digest {
+		t.Fatalf("expected stable digest %s, got %s (%v)", digest, again, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "values.yaml"), []byte("replicas: 2\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = resolver.Resolve(context.Background(), helm.ResolveOptions{ChartDirectory: dir, ExpectedDigest: digest})
+	if !errors.Is(err, helm.ErrResolverDigestMismatch()) {
+		t.Fatalf("expected digest mismatch after edit, got %v", err)
+	}
+
+	if _, err := resolver.Resolve(context.Background(), helm.ResolveOptions{ChartDirectory: t.TempDir()}); !errors.Is(err, helm.ErrResolverInvalidChartDir()) {
+		t.Fatalf("expected invalid chart directory error, got %v", err)
+	}
+}
+
+func TestResolverRejectsMultipleChartSources(t *testing.T) {
+	resolver := helm.NewResolver(&stubPuller{}, (&stubBundleLoader{}).Load)
+	_, err := resolver.Resolve(context.Background(), helm.ResolveOptions{
+		RepoReference:  "https://charts.example.com/app",
+		ChartDirectory: "./charts/app",
+	})
+	if !errors.Is(err, helm.ErrResolverConflictingSources()) {
+		t.Fatalf("expected conflicting sources error, got %v", err)
+	}
+}
+
+// serveRepository packages the given chart versions into an index.yaml repository.
+func serveRepository(t *testing.T, versions ...string) (*httptest.Server, map[string]string) {
+	t.Helper()
+	root := t.TempDir()
+	digests := map[string]string{}
+	index := repo.NewIndexFile()
+	for _, version := range versions {
+		chrt := &chart.Chart{Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "demo", Version: version}}
+		archive, err := chartutil.Save(chrt, root)
+		if err != nil {
+			t.Fatalf("package chart: %v", err)
+		}
+		data, err := os.ReadFile(archive)
+		if err != nil {
+			t.Fatalf("read archive: %v", err)
+		}
+		sum := sha256.Sum256(data)
+		digests[version] = hex.EncodeToString(sum[:])
+		if err := index.MustAdd(chrt.Metadata, filepath.Base(archive), "", digests[version]); err != nil {
+			t.Fatalf("index chart: %v", err)
+		}
+	}
+	if err := index.WriteFile(filepath.Join(root, "index.yaml"), 0o644); err != nil {
+		t.Fatalf("write index: %v", err)
+	}
+	server := httptest.NewServer(http.FileServer(http.Dir(root)))
+	t.Cleanup(server.Close)
+	return server, digests
+}
+
+func TestResolverResolvesRepositoryChart(t *testing.T) {
+	server, digests := serveRepository(t, "1.0.0", "1.1.0")
+	resolver := helm.NewResolver(&stubPuller{}, (&stubBundleLoader{}).Load, helm.WithRepoPuller(helm.NewRepoPuller(nil)))
+
+	result, err := resolver.Resolve(context.Background(), helm.ResolveOptions{RepoReference: server.URL + "/demo"})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if result.Source.Type != state.SourceTypeRepo || result.Source.Version != "1.1.0" {
+		t.Fatalf("expected latest repository chart, got %+v", result.Source)
+	}
+	if result.Source.Digest != "sha256:"+digests["1.1.0"] {
+		t.Fatalf("expected archive digest, got %s", result.Source.Digest)
+	}
+	if _, err := os.Stat(result.ChartPath); err != nil {
+		t.Fatalf("expected downloaded archive: %v", err)
+	}
+
+	pinned, err := resolver.Resolve(context.Background(), helm.ResolveOptions{
+		RepoReference:  server.URL + "/demo:1.0.0",
+		ExpectedDigest: digests["1.0.0"],
+	})
+	if err != nil || pinned.Source.Version != "1.0.0" {
+		t.Fatalf("expected pinned version, got %+v (%v)", pinned.Source, err)
+	}
+
+	if _, err := resolver.Resolve(context.Background(), helm.ResolveOptions{RepoReference: server.URL + "/demo:2.0.0"}); !errors.Is(err, helm.ErrRepoChartNotFound()) {
+		t.Fatalf("expected chart not found, got %v", err)
+	}
+	if _, err := resolver.Resolve(context.Background(), helm.ResolveOptions{RepoReference: server.URL + "/demo", Offline: true}); err == nil {
+		t.Fatal("expected offline resolution of repository chart to fail")
+	}
+}
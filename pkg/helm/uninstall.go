@@ -0,0 +1,138 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// LabelInstance is the standard label Helm charts use to tie resources to a release.
+	LabelInstance = "app.kubernetes.io/instance"
+
+	pvcPollInterval = 2 * time.Second
+)
+
+// ErrReleaseNotFound exposes the Helm storage sentinel for missing releases.
+func ErrReleaseNotFound() error { return driver.ErrReleaseNotFound }
+
+// UninstallOptions controls how a release and its leftovers are removed.
+type UninstallOptions struct {
+	Release     string
+	Namespace   string
+	KeepHistory bool
+	// Wait blocks until the release resources (and deleted PVCs) are gone.
+	Wait    bool
+	Timeout time.Duration
+	// DeletePVCs removes PersistentVolumeClaims labelled with the release instance, which
+	// Helm leaves behind for StatefulSet volume claim templates.
+	DeletePVCs bool
+}
+
+// UninstallResult reports what was removed.
+type UninstallResult struct {
+	Release     ReleaseInfo
+	DeletedPVCs []string
+}
+
+// Uninstaller removes Helm releases.
+type Uninstaller interface {
+	Uninstall(UninstallOptions) (UninstallResult, error)
+}
+
+// WithClientsetFactory overrides how Kubernetes clients are derived from Helm configurations (tests).
+func WithClientsetFactory(factory func(*action.Configuration) (kubernetes.Interface, error)) SDKOption {
+	return func(e *SDKExecutor) {
+		if factory != nil {
+			e.clientset = factory
+		}
+	}
+}
+
+// Uninstall removes the release through the Helm uninstall action and optionally its PVCs.
+func (e *SDKExecutor) Uninstall(opts UninstallOptions) (UninstallResult, error) {
+	if strings.TrimSpace(opts.Release) == "" {
+		return UninstallResult{}, errReleaseNameEmpty
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = e.timeout
+	}
+
+	cfg, err := e.configFactory(opts.Namespace)
+	if err != nil {
+		return UninstallResult{}, err
+	}
+
+	uninstall := action.NewUninstall(cfg)
+	uninstall.KeepHistory = opts.KeepHistory
+	uninstall.Wait = opts.Wait
+	uninstall.Timeout = timeout
+	uninstall.DeletionPropagation = "background"
+	if opts.Wait {
+		uninstall.DeletionPropagation = "foreground"
+	}
+
+	resp, err := uninstall.Run(opts.Release)
+	if err != nil {
+		return UninstallResult{}, fmt.Errorf("helm uninstall %s: %w", opts.Release, err)
+	}
+
+	result := UninstallResult{}
+	if resp != nil {
+		result.Release = releaseInfoFrom(resp.Release)
+	}
+	if !opts.DeletePVCs {
+		return result, nil
+	}
+
+	client, err := e.clientset(cfg)
+	if err != nil {
+		return result, fmt.Errorf("kubernetes client: %w", err)
+	}
+	result.DeletedPVCs, err = deleteReleasePVCs(client, opts, timeout)
+	return result, err
+}
+
+func deleteReleasePVCs(client kubernetes.Interface, opts UninstallOptions, timeout time.Duration) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	pvcs := client.CoreV1().PersistentVolumeClaims(opts.Namespace)
+	selector := metav1.ListOptions{LabelSelector: LabelInstance + "=" + opts.Release}
+	list, err := pvcs.List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("list persistent volume claims: %w", err)
+	}
+
+	deleted := make([]string, 0, len(list.Items))
+	for _, pvc := range list.Items {
+		if err := pvcs.Delete(ctx, pvc.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return deleted, fmt.Errorf("delete persistent volume claim %s: %w", pvc.Name, err)
+		}
+		deleted = append(deleted, pvc.Name)
+	}
+	if !opts.Wait || len(deleted) == 0 {
+		return deleted, nil
+	}
+
+	err = wait.PollUntilContextCancel(ctx, pvcPollInterval, true, func(ctx context.Context) (bool, error) {
+		remaining, err := pvcs.List(ctx, selector)
+		if err != nil {
+			return false, err
+		}
+		return len(remaining.Items) == 0, nil
+	})
+	if err != nil {
+		return deleted, fmt.Errorf("wait for persistent volume claim deletion: %w", err)
+	}
+	return deleted, nil
+}
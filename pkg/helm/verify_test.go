@@ -0,0 +1,255 @@
+package helm_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/openpgp" //nolint:staticcheck // Helm provenance files are produced with this package.
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/provenance"
+
+	"github.com/dobrovols/chainctl/pkg/bundle"
+	"github.com/dobrovols/chainctl/pkg/helm"
+)
+
+func packageTestChart(t *testing.T) string {
+	t.Helper()
+	chrt := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "demo", Version: "0.1.0"},
+	}
+	path, err := chartutil.Save(chrt, t.TempDir())
+	if err != nil {
+		t.Fatalf("package chart: %v", err)
+	}
+	return path
+}
+
+func writePublicKey(t *testing.T, name string, pub crypto.PublicKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write public key: %v", err)
+	}
+	return path
+}
+
+func writeSignature(t *testing.T, chartPath string, sig []byte) {
+	t.Helper()
+	if err := os.WriteFile(chartPath+".sig", []byte(base64.StdEncoding.EncodeToString(sig)), 0o600); err != nil {
+		t.Fatalf("write signature: %v", err)
+	}
+}
+
+func readChart(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read chart: %v", err)
+	}
+	return data
+}
+
+func newPGPEntity(t *testing.T, name string) (*openpgp.Entity, string) {
+	t.Helper()
+	entity, err := openpgp.NewEntity(name, "", name+"@example.com", nil)
+	if err != nil {
+		t.Fatalf("create pgp entity: %v", err)
+	}
+	keyring := filepath.Join(t.TempDir(), "pubring.gpg")
+	f, err := os.Create(keyring)
+	if err != nil {
+		t.Fatalf("create keyring: %v", err)
+	}
+	defer f.Close()
+	if err := entity.Serialize(f); err != nil {
+		t.Fatalf("write keyring: %v", err)
+	}
+	return entity, keyring
+}
+
+func TestVerifyChartAcceptsEd25519Signature(t *testing.T) {
+	chartPath := packageTestChart(t)
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	writeSignature(t, chartPath, ed25519.Sign(priv, readChart(t, chartPath)))
+
+	result, err := helm.VerifyChart(chartPath, helm.VerifyOptions{PublicKeys: []string{writePublicKey(t, "release-team.pub", pub)}})
+	if err != nil {
+		t.Fatalf("VerifyChart: %v", err)
+	}
+	if result.Method != helm.VerifyMethodSignature || result.Signer != "release-team" || result.KeyID == "" {
+		t.Fatalf("unexpected verification %+v", result)
+	}
+}
+
+func TestVerifyChartAcceptsECDSASignature(t *testing.T) {
+	chartPath := packageTestChart(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	digest := sha256.Sum256(readChart(t, chartPath))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	writeSignature(t, chartPath, sig)
+
+	other, _, _ := ed25519.GenerateKey(rand.Reader)
+	keys := []string{writePublicKey(t, "other.pub", other), writePublicKey(t, "cosign.pub", &key.PublicKey)}
+	result, err := helm.VerifyChart(chartPath, helm.VerifyOptions{PublicKeys: keys})
+	if err != nil {
+		t.Fatalf("VerifyChart: %v", err)
+	}
+	if result.Signer != "cosign" {
+		t.Fatalf("expected matching key to be reported, got %+v", result)
+	}
+}
+
+func TestVerifyChartRejectsUntrustedSignature(t *testing.T) {
+	chartPath := packageTestChart(t)
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	trusted, _, _ := ed25519.GenerateKey(rand.Reader)
+	writeSignature(t, chartPath, ed25519.Sign(priv, readChart(t, chartPath)))
+
+	_, err := helm.VerifyChart(chartPath, helm.VerifyOptions{PublicKeys: []string{writePublicKey(t, "trusted.pub", trusted)}})
+	if !errors.Is(err, helm.ErrChartUntrusted()) {
+		t.Fatalf("expected untrusted error, got %v", err)
+	}
+}
+
+func TestVerifyChartSkipsUnreadableKeys(t *testing.T) {
+	chartPath := packageTestChart(t)
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	writeSignature(t, chartPath, ed25519.Sign(priv, readChart(t, chartPath)))
+	broken := filepath.Join(t.TempDir(), "broken.pub")
+	if err := os.WriteFile(broken, []byte("not a key"), 0o600); err != nil {
+		t.Fatalf("write broken key: %v", err)
+	}
+
+	keys := []string{broken, filepath.Join(t.TempDir(), "absent.pub"), writePublicKey(t, "release.pub", pub)}
+	result, err := helm.VerifyChart(chartPath, helm.VerifyOptions{PublicKeys: keys})
+	if err != nil {
+		t.Fatalf("VerifyChart: %v", err)
+	}
+	if result.Signer != "release" {
+		t.Fatalf("expected the valid key to verify the chart, got %+v", result)
+	}
+
+	_, err = helm.VerifyChart(chartPath, helm.VerifyOptions{PublicKeys: keys[:2]})
+	if !errors.Is(err, helm.ErrChartUntrusted()) || !strings.Contains(err.Error(), "broken.pub") {
+		t.Fatalf("expected untrusted error naming the skipped key, got %v", err)
+	}
+}
+
+func TestVerifyChartRejectsUnsignedChart(t *testing.T) {
+	chartPath := packageTestChart(t)
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	_, err := helm.VerifyChart(chartPath, helm.VerifyOptions{PublicKeys: []string{writePublicKey(t, "key.pub", pub)}})
+	if !errors.Is(err, helm.ErrChartUnsigned()) {
+		t.Fatalf("expected unsigned error, got %v", err)
+	}
+}
+
+func TestVerifyChartAcceptsProvenance(t *testing.T) {
+	chartPath := packageTestChart(t)
+	entity, keyring := newPGPEntity(t, "Release Team")
+	signed, err := (&provenance.Signatory{Entity: entity}).ClearSign(chartPath)
+	if err != nil {
+		t.Fatalf("sign chart: %v", err)
+	}
+	if err := os.WriteFile(chartPath+".prov", []byte(signed), 0o600); err != nil {
+		t.Fatalf("write provenance: %v", err)
+	}
+
+	result, err := helm.VerifyChart(chartPath, helm.VerifyOptions{Keyring: keyring})
+	if err != nil {
+		t.Fatalf("VerifyChart: %v", err)
+	}
+	if result.Method != helm.VerifyMethodProvenance {
+		t.Fatalf("expected provenance method, got %s", result.Method)
+	}
+	if result.KeyID != entity.PrimaryKey.KeyIdString() {
+		t.Fatalf("expected key id %s, got %s", entity.PrimaryKey.KeyIdString(), result.KeyID)
+	}
+	if result.Signer != "Release Team <Release Team@example.com>" {
+		t.Fatalf("unexpected signer %q", result.Signer)
+	}
+
+	_, otherKeyring := newPGPEntity(t, "Someone Else")
+	if _, err := helm.VerifyChart(chartPath, helm.VerifyOptions{Keyring: otherKeyring}); !errors.Is(err, helm.ErrChartUntrusted()) {
+		t.Fatalf("expected untrusted error for foreign keyring, got %v", err)
+	}
+}
+
+func TestResolverVerifiesBundleChart(t *testing.T) {
+	chartPath := packageTestChart(t)
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	writeSignature(t, chartPath, ed25519.Sign(priv, readChart(t, chartPath)))
+
+	tb := &bundle.Bundle{Path: "/tmp/bundle.tar", Extracted: filepath.Dir(chartPath)}
+	tb.Manifest.Charts = []bundle.ChartRecord{{Name: "demo", Path: filepath.Base(chartPath)}}
+	loader := &stubBundleLoader{bundle: tb}
+	resolver := helm.NewResolver(&stubPuller{}, loader.Load)
+
+	result, err := resolver.Resolve(t.Context(), helm.ResolveOptions{
+		BundlePath:  "/tmp/bundle.tar",
+		ReleaseName: "demo",
+		Verify:      helm.VerifyOptions{PublicKeys: []string{writePublicKey(t, "bundle.pub", pub)}},
+	})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if result.Verification == nil || result.Verification.Method != helm.VerifyMethodSignature {
+		t.Fatalf("expected signature verification recorded, got %+v", result.Verification)
+	}
+}
+
+func TestResolverRejectsUnsignedOCIChartWhenVerifying(t *testing.T) {
+	chartPath := packageTestChart(t)
+	_, keyring := newPGPEntity(t, "release")
+	puller := &stubPuller{result: helm.PullResult{ChartPath: chartPath, Digest: "sha256:abc"}}
+	resolver := helm.NewResolver(puller, (&stubBundleLoader{}).Load)
+
+	_, err := resolver.Resolve(t.Context(), helm.ResolveOptions{
+		OCIReference: "oci://example.com/demo:0.1.0",
+		Verify:       helm.VerifyOptions{Keyring: keyring},
+	})
+	if !errors.Is(err, helm.ErrChartUnsigned()) {
+		t.Fatalf("expected unsigned error, got %v", err)
+	}
+}
+
+func TestResolverRejectsDetachedSignatureKeysForOCIChart(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	puller := &stubPuller{result: helm.PullResult{ChartPath: packageTestChart(t), Digest: "sha256:abc"}}
+	resolver := helm.NewResolver(puller, (&stubBundleLoader{}).Load)
+
+	_, err := resolver.Resolve(t.Context(), helm.ResolveOptions{
+		OCIReference: "oci://example.com/demo:0.1.0",
+		Verify:       helm.VerifyOptions{PublicKeys: []string{writePublicKey(t, "key.pub", pub)}},
+	})
+	if !errors.Is(err, helm.ErrResolverSignatureOCI()) || puller.called {
+		t.Fatalf("expected detached signatures to be rejected before pulling, got %v (pulled=%v)", err, puller.called)
+	}
+}
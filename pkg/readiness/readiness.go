@@ -0,0 +1,193 @@
+package readiness
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/dobrovols/chainctl/pkg/helm"
+)
+
+// Supported workload kinds.
+const (
+	KindDeployment  = "Deployment"
+	KindStatefulSet = "StatefulSet"
+	KindDaemonSet   = "DaemonSet"
+	KindJob         = "Job"
+)
+
+// Resource identifies a workload owned by a release.
+type Resource struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// Status reports the readiness of a single workload.
+type Status struct {
+	Resource
+	Ready   bool   `json:"ready"`
+	Message string `json:"message"`
+}
+
+// Checker evaluates workload readiness through the Kubernetes API.
+type Checker struct {
+	client kubernetes.Interface
+}
+
+// NewChecker constructs a readiness checker backed by the provided client.
+func NewChecker(client kubernetes.Interface) *Checker {
+	return &Checker{client: client}
+}
+
+// WorkloadsFromManifest extracts the supported workloads from a rendered Helm manifest.
+// Resources without an explicit namespace are attributed to defaultNamespace.
+func WorkloadsFromManifest(manifest, defaultNamespace string) ([]Resource, error) {
+	var resources []Resource
+	for _, doc := range helm.SplitManifest(manifest) {
+		var object struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+		}
+		if err := yaml.Unmarshal([]byte(doc), &object); err != nil {
+			return nil, fmt.Errorf("parse manifest: %w", err)
+		}
+		switch object.Kind {
+		case KindDeployment, KindStatefulSet, KindDaemonSet, KindJob:
+		default:
+			continue
+		}
+		namespace := object.Metadata.Namespace
+		if namespace == "" {
+			namespace = defaultNamespace
+		}
+		resources = append(resources, Resource{Kind: object.Kind, Namespace: namespace, Name: object.Metadata.Name})
+	}
+	return resources, nil
+}
+
+// Check evaluates every resource and returns their readiness in input order.
+func (c *Checker) Check(ctx context.Context, resources []Resource) ([]Status, error) {
+	if c == nil || c.client == nil {
+		return nil, fmt.Errorf("readiness checker not initialised")
+	}
+	statuses := make([]Status, 0, len(resources))
+	for _, res := range resources {
+		status, err := c.check(ctx, res)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// AllReady reports whether every status is ready.
+func AllReady(statuses []Status) bool {
+	for _, status := range statuses {
+		if !status.Ready {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Checker) check(ctx context.Context, res Resource) (Status, error) {
+	var (
+		ready   bool
+		message string
+		err     error
+	)
+	switch res.Kind {
+	case KindDeployment:
+		var obj *appsv1.Deployment
+		if obj, err = c.client.AppsV1().Deployments(res.Namespace).Get(ctx, res.Name, metav1.GetOptions{}); err == nil {
+			ready, message = deploymentReady(obj)
+		}
+	case KindStatefulSet:
+		var obj *appsv1.StatefulSet
+		if obj, err = c.client.AppsV1().StatefulSets(res.Namespace).Get(ctx, res.Name, metav1.GetOptions{}); err == nil {
+			ready, message = statefulSetReady(obj)
+		}
+	case KindDaemonSet:
+		var obj *appsv1.DaemonSet
+		if obj, err = c.client.AppsV1().DaemonSets(res.Namespace).Get(ctx, res.Name, metav1.GetOptions{}); err == nil {
+			ready, message = daemonSetReady(obj)
+		}
+	case KindJob:
+		var obj *batchv1.Job
+		if obj, err = c.client.BatchV1().Jobs(res.Namespace).Get(ctx, res.Name, metav1.GetOptions{}); err == nil {
+			ready, message = jobReady(obj)
+		}
+	default:
+		return Status{}, fmt.Errorf("unsupported workload kind %q", res.Kind)
+	}
+	if apierrors.IsNotFound(err) {
+		return Status{Resource: res, Message: "not found"}, nil
+	}
+	if err != nil {
+		return Status{}, fmt.Errorf("get %s %s/%s: %w", strings.ToLower(res.Kind), res.Namespace, res.Name, err)
+	}
+	return Status{Resource: res, Ready: ready, Message: message}, nil
+}
+
+func deploymentReady(obj *appsv1.Deployment) (bool, string) {
+	desired := replicasOrDefault(obj.Spec.Replicas)
+	message := fmt.Sprintf("%d/%d available", obj.Status.AvailableReplicas, desired)
+	if obj.Status.ObservedGeneration < obj.Generation {
+		return false, message + ", rollout pending"
+	}
+	return obj.Status.UpdatedReplicas >= desired && obj.Status.AvailableReplicas >= desired, message
+}
+
+func statefulSetReady(obj *appsv1.StatefulSet) (bool, string) {
+	desired := replicasOrDefault(obj.Spec.Replicas)
+	message := fmt.Sprintf("%d/%d ready", obj.Status.ReadyReplicas, desired)
+	if obj.Status.ObservedGeneration < obj.Generation {
+		return false, message + ", rollout pending"
+	}
+	return obj.Status.UpdatedReplicas >= desired && obj.Status.ReadyReplicas >= desired, message
+}
+
+func daemonSetReady(obj *appsv1.DaemonSet) (bool, string) {
+	desired := obj.Status.DesiredNumberScheduled
+	message := fmt.Sprintf("%d/%d ready", obj.Status.NumberReady, desired)
+	if obj.Status.ObservedGeneration < obj.Generation {
+		return false, message + ", rollout pending"
+	}
+	return obj.Status.UpdatedNumberScheduled >= desired && obj.Status.NumberReady >= desired, message
+}
+
+func jobReady(obj *batchv1.Job) (bool, string) {
+	for _, cond := range obj.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return true, "complete"
+		case batchv1.JobFailed:
+			return false, "failed: " + cond.Reason
+		}
+	}
+	completions := replicasOrDefault(obj.Spec.Completions)
+	return obj.Status.Succeeded >= completions, fmt.Sprintf("%d/%d succeeded", obj.Status.Succeeded, completions)
+}
+
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
@@ -0,0 +1,116 @@
+package readiness
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const releaseManifest = `---
+# Source: demo/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+# Source: demo/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+# Source: demo/templates/statefulset.yaml
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+  namespace: data
+---
+# Source: demo/templates/job.yaml
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+`
+
+func int32Ptr(v int32) *int32 { return &v }
+
+func TestWorkloadsFromManifestSelectsSupportedKinds(t *testing.T) {
+	resources, err := WorkloadsFromManifest(releaseManifest, "apps")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := []Resource{
+		{Kind: KindDeployment, Namespace: "apps", Name: "web"},
+		{Kind: KindStatefulSet, Namespace: "data", Name: "db"},
+		{Kind: KindJob, Namespace: "apps", Name: "migrate"},
+	}
+	if len(resources) != len(want) {
+		t.Fatalf("expected %d workloads, got %+v", len(want), resources)
+	}
+	for i := range want {
+		if resources[i] != want[i] {
+			t.Fatalf("workload %d: expected %+v, got %+v", i, want[i], resources[i])
+		}
+	}
+}
+
+func TestCheckerEvaluatesWorkloads(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps", Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+			Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "data"},
+			Spec:       appsv1.StatefulSetSpec{Replicas: int32Ptr(3)},
+			Status:     appsv1.StatefulSetStatus{UpdatedReplicas: 3, ReadyReplicas: 1},
+		},
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "apps"},
+			Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, UpdatedNumberScheduled: 2, NumberReady: 2},
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "apps"},
+			Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"},
+			}},
+		},
+	)
+
+	statuses, err := NewChecker(client).Check(context.Background(), []Resource{
+		{Kind: KindDeployment, Namespace: "apps", Name: "web"},
+		{Kind: KindStatefulSet, Namespace: "data", Name: "db"},
+		{Kind: KindDaemonSet, Namespace: "apps", Name: "agent"},
+		{Kind: KindJob, Namespace: "apps", Name: "migrate"},
+		{Kind: KindDeployment, Namespace: "apps", Name: "missing"},
+	})
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+
+	want := []struct {
+		ready   bool
+		message string
+	}{
+		{true, "2/2 available"},
+		{false, "1/3 ready"},
+		{true, "2/2 ready"},
+		{false, "failed: BackoffLimitExceeded"},
+		{false, "not found"},
+	}
+	for i, w := range want {
+		if statuses[i].Ready != w.ready || statuses[i].Message != w.message {
+			t.Fatalf("status %d: expected ready=%t %q, got %+v", i, w.ready, w.message, statuses[i])
+		}
+	}
+	if AllReady(statuses) {
+		t.Fatal("expected aggregate readiness to be false")
+	}
+}
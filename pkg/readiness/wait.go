@@ -0,0 +1,131 @@
+package readiness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const defaultPollInterval = 2 * time.Second
+
+var errNotReady = errors.New("workloads not ready")
+
+// ErrNotReady exposes the sentinel matched by NotReadyError.
+func ErrNotReady() error { return errNotReady }
+
+// Prober evaluates the readiness of a set of resources once.
+type Prober interface {
+	Check(context.Context, []Resource) ([]Status, error)
+}
+
+// NotReadyError lists the workloads that did not become ready before the deadline.
+type NotReadyError struct {
+	Timeout time.Duration
+	Unready []Status
+}
+
+func (e *NotReadyError) Error() string {
+	parts := make([]string, 0, len(e.Unready))
+	for _, status := range e.Unready {
+		parts = append(parts, fmt.Sprintf("%s %s/%s (%s)", status.Kind, status.Namespace, status.Name, status.Message))
+	}
+	return fmt.Sprintf("%s after %s: %s", errNotReady, e.Timeout, strings.Join(parts, "; "))
+}
+
+// Is lets errors.Is match ErrNotReady.
+func (e *NotReadyError) Is(target error) bool { return target == errNotReady }
+
+type waitConfig struct {
+	interval time.Duration
+	progress func(Status)
+}
+
+// WaitOption configures Wait.
+type WaitOption func(*waitConfig)
+
+// WithPollInterval overrides how often readiness is re-evaluated.
+func WithPollInterval(interval time.Duration) WaitOption {
+	return func(c *waitConfig) {
+		if interval > 0 {
+			c.interval = interval
+		}
+	}
+}
+
+// WithProgress registers a callback invoked whenever a resource's readiness or message changes.
+func WithProgress(fn func(Status)) WaitOption {
+	return func(c *waitConfig) {
+		c.progress = fn
+	}
+}
+
+// Wait polls the resources until all are ready or the timeout expires. On timeout it
+// returns the last observed statuses and a *NotReadyError naming the unready workloads.
+func Wait(ctx context.Context, prober Prober, resources []Resource, timeout time.Duration, opts ...WaitOption) ([]Status, error) {
+	cfg := waitConfig{interval: defaultPollInterval}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if len(resources) == 0 {
+		return nil, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	seen := make(map[Resource]Status, len(resources))
+	ticker := time.NewTicker(cfg.interval)
+	defer ticker.Stop()
+
+	var statuses []Status
+	for {
+		current, err := prober.Check(ctx, resources)
+		switch {
+		case err == nil:
+			statuses = current
+			reportProgress(seen, statuses, cfg.progress)
+			if AllReady(statuses) {
+				return statuses, nil
+			}
+		case ctx.Err() == nil:
+			return statuses, err
+		}
+
+		select {
+		case <-ctx.Done():
+			if statuses == nil {
+				return nil, fmt.Errorf("%w after %s: %v", errNotReady, timeout, ctx.Err())
+			}
+			return statuses, &NotReadyError{Timeout: timeout, Unready: Unready(statuses)}
+		case <-ticker.C:
+		}
+	}
+}
+
+// Unready filters the statuses that are not ready.
+func Unready(statuses []Status) []Status {
+	var unready []Status
+	for _, status := range statuses {
+		if !status.Ready {
+			unready = append(unready, status)
+		}
+	}
+	return unready
+}
+
+func reportProgress(seen map[Resource]Status, statuses []Status, progress func(Status)) {
+	for _, status := range statuses {
+		if previous, ok := seen[status.Resource]; ok && previous == status {
+			continue
+		}
+		seen[status.Resource] = status
+		if progress != nil {
+			progress(status)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package readiness
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type scriptedProber struct {
+	rounds [][]Status
+	calls  int
+}
+
+func (p *scriptedProber) Check(context.Context, []Resource) ([]Status, error) {
+	round := p.rounds[min(p.calls, len(p.rounds)-1)]
+	p.calls++
+	return round, nil
+}
+
+func TestWaitReturnsOnceAllReady(t *testing.T) {
+	web := Resource{Kind: KindDeployment, Namespace: "demo", Name: "web"}
+	prober := &scriptedProber{rounds: [][]Status{
+		{{Resource: web, Message: "0/2 available"}},
+		{{Resource: web, Message: "0/2 available"}},
+		{{Resource: web, Ready: true, Message: "2/2 available"}},
+	}}
+	var progress []string
+
+	statuses, err := Wait(context.Background(), prober, []Resource{web}, time.Second,
+		WithPollInterval(time.Millisecond),
+		WithProgress(func(s Status) { progress = append(progress, s.Message) }),
+	)
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if !AllReady(statuses) {
+		t.Fatalf("expected ready statuses, got %+v", statuses)
+	}
+	if strings.Join(progress, ",") != "0/2 available,2/2 available" {
+		t.Fatalf("expected progress only on change, got %v", progress)
+	}
+}
+
+func TestWaitReportsUnreadyWorkloadsOnTimeout(t *testing.T) {
+	web := Resource{Kind: KindDeployment, Namespace: "demo", Name: "web"}
+	job := Resource{Kind: KindJob, Namespace: "demo", Name: "migrate"}
+	prober := &scriptedProber{rounds: [][]Status{
+		{{Resource: web, Ready: true, Message: "1/1 available"}, {Resource: job, Message: "failed: BackoffLimitExceeded"}},
+	}}
+
+	_, err := Wait(context.Background(), prober, []Resource{web, job}, 20*time.Millisecond, WithPollInterval(time.Millisecond))
+	if !errors.Is(err, ErrNotReady()) {
+		t.Fatalf("expected not ready error, got %v", err)
+	}
+	var notReady *NotReadyError
+	if !errors.As(err, &notReady) || len(notReady.Unready) != 1 || notReady.Unready[0].Name != "migrate" {
+		t.Fatalf("expected only the job to be reported, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Job demo/migrate (failed: BackoffLimitExceeded)") {
+		t.Fatalf("expected unhealthy object in message, got %q", err.Error())
+	}
+}
+
+func TestWaitSkipsEmptyResourceList(t *testing.T) {
+	statuses, err := Wait(context.Background(), &scriptedProber{}, nil, time.Second)
+	if err != nil || statuses != nil {
+		t.Fatalf("expected no-op wait, got %v %v", statuses, err)
+	}
+}
@@ -0,0 +1,83 @@
+// Package registryauth resolves OCI registry credentials from the chainctl encrypted store,
+// docker config files, and Kubernetes image pull secrets.
+package registryauth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// PassphraseEnv names the environment variable holding the credential store passphrase for
+// non-interactive use.
+const PassphraseEnv = "CHAINCTL_REGISTRY_PASSPHRASE"
+
+const dockerHubHost = "docker.io"
+
+// Credential is a username and password for one registry host. The password is excluded from
+// String and JSON output so credentials cannot leak through logs.
+type Credential struct {
+	Host     string `json:"host"`
+	Username string `json:"username"`
+	Password string `json:"-"`
+}
+
+// String renders the credential without its password.
+func (c Credential) String() string {
+	return fmt.Sprintf("%s@%s", c.Username, c.Host)
+}
+
+// GoString keeps %#v from printing the password.
+func (c Credential) GoString() string {
+	return fmt.Sprintf("registryauth.Credential{Host:%q, Username:%q, Password:<redacted>}", c.Host, c.Username)
+}
+
+// Provider looks up the credential for a registry host.
+type Provider interface {
+	Credential(ctx context.Context, host string) (Credential, bool, error)
+}
+
+// ProviderFunc adapts a function to the Provider interface.
+type ProviderFunc func(ctx context.Context, host string) (Credential, bool, error)
+
+// Credential calls f.
+func (f ProviderFunc) Credential(ctx context.Context, host string) (Credential, bool, error) {
+	return f(ctx, host)
+}
+
+// Chain consults providers in order and returns the first credential found.
+type Chain []Provider
+
+// Credential implements Provider.
+func (c Chain) Credential(ctx context.Context, host string) (Credential, bool, error) {
+	for _, provider := range c {
+		if provider == nil {
+			continue
+		}
+		cred, ok, err := provider.Credential(ctx, host)
+		if err != nil {
+			return Credential{}, false, err
+		}
+		if ok {
+			return cred, true, nil
+		}
+	}
+	return Credential{}, false, nil
+}
+
+// NormalizeHost reduces a registry reference, URL, or docker config key to its host[:port],
+// folding the Docker Hub aliases into docker.io.
+func NormalizeHost(raw string) string {
+	host := strings.ToLower(strings.TrimSpace(raw))
+	for _, scheme := range []string{"oci://", "https://", "http://"} {
+		host = strings.TrimPrefix(host, scheme)
+	}
+	if idx := strings.IndexAny(host, "/@"); idx != -1 {
+		host = host[:idx]
+	}
+	switch host {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return dockerHubHost
+	}
+	return host
+}
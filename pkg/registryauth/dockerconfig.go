@@ -0,0 +1,126 @@
+package registryauth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	errInvalidDockerConfig = errors.New("invalid docker config")
+	errUnsupportedSecret   = errors.New("secret is not an image pull secret")
+)
+
+// ErrInvalidDockerConfig exposes the docker config parsing sentinel.
+func ErrInvalidDockerConfig() error { return errInvalidDockerConfig }
+
+// ErrUnsupportedSecret exposes the pull secret type sentinel.
+func ErrUnsupportedSecret() error { return errUnsupportedSecret }
+
+type dockerAuth struct {
+	Auth     string `json:"auth"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// DefaultDockerConfigPath returns $DOCKER_CONFIG/config.json or ~/.docker/config.json.
+func DefaultDockerConfigPath() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".docker", "config.json")
+}
+
+// ParseDockerConfig reads the inline credentials of a docker config.json ({"auths": {...}}) or a
+// legacy .dockercfg document. Entries delegated to credential helpers carry no secret and are skipped.
+func ParseDockerConfig(data []byte) ([]Credential, error) {
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidDockerConfig, err)
+	}
+	auths := map[string]dockerAuth{}
+	if raw, ok := top["auths"]; ok {
+		if err := json.Unmarshal(raw, &auths); err != nil {
+			return nil, fmt.Errorf("%w: %v", errInvalidDockerConfig, err)
+		}
+	} else {
+		// Legacy .dockercfg documents map hosts directly to auth entries; other
+		// top-level settings such as credsStore are not objects and are ignored.
+		for host, raw := range top {
+			var entry dockerAuth
+			if json.Unmarshal(raw, &entry) == nil {
+				auths[host] = entry
+			}
+		}
+	}
+
+	hosts := make([]string, 0, len(auths))
+	for host := range auths {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	var creds []Credential
+	for _, host := range hosts {
+		cred, ok, err := decodeDockerAuth(host, auths[host])
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			creds = append(creds, cred)
+		}
+	}
+	return creds, nil
+}
+
+func decodeDockerAuth(host string, entry dockerAuth) (Credential, bool, error) {
+	cred := Credential{Host: NormalizeHost(host), Username: entry.Username, Password: entry.Password}
+	if entry.Auth != "" {
+		decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+		if err != nil {
+			return Credential{}, false, fmt.Errorf("%w: auth for %s is not base64", errInvalidDockerConfig, cred.Host)
+		}
+		username, password, found := strings.Cut(string(decoded), ":")
+		if !found {
+			return Credential{}, false, fmt.Errorf("%w: auth for %s is not username:password", errInvalidDockerConfig, cred.Host)
+		}
+		cred.Username, cred.Password = username, password
+	}
+	if cred.Host == "" || cred.Username == "" || cred.Password == "" {
+		return Credential{}, false, nil
+	}
+	return cred, true, nil
+}
+
+// FromPullSecret extracts the credentials of a kubernetes.io/dockerconfigjson or
+// kubernetes.io/dockercfg secret.
+func FromPullSecret(secret *corev1.Secret) ([]Credential, error) {
+	if secret == nil {
+		return nil, fmt.Errorf("%w: no secret provided", errUnsupportedSecret)
+	}
+	var key string
+	switch secret.Type {
+	case corev1.SecretTypeDockerConfigJson:
+		key = corev1.DockerConfigJsonKey
+	case corev1.SecretTypeDockercfg:
+		key = corev1.DockerConfigKey
+	default:
+		return nil, fmt.Errorf("%w: %s/%s has type %q", errUnsupportedSecret, secret.Namespace, secret.Name, secret.Type)
+	}
+	data, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s has no %s key", errUnsupportedSecret, secret.Namespace, secret.Name, key)
+	}
+	return ParseDockerConfig(data)
+}
@@ -0,0 +1,147 @@
+package registryauth_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dobrovols/chainctl/pkg/registryauth"
+)
+
+func TestStoreLoginEncryptsPasswords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registries.json")
+	store := registryauth.NewStore(path, "store-pass")
+
+	if err := store.Login(registryauth.Credential{Host: "https://GHCR.io/v2/", Username: "bot", Password: "hunter2"}, registryauth.SourceLogin); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read store: %v", err)
+	}
+	if strings.Contains(string(raw), "hunter2") {
+		t.Fatalf("password stored in clear text: %s", raw)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected 0600 store, got %v", info.Mode().Perm())
+	}
+
+	cred, ok, err := store.Credential(context.Background(), "ghcr.io")
+	if err != nil || !ok {
+		t.Fatalf("expected stored credential, ok=%v err=%v", ok, err)
+	}
+	if cred.Username != "bot" || cred.Password != "hunter2" {
+		t.Fatalf("unexpected credential %s", cred)
+	}
+
+	entries, err := registryauth.NewStore(path, "").List()
+	if err != nil || len(entries) != 1 || entries[0].Host != "ghcr.io" || entries[0].Source != registryauth.SourceLogin {
+		t.Fatalf("unexpected entries %+v (%v)", entries, err)
+	}
+}
+
+func TestStoreRejectsWrongPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registries.json")
+	if err := registryauth.NewStore(path, "first").Login(registryauth.Credential{Host: "ghcr.io", Username: "bot", Password: "pw"}, registryauth.SourceLogin); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	other := registryauth.NewStore(path, "second")
+	if err := other.Login(registryauth.Credential{Host: "quay.io", Username: "bot", Password: "pw"}, registryauth.SourceLogin); !errors.Is(err, registryauth.ErrPassphraseMismatch()) {
+		t.Fatalf("expected passphrase mismatch on login, got %v", err)
+	}
+	if _, _, err := other.Credential(context.Background(), "ghcr.io"); !errors.Is(err, registryauth.ErrPassphraseMismatch()) {
+		t.Fatalf("expected passphrase mismatch on lookup, got %v", err)
+	}
+	if _, _, err := registryauth.NewStore(path, "").Credential(context.Background(), "ghcr.io"); !errors.Is(err, registryauth.ErrPassphraseRequired()) {
+		t.Fatalf("expected passphrase required, got %v", err)
+	}
+
+	removed, err := registryauth.NewStore(path, "").Logout("ghcr.io")
+	if err != nil || !removed {
+		t.Fatalf("expected logout without passphrase, removed=%v err=%v", removed, err)
+	}
+}
+
+func dockerConfigJSON(host, user, pass string) []byte {
+	auth := base64.StdEncoding.EncodeToString([]byte(user + ":" + pass))
+	return []byte(fmt.Sprintf(`{"auths":{%q:{"auth":%q},"helper.example.com":{}},"credsStore":"desktop"}`, host, auth))
+}
+
+func TestParseDockerConfigSkipsHelperEntries(t *testing.T) {
+	creds, err := registryauth.ParseDockerConfig(dockerConfigJSON("https://index.docker.io/v1/", "dev", "s3cret:with-colon"))
+	if err != nil {
+		t.Fatalf("ParseDockerConfig: %v", err)
+	}
+	if len(creds) != 1 || creds[0].Host != "docker.io" || creds[0].Username != "dev" || creds[0].Password != "s3cret:with-colon" {
+		t.Fatalf("unexpected credentials %v", creds)
+	}
+	if _, err := registryauth.ParseDockerConfig([]byte("not json")); !errors.Is(err, registryauth.ErrInvalidDockerConfig()) {
+		t.Fatalf("expected invalid docker config error, got %v", err)
+	}
+}
+
+func TestFromPullSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "regcred"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: dockerConfigJSON("registry.example.com:5000", "ci", "token")},
+	}
+	creds, err := registryauth.FromPullSecret(secret)
+	if err != nil || len(creds) != 1 || creds[0].Host != "registry.example.com:5000" {
+		t.Fatalf("unexpected credentials %v (%v)", creds, err)
+	}
+
+	secret.Type = corev1.SecretTypeOpaque
+	if _, err := registryauth.FromPullSecret(secret); !errors.Is(err, registryauth.ErrUnsupportedSecret()) {
+		t.Fatalf("expected unsupported secret error, got %v", err)
+	}
+}
+
+func TestChainPrefersEarlierProviders(t *testing.T) {
+	store := registryauth.NewStore(filepath.Join(t.TempDir(), "registries.json"), "pass")
+	if err := store.Login(registryauth.Credential{Host: "quay.io", Username: "store-user", Password: "store-pass"}, registryauth.SourceLogin); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	fallback := registryauth.ProviderFunc(func(_ context.Context, host string) (registryauth.Credential, bool, error) {
+		if host == "docker.io" {
+			return registryauth.Credential{}, false, nil
+		}
+		return registryauth.Credential{Host: host, Username: "fallback-user", Password: "pw"}, true, nil
+	})
+	chain := registryauth.Chain{store, fallback}
+
+	for host, user := range map[string]string{"quay.io": "store-user", "ghcr.io": "fallback-user"} {
+		cred, ok, err := chain.Credential(context.Background(), host)
+		if err != nil || !ok || cred.Username != user {
+			t.Fatalf("%s: expected %s, got %v ok=%v err=%v", host, user, cred, ok, err)
+		}
+	}
+	if _, ok, err := chain.Credential(context.Background(), "docker.io"); ok || err != nil {
+		t.Fatalf("expected miss for unknown host, ok=%v err=%v", ok, err)
+	}
+}
+
+func TestCredentialFormattingOmitsPassword(t *testing.T) {
+	cred := registryauth.Credential{Host: "ghcr.io", Username: "bot", Password: "hunter2"}
+	for _, rendered := range []string{cred.String(), fmt.Sprintf("%v", cred), fmt.Sprintf("%#v", cred)} {
+		if strings.Contains(rendered, "hunter2") {
+			t.Fatalf("password leaked in %q", rendered)
+		}
+	}
+}
+
+func TestParseDockerConfigAcceptsHelperOnlyConfig(t *testing.T) {
+	creds, err := registryauth.ParseDockerConfig([]byte(`{"credsStore":"osxkeychain"}`))
+	if err != nil || len(creds) != 0 {
+		t.Fatalf("expected no credentials without error, got %v (%v)", creds, err)
+	}
+}
@@ -0,0 +1,232 @@
+package registryauth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dobrovols/chainctl/pkg/secrets"
+)
+
+const (
+	// SourceLogin marks credentials entered through `chainctl registry login`.
+	SourceLogin = "login"
+	// SourceDockerConfig marks credentials imported from a docker config file.
+	SourceDockerConfig = "docker-config"
+	// SourcePullSecret marks credentials imported from a Kubernetes image pull secret.
+	SourcePullSecret = "pull-secret"
+)
+
+var (
+	errPassphraseRequired = errors.New("registry credential passphrase required (set " + PassphraseEnv + " or --passphrase)")
+	errPassphraseMismatch = errors.New("passphrase does not match the registry credential store")
+	errInvalidCredential  = errors.New("registry credential requires host, username, and password")
+)
+
+// ErrPassphraseRequired exposes the missing passphrase sentinel.
+func ErrPassphraseRequired() error { return errPassphraseRequired }
+
+// ErrPassphraseMismatch exposes the wrong passphrase sentinel.
+func ErrPassphraseMismatch() error { return errPassphraseMismatch }
+
+// ErrInvalidCredential exposes the incomplete credential sentinel.
+func ErrInvalidCredential() error { return errInvalidCredential }
+
+// Entry is the non-secret view of a stored credential.
+type Entry struct {
+	Host      string    `json:"host"`
+	Username  string    `json:"username"`
+	Source    string    `json:"source"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type storedCredential struct {
+	Username string `json:"username"`
+	// Password holds the base64 CENC envelope of the password, sealed with the store passphrase.
+	Password  string    `json:"password"`
+	Source    string    `json:"source"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type storeDocument struct {
+	Registries map[string]storedCredential `json:"registries"`
+}
+
+// Store persists registry credentials on disk. Hosts and usernames are kept in clear text so
+// entries can be listed and removed without the passphrase; each password is sealed in its own
+// CENC envelope.
+type Store struct {
+	path       string
+	passphrase string
+	now        func() time.Time
+}
+
+// NewStore constructs a store backed by path. The passphrase may be empty for read-only listing.
+func NewStore(path, passphrase string) *Store {
+	return &Store{path: path, passphrase: passphrase, now: time.Now}
+}
+
+// Path returns the backing file.
+func (s *Store) Path() string { return s.path }
+
+// Login stores or replaces the credential for cred.Host.
+func (s *Store) Login(cred Credential, source string) error {
+	cred.Host = NormalizeHost(cred.Host)
+	if cred.Host == "" || cred.Username == "" || cred.Password == "" {
+		return errInvalidCredential
+	}
+	if s.passphrase == "" {
+		return errPassphraseRequired
+	}
+	doc, err := s.load()
+	if err != nil {
+		return err
+	}
+	if err := s.checkPassphrase(doc); err != nil {
+		return err
+	}
+
+	sealed, err := secrets.Seal([]byte(cred.Password), s.passphrase)
+	if err != nil {
+		return fmt.Errorf("encrypt credential for %s: %w", cred.Host, err)
+	}
+	doc.Registries[cred.Host] = storedCredential{
+		Username:  cred.Username,
+		Password:  base64.StdEncoding.EncodeToString(sealed),
+		Source:    source,
+		UpdatedAt: s.now().UTC(),
+	}
+	return s.save(doc)
+}
+
+// Logout removes the credential stored for host and reports whether one existed.
+func (s *Store) Logout(host string) (bool, error) {
+	doc, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	host = NormalizeHost(host)
+	if _, ok := doc.Registries[host]; !ok {
+		return false, nil
+	}
+	delete(doc.Registries, host)
+	return true, s.save(doc)
+}
+
+// List returns the stored entries sorted by host, without passwords.
+func (s *Store) List() ([]Entry, error) {
+	doc, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(doc.Registries))
+	for host, stored := range doc.Registries {
+		entries = append(entries, Entry{Host: host, Username: stored.Username, Source: stored.Source, UpdatedAt: stored.UpdatedAt})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Host < entries[j].Host })
+	return entries, nil
+}
+
+// Credential implements Provider. A stored entry without a passphrase to open it is an error
+// rather than a miss, so pulls do not silently fall back to anonymous access.
+func (s *Store) Credential(_ context.Context, host string) (Credential, bool, error) {
+	doc, err := s.load()
+	if err != nil {
+		return Credential{}, false, err
+	}
+	host = NormalizeHost(host)
+	stored, ok := doc.Registries[host]
+	if !ok {
+		return Credential{}, false, nil
+	}
+	if s.passphrase == "" {
+		return Credential{}, false, fmt.Errorf("credential for %s: %w", host, errPassphraseRequired)
+	}
+	password, err := s.open(stored)
+	if err != nil {
+		return Credential{}, false, fmt.Errorf("credential for %s: %w", host, err)
+	}
+	return Credential{Host: host, Username: stored.Username, Password: password}, true, nil
+}
+
+// checkPassphrase keeps every entry sealed with the same passphrase by opening an existing one.
+func (s *Store) checkPassphrase(doc storeDocument) error {
+	for _, stored := range doc.Registries {
+		if _, err := s.open(stored); err != nil {
+			return err
+		}
+		return nil
+	}
+	return nil
+}
+
+func (s *Store) open(stored storedCredential) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(stored.Password)
+	if err != nil {
+		return "", fmt.Errorf("decode stored credential: %w", err)
+	}
+	plaintext, err := secrets.Open(sealed, s.passphrase)
+	if err != nil {
+		return "", errPassphraseMismatch
+	}
+	return string(plaintext), nil
+}
+
+func (s *Store) load() (storeDocument, error) {
+	doc := storeDocument{Registries: map[string]storedCredential{}}
+	if strings.TrimSpace(s.path) == "" {
+		return doc, errors.New("registry credential store path not configured")
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return doc, nil
+		}
+		return doc, fmt.Errorf("read registry credentials: %w", err)
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return doc, fmt.Errorf("decode registry credentials %s: %w", s.path, err)
+	}
+	if doc.Registries == nil {
+		doc.Registries = map[string]storedCredential{}
+	}
+	return doc, nil
+}
+
+func (s *Store) save(doc storeDocument) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode registry credentials: %w", err)
+	}
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create registry credential directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".registries-*.json")
+	if err != nil {
+		return fmt.Errorf("write registry credentials: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write registry credentials: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write registry credentials: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write registry credentials: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("write registry credentials: %w", err)
+	}
+	return nil
+}
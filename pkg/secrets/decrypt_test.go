@@ -145,3 +145,49 @@ func TestEncryptFileRejectsEmptyPayload(t *testing.T) {
 		t.Fatalf("expected error for empty input file")
 	}
 }
+
+func TestIsEnvelopeDetectsEncryptedPayload(t *testing.T) {
+	tempDir := t.TempDir()
+	input := filepath.Join(tempDir, "values.yaml")
+	if err := os.WriteFile(input, []byte("replicaCount: 2\n"), 0o600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	output := filepath.Join(tempDir, "values.enc")
+	if _, err := secrets.EncryptFile(secrets.EncryptOptions{InputPath: input, OutputPath: output, Passphrase: "secret"}); err != nil {
+		t.Fatalf("encrypt file: %v", err)
+	}
+
+	encrypted, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("read envelope: %v", err)
+	}
+	if !secrets.IsEnvelope(encrypted) {
+		t.Fatalf("expected encrypted payload to be detected")
+	}
+	if secrets.IsEnvelope([]byte("replicaCount: 2\n")) {
+		t.Fatalf("expected plaintext values not to be detected as envelope")
+	}
+}
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	envelope, err := secrets.Seal([]byte("registry-password"), "passphrase")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if !secrets.IsEnvelope(envelope) {
+		t.Fatal("expected sealed payload to carry the envelope header")
+	}
+	plaintext, err := secrets.Open(envelope, "passphrase")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if string(plaintext) != "registry-password" {
+		t.Fatalf("unexpected plaintext %q", plaintext)
+	}
+
+	_, err = secrets.Open(envelope, "wrong")
+	var encErr *secrets.Error
+	if !errors.As(err, &encErr) || encErr.Code != secrets.ErrCodeEncryption {
+		t.Fatalf("expected encryption error for wrong passphrase, got %v", err)
+	}
+}
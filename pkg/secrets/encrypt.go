@@ -64,18 +64,43 @@ func EncryptFile(opts EncryptOptions) (*EncryptResult, error) {
 		return nil, NewError(ErrCodeValidation, errors.New("input file is empty"))
 	}
 
+	envelope, err := Seal(plaintext, opts.Passphrase)
+	zeroBytes(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(opts.OutputPath, envelope, 0o600); err != nil {
+		return nil, NewError(ErrCodeEncryption, fmt.Errorf("write output file: %w", err))
+	}
+
+	checksum := sha256.Sum256(envelope[envelopeHeaderSize()+saltSize+nonceSize:])
+
+	return &EncryptResult{
+		OutputPath: opts.OutputPath,
+		Checksum:   fmt.Sprintf("%x", checksum),
+	}, nil
+}
+
+// Seal encrypts plaintext with a key derived from passphrase and returns the CENC envelope:
+// header, scrypt salt, GCM nonce, then ciphertext.
+func Seal(plaintext []byte, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, NewError(ErrCodeValidation, errors.New("passphrase cannot be empty"))
+	}
+
 	salt := make([]byte, saltSize)
 	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
 		return nil, NewError(ErrCodeEncryption, fmt.Errorf("generate salt: %w", err))
 	}
 
-	passBytes := []byte(opts.Passphrase)
+	passBytes := []byte(passphrase)
 	key, err := scrypt.Key(passBytes, salt, 1<<15, 8, 1, 32)
+	zeroBytes(passBytes)
 	if err != nil {
 		return nil, NewError(ErrCodeEncryption, fmt.Errorf("derive key: %w", err))
 	}
 	defer zeroBytes(key)
-	zeroBytes(passBytes)
 
 	block, err := aes.NewCipher(key)
 	if err != nil {
@@ -100,19 +125,13 @@ func EncryptFile(opts EncryptOptions) (*EncryptResult, error) {
 	buf.Write(salt)
 	buf.Write(nonce)
 	buf.Write(ciphertext)
+	return buf.Bytes(), nil
+}
 
-	if err := os.WriteFile(opts.OutputPath, buf.Bytes(), 0o600); err != nil {
-		return nil, NewError(ErrCodeEncryption, fmt.Errorf("write output file: %w", err))
-	}
-
-	checksum := sha256.Sum256(ciphertext)
-
-	zeroBytes(plaintext)
-
-	return &EncryptResult{
-		OutputPath: opts.OutputPath,
-		Checksum:   fmt.Sprintf("%x", checksum),
-	}, nil
+// IsEnvelope reports whether data starts with the chainctl encryption envelope header,
+// letting callers accept encrypted and plaintext values files interchangeably.
+func IsEnvelope(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(envelopeMagic))
 }
 
 // DecryptFile decrypts an encrypted values file and returns plaintext bytes.
@@ -129,7 +148,16 @@ func DecryptFile(opts DecryptOptions) ([]byte, error) {
 		return nil, NewError(ErrCodeValidation, fmt.Errorf("read input file: %w", err))
 	}
 
-	headerSize := len(envelopeMagic) + 1
+	return Open(payload, opts.Passphrase)
+}
+
+// Open decrypts a CENC envelope produced by Seal or EncryptFile.
+func Open(payload []byte, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, NewError(ErrCodeValidation, errors.New("passphrase cannot be empty"))
+	}
+
+	headerSize := envelopeHeaderSize()
 	minSize := headerSize + saltSize + nonceSize + 1
 	if len(payload) < minSize {
 		return nil, NewError(ErrCodeEncryption, errors.New("encrypted payload too small"))
@@ -149,7 +177,7 @@ func DecryptFile(opts DecryptOptions) ([]byte, error) {
 	offset += nonceSize
 	ciphertext := payload[offset:]
 
-	passBytes := []byte(opts.Passphrase)
+	passBytes := []byte(passphrase)
 	key, err := scrypt.Key(passBytes, salt, 1<<15, 8, 1, 32)
 	zeroBytes(passBytes)
 	if err != nil {
@@ -179,6 +207,10 @@ func DecryptFile(opts DecryptOptions) ([]byte, error) {
 	return result, nil
 }
 
+func envelopeHeaderSize() int {
+	return len(envelopeMagic) + 1
+}
+
 func zeroBytes(buf []byte) {
 	for i := range buf {
 		buf[i] = 0
@@ -0,0 +1,187 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// SchemaVersion is the state document format written by Manager. Files without a schemaVersion
+// predate the document format and hold a single record; they are read as version 1. Older
+// documents are migrated on read (see Migrations) and rewritten in the current format on the
+// next write, which keeps the original next to the state file.
+const SchemaVersion = 2
+
+const legacySchemaVersion = 1
+
+var errUnsupportedSchema = errors.New("state file schema version is not supported")
+
+// ErrUnsupportedSchema exposes the sentinel returned for state documents written by a newer chainctl.
+func ErrUnsupportedSchema() error { return errUnsupportedSchema }
+
+// Document is the on-disk state file: every release recorded by the operator, keyed by cluster
+// endpoint, namespace, and release name.
+type Document struct {
+	SchemaVersion int      `json:"schemaVersion"`
+	Records       []Record `json:"records"`
+}
+
+// RecordKey identifies a record within a Document.
+type RecordKey struct {
+	ClusterEndpoint string
+	Namespace       string
+	Release         string
+}
+
+// String renders the key as cluster|namespace/release.
+func (k RecordKey) String() string {
+	return WorkflowLockKey(k.ClusterEndpoint, k.Namespace, k.Release)
+}
+
+// Key returns the document key of the record.
+func (r Record) Key() RecordKey {
+	return RecordKey{ClusterEndpoint: r.ClusterEndpoint, Namespace: r.Namespace, Release: r.Release}
+}
+
+// Lookup returns the record stored under exactly the given key.
+func (d Document) Lookup(key RecordKey) (Record, bool) {
+	for _, record := range d.Records {
+		if record.Key() == key {
+			return record, true
+		}
+	}
+	return Record{}, false
+}
+
+// Select returns the records matching the non-empty Release, Namespace, and ClusterEndpoint
+// overrides. When a cluster endpoint is given, records stored without one still match so that
+// releases installed before the endpoint was known are found; exact matches take precedence.
+func (d Document) Select(overrides Overrides) []Record {
+	var exact, unkeyed []Record
+	for _, record := range d.Records {
+		if overrides.Release != "" && record.Release != overrides.Release {
+			continue
+		}
+		if overrides.Namespace != "" && record.Namespace != overrides.Namespace {
+			continue
+		}
+		switch {
+		case overrides.ClusterEndpoint == "" || record.ClusterEndpoint == overrides.ClusterEndpoint:
+			exact = append(exact, record)
+		case record.ClusterEndpoint == "":
+			unkeyed = append(unkeyed, record)
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+	return unkeyed
+}
+
+// Find returns the single record matching the overrides.
+func (d Document) Find(overrides Overrides) (Record, error) {
+	matches := d.Select(overrides)
+	switch len(matches) {
+	case 0:
+		return Record{}, errStateNotFound
+	case 1:
+		return matches[0], nil
+	default:
+		return Record{}, errAmbiguousState
+	}
+}
+
+// Put stores the record, replacing the record it supersedes: the one with the same key or, for
+// records carrying a cluster endpoint, the same release stored without one. It returns the
+// superseded record, if any.
+func (d *Document) Put(record Record) (Record, bool) {
+	previous, found := d.superseded(record)
+	if found {
+		d.Delete(previous.Key())
+	}
+	d.Records = append(d.Records, record)
+	d.sort()
+	return previous, found
+}
+
+func (d Document) superseded(record Record) (Record, bool) {
+	if previous, ok := d.Lookup(record.Key()); ok || record.ClusterEndpoint == "" {
+		return previous, ok
+	}
+	return d.Lookup(RecordKey{Namespace: record.Namespace, Release: record.Release})
+}
+
+// Delete removes the record stored under key and reports whether it existed.
+func (d *Document) Delete(key RecordKey) bool {
+	for i, record := range d.Records {
+		if record.Key() == key {
+			d.Records = append(d.Records[:i:i], d.Records[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Document) sort() {
+	sort.SliceStable(d.Records, func(i, j int) bool {
+		a, b := d.Records[i].Key(), d.Records[j].Key()
+		if a.ClusterEndpoint != b.ClusterEndpoint {
+			return a.ClusterEndpoint < b.ClusterEndpoint
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Release < b.Release
+	})
+}
+
+// DecodeDocument parses a state file, migrating documents stored at an older schema version.
+func DecodeDocument(data []byte) (Document, error) {
+	doc, _, err := decodeStored(data)
+	return doc, err
+}
+
+// decodeStored parses a state file and reports the schema version it was stored at.
+func decodeStored(data []byte) (Document, int, error) {
+	migrated, version, err := MigrateDocument(data)
+	if err != nil {
+		return Document{}, version, err
+	}
+	var doc Document
+	if err := json.Unmarshal(migrated, &doc); err != nil {
+		return Document{}, version, err
+	}
+	return doc, version, nil
+}
+
+func (m *Manager) readDocument(path string) (Document, error) {
+	doc, _, err := m.readStateFile(path)
+	return doc, err
+}
+
+// readStateFile reads the state document at path, decrypting it when it is encrypted. When the
+// file was migrated from an older schema version its original contents are returned as well, so
+// writers can back them up.
+func (m *Manager) readStateFile(path string) (Document, []byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Document{}, nil, fmt.Errorf("%w: %s", errStateNotFound, path)
+		}
+		return Document{}, nil, fmt.Errorf("%w: %w", errReadFailed, err)
+	}
+	plaintext, err := m.decodeFile(path, data)
+	if err != nil {
+		return Document{}, nil, err
+	}
+	doc, version, err := decodeStored(plaintext)
+	if err != nil {
+		return Document{}, nil, fmt.Errorf("%w: decode %s: %w", errReadFailed, path, err)
+	}
+	if version < SchemaVersion {
+		return doc, data, nil
+	}
+	return doc, nil, nil
+}
@@ -0,0 +1,158 @@
+package state_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	state "github.com/dobrovols/chainctl/pkg/state"
+)
+
+func releaseRecord(release, namespace, version string) state.Record {
+	record := sampleRecord(version)
+	record.Release, record.Namespace = release, namespace
+	return record
+}
+
+func TestManagerUpgradesSingleRecordFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.json")
+	legacy := `{"release":"myapp","namespace":"demo","chart":{"type":"oci","reference":"oci://registry.example.com/apps/myapp:1.0.0"},` +
+		`"version":"1.0.0","lastAction":"install","timestamp":"2025-01-01T00:00:00Z","revision":1}`
+	if err := os.WriteFile(path, []byte(legacy), 0o600); err != nil {
+		t.Fatalf("write legacy state: %v", err)
+	}
+	manager := state.NewManager(&stubResolver{fixedPath: path})
+
+	record, err := manager.Read(state.Overrides{})
+	if err != nil || record.Release != "myapp" || record.Revision != 1 {
+		t.Fatalf("expected legacy record to be readable, got %+v (%v)", record, err)
+	}
+
+	if _, err := manager.Write(releaseRecord("other", "staging", "2.0.0"), state.Overrides{}); err != nil {
+		t.Fatalf("write second release: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read state: %v", err)
+	}
+	doc, err := state.DecodeDocument(data)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if doc.SchemaVersion != state.SchemaVersion || len(doc.Records) != 2 {
+		t.Fatalf("expected upgraded document with both releases, got %+v", doc)
+	}
+	if _, ok := doc.Lookup(state.RecordKey{Namespace: "demo", Release: "myapp"}); !ok {
+		t.Fatalf("expected legacy release keyed in document, got %+v", doc.Records)
+	}
+}
+
+func TestManagerKeepsReleasesApart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.json")
+	manager := state.NewManager(&stubResolver{fixedPath: path})
+	for _, record := range []state.Record{
+		releaseRecord("myapp", "demo", "1.0.0"),
+		releaseRecord("billing", "demo", "3.0.0"),
+		releaseRecord("myapp", "demo", "1.1.0"),
+	} {
+		if _, err := manager.Write(record, state.Overrides{}); err != nil {
+			t.Fatalf("write %s: %v", record.Release, err)
+		}
+	}
+
+	if _, err := manager.Read(state.Overrides{Namespace: "demo"}); !errors.Is(err, state.ErrAmbiguousState()) {
+		t.Fatalf("expected ambiguous selection, got %v", err)
+	}
+	myapp, err := manager.Read(state.Overrides{Release: "myapp", Namespace: "demo"})
+	if err != nil || myapp.Version != "1.1.0" || len(myapp.History) != 2 {
+		t.Fatalf("expected myapp with its own history, got %+v (%v)", myapp, err)
+	}
+	billing, err := manager.Lookup(state.RecordKey{ClusterEndpoint: "https://127.0.0.1:6443", Namespace: "demo", Release: "billing"}, state.Overrides{})
+	if err != nil || billing.Version != "3.0.0" || len(billing.History) != 1 {
+		t.Fatalf("expected billing record untouched by myapp writes, got %+v (%v)", billing, err)
+	}
+	records, err := manager.List(state.Overrides{})
+	if err != nil || len(records) != 2 || records[0].Release != "billing" {
+		t.Fatalf("expected records sorted by key, got %+v (%v)", records, err)
+	}
+
+	if _, err := manager.Remove(state.Overrides{Release: "myapp"}); err != nil {
+		t.Fatalf("remove myapp: %v", err)
+	}
+	if _, err := manager.Read(state.Overrides{}); err != nil {
+		t.Fatalf("expected billing to remain, got %v", err)
+	}
+	if _, err := manager.Remove(state.Overrides{Release: "billing"}); err != nil {
+		t.Fatalf("remove billing: %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected state file deleted with its last record, got %v", err)
+	}
+}
+
+func TestManagerAdoptsRecordsWithoutClusterEndpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.json")
+	manager := state.NewManager(&stubResolver{fixedPath: path})
+
+	installed := sampleRecord("1.0.0")
+	installed.ClusterEndpoint = ""
+	if _, err := manager.Write(installed, state.Overrides{}); err != nil {
+		t.Fatalf("write install: %v", err)
+	}
+	if _, err := manager.Read(state.Overrides{ClusterEndpoint: "https://127.0.0.1:6443"}); err != nil {
+		t.Fatalf("expected record without endpoint to match, got %v", err)
+	}
+	if _, err := manager.Write(sampleRecord("1.1.0"), state.Overrides{}); err != nil {
+		t.Fatalf("write upgrade: %v", err)
+	}
+
+	records, err := manager.List(state.Overrides{})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("expected the upgrade to replace the unkeyed record, got %+v (%v)", records, err)
+	}
+	if records[0].ClusterEndpoint == "" || len(records[0].History) != 2 {
+		t.Fatalf("expected keyed record continuing history, got %+v", records[0])
+	}
+}
+
+func TestDecodeDocumentRejectsNewerSchema(t *testing.T) {
+	_, err := state.DecodeDocument([]byte(`{"schemaVersion":99,"records":[]}`))
+	if !errors.Is(err, state.ErrUnsupportedSchema()) {
+		t.Fatalf("expected unsupported schema error, got %v", err)
+	}
+}
+
+func TestManagerUpdateDeletesEmptyDocument(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.json")
+	manager := state.NewManager(&stubResolver{fixedPath: path})
+	record := releaseRecord("myapp", "demo", "1.0.0")
+	if _, err := manager.Write(record, state.Overrides{}); err != nil {
+		t.Fatalf("write state: %v", err)
+	}
+
+	boom := errors.New("boom")
+	if _, err := manager.Update(state.Overrides{}, func(*state.Document) error { return boom }); !errors.Is(err, boom) {
+		t.Fatalf("expected update error to propagate, got %v", err)
+	}
+	if _, err := manager.Update(state.Overrides{}, func(doc *state.Document) error {
+		doc.Delete(record.Key())
+		return nil
+	}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected empty document to be removed, got %v", err)
+	}
+}
+
+func TestValidateDocument(t *testing.T) {
+	valid := `{"schemaVersion":2,"records":[{"release":"myapp","namespace":"demo","chart":{"type":"oci","reference":"oci://registry.example.com/apps/myapp:1.0.0"},` +
+		`"version":"1.0.0","lastAction":"install","timestamp":"2025-01-01T00:00:00Z"}]}`
+	if err := state.ValidateDocument([]byte(valid)); err != nil {
+		t.Fatalf("expected valid document, got %v", err)
+	}
+	if err := state.ValidateDocument([]byte(`{"schemaVersion":2,"records":[{"release":"myapp"}]}`)); !errors.Is(err, state.ErrSchemaViolation()) {
+		t.Fatalf("expected schema violation, got %v", err)
+	}
+}
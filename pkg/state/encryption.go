@@ -0,0 +1,114 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dobrovols/chainctl/pkg/secrets"
+)
+
+const (
+	// PassphraseEnv names the environment variable holding the state encryption passphrase.
+	PassphraseEnv = "CHAINCTL_STATE_PASSPHRASE"
+	// PassphraseFileEnv names the environment variable pointing at a file holding the passphrase.
+	PassphraseFileEnv = "CHAINCTL_STATE_PASSPHRASE_FILE"
+)
+
+var errEncrypted = errors.New("state file is encrypted; set " + PassphraseEnv + " or " + PassphraseFileEnv + " to read it")
+
+// ErrEncrypted exposes the sentinel returned for encrypted state files read without a passphrase.
+func ErrEncrypted() error { return errEncrypted }
+
+// PassphraseSource supplies the state encryption passphrase. An empty passphrase leaves state
+// files unencrypted.
+type PassphraseSource func() (string, error)
+
+// EnvPassphrase reads the passphrase from PassphraseEnv or, when that is unset, from the file
+// named by PassphraseFileEnv. Neither being set yields an empty passphrase.
+func EnvPassphrase() (string, error) {
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+	file := os.Getenv(PassphraseFileEnv)
+	if file == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", PassphraseFileEnv, err)
+	}
+	passphrase := strings.TrimRight(string(data), "\r\n")
+	if passphrase == "" {
+		return "", fmt.Errorf("%s names an empty file", PassphraseFileEnv)
+	}
+	return passphrase, nil
+}
+
+// WithPassphraseSource enables encryption at rest: when source yields a passphrase, state files
+// are written as CENC envelopes (see pkg/secrets). Encrypted files are recognised on read
+// regardless of the option and need the passphrase to be opened.
+func WithPassphraseSource(source PassphraseSource) ManagerOption {
+	return func(m *Manager) {
+		m.passphrase = source
+	}
+}
+
+// WithPassphrase returns a copy of the manager that encrypts and decrypts state with passphrase.
+func (m *Manager) WithPassphrase(passphrase string) *Manager {
+	clone := *m
+	clone.passphrase = func() (string, error) { return passphrase, nil }
+	return &clone
+}
+
+func (m *Manager) resolvePassphrase() (string, error) {
+	if m.passphrase == nil {
+		return "", nil
+	}
+	return m.passphrase()
+}
+
+// decodeFile returns the plaintext of a state file, opening CENC envelopes with the configured
+// passphrase.
+func (m *Manager) decodeFile(path string, data []byte) ([]byte, error) {
+	if !secrets.IsEnvelope(data) {
+		return data, nil
+	}
+	passphrase, err := m.resolvePassphrase()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errReadFailed, err)
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("%w: %s", errEncrypted, path)
+	}
+	plaintext, err := secrets.Open(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%w: decrypt %s: %w", errReadFailed, path, err)
+	}
+	return plaintext, nil
+}
+
+// encodeFile renders the document as written to disk, sealed when a passphrase is configured.
+func (m *Manager) encodeFile(doc Document) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", errWriteFailed, err)
+	}
+	passphrase, err := m.resolvePassphrase()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errWriteFailed, err)
+	}
+	if passphrase == "" {
+		return buf.Bytes(), nil
+	}
+	sealed, err := secrets.Seal(buf.Bytes(), passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%w: encrypt state: %w", errWriteFailed, err)
+	}
+	return sealed, nil
+}
@@ -0,0 +1,101 @@
+package state_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dobrovols/chainctl/pkg/secrets"
+	state "github.com/dobrovols/chainctl/pkg/state"
+)
+
+func staticPassphrase(passphrase string) state.PassphraseSource {
+	return func() (string, error) { return passphrase, nil }
+}
+
+func TestManagerEncryptsStateAtRest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.json")
+	manager := state.NewManager(&stubResolver{fixedPath: path}, state.WithPassphraseSource(staticPassphrase("s3cret")))
+	record := sampleRecord("1.0.0")
+	if _, err := manager.Write(record, state.Overrides{}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read state file: %v", err)
+	}
+	if !secrets.IsEnvelope(data) || strings.Contains(string(data), record.Chart.Reference) {
+		t.Fatalf("expected encrypted state file, got %q", data)
+	}
+	got, err := manager.Read(state.Overrides{})
+	if err != nil || got.Version != "1.0.0" {
+		t.Fatalf("expected encrypted record to be readable, got %+v (%v)", got, err)
+	}
+
+	plain := state.NewManager(&stubResolver{fixedPath: path})
+	if _, err := plain.Read(state.Overrides{}); !errors.Is(err, state.ErrEncrypted()) {
+		t.Fatalf("expected passphrase to be required, got %v", err)
+	}
+	if _, err := plain.Write(sampleRecord("2.0.0"), state.Overrides{}); !errors.Is(err, state.ErrEncrypted()) {
+		t.Fatalf("expected write without passphrase to leave encrypted state alone, got %v", err)
+	}
+	if _, err := plain.WithPassphrase("wrong").Read(state.Overrides{}); !errors.Is(err, state.ErrReadFailed()) {
+		t.Fatalf("expected wrong passphrase to fail, got %v", err)
+	}
+	if got, err := plain.WithPassphrase("s3cret").Read(state.Overrides{}); err != nil || got.Version != "1.0.0" {
+		t.Fatalf("expected explicit passphrase to open state, got %+v (%v)", got, err)
+	}
+}
+
+func TestManagerEncryptsPlaintextStateOnNextWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.json")
+	if _, err := state.NewManager(&stubResolver{fixedPath: path}).Write(sampleRecord("1.0.0"), state.Overrides{}); err != nil {
+		t.Fatalf("write plaintext: %v", err)
+	}
+	manager := state.NewManager(&stubResolver{fixedPath: path}, state.WithPassphraseSource(staticPassphrase("s3cret")))
+	if _, err := manager.Read(state.Overrides{}); err != nil {
+		t.Fatalf("read plaintext with passphrase configured: %v", err)
+	}
+	if _, err := manager.Write(sampleRecord("1.1.0"), state.Overrides{}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !secrets.IsEnvelope(data) {
+		t.Fatalf("expected state to be encrypted after the next write (%v)", err)
+	}
+	record, err := manager.Read(state.Overrides{})
+	if err != nil || len(record.History) != 2 {
+		t.Fatalf("expected history carried over, got %+v (%v)", record, err)
+	}
+}
+
+func TestEnvPassphrase(t *testing.T) {
+	t.Setenv(state.PassphraseEnv, "")
+	t.Setenv(state.PassphraseFileEnv, "")
+	if passphrase, err := state.EnvPassphrase(); err != nil || passphrase != "" {
+		t.Fatalf("expected no passphrase, got %q (%v)", passphrase, err)
+	}
+
+	file := filepath.Join(t.TempDir(), "passphrase")
+	if err := os.WriteFile(file, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatalf("write passphrase file: %v", err)
+	}
+	t.Setenv(state.PassphraseFileEnv, file)
+	if passphrase, err := state.EnvPassphrase(); err != nil || passphrase != "from-file" {
+		t.Fatalf("expected passphrase from file, got %q (%v)", passphrase, err)
+	}
+
+	t.Setenv(state.PassphraseEnv, "from-env")
+	if passphrase, err := state.EnvPassphrase(); err != nil || passphrase != "from-env" {
+		t.Fatalf("expected environment to take precedence, got %q (%v)", passphrase, err)
+	}
+
+	t.Setenv(state.PassphraseEnv, "")
+	t.Setenv(state.PassphraseFileEnv, filepath.Join(t.TempDir(), "missing"))
+	if _, err := state.EnvPassphrase(); err == nil {
+		t.Fatal("expected missing passphrase file to fail")
+	}
+}
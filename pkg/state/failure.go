@@ -0,0 +1,79 @@
+package state
+
+import (
+	"fmt"
+	"time"
+)
+
+// StatusFailed is the record status of a release whose first install failed.
+const StatusFailed = "failed"
+
+// Failure describes an install or upgrade that failed after it started changing the release.
+// It stays on the record until a later run for the release succeeds.
+type Failure struct {
+	Action string `json:"action"`
+	// Phase is the telemetry phase that failed, such as helm or verify.
+	Phase string `json:"phase"`
+	// Error is the sanitized error reported by the failed phase.
+	Error      string      `json:"error"`
+	Chart      ChartSource `json:"chart"`
+	Version    string      `json:"version,omitempty"`
+	Revision   int         `json:"revision,omitempty"`
+	Operator   string      `json:"operator,omitempty"`
+	WorkflowID string      `json:"workflowId,omitempty"`
+	Timestamp  string      `json:"timestamp"`
+}
+
+// String summarises the failure for operators.
+func (f Failure) String() string {
+	summary := fmt.Sprintf("%s of %s failed in %s phase at %s", f.Action, f.Chart.Reference, f.Phase, f.Timestamp)
+	if f.WorkflowID != "" {
+		summary += " (workflow " + f.WorkflowID + ")"
+	}
+	if f.Error != "" {
+		summary += ": " + f.Error
+	}
+	return summary
+}
+
+// ReleaseChanged reports whether the failed run created a release revision. Failures raised
+// before Helm applied anything, such as template or connection errors, carry revision zero.
+func (f Failure) ReleaseChanged() bool { return f.Revision > 0 }
+
+// Entry summarises the failure as a failed history entry.
+func (f Failure) Entry() HistoryEntry {
+	return HistoryEntry{
+		Revision:   f.Revision,
+		Action:     f.Action,
+		Chart:      f.Chart,
+		Version:    f.Version,
+		Timestamp:  f.Timestamp,
+		Outcome:    OutcomeFailed,
+		Operator:   f.Operator,
+		WorkflowID: f.WorkflowID,
+	}
+}
+
+// WithFailure returns the record annotated with the failure and the failed attempt appended to
+// its history, leaving the last good release untouched. A record without a previous action
+// (a failed first install) takes the attempted chart and the failed status instead.
+func (r Record) WithFailure(failure Failure) Record {
+	if failure.Timestamp == "" {
+		failure.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+	if r.LastAction == "" {
+		r.Chart = failure.Chart
+		r.Version = failure.Version
+		r.LastAction = failure.Action
+		r.Timestamp = failure.Timestamp
+		r.Revision = failure.Revision
+		r.Status = StatusFailed
+		r.Operator = failure.Operator
+		r.WorkflowID = failure.WorkflowID
+		r.History = []HistoryEntry{failure.Entry()}
+	} else {
+		r = r.WithFailedAttempt(failure.Entry())
+	}
+	r.Failure = &failure
+	return r
+}
@@ -0,0 +1,118 @@
+package state
+
+import "time"
+
+// History entry outcomes.
+const (
+	// OutcomeSucceeded marks a history entry for a revision that was applied.
+	OutcomeSucceeded = "succeeded"
+	// OutcomeFailed marks a history entry for an attempt that did not leave its revision deployed.
+	OutcomeFailed = "failed"
+)
+
+// DefaultHistoryLimit is the number of history entries a Manager retains per state file.
+const DefaultHistoryLimit = 50
+
+// HistoryEntry captures a release revision applied by chainctl and the chart it was built from.
+type HistoryEntry struct {
+	Revision  int         `json:"revision,omitempty"`
+	Action    string      `json:"action"`
+	Chart     ChartSource `json:"chart"`
+	Version   string      `json:"version,omitempty"`
+	Timestamp string      `json:"timestamp"`
+	// Outcome is OutcomeSucceeded for applied revisions and OutcomeFailed for attempts that were
+	// rolled back. Entries written before outcomes were tracked leave it empty.
+	Outcome    string `json:"outcome,omitempty"`
+	Operator   string `json:"operator,omitempty"`
+	WorkflowID string `json:"workflowId,omitempty"`
+}
+
+// Failed reports whether the entry records a failed attempt.
+func (e HistoryEntry) Failed() bool { return e.Outcome == OutcomeFailed }
+
+// Entry summarises the record as a history entry.
+func (r Record) Entry() HistoryEntry {
+	return HistoryEntry{
+		Revision:   r.Revision,
+		Action:     r.LastAction,
+		Chart:      r.Chart,
+		Version:    r.Version,
+		Timestamp:  r.Timestamp,
+		Outcome:    OutcomeSucceeded,
+		Operator:   r.Operator,
+		WorkflowID: r.WorkflowID,
+	}
+}
+
+// WithHistory returns the record with the previous record's history carried forward
+// and its own entry appended. History only carries over for the same release and
+// namespace; records written before history was tracked seed it with their own entry.
+func (r Record) WithHistory(previous *Record) Record {
+	ensureTimestamp(&r)
+
+	var history []HistoryEntry
+	if previous != nil && previous.Release == r.Release && previous.Namespace == r.Namespace {
+		history = append(history, previous.History...)
+		if len(history) == 0 && previous.LastAction != "" {
+			history = append(history, previous.Entry())
+		}
+	}
+	r.History = append(history, r.Entry())
+	return r
+}
+
+// WithFailedAttempt appends a failed attempt to the history while leaving the recorded
+// release untouched, seeding the history with the record's own entry when it has none.
+func (r Record) WithFailedAttempt(attempt HistoryEntry) Record {
+	history := append([]HistoryEntry(nil), r.History...)
+	if len(history) == 0 && r.LastAction != "" {
+		history = append(history, r.Entry())
+	}
+	if attempt.Timestamp == "" {
+		attempt.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+	attempt.Outcome = OutcomeFailed
+	r.History = append(history, attempt)
+	return r
+}
+
+// TrimHistory keeps the newest limit history entries. A limit of zero or less keeps them all.
+func (r Record) TrimHistory(limit int) Record {
+	if limit > 0 && len(r.History) > limit {
+		r.History = append([]HistoryEntry(nil), r.History[len(r.History)-limit:]...)
+	}
+	return r
+}
+
+// FindRevision returns the most recent successful history entry recorded for the revision.
+func (r Record) FindRevision(revision int) (HistoryEntry, bool) {
+	if revision <= 0 {
+		return HistoryEntry{}, false
+	}
+	for i := len(r.History) - 1; i >= 0; i-- {
+		if r.History[i].Revision == revision && !r.History[i].Failed() {
+			return r.History[i], true
+		}
+	}
+	if r.Revision == revision {
+		return r.Entry(), true
+	}
+	return HistoryEntry{}, false
+}
+
+// PreviousRevision returns the latest recorded entry preceding the current revision.
+func (r Record) PreviousRevision() (HistoryEntry, bool) {
+	var (
+		best  HistoryEntry
+		found bool
+	)
+	for _, entry := range r.History {
+		if entry.Revision <= 0 || entry.Failed() || (r.Revision > 0 && entry.Revision >= r.Revision) {
+			continue
+		}
+		if !found || entry.Revision > best.Revision {
+			best, found = entry, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,292 @@
+package state
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultLockStaleAfter is the age after which a lock taken on another host, whose holder
+	// process cannot be probed, is considered abandoned.
+	DefaultLockStaleAfter = time.Hour
+	// DefaultWriteLockTimeout bounds how long Manager waits for another process writing the same state file.
+	DefaultWriteLockTimeout = 30 * time.Second
+
+	lockPollInterval = 200 * time.Millisecond
+)
+
+var (
+	errLocked     = errors.New("state is locked by another chainctl run")
+	errLockFailed = errors.New("state lock could not be acquired")
+)
+
+// ErrLocked exposes the sentinel returned when a lock is held by a live process.
+func ErrLocked() error { return errLocked }
+
+// ErrLockFailed exposes the sentinel returned when the lock file cannot be created or inspected.
+func ErrLockFailed() error { return errLockFailed }
+
+// LockInfo describes the process holding a lock; it is the content of the lock file.
+type LockInfo struct {
+	Key        string    `json:"key,omitempty"`
+	PID        int       `json:"pid"`
+	Host       string    `json:"host"`
+	Operator   string    `json:"operator,omitempty"`
+	WorkflowID string    `json:"workflowId,omitempty"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+}
+
+// String names the holder for error messages.
+func (i LockInfo) String() string {
+	holder := i.Operator
+	if holder == "" {
+		holder = "unknown operator"
+	}
+	details := fmt.Sprintf("pid %d on %s", i.PID, i.Host)
+	if i.WorkflowID != "" {
+		details += ", workflow " + i.WorkflowID
+	}
+	return fmt.Sprintf("%s (%s) since %s", holder, details, i.AcquiredAt.UTC().Format(time.RFC3339))
+}
+
+// LockOptions tunes lock acquisition.
+type LockOptions struct {
+	// Key identifies what the lock protects and is recorded in the lock file.
+	Key        string
+	Operator   string
+	WorkflowID string
+	// Timeout is how long to wait for a live holder; zero fails on the first attempt.
+	Timeout time.Duration
+	// StaleAfter overrides DefaultLockStaleAfter.
+	StaleAfter time.Duration
+}
+
+// FileLock is an advisory lock backed by a file created with O_EXCL.
+type FileLock struct {
+	path string
+}
+
+// Path returns the lock file location.
+func (l *FileLock) Path() string {
+	if l == nil {
+		return ""
+	}
+	return l.path
+}
+
+// Release removes the lock file. Releasing a nil lock is a no-op.
+func (l *FileLock) Release() error {
+	if l == nil || l.path == "" {
+		return nil
+	}
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("release state lock: %w", err)
+	}
+	return nil
+}
+
+// AcquireLock creates the lock file at path, waiting up to opts.Timeout for a live holder to
+// release it. Locks whose holder process no longer runs on this host, or that were taken on
+// another host longer ago than the stale threshold, are broken. The returned error names the
+// holder when the wait times out.
+func AcquireLock(path string, opts LockOptions) (*FileLock, error) {
+	staleAfter := opts.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = DefaultLockStaleAfter
+	}
+	info := LockInfo{
+		Key:        opts.Key,
+		PID:        os.Getpid(),
+		Host:       hostName(),
+		Operator:   firstSet(opts.Operator, OperatorName()),
+		WorkflowID: opts.WorkflowID,
+	}
+	deadline := time.Now().Add(opts.Timeout)
+
+	for {
+		info.AcquiredAt = time.Now().UTC()
+		created, err := createLockFile(path, info)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			return &FileLock{path: path}, nil
+		}
+
+		holder, content, stale, err := inspectLock(path, staleAfter)
+		switch {
+		case err != nil:
+			return nil, err
+		case stale:
+			if err := breakStaleLock(path, content); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, fmt.Errorf("%w: held by %s (lock file %s)", errLocked, holder, path)
+		}
+		time.Sleep(min(remaining, lockPollInterval))
+	}
+}
+
+func createLockFile(path string, info LockInfo) (bool, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("%w: %w", errLockFailed, err)
+	}
+	enc := json.NewEncoder(file)
+	if err := enc.Encode(info); err != nil {
+		file.Close()
+		os.Remove(path)
+		return false, fmt.Errorf("%w: %w", errLockFailed, err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(path)
+		return false, fmt.Errorf("%w: %w", errLockFailed, err)
+	}
+	return true, nil
+}
+
+// inspectLock reads the current holder and its raw lock file content and decides whether the
+// lock was abandoned. Holders on this host are judged by whether their process still runs, so a
+// long workflow keeps its lock; holders on other hosts cannot be probed and go stale after
+// staleAfter. A lock file that cannot be decoded yet is treated as being written unless it is
+// older than staleAfter. A lock file that disappeared is reported as stale with no content.
+func inspectLock(path string, staleAfter time.Duration) (LockInfo, []byte, bool, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return LockInfo{}, nil, true, nil
+		}
+		return LockInfo{}, nil, false, fmt.Errorf("%w: %w", errLockFailed, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return LockInfo{}, nil, true, nil
+		}
+		return LockInfo{}, nil, false, fmt.Errorf("%w: %w", errLockFailed, err)
+	}
+
+	var holder LockInfo
+	if err := json.Unmarshal(data, &holder); err != nil || holder.AcquiredAt.IsZero() {
+		holder = LockInfo{Host: "unknown host", AcquiredAt: stat.ModTime()}
+		return holder, data, time.Since(stat.ModTime()) > staleAfter, nil
+	}
+	if holder.Host == hostName() {
+		return holder, data, !processAlive(holder.PID), nil
+	}
+	return holder, data, time.Since(holder.AcquiredAt) > staleAfter, nil
+}
+
+// breakStaleLock removes the lock file judged stale from content. The file is first renamed to a
+// name unique to this process, so two processes breaking the same lock cannot both succeed, and
+// is only deleted when it still holds content: a lock re-created by another process in the
+// meantime is put back instead. A nil content means the lock file was already gone.
+func breakStaleLock(path string, content []byte) error {
+	if content == nil {
+		return nil
+	}
+	claimed := fmt.Sprintf("%s.stale-%d-%d", path, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(path, claimed); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("%w: break stale lock %s: %w", errLockFailed, path, err)
+	}
+	current, err := os.ReadFile(claimed)
+	if err != nil {
+		return fmt.Errorf("%w: break stale lock %s: %w", errLockFailed, path, err)
+	}
+	if !bytes.Equal(current, content) {
+		// Another process replaced the stale lock before the rename; hand its lock back unless
+		// yet another holder already took the path.
+		if err := os.Link(claimed, path); err != nil && !errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("%w: restore lock %s: %w", errLockFailed, path, err)
+		}
+	}
+	if err := os.Remove(claimed); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: remove stale lock %s: %w", errLockFailed, path, err)
+	}
+	return nil
+}
+
+// processAlive probes a local process with signal 0. Platforms without signal support report
+// every process as alive, so their local locks are only released by their holders.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = process.Signal(syscall.Signal(0))
+	return !errors.Is(err, os.ErrProcessDone) && !errors.Is(err, syscall.ESRCH)
+}
+
+func hostName() string {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		return "unknown host"
+	}
+	return name
+}
+
+// OperatorName identifies the local operator recorded in state and lock files: $USER, falling
+// back to the account name.
+func OperatorName() string {
+	if name := strings.TrimSpace(os.Getenv("USER")); name != "" {
+		return name
+	}
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+	return ""
+}
+
+// Locker hands out workflow locks stored in a shared directory, one file per key.
+type Locker struct {
+	dir string
+}
+
+// NewLocker constructs a Locker that keeps its lock files in dir.
+func NewLocker(dir string) *Locker {
+	return &Locker{dir: dir}
+}
+
+// Acquire takes the lock for key, creating the lock directory with 0700 permissions when needed.
+func (l *Locker) Acquire(key string, opts LockOptions) (*FileLock, error) {
+	if err := os.MkdirAll(l.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("%w: %w", errLockFailed, err)
+	}
+	opts.Key = key
+	return AcquireLock(filepath.Join(l.dir, LockFileName(key)), opts)
+}
+
+// LockFileName derives a filesystem-safe lock file name from a workflow key.
+func LockFileName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:12]) + ".lock"
+}
+
+// WorkflowLockKey identifies the release a mutating workflow targets.
+func WorkflowLockKey(cluster, namespace, release string) string {
+	return cluster + "|" + namespace + "/" + release
+}
@@ -0,0 +1,175 @@
+package state_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	state "github.com/dobrovols/chainctl/pkg/state"
+)
+
+func writeLockFile(t *testing.T, path string, info state.LockInfo) {
+	t.Helper()
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatalf("marshal lock: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write lock: %v", err)
+	}
+}
+
+func TestAcquireLockNamesHolderAndWaitsForRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.json.lock")
+	held, err := state.AcquireLock(path, state.LockOptions{Operator: "alice", WorkflowID: "wf-1"})
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	_, err = state.AcquireLock(path, state.LockOptions{Operator: "bob"})
+	if !errors.Is(err, state.ErrLocked()) {
+		t.Fatalf("expected locked error, got %v", err)
+	}
+	for _, want := range []string{"alice", "wf-1", "pid"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error to name holder (%s), got %v", want, err)
+		}
+	}
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		held.Release()
+	}()
+	lock, err := state.AcquireLock(path, state.LockOptions{Operator: "bob", Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("expected lock after holder released it, got %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected lock file removed, stat returned %v", err)
+	}
+}
+
+func TestAcquireLockBreaksStaleLocks(t *testing.T) {
+	host, err := os.Hostname()
+	if err != nil {
+		t.Skipf("hostname unavailable: %v", err)
+	}
+	exited := exec.Command(os.Args[0], "-test.run=^$")
+	if err := exited.Run(); err != nil {
+		t.Fatalf("run helper process: %v", err)
+	}
+
+	dir := t.TempDir()
+	cases := map[string]state.LockInfo{
+		"dead pid": {PID: exited.Process.Pid, Host: host, Operator: "alice", AcquiredAt: time.Now()},
+		"too old":  {PID: os.Getpid(), Host: "other-host", Operator: "alice", AcquiredAt: time.Now().Add(-2 * time.Hour)},
+	}
+	for name, info := range cases {
+		path := filepath.Join(dir, strings.ReplaceAll(name, " ", "-")+".lock")
+		writeLockFile(t, path, info)
+		lock, err := state.AcquireLock(path, state.LockOptions{})
+		if err != nil {
+			t.Fatalf("%s: expected stale lock to be broken, got %v", name, err)
+		}
+		lock.Release()
+	}
+
+	live := filepath.Join(dir, "live.lock")
+	writeLockFile(t, live, state.LockInfo{PID: os.Getpid(), Host: "other-host", Operator: "alice", AcquiredAt: time.Now()})
+	if _, err := state.AcquireLock(live, state.LockOptions{}); !errors.Is(err, state.ErrLocked()) {
+		t.Fatalf("expected recent remote lock to be honoured, got %v", err)
+	}
+
+	// A long-running local workflow keeps its lock however old it is.
+	longRunning := filepath.Join(dir, "long-running.lock")
+	writeLockFile(t, longRunning, state.LockInfo{PID: os.Getpid(), Host: host, Operator: "alice", AcquiredAt: time.Now().Add(-2 * time.Hour)})
+	if _, err := state.AcquireLock(longRunning, state.LockOptions{}); !errors.Is(err, state.ErrLocked()) {
+		t.Fatalf("expected old lock of a live local process to be honoured, got %v", err)
+	}
+}
+
+func TestAcquireLockBreaksStaleLockOnce(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.json.lock")
+	writeLockFile(t, path, state.LockInfo{PID: 1, Host: "other-host", Operator: "alice", AcquiredAt: time.Now().Add(-2 * time.Hour)})
+
+	const contenders = 8
+	results := make(chan error, contenders)
+	start := make(chan struct{})
+	for i := 0; i < contenders; i++ {
+		go func() {
+			<-start
+			_, err := state.AcquireLock(path, state.LockOptions{Operator: "bob"})
+			results <- err
+		}()
+	}
+	close(start)
+
+	acquired := 0
+	for i := 0; i < contenders; i++ {
+		switch err := <-results; {
+		case err == nil:
+			acquired++
+		case !errors.Is(err, state.ErrLocked()):
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if acquired != 1 {
+		t.Fatalf("expected exactly one contender to break the stale lock, got %d", acquired)
+	}
+	leftovers, err := filepath.Glob(filepath.Join(dir, "*.stale-*"))
+	if err != nil || len(leftovers) != 0 {
+		t.Fatalf("expected no claimed stale lock files left behind, got %v (%v)", leftovers, err)
+	}
+}
+
+func TestManagerWriteRespectsStateFileLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.json")
+	held, err := state.AcquireLock(path+".lock", state.LockOptions{Operator: "alice"})
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer held.Release()
+
+	manager := state.NewManager(&stubResolver{fixedPath: path}, state.WithLockTimeout(0))
+	_, err = manager.Write(sampleRecord("1.2.3"), state.Overrides{})
+	if !errors.Is(err, state.ErrWriteFailed()) || !errors.Is(err, state.ErrLocked()) {
+		t.Fatalf("expected locked write failure, got %v", err)
+	}
+	if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+		t.Fatalf("expected state file untouched, stat returned %v", statErr)
+	}
+}
+
+func TestOperatorNamePrefersUserVariable(t *testing.T) {
+	t.Setenv("USER", " carol ")
+	if got := state.OperatorName(); got != "carol" {
+		t.Fatalf("expected operator from $USER, got %q", got)
+	}
+
+	t.Setenv("USER", "")
+	lock, err := state.AcquireLock(filepath.Join(t.TempDir(), "app.json.lock"), state.LockOptions{})
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer lock.Release()
+	data, err := os.ReadFile(lock.Path())
+	if err != nil {
+		t.Fatalf("read lock: %v", err)
+	}
+	var info state.LockInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		t.Fatalf("decode lock: %v", err)
+	}
+	if info.Operator != state.OperatorName() {
+		t.Fatalf("expected lock to record the account operator %q, got %q", state.OperatorName(), info.Operator)
+	}
+}
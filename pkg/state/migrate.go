@@ -0,0 +1,149 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// BackupSuffix is appended to the state file path to keep the original of a migrated document.
+const BackupSuffix = ".bak"
+
+// Migration upgrades a decoded state document from schema version From to From+1. Migrate
+// receives the document as generic JSON, since older formats do not decode into Document, and
+// must return it with schemaVersion set to From+1.
+type Migration struct {
+	From        int
+	Description string
+	Migrate     func(doc map[string]interface{}) (map[string]interface{}, error)
+}
+
+// migrations lists one step per schema version below SchemaVersion, oldest first. Changing the
+// document format means bumping SchemaVersion and registering the step that upgrades the
+// previous version here.
+var migrations = []Migration{
+	{
+		From:        legacySchemaVersion,
+		Description: "move the single root record into the records list",
+		Migrate:     migrateSingleRecord,
+	},
+}
+
+// Migrations returns the registered migration steps, oldest first.
+func Migrations() []Migration {
+	return append([]Migration(nil), migrations...)
+}
+
+// MigrateDocument upgrades raw state JSON to SchemaVersion by applying every registered step
+// from the document's version onwards. It returns the migrated JSON together with the version
+// the document was stored at; current documents are returned unchanged. Documents written by a
+// newer chainctl are rejected with ErrUnsupportedSchema rather than downgraded.
+func MigrateDocument(data []byte) ([]byte, int, error) {
+	doc, err := decodeGeneric(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	version, err := documentVersion(doc)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version > SchemaVersion {
+		return nil, version, fmt.Errorf("%w: version %d is newer than %d; upgrade chainctl to read it", errUnsupportedSchema, version, SchemaVersion)
+	}
+	if version == SchemaVersion {
+		return data, version, nil
+	}
+
+	for current := version; current < SchemaVersion; current++ {
+		step, ok := migrationFrom(current)
+		if !ok {
+			return nil, version, fmt.Errorf("%w: no migration from version %d", errUnsupportedSchema, current)
+		}
+		if doc, err = step.Migrate(doc); err != nil {
+			return nil, version, fmt.Errorf("migrate state from version %d: %w", current, err)
+		}
+		if next, err := documentVersion(doc); err != nil || next != current+1 {
+			return nil, version, fmt.Errorf("migrate state from version %d: step produced version %d", current, next)
+		}
+	}
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, version, err
+	}
+	return migrated, version, nil
+}
+
+func migrationFrom(version int) (Migration, bool) {
+	for _, step := range migrations {
+		if step.From == version {
+			return step, true
+		}
+	}
+	return Migration{}, false
+}
+
+// documentVersion reports the schemaVersion of a decoded document. Files without one predate
+// versioning and are version 1.
+func documentVersion(doc map[string]interface{}) (int, error) {
+	raw, ok := doc["schemaVersion"]
+	if !ok {
+		return legacySchemaVersion, nil
+	}
+	var version int64
+	switch value := raw.(type) {
+	case json.Number:
+		parsed, err := value.Int64()
+		if err != nil {
+			return 0, fmt.Errorf("%w: schemaVersion %q is not an integer", errUnsupportedSchema, value)
+		}
+		version = parsed
+	case int:
+		version = int64(value)
+	default:
+		return 0, fmt.Errorf("%w: schemaVersion %v is not an integer", errUnsupportedSchema, raw)
+	}
+	if version < legacySchemaVersion {
+		return 0, fmt.Errorf("%w: version %d", errUnsupportedSchema, version)
+	}
+	return int(version), nil
+}
+
+func decodeGeneric(data []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("state document is not a JSON object")
+	}
+	return doc, nil
+}
+
+// migrateSingleRecord upgrades a version 1 file, which holds one record at the root, to a
+// version 2 document. A file without a release or namespace holds no record.
+func migrateSingleRecord(doc map[string]interface{}) (map[string]interface{}, error) {
+	records := []interface{}{}
+	if doc["release"] != nil || doc["namespace"] != nil {
+		delete(doc, "schemaVersion")
+		records = append(records, doc)
+	}
+	return map[string]interface{}{
+		"schemaVersion": legacySchemaVersion + 1,
+		"records":       records,
+	}, nil
+}
+
+// backupOriginal keeps the pre-migration contents of the state file at path+BackupSuffix before
+// the migrated document replaces it.
+func (m *Manager) backupOriginal(path string, original []byte) error {
+	if err := os.WriteFile(path+BackupSuffix, original, m.filePerm); err != nil {
+		return fmt.Errorf("%w: back up state before migration: %w", errWriteFailed, err)
+	}
+	if err := os.Chmod(path+BackupSuffix, m.filePerm); err != nil {
+		return fmt.Errorf("%w: %w", errWriteFailed, err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package state_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	state "github.com/dobrovols/chainctl/pkg/state"
+)
+
+const legacyRecordJSON = `{"release":"myapp","namespace":"demo","chart":{"type":"oci","reference":"oci://registry.example.com/apps/myapp:1.0.0"},` +
+	`"version":"1.0.0","lastAction":"install","timestamp":"2025-01-01T00:00:00Z","revision":1}`
+
+func TestMigrateDocumentUpgradesLegacyRecord(t *testing.T) {
+	migrated, from, err := state.MigrateDocument([]byte(legacyRecordJSON))
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if from != 1 {
+		t.Fatalf("expected legacy file to be version 1, got %d", from)
+	}
+	var doc state.Document
+	if err := json.Unmarshal(migrated, &doc); err != nil {
+		t.Fatalf("decode migrated document: %v", err)
+	}
+	if doc.SchemaVersion != state.SchemaVersion || len(doc.Records) != 1 || doc.Records[0].Revision != 1 {
+		t.Fatalf("unexpected migrated document %s", migrated)
+	}
+
+	current := []byte(`{"schemaVersion":2,"records":[]}`)
+	unchanged, from, err := state.MigrateDocument(current)
+	if err != nil || from != state.SchemaVersion || string(unchanged) != string(current) {
+		t.Fatalf("expected current document unchanged, got %s (%d, %v)", unchanged, from, err)
+	}
+}
+
+func TestMigrateDocumentRefusesDowngradeAndUnknownVersions(t *testing.T) {
+	for _, input := range []string{
+		`{"schemaVersion":3,"records":[]}`,
+		`{"schemaVersion":0,"records":[]}`,
+		`{"schemaVersion":"2","records":[]}`,
+		`{"schemaVersion":1.5,"records":[]}`,
+	} {
+		if _, _, err := state.MigrateDocument([]byte(input)); !errors.Is(err, state.ErrUnsupportedSchema()) {
+			t.Fatalf("expected unsupported schema for %s, got %v", input, err)
+		}
+	}
+}
+
+func TestMigrationsCoverEveryOlderVersion(t *testing.T) {
+	steps := state.Migrations()
+	if len(steps) != state.SchemaVersion-1 {
+		t.Fatalf("expected %d migration steps, got %d", state.SchemaVersion-1, len(steps))
+	}
+	for i, step := range steps {
+		if step.From != i+1 || step.Migrate == nil || step.Description == "" {
+			t.Fatalf("migration %d is not registered in order: %+v", i, step)
+		}
+	}
+}
+
+func TestManagerBacksUpOriginalWhenPersistingMigration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.json")
+	if err := os.WriteFile(path, []byte(legacyRecordJSON), 0o600); err != nil {
+		t.Fatalf("write legacy state: %v", err)
+	}
+	manager := state.NewManager(&stubResolver{fixedPath: path})
+
+	if _, err := manager.Read(state.Overrides{}); err != nil {
+		t.Fatalf("read legacy state: %v", err)
+	}
+	if _, err := os.Stat(path + state.BackupSuffix); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("reads must not touch the state file, got backup stat %v", err)
+	}
+
+	if _, err := manager.Write(releaseRecord("other", "staging", "2.0.0"), state.Overrides{}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	backup, err := os.ReadFile(path + state.BackupSuffix)
+	if err != nil || string(backup) != legacyRecordJSON {
+		t.Fatalf("expected original kept in backup, got %q (%v)", backup, err)
+	}
+	if info, err := os.Stat(path + state.BackupSuffix); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected backup with 0600 permissions, got %v (%v)", info, err)
+	}
+
+	if err := os.Remove(path + state.BackupSuffix); err != nil {
+		t.Fatalf("remove backup: %v", err)
+	}
+	if _, err := manager.Write(releaseRecord("other", "staging", "2.1.0"), state.Overrides{}); err != nil {
+		t.Fatalf("second write: %v", err)
+	}
+	if _, err := os.Stat(path + state.BackupSuffix); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no backup for a current document, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package state
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"sync"
+
+	jsonschema "github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// schemaJSON mirrors specs/002-oci-helm-state/contracts/state-schema.json; a contract test keeps
+// the two in sync.
+//
+//go:embed state-schema.json
+var schemaJSON []byte
+
+var (
+	errSchemaViolation = errors.New("state document does not match the state schema")
+
+	compileSchemaOnce sync.Once
+	compiledSchema    *jsonschema.Schema
+	compileSchemaErr  error
+)
+
+// ErrSchemaViolation exposes the sentinel returned for state JSON rejected by the schema.
+func ErrSchemaViolation() error { return errSchemaViolation }
+
+// Schema returns the JSON schema state files are validated against.
+func Schema() []byte {
+	return append([]byte(nil), schemaJSON...)
+}
+
+// ValidateDocument checks raw state JSON, either a document or a single-record file, against Schema.
+func ValidateDocument(data []byte) error {
+	schema, err := stateSchema()
+	if err != nil {
+		return err
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: %w", errSchemaViolation, err)
+	}
+	if err := schema.Validate(instance); err != nil {
+		return fmt.Errorf("%w: %w", errSchemaViolation, err)
+	}
+	return nil
+}
+
+func stateSchema() (*jsonschema.Schema, error) {
+	compileSchemaOnce.Do(func() {
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaJSON))
+		if err != nil {
+			compileSchemaErr = fmt.Errorf("decode state schema: %w", err)
+			return
+		}
+		compiler := jsonschema.NewCompiler()
+		if err := compiler.AddResource("state-schema.json", doc); err != nil {
+			compileSchemaErr = fmt.Errorf("load state schema: %w", err)
+			return
+		}
+		compiledSchema, compileSchemaErr = compiler.Compile("state-schema.json")
+	})
+	return compiledSchema, compileSchemaErr
+}
@@ -0,0 +1,308 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	stateSecretPrefix    = "chainctl-state-"
+	secretDataKeyRecord  = "record"
+	labelManagedBy       = "app.kubernetes.io/managed-by"
+	managedByValue       = "chainctl"
+	labelComponent       = "chainctl.io/component"
+	componentState       = "state"
+	labelRelease         = "chainctl.io/release"
+	secretRequestTimeout = 10 * time.Second
+)
+
+var (
+	errConflict               = errors.New("state was modified concurrently; re-run the command to pick up the latest record")
+	errSecretNamespaceMissing = errors.New("namespace is required to locate state in the secret backend")
+	errAmbiguousState         = errors.New("multiple releases match the recorded state; specify the release name and namespace")
+)
+
+// ErrConflict exposes the optimistic concurrency failure sentinel.
+func ErrConflict() error { return errConflict }
+
+// ErrSecretNamespaceMissing exposes the missing namespace sentinel of the secret backend.
+func ErrSecretNamespaceMissing() error { return errSecretNamespaceMissing }
+
+// ErrAmbiguousState exposes the sentinel returned when a namespace holds state for several releases.
+func ErrAmbiguousState() error { return errAmbiguousState }
+
+// SecretStore keeps one record per release in a labelled Secret in the release namespace, so
+// every operator with cluster access sees the same state. Writes use the resourceVersion observed
+// by the preceding Read (or the latest one when nothing was read) and fail with ErrConflict when
+// another writer changed the Secret in between.
+type SecretStore struct {
+	client       kubernetes.Interface
+	historyLimit int
+
+	mu       sync.Mutex
+	observed map[string]string
+}
+
+// SecretStoreOption customises a SecretStore.
+type SecretStoreOption func(*SecretStore)
+
+// WithSecretHistoryLimit caps the history entries retained in each release Secret, dropping the
+// oldest first. A limit of zero or less keeps the full history.
+func WithSecretHistoryLimit(limit int) SecretStoreOption {
+	return func(s *SecretStore) {
+		s.historyLimit = limit
+	}
+}
+
+// NewSecretStore constructs a Secret-backed state store. History is capped at
+// DefaultHistoryLimit entries unless WithSecretHistoryLimit says otherwise.
+func NewSecretStore(client kubernetes.Interface, opts ...SecretStoreOption) *SecretStore {
+	s := &SecretStore{
+		client:       client,
+		historyLimit: DefaultHistoryLimit,
+		observed:     map[string]string{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// StateSecretName returns the name of the Secret holding the release's state.
+func StateSecretName(release string) string {
+	return stateSecretPrefix + release
+}
+
+// Read loads the record for overrides.Release in overrides.Namespace. Without a release, the
+// namespace must hold state for exactly one release.
+func (s *SecretStore) Read(overrides Overrides) (Record, error) {
+	namespace := strings.TrimSpace(overrides.Namespace)
+	if namespace == "" {
+		return Record{}, errSecretNamespaceMissing
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretRequestTimeout)
+	defer cancel()
+
+	secret, err := s.find(ctx, namespace, strings.TrimSpace(overrides.Release))
+	if err != nil {
+		return Record{}, err
+	}
+	record, err := decodeSecretRecord(secret)
+	if err != nil {
+		return Record{}, err
+	}
+	s.observe(secret)
+	return record, nil
+}
+
+// Write stores the record in the Secret of its release and returns the Secret location. Records
+// without history are appended to the stored history, as with Manager.Write.
+func (s *SecretStore) Write(record Record, overrides Overrides) (string, error) {
+	namespace := firstSet(record.Namespace, overrides.Namespace)
+	release := firstSet(record.Release, overrides.Release)
+	if namespace == "" {
+		return "", errSecretNamespaceMissing
+	}
+	if release == "" {
+		return "", fmt.Errorf("%w: record has no release name", errWriteFailed)
+	}
+	ensureTimestamp(&record)
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretRequestTimeout)
+	defer cancel()
+
+	secrets := s.client.CoreV1().Secrets(namespace)
+	name := StateSecretName(release)
+	location := secretLocation(namespace, name)
+
+	current, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	exists := err == nil
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("%w: get %s: %w", errWriteFailed, location, err)
+	}
+	if version, seen := s.observedVersion(namespace, name); seen && (!exists || current.ResourceVersion != version) {
+		return "", fmt.Errorf("%w: %s", errConflict, location)
+	}
+
+	if exists {
+		// Decode the stored payload even when the record brings its own history, so a Secret
+		// written by a newer chainctl is never overwritten with an older schema.
+		previous, err := decodeSecretRecord(current)
+		switch {
+		case errors.Is(err, errUnsupportedSchema) || (err != nil && len(record.History) == 0):
+			return "", fmt.Errorf("%w: %w", errWriteFailed, err)
+		case len(record.History) == 0:
+			record = record.WithHistory(&previous)
+		}
+	} else if len(record.History) == 0 {
+		record = record.WithHistory(nil)
+	}
+	record = record.TrimHistory(s.historyLimit)
+
+	payload, err := json.Marshal(Document{SchemaVersion: SchemaVersion, Records: []Record{record}})
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errWriteFailed, err)
+	}
+
+	var stored *corev1.Secret
+	if exists {
+		current.Labels = stateSecretLabels(current.Labels, release)
+		current.Data = map[string][]byte{secretDataKeyRecord: payload}
+		stored, err = secrets.Update(ctx, current, metav1.UpdateOptions{})
+	} else {
+		stored, err = secrets.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    stateSecretLabels(nil, release),
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{secretDataKeyRecord: payload},
+		}, metav1.CreateOptions{})
+	}
+	switch {
+	case apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err):
+		return "", fmt.Errorf("%w: %s", errConflict, location)
+	case err != nil:
+		return "", fmt.Errorf("%w: %s: %w", errWriteFailed, location, err)
+	}
+	s.observe(stored)
+	return location, nil
+}
+
+// Remove deletes the release's state Secret, guarded by the observed resourceVersion.
+func (s *SecretStore) Remove(overrides Overrides) (string, error) {
+	namespace := strings.TrimSpace(overrides.Namespace)
+	if namespace == "" {
+		return "", errSecretNamespaceMissing
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretRequestTimeout)
+	defer cancel()
+
+	secret, err := s.find(ctx, namespace, strings.TrimSpace(overrides.Release))
+	if err != nil {
+		return "", err
+	}
+	location := secretLocation(namespace, secret.Name)
+	version := secret.ResourceVersion
+	if seen, ok := s.observedVersion(namespace, secret.Name); ok {
+		version = seen
+	}
+
+	opts := metav1.DeleteOptions{}
+	if version != "" {
+		opts.Preconditions = &metav1.Preconditions{ResourceVersion: &version}
+	}
+	err = s.client.CoreV1().Secrets(namespace).Delete(ctx, secret.Name, opts)
+	switch {
+	case apierrors.IsNotFound(err):
+		return location, fmt.Errorf("%w: %s", errStateNotFound, location)
+	case apierrors.IsConflict(err):
+		return location, fmt.Errorf("%w: %s", errConflict, location)
+	case err != nil:
+		return location, fmt.Errorf("%w: %w", errWriteFailed, err)
+	}
+
+	s.mu.Lock()
+	delete(s.observed, namespace+"/"+secret.Name)
+	s.mu.Unlock()
+	return location, nil
+}
+
+func (s *SecretStore) find(ctx context.Context, namespace, release string) (*corev1.Secret, error) {
+	secrets := s.client.CoreV1().Secrets(namespace)
+	if release != "" {
+		name := StateSecretName(release)
+		secret, err := secrets.Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			return nil, fmt.Errorf("%w: %s", errStateNotFound, secretLocation(namespace, name))
+		case err != nil:
+			return nil, fmt.Errorf("%w: %w", errReadFailed, err)
+		}
+		return secret, nil
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{labelManagedBy: managedByValue, labelComponent: componentState})
+	list, err := secrets.List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errReadFailed, err)
+	}
+	switch len(list.Items) {
+	case 0:
+		return nil, fmt.Errorf("%w: no state secrets in namespace %s", errStateNotFound, namespace)
+	case 1:
+		return &list.Items[0], nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errAmbiguousState, namespace)
+	}
+}
+
+func (s *SecretStore) observe(secret *corev1.Secret) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.observed[secret.Namespace+"/"+secret.Name] = secret.ResourceVersion
+}
+
+func (s *SecretStore) observedVersion(namespace, name string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	version, ok := s.observed[namespace+"/"+name]
+	return version, ok
+}
+
+// decodeSecretRecord reads the single-record state document stored in a Secret. Payloads written
+// before the Secret was versioned hold a bare record and are migrated like a schema version 1
+// state file.
+func decodeSecretRecord(secret *corev1.Secret) (Record, error) {
+	location := secretLocation(secret.Namespace, secret.Name)
+	raw, ok := secret.Data[secretDataKeyRecord]
+	if !ok {
+		return Record{}, fmt.Errorf("%w: %s has no %q key", errReadFailed, location, secretDataKeyRecord)
+	}
+	doc, _, err := decodeStored(raw)
+	if err != nil {
+		return Record{}, fmt.Errorf("%w: decode %s: %w", errReadFailed, location, err)
+	}
+	if len(doc.Records) != 1 {
+		return Record{}, fmt.Errorf("%w: %s holds %d records, expected 1", errReadFailed, location, len(doc.Records))
+	}
+	return doc.Records[0], nil
+}
+
+func stateSecretLabels(existing map[string]string, release string) map[string]string {
+	out := make(map[string]string, len(existing)+3)
+	for k, v := range existing {
+		out[k] = v
+	}
+	out[labelManagedBy] = managedByValue
+	out[labelComponent] = componentState
+	out[labelRelease] = release
+	return out
+}
+
+func secretLocation(namespace, name string) string {
+	return "secret://" + namespace + "/" + name
+}
+
+func firstSet(values ...string) string {
+	for _, v := range values {
+		if trimmed := strings.TrimSpace(v); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+package state_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	state "github.com/dobrovols/chainctl/pkg/state"
+)
+
+// versionedClient bumps the resourceVersion on every write, as the API server does.
+func versionedClient() *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	version := 0
+	client.PrependReactor("*", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetVerb() != "create" && action.GetVerb() != "update" {
+			return false, nil, nil
+		}
+		if secret, ok := action.(k8stesting.CreateAction).GetObject().(*corev1.Secret); ok {
+			version++
+			secret.ResourceVersion = strconv.Itoa(version)
+		}
+		return false, nil, nil
+	})
+	return client
+}
+
+func TestSecretStoreRoundTripsRecordsWithHistory(t *testing.T) {
+	client := versionedClient()
+	store := state.NewSecretStore(client)
+
+	location, err := store.Write(sampleRecord("1.2.3"), state.Overrides{})
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if location != "secret://demo/chainctl-state-myapp" {
+		t.Fatalf("unexpected location %s", location)
+	}
+	secret, err := client.CoreV1().Secrets("demo").Get(context.Background(), state.StateSecretName("myapp"), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get secret: %v", err)
+	}
+	if secret.Labels["app.kubernetes.io/managed-by"] != "chainctl" || secret.Labels["chainctl.io/release"] != "myapp" {
+		t.Fatalf("expected labelled secret, got %v", secret.Labels)
+	}
+
+	// A second operator discovers the release from the namespace alone and appends to its history.
+	other := state.NewSecretStore(client)
+	previous, err := other.Read(state.Overrides{Namespace: "demo"})
+	if err != nil || previous.Version != "1.2.3" {
+		t.Fatalf("expected recorded release, got %+v (%v)", previous, err)
+	}
+	if _, err := other.Write(sampleRecord("1.3.0"), state.Overrides{}); err != nil {
+		t.Fatalf("second write: %v", err)
+	}
+	loaded, err := other.Read(state.Overrides{Release: "myapp", Namespace: "demo"})
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if loaded.Version != "1.3.0" || len(loaded.History) != 2 || loaded.History[0].Version != "1.2.3" {
+		t.Fatalf("expected history carried across writes, got %+v", loaded)
+	}
+
+	if _, err := other.Remove(state.Overrides{Release: "myapp", Namespace: "demo"}); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, err := other.Read(state.Overrides{Release: "myapp", Namespace: "demo"}); !errors.Is(err, state.ErrNotFound()) {
+		t.Fatalf("expected not found after remove, got %v", err)
+	}
+}
+
+func TestSecretStoreAppliesHistoryLimit(t *testing.T) {
+	store := state.NewSecretStore(versionedClient(), state.WithSecretHistoryLimit(2))
+
+	for _, version := range []string{"1.0.0", "1.1.0", "1.2.0"} {
+		if _, err := store.Write(sampleRecord(version), state.Overrides{}); err != nil {
+			t.Fatalf("write %s: %v", version, err)
+		}
+	}
+	loaded, err := store.Read(state.Overrides{Release: "myapp", Namespace: "demo"})
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(loaded.History) != 2 || loaded.History[0].Version != "1.1.0" || loaded.History[1].Version != "1.2.0" {
+		t.Fatalf("expected history capped at the two newest entries, got %+v", loaded.History)
+	}
+}
+
+func TestSecretStoreRejectsConcurrentWrites(t *testing.T) {
+	client := versionedClient()
+	if _, err := state.NewSecretStore(client).Write(sampleRecord("1.2.3"), state.Overrides{}); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	first, second := state.NewSecretStore(client), state.NewSecretStore(client)
+	overrides := state.Overrides{Release: "myapp", Namespace: "demo"}
+	for _, store := range []*state.SecretStore{first, second} {
+		if _, err := store.Read(overrides); err != nil {
+			t.Fatalf("read: %v", err)
+		}
+	}
+	if _, err := second.Write(sampleRecord("1.3.0"), overrides); err != nil {
+		t.Fatalf("second write: %v", err)
+	}
+	if _, err := first.Write(sampleRecord("1.4.0"), overrides); !errors.Is(err, state.ErrConflict()) {
+		t.Fatalf("expected conflict for stale writer, got %v", err)
+	}
+}
+
+func TestSecretStoreRequiresNamespaceAndUniqueRelease(t *testing.T) {
+	client := versionedClient()
+	store := state.NewSecretStore(client)
+	if _, err := store.Read(state.Overrides{}); !errors.Is(err, state.ErrSecretNamespaceMissing()) {
+		t.Fatalf("expected namespace error, got %v", err)
+	}
+	for _, release := range []string{"one", "two"} {
+		record := sampleRecord("1.0.0")
+		record.Release = release
+		if _, err := store.Write(record, state.Overrides{}); err != nil {
+			t.Fatalf("write %s: %v", release, err)
+		}
+	}
+	if _, err := store.Read(state.Overrides{Namespace: "demo"}); !errors.Is(err, state.ErrAmbiguousState()) {
+		t.Fatalf("expected ambiguous state error, got %v", err)
+	}
+	if err := state.ValidateBackend("etcd"); !errors.Is(err, state.ErrUnknownBackend()) {
+		t.Fatalf("expected unknown backend error, got %v", err)
+	}
+}
@@ -1,7 +1,6 @@
 package state
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -10,22 +9,51 @@ import (
 	"time"
 )
 
+// Chart source types recorded in ChartSource.Type.
+const (
+	SourceTypeOCI    = "oci"
+	SourceTypeRepo   = "repo"
+	SourceTypeDir    = "dir"
+	SourceTypeBundle = "bundle"
+)
+
 // ChartSource captures the origin of the Helm chart applied during install/update.
 type ChartSource struct {
 	Type      string `json:"type"`
 	Reference string `json:"reference"`
-	Digest    string `json:"digest,omitempty"`
+	// Version is the chart version that was resolved from the source.
+	Version string `json:"version,omitempty"`
+	Digest  string `json:"digest,omitempty"`
+}
+
+// ChartVerification records how the applied chart's signature was verified.
+type ChartVerification struct {
+	Method string `json:"method"`
+	Signer string `json:"signer,omitempty"`
+	KeyID  string `json:"keyId,omitempty"`
 }
 
 // Record stores the last successful install or update metadata for the application.
 type Record struct {
-	Release         string      `json:"release"`
-	Namespace       string      `json:"namespace"`
-	Chart           ChartSource `json:"chart"`
-	Version         string      `json:"version"`
-	LastAction      string      `json:"lastAction"`
-	Timestamp       string      `json:"timestamp"`
-	ClusterEndpoint string      `json:"clusterEndpoint,omitempty"`
+	Release         string             `json:"release"`
+	Namespace       string             `json:"namespace"`
+	Chart           ChartSource        `json:"chart"`
+	Verification    *ChartVerification `json:"verification,omitempty"`
+	Version         string             `json:"version"`
+	LastAction      string             `json:"lastAction"`
+	Timestamp       string             `json:"timestamp"`
+	ClusterEndpoint string             `json:"clusterEndpoint,omitempty"`
+	Revision        int                `json:"revision,omitempty"`
+	Status          string             `json:"status,omitempty"`
+	Notes           string             `json:"notes,omitempty"`
+	// ValuesHash digests the user-supplied values stored on the applied Helm release.
+	ValuesHash string `json:"valuesHash,omitempty"`
+	// Operator and WorkflowID identify who applied the record and the CLI run that wrote it.
+	Operator   string         `json:"operator,omitempty"`
+	WorkflowID string         `json:"workflowId,omitempty"`
+	History    []HistoryEntry `json:"history,omitempty"`
+	// Failure is set while the latest install or upgrade of the release is an unresolved failure.
+	Failure *Failure `json:"failure,omitempty"`
 }
 
 // Overrides defines user-supplied preferences for the state file location.
@@ -33,6 +61,24 @@ type Overrides struct {
 	StateDirectory string
 	StateFileName  string
 	StateFilePath  string
+	// Release, Namespace, and ClusterEndpoint select the record within the state document; empty
+	// fields match any record. SecretStore locates its Secret by Release and Namespace alone.
+	Release         string
+	Namespace       string
+	ClusterEndpoint string
+}
+
+// Backend names accepted by --state-backend.
+const (
+	BackendFile   = "file"
+	BackendSecret = "secret"
+)
+
+// Store is implemented by every state backend.
+type Store interface {
+	Write(Record, Overrides) (string, error)
+	Read(Overrides) (Record, error)
+	Remove(Overrides) (string, error)
 }
 
 // PathResolver resolves the effective filesystem path for the state file.
@@ -42,29 +88,80 @@ type PathResolver interface {
 
 // Manager coordinates persistence of application state records.
 type Manager struct {
-	resolver PathResolver
-	dirPerm  os.FileMode
-	filePerm os.FileMode
+	resolver     PathResolver
+	dirPerm      os.FileMode
+	filePerm     os.FileMode
+	historyLimit int
+	lockTimeout  time.Duration
+	passphrase   PassphraseSource
+}
+
+// ManagerOption customises a Manager.
+type ManagerOption func(*Manager)
+
+// WithHistoryLimit caps the history entries retained in the state file, dropping the oldest
+// first. A limit of zero or less keeps the full history.
+func WithHistoryLimit(limit int) ManagerOption {
+	return func(m *Manager) {
+		m.historyLimit = limit
+	}
+}
+
+// WithLockTimeout bounds how long Write and Remove wait for another process holding the state
+// file lock.
+func WithLockTimeout(timeout time.Duration) ManagerOption {
+	return func(m *Manager) {
+		m.lockTimeout = timeout
+	}
 }
 
 var (
 	errPathResolverMissing = errors.New("state path resolver not configured")
 	errEmptyStatePath      = errors.New("resolved state file path empty")
 	errWriteFailed         = errors.New("state file could not be written")
+	errReadFailed          = errors.New("state file could not be read")
+	errStateNotFound       = errors.New("state file not found")
+	errUnknownBackend      = errors.New("unknown state backend")
 )
 
-// NewManager constructs a Manager with the provided resolver.
-func NewManager(resolver PathResolver) *Manager {
-	return &Manager{
-		resolver: resolver,
-		dirPerm:  0o700,
-		filePerm: 0o600,
+// NewManager constructs a Manager with the provided resolver. History is capped at
+// DefaultHistoryLimit entries unless WithHistoryLimit says otherwise.
+func NewManager(resolver PathResolver, opts ...ManagerOption) *Manager {
+	m := &Manager{
+		resolver:     resolver,
+		dirPerm:      0o700,
+		filePerm:     0o600,
+		historyLimit: DefaultHistoryLimit,
+		lockTimeout:  DefaultWriteLockTimeout,
+	}
+	for _, opt := range opts {
+		opt(m)
 	}
+	return m
 }
 
 // ErrWriteFailed exposes the write failure sentinel.
 func ErrWriteFailed() error { return errWriteFailed }
 
+// ErrReadFailed exposes the read failure sentinel.
+func ErrReadFailed() error { return errReadFailed }
+
+// ErrNotFound exposes the missing state file sentinel.
+func ErrNotFound() error { return errStateNotFound }
+
+// ErrUnknownBackend exposes the unsupported state backend sentinel.
+func ErrUnknownBackend() error { return errUnknownBackend }
+
+// ValidateBackend accepts the backend names understood by --state-backend; empty selects the file backend.
+func ValidateBackend(name string) error {
+	switch name {
+	case "", BackendFile, BackendSecret:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q (expected %s or %s)", errUnknownBackend, name, BackendFile, BackendSecret)
+	}
+}
+
 func (m *Manager) resolvePath(overrides Overrides) (string, error) {
 	if m == nil || m.resolver == nil {
 		return "", errPathResolverMissing
@@ -79,7 +176,11 @@ func (m *Manager) resolvePath(overrides Overrides) (string, error) {
 	return path, nil
 }
 
-// Write persists the provided record to the resolved state path.
+// Write persists the provided record to the resolved state path. A record without history is
+// appended to the history of the record already stored there, so earlier deployments are kept;
+// records that already carry history are written as given. History is trimmed to the retention
+// limit before the file is replaced atomically. The read-modify-write runs under an advisory
+// lock on <path>.lock so concurrent processes cannot drop each other's history entries.
 func (m *Manager) Write(record Record, overrides Overrides) (string, error) {
 	path, err := m.resolvePath(overrides)
 	if err != nil {
@@ -91,14 +192,178 @@ func (m *Manager) Write(record Record, overrides Overrides) (string, error) {
 	if err := m.ensureDirectory(dir); err != nil {
 		return "", err
 	}
+	lock, err := m.lock(path)
+	if err != nil {
+		return "", err
+	}
+	defer lock.Release()
+
+	doc, original, err := m.readStateFile(path)
+	if err != nil && !errors.Is(err, errStateNotFound) {
+		return "", fmt.Errorf("%w: %w", errWriteFailed, err)
+	}
+	// The record replaces the one stored for the same release and continues its history.
+	previous, found := doc.superseded(record)
+	if len(record.History) == 0 {
+		if found {
+			record = record.WithHistory(&previous)
+		} else {
+			record = record.WithHistory(nil)
+		}
+	}
+	doc.Put(record.TrimHistory(m.historyLimit))
+	doc.SchemaVersion = SchemaVersion
 
-	if err := m.writeStateFile(dir, path, record); err != nil {
+	if original != nil {
+		if err := m.backupOriginal(path, original); err != nil {
+			return "", err
+		}
+	}
+	if err := m.writeStateFile(dir, path, doc); err != nil {
 		return "", err
 	}
 
 	return path, nil
 }
 
+// Read loads the record matching the overrides from the state document at the resolved path.
+// Without release selectors, a document holding a single record returns it; several records
+// yield ErrAmbiguousState.
+func (m *Manager) Read(overrides Overrides) (Record, error) {
+	path, err := m.resolvePath(overrides)
+	if err != nil {
+		return Record{}, err
+	}
+	doc, err := m.readDocument(path)
+	if err != nil {
+		return Record{}, err
+	}
+	return findRecord(doc, overrides, path)
+}
+
+// List returns the records matching the overrides, or every record when none are set. A missing
+// state file yields no records.
+func (m *Manager) List(overrides Overrides) ([]Record, error) {
+	path, err := m.resolvePath(overrides)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := m.readDocument(path)
+	switch {
+	case errors.Is(err, errStateNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	return doc.Select(overrides), nil
+}
+
+// Lookup returns the record stored under exactly the given key.
+func (m *Manager) Lookup(key RecordKey, overrides Overrides) (Record, error) {
+	path, err := m.resolvePath(overrides)
+	if err != nil {
+		return Record{}, err
+	}
+	doc, err := m.readDocument(path)
+	if err != nil {
+		return Record{}, err
+	}
+	record, ok := doc.Lookup(key)
+	if !ok {
+		return Record{}, fmt.Errorf("%w: %s in %s", errStateNotFound, key, path)
+	}
+	return record, nil
+}
+
+func findRecord(doc Document, overrides Overrides, path string) (Record, error) {
+	record, err := doc.Find(overrides)
+	if err != nil {
+		return Record{}, fmt.Errorf("%w: %s", err, path)
+	}
+	return record, nil
+}
+
+// Remove deletes the record matching the overrides from the state document and returns the
+// state file path. The file itself is deleted once its last record is removed.
+func (m *Manager) Remove(overrides Overrides) (string, error) {
+	return m.Update(overrides, func(doc *Document) error {
+		record, err := doc.Find(overrides)
+		if err != nil {
+			return err
+		}
+		doc.Delete(record.Key())
+		return nil
+	})
+}
+
+// Load returns the state document at the resolved path together with that path.
+func (m *Manager) Load(overrides Overrides) (Document, string, error) {
+	path, err := m.resolvePath(overrides)
+	if err != nil {
+		return Document{}, "", err
+	}
+	doc, err := m.readDocument(path)
+	return doc, path, err
+}
+
+// Update applies fn to the state document under the state file lock and writes the result,
+// starting from an empty document when the file does not exist. The file is deleted once no
+// records remain. Errors returned by fn abort the update and are annotated with the path.
+func (m *Manager) Update(overrides Overrides, fn func(*Document) error) (string, error) {
+	path, err := m.resolvePath(overrides)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Dir(path)
+	if err := m.ensureDirectory(dir); err != nil {
+		return path, err
+	}
+	lock, err := m.lock(path)
+	if err != nil {
+		return path, err
+	}
+	defer lock.Release()
+
+	doc, original, err := m.readStateFile(path)
+	exists := err == nil
+	if err != nil && !errors.Is(err, errStateNotFound) {
+		return path, err
+	}
+	if err := fn(&doc); err != nil {
+		return path, fmt.Errorf("%w: %s", err, path)
+	}
+
+	if len(doc.Records) > 0 {
+		for i := range doc.Records {
+			doc.Records[i] = doc.Records[i].TrimHistory(m.historyLimit)
+		}
+		doc.SchemaVersion = SchemaVersion
+		doc.sort()
+		if original != nil {
+			if err := m.backupOriginal(path, original); err != nil {
+				return path, err
+			}
+		}
+		return path, m.writeStateFile(dir, path, doc)
+	}
+	if !exists {
+		return path, nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return path, fmt.Errorf("%w: %w", errWriteFailed, err)
+	}
+	return path, nil
+}
+
+// lock takes the advisory lock guarding the state file at path.
+func (m *Manager) lock(path string) (*FileLock, error) {
+	lock, err := AcquireLock(path+".lock", LockOptions{Key: path, Timeout: m.lockTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errWriteFailed, err)
+	}
+	return lock, nil
+}
+
 func ensureTimestamp(record *Record) {
 	if record.Timestamp == "" {
 		record.Timestamp = time.Now().UTC().Format(time.RFC3339)
@@ -123,7 +388,12 @@ func (m *Manager) ensureDirectory(dir string) error {
 	return nil
 }
 
-func (m *Manager) writeStateFile(dir, path string, record Record) error {
+func (m *Manager) writeStateFile(dir, path string, doc Document) error {
+	data, err := m.encodeFile(doc)
+	if err != nil {
+		return err
+	}
+
 	tmp, err := os.CreateTemp(dir, "state-*.json")
 	if err != nil {
 		return fmt.Errorf("%w: %w", errWriteFailed, err)
@@ -135,9 +405,9 @@ func (m *Manager) writeStateFile(dir, path string, record Record) error {
 		return fmt.Errorf("%w: %w", errWriteFailed, err)
 	}
 
-	if err := encodeJSON(tmp, record); err != nil {
+	if _, err := tmp.Write(data); err != nil {
 		tmp.Close()
-		return err
+		return fmt.Errorf("%w: %w", errWriteFailed, err)
 	}
 
 	if err := tmp.Close(); err != nil {
@@ -153,12 +423,3 @@ func (m *Manager) writeStateFile(dir, path string, record Record) error {
 	}
 	return nil
 }
-
-func encodeJSON(file *os.File, record Record) error {
-	enc := json.NewEncoder(file)
-	enc.SetEscapeHTML(false)
-	if err := enc.Encode(record); err != nil {
-		return fmt.Errorf("%w: %w", errWriteFailed, err)
-	}
-	return nil
-}
@@ -61,16 +61,23 @@ func sampleRecord(version string) state.Record {
 	}
 }
 
+// readJSON returns the only record stored in the state document at path.
 func readJSON(t *testing.T, path string) map[string]any {
 	bytes, err := os.ReadFile(path)
 	if err != nil {
 		t.Fatalf("read file: %v", err)
 	}
-	var payload map[string]any
+	var payload struct {
+		SchemaVersion int              `json:"schemaVersion"`
+		Records       []map[string]any `json:"records"`
+	}
 	if err := json.Unmarshal(bytes, &payload); err != nil {
 		t.Fatalf("unmarshal json: %v", err)
 	}
-	return payload
+	if payload.SchemaVersion != state.SchemaVersion || len(payload.Records) != 1 {
+		t.Fatalf("expected a schema %d document with one record, got %s", state.SchemaVersion, bytes)
+	}
+	return payload.Records[0]
 }
 
 func TestManagerCreatesStateFileWithDefaultDirectory(t *testing.T) {
@@ -241,3 +248,154 @@ func TestManagerSetsTimestampWhenMissing(t *testing.T) {
 		t.Fatal("expected timestamp to be set")
 	}
 }
+
+func TestManagerReadsPersistedRecord(t *testing.T) {
+	base := t.TempDir()
+	manager := state.NewManager(&stubResolver{baseDir: base})
+
+	if _, err := manager.Read(state.Overrides{StateDirectory: base}); !errors.Is(err, state.ErrNotFound()) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+
+	rec := sampleRecord("1.2.3").WithHistory(nil)
+	if _, err := manager.Write(rec, state.Overrides{StateDirectory: base}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	got, err := manager.Read(state.Overrides{StateDirectory: base})
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if got.Version != "1.2.3" || len(got.History) != 1 {
+		t.Fatalf("unexpected record %+v", got)
+	}
+}
+
+func TestRecordHistoryTracksRevisions(t *testing.T) {
+	first := sampleRecord("1.0.0")
+	first.Revision = 1
+	first = first.WithHistory(nil)
+
+	second := sampleRecord("2.0.0")
+	second.Revision = 2
+	second.LastAction = "upgrade"
+	second = second.WithHistory(&first)
+
+	if len(second.History) != 2 {
+		t.Fatalf("expected two history entries, got %+v", second.History)
+	}
+	prev, ok := second.PreviousRevision()
+	if !ok || prev.Revision != 1 || prev.Version != "1.0.0" {
+		t.Fatalf("expected previous revision 1, got %+v (%v)", prev, ok)
+	}
+	if _, ok := second.FindRevision(5); ok {
+		t.Fatal("expected unknown revision lookup to fail")
+	}
+
+	other := sampleRecord("3.0.0")
+	other.Release = "other"
+	if got := other.WithHistory(&second); len(got.History) != 1 {
+		t.Fatalf("expected history reset for a different release, got %+v", got.History)
+	}
+}
+
+func TestRecordFailedAttemptsAreNotRollbackTargets(t *testing.T) {
+	good := sampleRecord("1.0.0")
+	good.Revision = 1
+
+	withFailure := good.WithFailedAttempt(state.HistoryEntry{Revision: 2, Action: "upgrade", Version: "2.0.0"})
+	if withFailure.Revision != 1 || len(withFailure.History) != 2 || !withFailure.History[1].Failed() {
+		t.Fatalf("expected failed attempt appended without changing the release, got %+v", withFailure)
+	}
+	if _, ok := withFailure.FindRevision(2); ok {
+		t.Fatal("expected failed revision to be skipped")
+	}
+
+	restored := sampleRecord("1.0.0")
+	restored.Revision = 3
+	restored.LastAction = "rollback"
+	restored = restored.WithHistory(&withFailure)
+	prev, ok := restored.PreviousRevision()
+	if !ok || prev.Revision != 1 {
+		t.Fatalf("expected previous good revision 1, got %+v (%v)", prev, ok)
+	}
+}
+
+func TestRecordWithFailureKeepsLastGoodRelease(t *testing.T) {
+	good := sampleRecord("1.0.0")
+	good.Revision = 1
+	failure := state.Failure{Action: "upgrade", Phase: "helm", Error: "timed out", Chart: state.ChartSource{Type: "oci", Reference: "oci://registry.example.com/app:2.0.0"}, Version: "2.0.0", Revision: 2}
+
+	failed := good.WithFailure(failure)
+	if failed.Failure == nil || failed.Failure.Timestamp == "" || failed.Revision != 1 || failed.Version != "1.0.0" {
+		t.Fatalf("expected failure annotation on the last good release, got %+v", failed)
+	}
+	if len(failed.History) != 2 || !failed.History[1].Failed() || failed.History[1].Version != "2.0.0" {
+		t.Fatalf("expected failed attempt in history, got %+v", failed.History)
+	}
+
+	first := state.Record{Release: good.Release, Namespace: good.Namespace}.WithFailure(state.Failure{Action: "install", Phase: "verify", Chart: failure.Chart, Version: "2.0.0", Revision: 1})
+	if first.Status != state.StatusFailed || first.LastAction != "install" || first.Chart.Reference != failure.Chart.Reference || len(first.History) != 1 {
+		t.Fatalf("expected failed first install to take the attempted chart, got %+v", first)
+	}
+	if !failure.ReleaseChanged() || (state.Failure{Action: "upgrade", Phase: "helm"}).ReleaseChanged() {
+		t.Fatal("expected only failures with a revision to report a changed release")
+	}
+}
+
+func TestManagerAppendsHistoryWithRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "app.json")
+	manager := state.NewManager(&stubResolver{fixedPath: path}, state.WithHistoryLimit(3))
+
+	for i, version := range []string{"1.0.0", "1.1.0", "1.2.0", "1.3.0"} {
+		record := sampleRecord(version)
+		record.Revision = i + 1
+		record.Operator = "alice"
+		record.WorkflowID = "wf-" + version
+		if _, err := manager.Write(record, state.Overrides{}); err != nil {
+			t.Fatalf("write %s: %v", version, err)
+		}
+	}
+
+	loaded, err := manager.Read(state.Overrides{})
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(loaded.History) != 3 {
+		t.Fatalf("expected history capped at 3 entries, got %+v", loaded.History)
+	}
+	oldest, newest := loaded.History[0], loaded.History[2]
+	if oldest.Revision != 2 || newest.Revision != 4 || newest.Version != "1.3.0" {
+		t.Fatalf("expected revisions 2-4 retained, got %+v", loaded.History)
+	}
+	if newest.Outcome != state.OutcomeSucceeded || newest.Operator != "alice" || newest.WorkflowID != "wf-1.3.0" || newest.Chart.Digest != "sha256:abc" {
+		t.Fatalf("unexpected newest entry %+v", newest)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected 0600 state file, got %v", info.Mode().Perm())
+	}
+}
+
+func TestManagerRemovesStateFile(t *testing.T) {
+	base := t.TempDir()
+	manager := state.NewManager(&stubResolver{baseDir: base})
+
+	written, err := manager.Write(sampleRecord("1.2.3"), state.Overrides{StateDirectory: base})
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	removed, err := manager.Remove(state.Overrides{StateDirectory: base})
+	if err != nil || removed != written {
+		t.Fatalf("expected %s to be removed, got %s (%v)", written, removed, err)
+	}
+	if _, err := os.Stat(written); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected state file to be gone, got %v", err)
+	}
+	if _, err := manager.Remove(state.Overrides{StateDirectory: base}); !errors.Is(err, state.ErrNotFound()) {
+		t.Fatalf("expected not found on second remove, got %v", err)
+	}
+}
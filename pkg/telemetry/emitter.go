@@ -16,11 +16,13 @@ type Phase string
 
 const (
 	PhasePreflight Phase = "preflight"
+	PhaseValidate  Phase = "validate"
 	PhaseBootstrap Phase = "bootstrap"
 	PhaseHelm      Phase = "helm"
 	PhaseUpgrade   Phase = "upgrade"
 	PhaseJoin      Phase = "join"
 	PhaseVerify    Phase = "verify"
+	PhaseRollback  Phase = "rollback"
 )
 
 // Event captures structured telemetry emitted by the CLI.
@@ -120,6 +122,16 @@ func (e *Emitter) EmitPhase(phase Phase, metadata map[string]string, fn func() e
 	return err
 }
 
+// NewCommandLogger returns the structured logger of a new emitter writing to w, for commands
+// that log workflow entries without emitting phase events.
+func NewCommandLogger(w io.Writer) (StructuredLogger, error) {
+	emitter, err := NewEmitter(w)
+	if err != nil {
+		return nil, fmt.Errorf("initialize structured logging: %w", err)
+	}
+	return emitter.StructuredLogger(), nil
+}
+
 // StructuredLogger exposes the structured logger associated with the emitter.
 func (e *Emitter) StructuredLogger() StructuredLogger {
 	return e.logger
@@ -71,3 +71,29 @@ func TestEmitterEmitPhasePropagatesError(t *testing.T) {
 		t.Fatalf("expected duration to be set")
 	}
 }
+
+func TestNewCommandLogger(t *testing.T) {
+	if _, err := telemetry.NewCommandLogger(nil); err == nil {
+		t.Fatalf("expected error for missing writer")
+	}
+
+	var buf bytes.Buffer
+	logger, err := telemetry.NewCommandLogger(&buf)
+	if err != nil {
+		t.Fatalf("new command logger: %v", err)
+	}
+	if err := logger.Emit(telemetry.Entry{Category: telemetry.CategoryWorkflow, Message: "cache list", Severity: telemetry.SeverityInfo}); err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+
+	var entry map[string]any
+	if err := json.NewDecoder(&buf).Decode(&entry); err != nil {
+		t.Fatalf("decode entry: %v", err)
+	}
+	if entry["message"] != "cache list" {
+		t.Fatalf("expected logged message, got %+v", entry)
+	}
+	if entry["workflowId"] == "" || entry["workflowId"] == nil {
+		t.Fatalf("expected workflow id, got %+v", entry)
+	}
+}
@@ -46,7 +46,7 @@ func TestLoggingDryRunEmitsWorkflowEntries(t *testing.T) {
 
 	opts := clustercmd.InstallOptions{
 		Bootstrap:        true,
-		ValuesFile:       t.TempDir() + "/values.enc",
+		ValuesFiles:      []string{t.TempDir() + "/values.enc"},
 		ValuesPassphrase: "super-secret",
 		DryRun:           true,
 		Output:           "json",
@@ -103,7 +103,7 @@ func TestLoggingCapturesHelmFailure(t *testing.T) {
 
 	opts := clustercmd.InstallOptions{
 		Bootstrap:        true,
-		ValuesFile:       t.TempDir() + "/values.enc",
+		ValuesFiles:      []string{t.TempDir() + "/values.enc"},
 		ValuesPassphrase: "another-secret",
 		Output:           "json",
 	}
@@ -159,7 +159,7 @@ func TestLoggingCapturesBootstrapFailure(t *testing.T) {
 
 	opts := clustercmd.InstallOptions{
 		Bootstrap:        true,
-		ValuesFile:       t.TempDir() + "/values.enc",
+		ValuesFiles:      []string{t.TempDir() + "/values.enc"},
 		ValuesPassphrase: "cluster-secret",
 		Output:           "json",
 	}
@@ -228,7 +228,7 @@ func TestLoggingDisabledBlocksExecution(t *testing.T) {
 
 	opts := clustercmd.InstallOptions{
 		Bootstrap:        true,
-		ValuesFile:       t.TempDir() + "/values.enc",
+		ValuesFiles:      []string{t.TempDir() + "/values.enc"},
 		ValuesPassphrase: "disable-secret",
 		Output:           "json",
 	}
@@ -0,0 +1,62 @@
+package statecontracts_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+)
+
+// migrationFixtures lists, per source schema version, state files written in that version's
+// format. Every registered migration step must have fixtures here.
+var migrationFixtures = map[int][]string{
+	1: {"app_success.json", "app_error.json"},
+}
+
+func TestStateMigrationsProduceSchemaValidDocuments(t *testing.T) {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("unable to determine caller information")
+	}
+	fixtureDir := filepath.Join(filepath.Dir(file), "..", "..", "fixtures", "state")
+
+	for _, step := range pkgstate.Migrations() {
+		fixtures := migrationFixtures[step.From]
+		if len(fixtures) == 0 {
+			t.Fatalf("migration from version %d has no contract fixtures", step.From)
+		}
+		for _, name := range fixtures {
+			data, err := os.ReadFile(filepath.Join(fixtureDir, name))
+			if err != nil {
+				t.Fatalf("read fixture: %v", err)
+			}
+			if err := pkgstate.ValidateDocument(data); err != nil {
+				t.Fatalf("fixture %s is not a valid version %d document: %v", name, step.From, err)
+			}
+			var doc map[string]interface{}
+			if err := json.Unmarshal(data, &doc); err != nil {
+				t.Fatalf("decode fixture: %v", err)
+			}
+			migrated, err := step.Migrate(doc)
+			if err != nil {
+				t.Fatalf("migrate %s from version %d: %v", name, step.From, err)
+			}
+			out, err := json.Marshal(migrated)
+			if err != nil {
+				t.Fatalf("encode migrated %s: %v", name, err)
+			}
+			if err := pkgstate.ValidateDocument(out); err != nil {
+				t.Fatalf("migrated %s does not satisfy schema: %v", name, err)
+			}
+			var header struct {
+				SchemaVersion int `json:"schemaVersion"`
+			}
+			if err := json.Unmarshal(out, &header); err != nil || header.SchemaVersion != step.From+1 {
+				t.Fatalf("expected %s migrated to version %d, got %s", name, step.From+1, out)
+			}
+		}
+	}
+}
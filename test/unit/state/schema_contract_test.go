@@ -1,12 +1,15 @@
 package statecontracts_test
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
 
 	jsonschema "github.com/santhosh-tekuri/jsonschema/v6"
+
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
 )
 
 func loadStateSchema(t *testing.T) *jsonschema.Schema {
@@ -76,3 +79,194 @@ func TestStateSchemaRejectsMissingChart(t *testing.T) {
 		t.Fatal("expected schema validation to fail when chart metadata missing")
 	}
 }
+
+func TestStateSchemaAcceptsChartSourceTypes(t *testing.T) {
+	schema := loadStateSchema(t)
+	sources := map[string]string{
+		"oci":    "oci://registry.example.com/apps/myapp:1.2.3",
+		"repo":   "https://charts.example.com/stable/myapp:1.2.3",
+		"dir":    "/src/charts/myapp",
+		"bundle": "/opt/bundles/myapp.tar",
+	}
+	for sourceType, reference := range sources {
+		record := map[string]any{
+			"release":   "myapp-demo",
+			"namespace": "demo",
+			"chart": map[string]any{
+				"type":      sourceType,
+				"reference": reference,
+				"version":   "1.2.3",
+				"digest":    "sha256:abc",
+			},
+			"version":    "1.2.3",
+			"lastAction": "install",
+			"timestamp":  "2025-10-07T12:34:56Z",
+		}
+		if err := schema.Validate(record); err != nil {
+			t.Fatalf("expected %s chart source to satisfy schema, got %v", sourceType, err)
+		}
+	}
+
+	record := map[string]any{
+		"release":    "myapp-demo",
+		"namespace":  "demo",
+		"chart":      map[string]any{"type": "git", "reference": "https://git.example.com/myapp"},
+		"version":    "1.2.3",
+		"lastAction": "install",
+		"timestamp":  "2025-10-07T12:34:56Z",
+	}
+	if err := schema.Validate(record); err == nil {
+		t.Fatal("expected unknown chart source type to be rejected")
+	}
+}
+
+func TestStateSchemaAcceptsRollbackHistory(t *testing.T) {
+	schema := loadStateSchema(t)
+	chart := map[string]any{
+		"type":      "oci",
+		"reference": "oci://registry.example.com/apps/myapp:1.2.3",
+		"digest":    "sha256:abc",
+	}
+	record := map[string]any{
+		"release":    "myapp-demo",
+		"namespace":  "demo",
+		"chart":      chart,
+		"version":    "1.2.3",
+		"lastAction": "rollback",
+		"timestamp":  "2025-10-07T12:34:56Z",
+		"revision":   3,
+		"history": []any{
+			map[string]any{"revision": 1, "action": "install", "chart": chart, "version": "1.2.3", "timestamp": "2025-10-05T12:34:56Z"},
+			map[string]any{"revision": 3, "action": "rollback", "chart": chart, "version": "1.2.3", "timestamp": "2025-10-07T12:34:56Z"},
+		},
+	}
+
+	if err := schema.Validate(record); err != nil {
+		t.Fatalf("expected rollback record to satisfy schema, got %v", err)
+	}
+}
+
+func TestStateSchemaAcceptsAuditedHistory(t *testing.T) {
+	schema := loadStateSchema(t)
+	chart := map[string]any{"type": "oci", "reference": "oci://registry.example.com/apps/myapp:1.3.0", "version": "1.3.0"}
+	entry := func(revision int, outcome string) map[string]any {
+		return map[string]any{
+			"revision":   revision,
+			"action":     "upgrade",
+			"chart":      chart,
+			"version":    "1.3.0",
+			"timestamp":  "2025-10-07T12:34:56Z",
+			"outcome":    outcome,
+			"operator":   "alice",
+			"workflowId": "wf-" + outcome,
+		}
+	}
+	record := map[string]any{
+		"release":    "myapp-demo",
+		"namespace":  "demo",
+		"chart":      chart,
+		"version":    "1.3.0",
+		"lastAction": "upgrade",
+		"timestamp":  "2025-10-07T12:34:56Z",
+		"operator":   "alice",
+		"workflowId": "wf-succeeded",
+		"history":    []any{entry(2, "failed"), entry(3, "succeeded")},
+	}
+	if err := schema.Validate(record); err != nil {
+		t.Fatalf("expected audited history to satisfy schema, got %v", err)
+	}
+
+	record["history"] = []any{entry(2, "aborted")}
+	if err := schema.Validate(record); err == nil {
+		t.Fatal("expected unknown history outcome to be rejected")
+	}
+}
+
+func TestStateSchemaAcceptsChartVerification(t *testing.T) {
+	schema := loadStateSchema(t)
+	record := map[string]any{
+		"release":    "myapp-demo",
+		"namespace":  "demo",
+		"chart":      map[string]any{"type": "oci", "reference": "oci://registry.example.com/apps/myapp:1.2.3"},
+		"version":    "1.2.3",
+		"lastAction": "install",
+		"timestamp":  "2025-10-07T12:34:56Z",
+		"verification": map[string]any{
+			"method": "signature",
+			"signer": "release-team",
+			"keyId":  "0f1e2d3c4b5a6978",
+		},
+	}
+	if err := schema.Validate(record); err != nil {
+		t.Fatalf("expected verified record to satisfy schema, got %v", err)
+	}
+
+	record["verification"] = map[string]any{"method": "checksum"}
+	if err := schema.Validate(record); err == nil {
+		t.Fatal("expected unknown verification method to be rejected")
+	}
+}
+
+func TestStateSchemaAcceptsMultiReleaseDocument(t *testing.T) {
+	schema := loadStateSchema(t)
+	record := func(release string) map[string]any {
+		return map[string]any{
+			"release":         release,
+			"namespace":       "demo",
+			"clusterEndpoint": "https://cluster.local",
+			"chart":           map[string]any{"type": "repo", "reference": "https://charts.example.com/stable/" + release + ":1.2.3", "version": "1.2.3"},
+			"version":         "1.2.3",
+			"lastAction":      "install",
+			"timestamp":       "2025-10-07T12:34:56Z",
+			"operator":        "alice",
+			"workflowId":      "wf-1",
+		}
+	}
+	document := map[string]any{
+		"schemaVersion": 2,
+		"records":       []any{record("billing"), record("myapp")},
+	}
+	if err := schema.Validate(document); err != nil {
+		t.Fatalf("expected multi-release document to satisfy schema, got %v", err)
+	}
+
+	document["schemaVersion"] = 3
+	if err := schema.Validate(document); err == nil {
+		t.Fatal("expected unknown schema version to be rejected")
+	}
+}
+
+func TestStateSchemaAcceptsFixtures(t *testing.T) {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("unable to determine caller information")
+	}
+	fixtures, err := filepath.Glob(filepath.Join(filepath.Dir(file), "..", "..", "fixtures", "state", "*.json"))
+	if err != nil || len(fixtures) == 0 {
+		t.Fatalf("expected state fixtures, got %v (%v)", fixtures, err)
+	}
+	for _, fixture := range fixtures {
+		data, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Fatalf("read fixture: %v", err)
+		}
+		if err := pkgstate.ValidateDocument(data); err != nil {
+			t.Fatalf("fixture %s does not satisfy schema: %v", filepath.Base(fixture), err)
+		}
+	}
+}
+
+func TestEmbeddedStateSchemaMatchesContract(t *testing.T) {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("unable to determine caller information")
+	}
+	repoRoot := filepath.Clean(filepath.Join(filepath.Dir(file), "..", "..", ".."))
+	contract, err := os.ReadFile(filepath.Join(repoRoot, "specs", "002-oci-helm-state", "contracts", "state-schema.json"))
+	if err != nil {
+		t.Fatalf("read contract: %v", err)
+	}
+	if !bytes.Equal(contract, pkgstate.Schema()) {
+		t.Fatal("pkg/state/state-schema.json is out of date; copy specs/002-oci-helm-state/contracts/state-schema.json")
+	}
+}
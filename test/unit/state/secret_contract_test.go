@@ -0,0 +1,122 @@
+package statecontracts_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	pkgstate "github.com/dobrovols/chainctl/pkg/state"
+)
+
+// seedStateSecret stores payload in the state Secret of release, as an earlier chainctl would have.
+func seedStateSecret(t *testing.T, client *fake.Clientset, namespace, release string, payload []byte) {
+	t.Helper()
+	_, err := client.CoreV1().Secrets(namespace).Create(context.Background(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pkgstate.StateSecretName(release),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "chainctl",
+				"chainctl.io/component":        "state",
+				"chainctl.io/release":          release,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{"record": payload},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("seed secret: %v", err)
+	}
+}
+
+func storedSecretPayload(t *testing.T, client *fake.Clientset, namespace, release string) []byte {
+	t.Helper()
+	secret, err := client.CoreV1().Secrets(namespace).Get(context.Background(), pkgstate.StateSecretName(release), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get secret: %v", err)
+	}
+	return secret.Data["record"]
+}
+
+func TestSecretBackendMigratesLegacyPayloadsToSchemaValidDocuments(t *testing.T) {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("unable to determine caller information")
+	}
+	fixtureDir := filepath.Join(filepath.Dir(file), "..", "..", "fixtures", "state")
+
+	for _, name := range migrationFixtures[1] {
+		legacy, err := os.ReadFile(filepath.Join(fixtureDir, name))
+		if err != nil {
+			t.Fatalf("read fixture: %v", err)
+		}
+		var fixture pkgstate.Record
+		if err := json.Unmarshal(legacy, &fixture); err != nil {
+			t.Fatalf("decode fixture: %v", err)
+		}
+
+		client := fake.NewSimpleClientset()
+		seedStateSecret(t, client, fixture.Namespace, fixture.Release, legacy)
+		store := pkgstate.NewSecretStore(client)
+		overrides := pkgstate.Overrides{Release: fixture.Release, Namespace: fixture.Namespace}
+
+		record, err := store.Read(overrides)
+		if err != nil {
+			t.Fatalf("read legacy %s: %v", name, err)
+		}
+		if record.Version != fixture.Version || record.LastAction != fixture.LastAction {
+			t.Fatalf("expected legacy %s decoded, got %+v", name, record)
+		}
+
+		record.Timestamp = ""
+		if _, err := store.Write(record, overrides); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		payload := storedSecretPayload(t, client, fixture.Namespace, fixture.Release)
+		if err := pkgstate.ValidateDocument(payload); err != nil {
+			t.Fatalf("secret payload for %s does not satisfy schema: %v", name, err)
+		}
+		var header struct {
+			SchemaVersion int               `json:"schemaVersion"`
+			Records       []json.RawMessage `json:"records"`
+		}
+		if err := json.Unmarshal(payload, &header); err != nil || header.SchemaVersion != pkgstate.SchemaVersion || len(header.Records) != 1 {
+			t.Fatalf("expected a single-record version %d document for %s, got %s", pkgstate.SchemaVersion, name, payload)
+		}
+	}
+}
+
+func TestSecretBackendRejectsNewerSchemaVersions(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	newer := []byte(`{"schemaVersion": 99, "records": []}`)
+	seedStateSecret(t, client, "demo", "myapp", newer)
+	store := pkgstate.NewSecretStore(client)
+	overrides := pkgstate.Overrides{Release: "myapp", Namespace: "demo"}
+
+	if _, err := store.Read(overrides); !errors.Is(err, pkgstate.ErrUnsupportedSchema()) {
+		t.Fatalf("expected unsupported schema error on read, got %v", err)
+	}
+
+	record := pkgstate.Record{
+		Release:    "myapp",
+		Namespace:  "demo",
+		Chart:      pkgstate.ChartSource{Type: "oci", Reference: "oci://registry.example.com/apps/myapp:1.2.3"},
+		Version:    "1.2.3",
+		LastAction: "upgrade",
+		History:    []pkgstate.HistoryEntry{{Version: "1.2.3", Action: "upgrade"}},
+	}
+	if _, err := store.Write(record, overrides); !errors.Is(err, pkgstate.ErrUnsupportedSchema()) {
+		t.Fatalf("expected unsupported schema error on write, got %v", err)
+	}
+	if payload := storedSecretPayload(t, client, "demo", "myapp"); string(payload) != string(newer) {
+		t.Fatalf("expected newer payload left untouched, got %s", payload)
+	}
+}